@@ -2,9 +2,9 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
-	"path/filepath"
 
 	"github.com/xsikor/go-battop/internal/app"
 )
@@ -23,9 +23,56 @@ func main() {
 		os.Exit(1)
 	}
 
+	build := app.BuildInfo{Version: version, Commit: commit, Date: date}
+
 	// Handle version flag
 	if config.Version {
-		fmt.Printf("battop %s (%s) built on %s\n", version, commit, date)
+		fmt.Println(build.String())
+		os.Exit(0)
+	}
+
+	// Handle info command
+	if config.Command == app.CommandInfo {
+		if err := app.PrintInfo(os.Stdout, config, build); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Handle statusline command
+	if config.Command == app.CommandStatusline {
+		if err := app.PrintStatusline(os.Stdout, config); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Handle share command
+	if config.Command == app.CommandShare {
+		if err := app.Share(os.Stdout, config, build); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Handle report command
+	if config.Command == app.CommandReport {
+		if err := app.Report(os.Stdout, config, build); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Handle diff command
+	if config.Command == app.CommandDiff {
+		if err := app.Diff(os.Stdout, config); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
 		os.Exit(0)
 	}
 
@@ -35,25 +82,35 @@ func main() {
 		logLevel = slog.LevelDebug
 	}
 
-	// Create or open error log file in temp directory
-	logPath := filepath.Join(os.TempDir(), "go-battop.log")
-	errorLog, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to open error log at %s: %v\n", logPath, err)
-		os.Exit(1)
-	}
-	defer errorLog.Close()
-
 	opts := &slog.HandlerOptions{
 		Level: logLevel,
 	}
 
-	// Log to error.log file
-	logger := slog.New(slog.NewTextHandler(errorLog, opts))
+	// The daemon logs to stderr for the service manager (journald, launchd)
+	var logOutput io.Writer = os.Stderr
+	if config.Command != app.CommandDaemon {
+		// Create or open error log file in temp directory
+		logPath := app.LogPath()
+		errorLog, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to open error log at %s: %v\n", logPath, err)
+			os.Exit(1)
+		}
+		defer errorLog.Close()
+		logOutput = errorLog
+	}
+
+	// Log to error.log file or stderr
+	logger := slog.New(slog.NewTextHandler(logOutput, opts))
 	slog.SetDefault(logger)
 
 	// Create and run application
-	application := app.New(config)
+	application, err := app.New(config)
+	if err != nil {
+		slog.Error("Failed to open the battery source", "error", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 	if err := application.Run(); err != nil {
 		slog.Error("Application error", "error", err)
 		os.Exit(1)
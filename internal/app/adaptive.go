@@ -0,0 +1,59 @@
+package app
+
+import (
+	"math"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/battery"
+)
+
+// AdaptiveSampler adapts the update delay to the battery activity to save
+// battop's own power: it doubles the delay up to AdaptiveMaxDelay while every
+// battery is at rest with a steady charge rate, and drops back to the base
+// delay as soon as a rate changes quickly or a battery starts charging or
+// discharging
+type AdaptiveSampler struct {
+	base  time.Duration
+	delay time.Duration
+
+	// Charge rates of the previous readings, per battery
+	rates []float64
+}
+
+// NewAdaptiveSampler creates a sampler starting at the base delay
+func NewAdaptiveSampler(base time.Duration) *AdaptiveSampler {
+	return &AdaptiveSampler{base: base, delay: base}
+}
+
+// Next returns the delay until the reading after the given one
+func (s *AdaptiveSampler) Next(batteries []*battery.Info) time.Duration {
+	settled := len(batteries) == len(s.rates)
+	for i, info := range batteries {
+		if settled && math.Abs(info.ChargeRate-s.rates[i]) >= AdaptiveRateChange {
+			settled = false
+		}
+		if !resting(info) {
+			settled = false
+		}
+	}
+
+	s.rates = s.rates[:0]
+	for _, info := range batteries {
+		s.rates = append(s.rates, info.ChargeRate)
+	}
+
+	if settled {
+		s.delay = min(2*s.delay, max(AdaptiveMaxDelay, s.base))
+	} else {
+		s.delay = s.base
+	}
+	return s.delay
+}
+
+// resting reports whether a battery is neither charging nor discharging by
+// AdaptiveRateChange or more. The rate decides rather than the state, which
+// some firmware leaves at full while on battery or at charging after a
+// charge limit stopped the charge.
+func resting(info *battery.Info) bool {
+	return math.Abs(info.ChargeRate) < AdaptiveRateChange
+}
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/battery"
+)
+
+func TestAdaptiveSampler(t *testing.T) {
+	sampler := NewAdaptiveSampler(time.Second)
+	reading := func(state battery.State, rate float64) []*battery.Info {
+		return []*battery.Info{{State: state, ChargeRate: rate}}
+	}
+
+	steps := []struct {
+		name  string
+		info  []*battery.Info
+		delay time.Duration
+	}{
+		{"first reading", reading(battery.StateFull, 0), time.Second},
+		{"steady full", reading(battery.StateFull, 0), 2 * time.Second},
+		{"steady full", reading(battery.StateFull, 200), 4 * time.Second},
+		{"steady full", reading(battery.StateFull, 0), 8 * time.Second},
+		{"capped", reading(battery.StateFull, 0), AdaptiveMaxDelay},
+		{"rate jump", reading(battery.StateFull, 5000), time.Second},
+		{"full but still charging", reading(battery.StateFull, 5000), time.Second},
+		{"discharging", reading(battery.StateDischarging, -5000), time.Second},
+		{"still discharging", reading(battery.StateDischarging, -5000), time.Second},
+		{"stopped at the charge limit", reading(battery.StateCharging, 0), time.Second},
+		{"still stopped", reading(battery.StateCharging, 0), 2 * time.Second},
+		{"trickle", reading(battery.StateNotCharging, 300), 4 * time.Second},
+	}
+	for _, step := range steps {
+		if delay := sampler.Next(step.info); delay != step.delay {
+			t.Errorf("%s: delay = %v, want %v", step.name, delay, step.delay)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/battery"
+)
+
+// AlarmMode selects how the critical battery alarm sounds
+type AlarmMode string
+
+// Alarm modes
+const (
+	// AlarmOff disables the alarm
+	AlarmOff AlarmMode = "off"
+
+	// AlarmBell rings the terminal bell
+	AlarmBell AlarmMode = "bell"
+
+	// AlarmSound plays the system alert sound, falling back to the bell
+	AlarmSound AlarmMode = "sound"
+)
+
+// ParseAlarmMode returns the alarm mode with the given name
+func ParseAlarmMode(name string) (AlarmMode, error) {
+	switch mode := AlarmMode(name); mode {
+	case AlarmOff, AlarmBell, AlarmSound:
+		return mode, nil
+	default:
+		return AlarmOff, fmt.Errorf("invalid alarm: must be 'off', 'bell' or 'sound'")
+	}
+}
+
+// AlarmHysteresis is how many percentage points above the critical threshold
+// the charge must rise before the alarm sounds again
+const AlarmHysteresis = 2.0
+
+// CriticalAlarm sounds once per crossing when a discharging battery drops to
+// the critical threshold. It re-arms once the charge is AlarmHysteresis points
+// above the threshold or the battery stops discharging, so a charge hovering
+// around the threshold doesn't sound every tick.
+type CriticalAlarm struct {
+	config *Config
+	bell   func()
+
+	// Per-battery edge tracking so each crossing sounds once
+	fired map[int]bool
+}
+
+// NewCriticalAlarm creates an alarm that rings the terminal bell with bell
+func NewCriticalAlarm(config *Config, bell func()) *CriticalAlarm {
+	return &CriticalAlarm{
+		config: config,
+		bell:   bell,
+		fired:  make(map[int]bool),
+	}
+}
+
+// Check sounds the alarm for every battery that dropped to the critical
+// threshold since the last check
+func (a *CriticalAlarm) Check(batteries []*battery.Info) {
+	if a.config.Alarm == AlarmOff || a.config.Alarm == "" {
+		return
+	}
+
+	for _, info := range batteries {
+		percent := info.ChargePercent()
+		if info.State.Base() != battery.StateDischarging || percent > a.config.CriticalThreshold+AlarmHysteresis {
+			a.fired[info.Index] = false
+			continue
+		}
+		if percent > a.config.CriticalThreshold || a.fired[info.Index] {
+			continue
+		}
+
+		a.fired[info.Index] = true
+		if a.config.Muted(time.Now()) {
+			slog.Info("Critical battery alarm muted by quiet hours", "index", info.Index, "percent", percent)
+			continue
+		}
+		slog.Info("Critical battery alarm", "index", info.Index, "percent", percent, "mode", a.config.Alarm)
+		a.sound()
+	}
+}
+
+// sound plays the alarm of the configured mode
+func (a *CriticalAlarm) sound() {
+	if a.config.Alarm == AlarmSound {
+		go func() {
+			if !playAlertSound() {
+				a.bell()
+			}
+		}()
+		return
+	}
+	a.bell()
+}
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/xsikor/go-battop/internal/battery"
+)
+
+func TestCriticalAlarmHysteresis(t *testing.T) {
+	config := DefaultConfig()
+	config.Alarm = AlarmBell
+	rings := 0
+	alarm := NewCriticalAlarm(config, func() { rings++ })
+
+	steps := []struct {
+		state   battery.State
+		percent float64
+		rings   int
+	}{
+		{battery.StateDischarging, 6, 0},
+		{battery.StateDischarging, 5, 1},
+		{battery.StateDischarging, 4, 1},
+		// Hovering around the threshold doesn't ring again
+		{battery.StateDischarging, 6, 1},
+		{battery.StateDischarging, 5, 1},
+		// Rising past the hysteresis re-arms it
+		{battery.StateDischarging, 8, 1},
+		{battery.StateDischarging, 5, 2},
+		// So does plugging in
+		{battery.StateCharging, 5, 2},
+		{battery.StateDischarging, 5, 3},
+	}
+	for i, step := range steps {
+		alarm.Check([]*battery.Info{{State: step.state, Current: step.percent, Full: 100}})
+		if rings != step.rings {
+			t.Fatalf("step %d: rang %d times, want %d", i, rings, step.rings)
+		}
+	}
+}
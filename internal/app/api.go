@@ -0,0 +1,160 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/xsikor/go-battop/internal/battery"
+	pkgErrors "github.com/xsikor/go-battop/internal/errors"
+	"github.com/xsikor/go-battop/internal/ui"
+)
+
+// APIServer serves live battery data as JSON over HTTP
+type APIServer struct {
+	mu          sync.RWMutex
+	server      *http.Server
+	manager     battery.Source
+	broadcaster *Broadcaster
+	history     func() []ui.ChartHistory
+
+	// origins are the web page origins allowed to open the WebSocket
+	// besides loopback pages
+	origins []string
+}
+
+// NewAPIServer creates an API server listening on addr
+func NewAPIServer(addr string, manager battery.Source) *APIServer {
+	s := &APIServer{
+		manager:     manager,
+		broadcaster: NewBroadcaster(),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /batteries", s.handleBatteries)
+	mux.HandleFunc("GET /batteries/{index}", s.handleBattery)
+	mux.HandleFunc("GET /power", s.handlePower)
+	mux.HandleFunc("GET /history", s.handleHistory)
+	mux.HandleFunc("GET /ws", s.handleWebSocket)
+
+	s.server = &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: APIReadHeaderTimeout,
+	}
+	return s
+}
+
+// SetHistory sets the source of the chart history, which only exists while
+// the terminal UI runs
+func (s *APIServer) SetHistory(history func() []ui.ChartHistory) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.history = history
+}
+
+// AllowOrigins allows web pages of the origins (e.g., "https://dash.example.com")
+// to open the WebSocket besides pages served from loopback
+func (s *APIServer) AllowOrigins(origins ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.origins = origins
+}
+
+// Publish sends a sample to every WebSocket client
+func (s *APIServer) Publish(sample any) {
+	s.broadcaster.Publish(sample)
+}
+
+// Start listens on the configured address and serves requests in the background
+func (s *APIServer) Start() error {
+	listener, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.server.Addr, err)
+	}
+	slog.Info("HTTP API listening", "addr", listener.Addr().String())
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("HTTP API stopped", "error", err)
+		}
+	}()
+	return nil
+}
+
+// Close stops the server
+func (s *APIServer) Close() {
+	if err := s.server.Close(); err != nil {
+		slog.Warn("Failed to close HTTP API", "error", err)
+	}
+}
+
+// handleBatteries serves all batteries
+func (s *APIServer) handleBatteries(w http.ResponseWriter, r *http.Request) {
+	batteries, err := s.manager.GetAll()
+	if err != nil {
+		writeAPIError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	writeJSON(w, batteries)
+}
+
+// handleBattery serves a single battery by its index
+func (s *APIServer) handleBattery(w http.ResponseWriter, r *http.Request) {
+	index, err := strconv.Atoi(r.PathValue("index"))
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, fmt.Errorf("invalid battery index %q", r.PathValue("index")))
+		return
+	}
+
+	info, err := s.manager.Get(index)
+	if errors.Is(err, pkgErrors.ErrBatteryNotFound) {
+		writeAPIError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		writeAPIError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	writeJSON(w, info)
+}
+
+// handlePower serves the power source and its adapters
+func (s *APIServer) handlePower(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.manager.PowerSource())
+}
+
+// handleHistory serves the in-memory chart series
+func (s *APIServer) handleHistory(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	history := s.history
+	s.mu.RUnlock()
+
+	if history == nil {
+		writeJSON(w, []ui.ChartHistory{})
+		return
+	}
+	writeJSON(w, history())
+}
+
+// writeJSON writes the value as a JSON response
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		slog.Debug("Failed to write API response", "error", err)
+	}
+}
+
+// writeAPIError writes an error as a JSON response with the status code
+func writeAPIError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,225 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/battery"
+	pkgErrors "github.com/xsikor/go-battop/internal/errors"
+	"github.com/xsikor/go-battop/internal/stats"
+	"github.com/xsikor/go-battop/internal/ui"
+	"github.com/xsikor/go-battop/pkg/plot"
+)
+
+// apiSource serves fixed readings to the API
+type apiSource struct {
+	infos  []*battery.Info
+	source battery.PowerSource
+	err    error
+}
+
+func (s *apiSource) Update() error                    { return nil }
+func (s *apiSource) PowerSource() battery.PowerSource { return s.source }
+func (s *apiSource) Count() int                       { return len(s.infos) }
+func (s *apiSource) GetAll() ([]*battery.Info, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.infos, nil
+}
+func (s *apiSource) Get(index int) (*battery.Info, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	if index < 0 || index >= len(s.infos) {
+		return nil, pkgErrors.ErrBatteryNotFound
+	}
+	return s.infos[index], nil
+}
+
+// newTestAPI starts an API server for the source on a test listener
+func newTestAPI(t *testing.T, source battery.Source) (*APIServer, *httptest.Server) {
+	t.Helper()
+	api := NewAPIServer("127.0.0.1:0", source)
+	server := httptest.NewServer(api.server.Handler)
+	t.Cleanup(server.Close)
+	return api, server
+}
+
+func TestAPIEndpoints(t *testing.T) {
+	source := &apiSource{
+		infos: []*battery.Info{
+			{Index: 0, State: battery.StateDischarging, Current: 25000, Full: 50000, ChargeRate: -9500},
+			{Index: 1, State: battery.StateFull, Current: 20000, Full: 20000},
+		},
+		source: battery.PowerSource{Detected: true, Adapters: []battery.ACAdapter{{Name: "AC", Online: false}}},
+	}
+	_, server := newTestAPI(t, source)
+
+	tests := []struct {
+		path   string
+		status int
+		check  func(t *testing.T, body []byte)
+	}{
+		{"/batteries", http.StatusOK, func(t *testing.T, body []byte) {
+			var infos []*battery.Info
+			if err := json.Unmarshal(body, &infos); err != nil || len(infos) != 2 || infos[1].State != battery.StateFull {
+				t.Errorf("batteries %s (%v)", body, err)
+			}
+		}},
+		{"/batteries/0", http.StatusOK, func(t *testing.T, body []byte) {
+			var info battery.Info
+			if err := json.Unmarshal(body, &info); err != nil || info.ChargeRate != -9500 {
+				t.Errorf("battery %s (%v)", body, err)
+			}
+		}},
+		{"/batteries/7", http.StatusNotFound, nil},
+		{"/batteries/first", http.StatusBadRequest, nil},
+		{"/power", http.StatusOK, func(t *testing.T, body []byte) {
+			var power battery.PowerSource
+			if err := json.Unmarshal(body, &power); err != nil || !power.Detected || len(power.Adapters) != 1 {
+				t.Errorf("power %s (%v)", body, err)
+			}
+		}},
+		{"/history", http.StatusOK, func(t *testing.T, body []byte) {
+			var history []ui.ChartHistory
+			if err := json.Unmarshal(body, &history); err != nil || len(history) != 0 {
+				t.Errorf("history without a UI %s (%v)", body, err)
+			}
+		}},
+		{"/ws", http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			status, body := apiGet(t, server.URL+tt.path)
+			if status != tt.status {
+				t.Fatalf("status %d, want %d: %s", status, tt.status, body)
+			}
+			if tt.check != nil {
+				tt.check(t, body)
+			}
+		})
+	}
+
+	source.err = fmt.Errorf("no batteries found")
+	if status, _ := apiGet(t, server.URL+"/batteries"); status != http.StatusServiceUnavailable {
+		t.Errorf("status %d while the batteries can't be read", status)
+	}
+}
+
+func TestAPIHistory(t *testing.T) {
+	api, server := newTestAPI(t, &apiSource{})
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	api.SetHistory(func() []ui.ChartHistory {
+		return []ui.ChartHistory{{Battery: 0, Chart: "power", Unit: "W", Points: []plot.Point{
+			{Time: now, Value: 9.5},
+			{Time: now.Add(time.Second), Value: 10},
+			// A suspend leaves a gap point without a value
+			{Time: now.Add(time.Minute), Value: math.NaN(), Gap: time.Hour},
+			{Time: now.Add(time.Hour), Value: 8},
+		}}}
+	})
+
+	status, body := apiGet(t, server.URL+"/history")
+	var history []ui.ChartHistory
+	if err := json.Unmarshal(body, &history); status != http.StatusOK || err != nil {
+		t.Fatalf("status %d, %v: %s", status, err, body)
+	}
+	if len(history) != 1 || len(history[0].Points) != 4 || history[0].Points[1].Value != 10 {
+		t.Fatalf("history %s", body)
+	}
+	if gap := history[0].Points[2]; !math.IsNaN(gap.Value) || gap.Gap != time.Hour {
+		t.Errorf("gap point %+v", gap)
+	}
+}
+
+func TestAPIRemoteRoundTrip(t *testing.T) {
+	source := &apiSource{
+		infos: []*battery.Info{
+			{Index: 0, State: battery.StateDischarging, Current: 25000, Full: 50000, Design: 55000, ChargeRate: -9500, Voltage: 11.4, CycleCount: 120},
+			{Index: 1, State: battery.StateFull, Current: 20000, Full: 20000},
+		},
+		source: battery.PowerSource{Detected: true, Adapters: []battery.ACAdapter{{Name: "AC", Online: false}}},
+	}
+	_, server := newTestAPI(t, source)
+
+	remote := battery.NewRemoteSource(server.URL)
+	if err := remote.Update(); err != nil {
+		t.Fatal(err)
+	}
+	if remote.Count() != 2 {
+		t.Fatalf("%d batteries through the API", remote.Count())
+	}
+	info, err := remote.Get(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := source.infos[0]
+	if info.Index != want.Index || info.State != want.State || info.Current != want.Current || info.Full != want.Full ||
+		info.Design != want.Design || info.ChargeRate != want.ChargeRate || info.Voltage != want.Voltage || info.CycleCount != want.CycleCount {
+		t.Errorf("battery %+v, want %+v", info, want)
+	}
+	if power := remote.PowerSource(); !power.Detected || len(power.Adapters) != 1 || power.Adapters[0].Name != "AC" {
+		t.Errorf("power source %+v", power)
+	}
+
+	// A remote whose batteries can't be read reports the failure
+	source.err = fmt.Errorf("no batteries found")
+	failing := battery.NewRemoteSource(server.URL)
+	if err := failing.Update(); err == nil {
+		t.Error("update succeeded while the remote has no batteries")
+	}
+}
+
+// apiGet returns the status and body of a GET request
+func apiGet(t *testing.T, url string) (int, []byte) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var body json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid JSON from %s: %v", url, err)
+	}
+	return resp.StatusCode, body
+}
+
+func TestAPIHistoryDuringReload(t *testing.T) {
+	source := &apiSource{infos: []*battery.Info{
+		{Index: 0, State: battery.StateDischarging, Current: 25000, Full: 50000, Design: 55000, ChargeRate: -9500},
+	}}
+	config := DefaultConfig()
+	face, err := ui.NewInterface(source, stats.NewTracker(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	face.Update()
+	api, server := newTestAPI(t, source)
+	api.SetHistory(face.History)
+
+	// The UI goroutine reloads the configuration while clients read the
+	// history; go test -race reports unguarded chart access
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 50 {
+			face.Reload()
+		}
+	}()
+	for range 50 {
+		status, body := apiGet(t, server.URL+"/history")
+		var history []ui.ChartHistory
+		if err := json.Unmarshal(body, &history); status != http.StatusOK || err != nil || len(history) == 0 {
+			t.Fatalf("status %d, %v: %s", status, err, body)
+		}
+	}
+	<-done
+}
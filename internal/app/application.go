@@ -2,41 +2,240 @@ package app
 
 import (
 	"fmt"
+	"image"
+	"io"
 	"log/slog"
+	"os"
+	"strings"
 	"time"
 
+	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 	"github.com/xsikor/go-battop/internal/battery"
+	"github.com/xsikor/go-battop/internal/export"
+	"github.com/xsikor/go-battop/internal/session"
+	"github.com/xsikor/go-battop/internal/stats"
+	"github.com/xsikor/go-battop/internal/store"
 	"github.com/xsikor/go-battop/internal/ui"
 )
 
 // Application orchestrates the battery monitoring terminal UI application
 type Application struct {
-	config   *Config
-	tviewApp *tview.Application
-	manager  *battery.Manager
-	events   *EventManager
-	ui       interface {
+	config    *Config
+	tviewApp  *tview.Application
+	manager   battery.Source
+	events    *EventManager
+	hooks     *HookRunner
+	target    *ChargeTargetNotifier
+	alarm     *CriticalAlarm
+	sampler   *AdaptiveSampler
+	stats     *stats.Tracker
+	idle      *session.IdleDetector
+	store     *store.Store
+	health    *stats.HealthHistory
+	timeline  *stats.Timeline
+	eventLog  *stats.EventLog
+	api       *APIServer
+	exporters *export.Set
+	control   *ControlServer
+
+	// paused skips sampling while set by the control socket
+	paused bool
+
+	// samples counts battery updates to withhold alerts during warm-up
+	samples int
+
+	// demoDir is the temporary data directory of the demo, removed on exit
+	demoDir string
+
+	// screen is the terminal screen screenshots are taken of
+	screen tcell.Screen
+
+	// Discharge recording and comparison (nil when disabled)
+	recorder  *stats.DischargeRecorder
+	reference *stats.ReferenceCurve
+	ui        interface {
 		GetRoot() tview.Primitive
 		Update() error
+		Refresh() error
 		NextTab()
 		PreviousTab()
+		ToggleHealthHistory()
+		ToggleTimeline()
+		TogglePeripherals()
+		TogglePowerBreakdown()
+		ToggleConsumers()
+		ToggleEventLog()
+		SetEventLog(log *stats.EventLog)
+		PromptNote(submit func(text string) error, closed func()) tview.Primitive
+		SearchEventLog(closed func()) tview.Primitive
+		CycleEventSeverity() stats.Severity
+		ToggleChart(position int)
+		ToggleChartLayout()
+		Resize(width int)
+		ScrollInfo(rows int)
+		PageInfo(pages int)
+		InfoAt(x, y int) bool
+		CycleZoom()
+		Click(x, y int)
+		Zoom(steps int)
+		ToggleInspect()
+		Inspecting() bool
+		MoveCursor(steps int)
+		CycleScale() string
+		CycleTheme() string
+		ToggleCompact()
+		ToggleHistogram()
+		Reload()
+		SetDelay(delay time.Duration)
+		SetTrueColor(enabled bool)
+		ToggleChargeBasis() ui.ChargeBasis
+		ToggleDetails()
+		SetReserve(reserve *stats.Reserve)
+		PromptReserve(submit func(text string) error, closed func()) tview.Primitive
+		SetGoal(goal *stats.Goal)
+		PromptGoal(submit func(text string) error, closed func()) tview.Primitive
+		ShowToast(message string)
+		ChartImage() image.Image
+		WriteChartSVG(w io.Writer) error
+		History() []ui.ChartHistory
 	}
 }
 
 // New creates and initializes a new Application with the given configuration
-func New(config *Config) *Application {
-	return &Application{
+func New(config *Config) (*Application, error) {
+	idle := session.NewIdleDetector(config.IdleSource, config.IdleFile)
+	source, err := newBatterySource(config, idle)
+	if err != nil {
+		return nil, err
+	}
+
+	// The demo and replays keep their health history, timeline and settings
+	// out of the data directory
+	var demoDir string
+	if config.Command == CommandDemo || config.Command == CommandReplay {
+		dir, err := os.MkdirTemp("", "battop-demo-")
+		if err != nil {
+			slog.Warn("Failed to create the demo data directory, using the default", "error", err)
+		} else {
+			demoDir, config.DataDir = dir, dir
+		}
+	}
+
+	app := &Application{
 		config:   config,
 		tviewApp: tview.NewApplication(),
-		manager:  battery.NewManager(),
+		manager:  source,
+		hooks:    NewHookRunner(config),
+		stats:    stats.NewTracker(),
+		idle:     idle,
+		store:    store.New(config.DataDir),
+		demoDir:  demoDir,
+	}
+	app.target = NewChargeTargetNotifier(config, app.hooks)
+	app.alarm = NewCriticalAlarm(config, app.ringBell)
+	app.timeline = stats.NewTimeline(app.store)
+	app.eventLog = stats.NewEventLog(eventThresholds(config)...)
+	return app, nil
+}
+
+// eventThresholds returns the charge thresholds whose crossings the event
+// log records: the hook thresholds and the charge target when set
+func eventThresholds(config *Config) []stats.ChargeThreshold {
+	thresholds := []stats.ChargeThreshold{
+		{Percent: config.CriticalThreshold, Severity: stats.SeverityCritical},
+		{Percent: config.LowThreshold, Severity: stats.SeverityWarn},
+	}
+	if config.ChargeTarget > 0 {
+		thresholds = append(thresholds, stats.ChargeThreshold{Percent: config.ChargeTarget, Severity: stats.SeverityInfo})
 	}
+	return thresholds
+}
+
+// newBatterySource returns the simulator for the demo, the replay or -source
+// opened by ParseFlags, the remote source when -connect is given, the
+// Android battery inside Termux and the local batteries otherwise; idle may
+// be nil
+func newBatterySource(config *Config, idle *session.IdleDetector) (battery.Source, error) {
+	if config.Command == CommandDemo {
+		return newDemoSource(), nil
+	}
+
+	source := config.source
+	switch {
+	case source != nil:
+	case config.Command == CommandReplay:
+		replay, err := battery.OpenReplay(config.ReplayFile, config.ReplayStep())
+		if err != nil {
+			return nil, fmt.Errorf("failed to open the recording: %w", err)
+		}
+		source = replay
+	case config.Source != "":
+		opened, err := battery.OpenSource(config.Source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open source %s: %w", config.Source, err)
+		}
+		source = opened
+	case config.Connect != "":
+		// The remote instance tags idle samples and smooths rates itself
+		return battery.NewRemoteSource(config.Connect), nil
+	}
+	if source != nil {
+		if manager, ok := source.(*battery.Manager); ok {
+			configureManager(manager, config, idle)
+		}
+		return source, nil
+	}
+
+	if battery.InTermux() {
+		termux, err := battery.NewTermuxSource()
+		if err == nil {
+			return termux, nil
+		}
+		slog.Warn("Android battery unavailable, trying the generic reader", "error", err)
+	}
+
+	manager := battery.NewManager()
+	configureManager(manager, config, idle)
+	return manager, nil
+}
+
+// isRemote reports whether the source reads the batteries of another battop
+// instance, through -connect or an http(s) -source
+func isRemote(source battery.Source) bool {
+	_, ok := source.(*battery.RemoteSource)
+	return ok
+}
+
+// configureManager applies the smoothing and idle detection to a manager of
+// the local batteries
+func configureManager(manager *battery.Manager, config *Config, idle *session.IdleDetector) {
+	manager.SetSmoothing(config.Smoothing)
+	if idle != nil && idle.Enabled() {
+		manager.SetIdleDetector(idle)
+	}
+}
+
+// newScreen creates the terminal screen, dropping all colors when monochrome
+func newScreen(monochrome bool) (tcell.Screen, error) {
+	screen, err := tcell.NewScreen()
+	if err != nil || !monochrome {
+		return screen, err
+	}
+	return ui.NewMonochromeScreen(screen), nil
 }
 
 // Run starts the main application event loop and blocks until exit
 func (a *Application) Run() error {
 	slog.Info("Starting battop", "version", "0.3.0")
 
+	if closer, ok := a.manager.(io.Closer); ok {
+		defer closer.Close()
+	}
+	if a.demoDir != "" {
+		defer os.RemoveAll(a.demoDir)
+	}
+
 	// Initial battery update
 	if err := a.manager.Update(); err != nil {
 		return fmt.Errorf("initial battery update failed: %w", err)
@@ -52,18 +251,125 @@ func (a *Application) Run() error {
 	}
 
 	slog.Info("Found batteries", "count", len(batteries))
+	slog.Info("Idle detection", "sources", a.idle.Sources())
+
+	if err := a.setupDischargeProfiles(); err != nil {
+		return err
+	}
+	defer a.closeDischargeProfiles()
+
+	a.health = stats.LoadHealthHistory(a.store, batteries[0])
+	slog.Info("Health history loaded", "records", len(a.health.Entries()))
+	a.backfillTimeline()
+
+	a.onBatteryUpdate()
+
+	if a.config.APIListen != "" {
+		a.api = NewAPIServer(a.config.APIListen, a.manager)
+		a.api.AllowOrigins(a.config.AllowedOrigins()...)
+		if err := a.api.Start(); err != nil {
+			return err
+		}
+		defer a.api.Close()
+	}
+	if a.exporters, err = export.Open(a.config.ExportSettings()); err != nil {
+		return err
+	}
+	defer a.exporters.Stop()
+	if a.config.ControlSocket != "" {
+		a.control = NewControlServer(a.config.ControlSocket)
+		if err := a.control.Start(); err != nil {
+			return err
+		}
+		defer a.control.Close()
+	}
+
+	if a.config.Command == CommandDaemon {
+		return a.runDaemon()
+	}
+
+	// JSON output, ticker and plain mode replace the full terminal UI
+	if a.config.Output == OutputJSON {
+		return a.runJSON()
+	}
+	if a.config.Ticker {
+		return a.runTicker()
+	}
+	if a.config.Plain {
+		return a.runPlain()
+	}
 
 	// Create UI
-	ui, err := ui.NewInterface(a.manager, a.config)
+	ui, err := ui.NewInterface(a.manager, a.stats, a.config)
 	if err != nil {
 		return fmt.Errorf("failed to create UI: %w", err)
 	}
+	if a.reference != nil {
+		ui.SetChargeReference(a.reference.Name(), a.reference.At)
+	}
+	if a.recorder != nil {
+		ui.SetStatusProvider(a.recorder.Status)
+	}
+	ui.SetHealthHistory(a.health)
+	ui.SetTimeline(a.timeline)
+	// Peripherals, RAPL domains and processes are local, so they aren't shown
+	// for a remote instance, the simulated battery of the demo or a replay
+	local := !isRemote(a.manager) && a.config.Command != CommandDemo && a.config.Command != CommandReplay
+	if reader, err := battery.NewPeripheralReader(); err == nil && local {
+		ui.SetPeripherals(reader.Read)
+	} else if err != nil {
+		slog.Info("Peripherals page disabled", "error", err)
+	}
+	if rapl, err := battery.NewRAPLReader(battery.PowercapPath); err == nil && local {
+		ui.SetPowerBreakdown(rapl.Read)
+	} else if err != nil {
+		slog.Info("Power breakdown page disabled", "error", err)
+	}
+	if processes, err := battery.NewProcessReader(battery.ProcPath); err == nil && local {
+		ui.SetConsumers(processes.Read)
+	} else if err != nil {
+		slog.Info("Top consumers page disabled", "error", err)
+	}
+	if a.config.Reserve != nil {
+		ui.SetReserve(a.config.Reserve)
+	}
+	if a.config.Goal != nil {
+		ui.SetGoal(a.config.Goal)
+	}
+	if err := a.eventLog.SetStore(a.store, time.Now()); err != nil {
+		slog.Warn("Failed to load today's notes", "error", err)
+	}
+	ui.SetEventLog(a.eventLog)
+	ui.SetDelay(a.config.Delay)
 	a.ui = ui
+	if a.api != nil {
+		a.api.SetHistory(ui.History)
+	}
 
 	// Set up event manager
 	a.events = NewEventManager(a.tviewApp, a.config)
 	a.events.Start()
 	defer a.events.Stop()
+	if a.config.Adaptive {
+		a.sampler = NewAdaptiveSampler(a.config.Delay)
+	}
+	if notifier, ok := a.manager.(battery.Notifier); ok {
+		a.events.Watch(notifier.Changes())
+	}
+	if a.control != nil {
+		a.events.Control(a.control.Commands())
+	}
+
+	// Create the screen up front to query its color capabilities
+	if screen, err := newScreen(a.config.Monochrome); err == nil {
+		a.tviewApp.SetScreen(screen)
+		a.screen = screen
+		trueColor := screen.Colors() >= TrueColorCount
+		a.ui.SetTrueColor(trueColor)
+		slog.Info("Terminal colors", "count", screen.Colors(), "truecolor", trueColor)
+	} else {
+		slog.Warn("Failed to create screen", "error", err)
+	}
 
 	// Set root and enable mouse
 	root := a.ui.GetRoot()
@@ -87,6 +393,15 @@ func (a *Application) Run() error {
 	// Start event processing in separate goroutine
 	go a.processEvents()
 
+	if a.config.Command == CommandDemo {
+		done := make(chan struct{})
+		defer close(done)
+		go a.runTour(done)
+	}
+	if replay, ok := a.manager.(*battery.ReplaySource); ok {
+		go a.announceReplayEnd(replay)
+	}
+
 	// Force initial UI update and draw
 	if err := a.ui.Update(); err != nil {
 		slog.Warn("Initial UI update failed", "error", err)
@@ -128,6 +443,10 @@ func (a *Application) processEvents() {
 			a.tviewApp.Draw()
 
 		case EventTick:
+			if a.paused {
+				continue
+			}
+
 			// Update battery information
 			if err := a.manager.Update(); err != nil {
 				slog.Error("Failed to update batteries",
@@ -137,6 +456,8 @@ func (a *Application) processEvents() {
 				)
 				// Don't exit on update errors, just log them
 			}
+			a.onBatteryUpdate()
+			a.adaptDelay()
 
 			// Update UI
 			if err := a.ui.Update(); err != nil {
@@ -149,9 +470,449 @@ func (a *Application) processEvents() {
 			// Redraw
 			a.tviewApp.Draw()
 
-		case EventResize:
-			slog.Debug("Resize event")
+		case EventSourceChanged:
+			// Refresh the readings without adding chart samples between ticks
+			if err := a.manager.Update(); err != nil {
+				slog.Error("Failed to update batteries", "error", err)
+				continue
+			}
+			if err := a.ui.Refresh(); err != nil {
+				slog.Error("Failed to refresh UI", "error", err)
+			}
+			a.tviewApp.Draw()
+
+		case EventToggleHealth:
+			slog.Debug("Toggle health history event")
+			a.ui.ToggleHealthHistory()
+			a.tviewApp.Draw()
+
+		case EventToggleTimeline:
+			slog.Debug("Toggle power timeline event")
+			a.ui.ToggleTimeline()
+			a.tviewApp.Draw()
+
+		case EventTogglePeripherals:
+			slog.Debug("Toggle peripherals event")
+			a.ui.TogglePeripherals()
+			a.tviewApp.Draw()
+
+		case EventToggleBreakdown:
+			slog.Debug("Toggle power breakdown event")
+			a.ui.TogglePowerBreakdown()
+			a.tviewApp.Draw()
+
+		case EventToggleConsumers:
+			slog.Debug("Toggle top consumers event")
+			a.ui.ToggleConsumers()
+			a.tviewApp.Draw()
+
+		case EventToggleEventLog:
+			slog.Debug("Toggle event log event")
+			a.ui.ToggleEventLog()
+			a.tviewApp.Draw()
+
+		case EventCycleEventSeverity:
+			severity := a.ui.CycleEventSeverity()
+			slog.Debug("Cycle event severity event", "severity", severity)
+			a.tviewApp.Draw()
+
+		case EventToggleChart:
+			slog.Debug("Toggle chart event", "chart", event.Chart)
+			a.ui.ToggleChart(event.Chart)
+			a.tviewApp.Draw()
+
+		case EventToggleLayout:
+			slog.Debug("Toggle chart layout event")
+			a.ui.ToggleChartLayout()
+			a.tviewApp.Draw()
+
+		case EventArrow:
+			switch {
+			case a.ui.Inspecting():
+				a.ui.MoveCursor(event.Step)
+			case event.Step > 0:
+				a.ui.NextTab()
+			default:
+				a.ui.PreviousTab()
+			}
+			a.tviewApp.Draw()
+
+		case EventScreenshot:
+			a.saveScreenshot()
+			a.tviewApp.Draw()
+
+		case EventExportCharts:
+			a.exportCharts()
+			a.tviewApp.Draw()
+
+		case EventClick:
+			a.ui.Click(event.X, event.Y)
+			a.tviewApp.Draw()
+
+		case EventScroll:
+			slog.Debug("Scroll event", "step", event.Step)
+			if a.ui.InfoAt(event.X, event.Y) {
+				a.ui.ScrollInfo(event.Step)
+			} else {
+				a.ui.Zoom(event.Step)
+			}
+			a.tviewApp.Draw()
+
+		case EventScrollInfo:
+			a.ui.ScrollInfo(event.Step)
+			a.tviewApp.Draw()
+
+		case EventPageInfo:
+			a.ui.PageInfo(event.Step)
+			a.tviewApp.Draw()
+
+		case EventToggleInspect:
+			slog.Debug("Toggle inspect event")
+			a.ui.ToggleInspect()
+			a.tviewApp.Draw()
+
+		case EventCycleScale:
+			scale := a.ui.CycleScale()
+			slog.Debug("Cycle scale event", "scale", scale)
+			a.tviewApp.Draw()
+
+		case EventCycleZoom:
+			slog.Debug("Cycle zoom event")
+			a.ui.CycleZoom()
+			a.tviewApp.Draw()
+
+		case EventCycleTheme:
+			if a.config.Monochrome {
+				a.showMessage("Themes are off without colors")
+				a.tviewApp.Draw()
+				continue
+			}
+			a.config.ThemeName = a.ui.CycleTheme()
+			slog.Info("Theme changed", "theme", a.config.ThemeName)
+			if err := saveSettings(a.store, a.config); err != nil {
+				slog.Warn("Failed to save settings", "error", err)
+			}
+			a.tviewApp.Draw()
+
+		case EventPromptReserve:
+			slog.Debug("Reserve prompt event")
+			a.tviewApp.QueueUpdateDraw(func() {
+				input := a.ui.PromptReserve(a.setReserve, func() {
+					a.tviewApp.SetFocus(a.ui.GetRoot())
+				})
+				a.tviewApp.SetFocus(input)
+			})
+
+		case EventPromptGoal:
+			slog.Debug("Goal prompt event")
+			a.tviewApp.QueueUpdateDraw(func() {
+				input := a.ui.PromptGoal(a.setGoal, func() {
+					a.tviewApp.SetFocus(a.ui.GetRoot())
+				})
+				a.tviewApp.SetFocus(input)
+			})
+
+		case EventPromptNote:
+			slog.Debug("Note prompt event")
+			a.tviewApp.QueueUpdateDraw(func() {
+				input := a.ui.PromptNote(a.addNote, func() {
+					a.tviewApp.SetFocus(a.ui.GetRoot())
+				})
+				a.tviewApp.SetFocus(input)
+			})
+
+		case EventSearchEventLog:
+			slog.Debug("Event log search event")
+			a.tviewApp.QueueUpdateDraw(func() {
+				input := a.ui.SearchEventLog(func() {
+					a.tviewApp.SetFocus(a.ui.GetRoot())
+				})
+				if input != nil {
+					a.tviewApp.SetFocus(input)
+				}
+			})
+
+		case EventToggleChargeBasis:
+			a.config.Basis = a.ui.ToggleChargeBasis()
+			slog.Debug("Charge basis changed", "basis", a.config.Basis)
 			a.tviewApp.Draw()
+
+		case EventToggleDetails:
+			slog.Debug("Toggle details event")
+			a.ui.ToggleDetails()
+			a.tviewApp.Draw()
+
+		case EventToggleCompact:
+			slog.Debug("Toggle compact layout event")
+			a.ui.ToggleCompact()
+			a.tviewApp.Draw()
+
+		case EventToggleHistogram:
+			slog.Debug("Toggle histogram event")
+			a.ui.ToggleHistogram()
+			a.tviewApp.Draw()
+
+		case EventControl:
+			delay, paused := a.config.Delay, a.paused
+			if a.handleControl(event.Command) {
+				a.tviewApp.Stop()
+				return
+			}
+			if a.config.Delay != delay {
+				a.setDelay(a.config.Delay)
+				a.tviewApp.Draw()
+			}
+			if a.paused != paused {
+				// The toast stays up while paused
+				message := ""
+				if a.paused {
+					message = "Sampling paused through the control socket"
+				}
+				a.ui.ShowToast(message)
+				a.tviewApp.Draw()
+			}
+
+		case EventReload:
+			a.reloadConfig()
+			a.tviewApp.Draw()
+
+		case EventChangeDelay:
+			delay := a.config.Delay * 2
+			if event.Step < 0 {
+				delay = a.config.Delay / 2
+			}
+			// A longer -delay isn't shortened by ]
+			a.setDelay(min(max(delay, MinDelay), max(MaxKeyDelay, a.config.Delay)))
+			slog.Debug("Update delay changed", "delay", a.config.Delay)
+			a.tviewApp.Draw()
+
+		case EventResize:
+			slog.Debug("Resize event", "width", event.X, "height", event.Y)
+			width := event.X
+			a.tviewApp.QueueUpdateDraw(func() { a.ui.Resize(width) })
+			// Render the charts at the sizes the layout got in that draw
+			a.tviewApp.QueueUpdateDraw(func() {
+				if err := a.ui.Refresh(); err != nil {
+					slog.Debug("Failed to refresh after resize", "error", err)
+				}
+			})
+		}
+	}
+}
+
+// onBatteryUpdate passes the latest battery readings to the statistics
+// tracker, the hook runner, the health history and the power timeline
+func (a *Application) onBatteryUpdate() {
+	batteries, err := a.manager.GetAll()
+	if err != nil {
+		slog.Debug("Skipping statistics and hooks, batteries unavailable", "error", err)
+		return
+	}
+
+	a.samples++
+	if a.warmingUp() {
+		slog.Debug("Warming up, withholding statistics and alerts", "sample", a.samples)
+	} else {
+		a.stats.Add(batteries)
+		a.hooks.Check(batteries, a.manager.PowerSource())
+		a.eventLog.Observe(batteries, a.manager.PowerSource(), time.Now())
+		a.target.Check(batteries)
+		a.alarm.Check(batteries)
+	}
+
+	if a.api != nil || a.exporters != nil {
+		if sample, err := a.currentSample(); err == nil {
+			if a.api != nil {
+				a.api.Publish(sample)
+			}
+			if a.exporters != nil {
+				a.exporters.Export(sample)
+			}
+		}
+	}
+
+	if a.recorder != nil {
+		a.recorder.Add(batteries[0])
+	}
+	if a.reference != nil {
+		a.reference.Observe(batteries[0])
+	}
+
+	// The remote instance keeps its own timeline and health history
+	if isRemote(a.manager) {
+		return
+	}
+	if err := a.timeline.Add(batteries, a.manager.PowerSource(), time.Now()); err != nil {
+		slog.Warn("Failed to save power timeline", "error", err)
+	}
+	if a.health != nil {
+		if _, err := a.health.Record(batteries[0], time.Now()); err != nil {
+			slog.Warn("Failed to save health history", "error", err)
 		}
 	}
 }
+
+// adaptDelay sets the next tick delay from the latest readings in the
+// adaptive sampling mode
+func (a *Application) adaptDelay() {
+	if a.sampler == nil {
+		return
+	}
+	batteries, err := a.manager.GetAll()
+	if err != nil {
+		return
+	}
+	a.events.SetDelay(a.sampler.Next(batteries))
+}
+
+// setDelay changes the update delay of the running UI
+func (a *Application) setDelay(delay time.Duration) {
+	a.config.Delay = delay
+	if a.sampler != nil {
+		a.sampler = NewAdaptiveSampler(delay)
+	}
+	a.events.SetDelay(delay)
+	a.ui.SetDelay(delay)
+}
+
+// reloadConfig re-reads the configuration file and applies the new settings
+// to the running UI; on errors the current settings are kept
+func (a *Application) reloadConfig() {
+	next, err := a.config.Reload()
+	if err != nil {
+		slog.Warn("Failed to reload the configuration", "error", err)
+		a.showMessage(fmt.Sprintf("[red]Reload failed: %v[-]", err))
+		return
+	}
+
+	delay := a.config.Delay
+	*a.config = *next
+	if a.config.Delay != delay {
+		a.setDelay(a.config.Delay)
+	}
+	a.eventLog.SetThresholds(eventThresholds(a.config)...)
+	a.ui.Reload()
+	if err := saveSettings(a.store, a.config); err != nil {
+		slog.Warn("Failed to save settings", "error", err)
+	}
+
+	slog.Info("Configuration reloaded", "path", a.config.configPath)
+	a.showMessage("Reloaded " + battery.Coalesce(a.config.ConfigFile, "the defaults (no config file)"))
+}
+
+// ringBell rings the terminal bell, through the screen while the UI runs
+func (a *Application) ringBell() {
+	if a.screen != nil && a.screen.Beep() == nil {
+		return
+	}
+	fmt.Fprint(os.Stderr, "\a")
+}
+
+// backfillTimeline fills today's power timeline from the history of sources
+// that keep one (UPower), covering the time before battop started
+func (a *Application) backfillTimeline() {
+	history, ok := a.manager.(battery.StateHistory)
+	if !ok {
+		return
+	}
+
+	// Start before midnight to catch the state the day began with
+	now := time.Now()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	records, err := history.StateHistory(0, now.Sub(midnight)+stats.MaxHistoryGap)
+	if err != nil {
+		slog.Warn("Failed to read battery state history", "error", err)
+		return
+	}
+	if err := a.timeline.Backfill(records, now); err != nil {
+		slog.Warn("Failed to save power timeline", "error", err)
+		return
+	}
+	slog.Info("Power timeline backfilled", "records", len(records))
+}
+
+// warmingUp reports whether the readings after launch are still withheld from
+// statistics and alerts
+func (a *Application) warmingUp() bool {
+	return a.samples <= a.config.Warmup
+}
+
+// setupDischargeProfiles prepares discharge recording and the reference curve
+func (a *Application) setupDischargeProfiles() error {
+	if a.config.RecordDischarge != "" {
+		a.recorder = stats.NewDischargeRecorder(a.store, a.config.RecordDischarge, a.config.CriticalThreshold)
+		slog.Info("Discharge recording enabled", "name", a.config.RecordDischarge, "dir", a.store.Dir())
+	}
+
+	if a.config.CompareDischarge != "" {
+		profile, err := stats.LoadDischargeProfile(a.store, a.config.CompareDischarge)
+		if err != nil {
+			return fmt.Errorf("failed to load discharge profile: %w", err)
+		}
+		a.reference = stats.NewReferenceCurve(profile)
+		a.stats.SetBaseline(profile.ActiveDrain())
+		slog.Info("Discharge comparison enabled", "name", profile.Name, "duration", profile.Duration())
+	}
+
+	return nil
+}
+
+// closeDischargeProfiles saves an in-progress discharge recording
+func (a *Application) closeDischargeProfiles() {
+	if a.recorder != nil {
+		a.recorder.Close()
+	}
+}
+
+// setReserve parses and applies an unplugged period entered in the UI; empty text clears it
+func (a *Application) setReserve(text string) error {
+	if strings.TrimSpace(text) == "" {
+		a.config.Reserve = nil
+		a.ui.SetReserve(nil)
+		slog.Info("Reserve cleared")
+		return nil
+	}
+
+	reserve, err := stats.ParseReserve(text, time.Now())
+	if err != nil {
+		return err
+	}
+	a.config.Reserve = &reserve
+	a.ui.SetReserve(&reserve)
+	slog.Info("Reserve set", "reserve", reserve.String())
+	return nil
+}
+
+// addNote tags the current moment with a note entered in the UI; empty text adds nothing
+func (a *Application) addNote(text string) error {
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+
+	note, err := a.eventLog.Annotate(text, time.Now())
+	if err != nil {
+		// The note is shown either way, only saving it failed
+		slog.Warn("Failed to save note", "error", err)
+	}
+	slog.Info("Note added", "note", note.Message)
+	return nil
+}
+
+// setGoal parses and applies a battery life goal entered in the UI; empty text clears it
+func (a *Application) setGoal(text string) error {
+	if strings.TrimSpace(text) == "" {
+		a.config.Goal = nil
+		a.ui.SetGoal(nil)
+		slog.Info("Goal cleared")
+		return nil
+	}
+
+	goal, err := stats.ParseGoal(text, time.Now())
+	if err != nil {
+		return err
+	}
+	a.config.Goal = &goal
+	a.ui.SetGoal(&goal)
+	slog.Info("Goal set", "goal", goal.String())
+	return nil
+}
@@ -0,0 +1,81 @@
+package app
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/xsikor/go-battop/internal/battery"
+)
+
+func TestNewBatterySource(t *testing.T) {
+	// The source opened by ParseFlags is used as is
+	opened := &apiSource{}
+	config := DefaultConfig()
+	config.Source = "upower"
+	config.source = opened
+	if source, err := newBatterySource(config, nil); err != nil || source != opened {
+		t.Errorf("source %v, %v, want the one opened by ParseFlags", source, err)
+	}
+
+	tests := []struct {
+		name  string
+		setup func(c *Config)
+	}{
+		{"missing recording", func(c *Config) {
+			c.Command, c.ReplayFile = CommandReplay, filepath.Join(t.TempDir(), "missing.csv")
+		}},
+		{"unsupported source", func(c *Config) { c.Source = "ftp://ups" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			tt.setup(config)
+			source, err := newBatterySource(config, nil)
+			if err == nil || source != nil {
+				t.Errorf("source %v, %v, want an error and no source", source, err)
+			}
+		})
+	}
+
+	config = DefaultConfig()
+	config.Connect = "laptop.lan:8080"
+	if source, err := newBatterySource(config, nil); err != nil {
+		t.Fatal(err)
+	} else if _, ok := source.(*battery.RemoteSource); !ok {
+		t.Errorf("source %T for -connect", source)
+	}
+}
+
+func TestRemoteSubsystems(t *testing.T) {
+	tests := []struct {
+		name   string
+		setup  func(c *Config)
+		remote bool
+	}{
+		{"local", func(c *Config) {}, false},
+		{"connect", func(c *Config) { c.Connect = "laptop.lan:8080" }, true},
+		{"http source", func(c *Config) {
+			c.Source, c.source = "http://laptop.lan:8080", battery.NewRemoteSource("http://laptop.lan:8080")
+		}, true},
+		{"nut source", func(c *Config) {
+			c.Source = "nut://ups.lan"
+			c.source, _ = battery.NewNUTSource(c.Source)
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			tt.setup(config)
+			enabled := make(map[string]bool)
+			for _, s := range Subsystems(config) {
+				enabled[s.Name] = s.Enabled
+			}
+			if enabled["Remote source"] != tt.remote || enabled["Peripherals"] == tt.remote || enabled["Top consumers"] == tt.remote {
+				t.Errorf("subsystems %v, remote %t", enabled, tt.remote)
+			}
+			if tt.remote != (config.source != nil && isRemote(config.source)) && config.Connect == "" {
+				t.Errorf("isRemote(%T) = %t", config.source, !tt.remote)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package app
+
+import (
+	"encoding/json"
+	"log/slog"
+	"sync"
+)
+
+// Broadcaster fans out JSON messages to subscribers, dropping messages for
+// subscribers that fall behind rather than blocking the update loop
+type Broadcaster struct {
+	mu          sync.Mutex
+	subscribers map[chan []byte]struct{}
+}
+
+// NewBroadcaster creates a broadcaster without subscribers
+func NewBroadcaster() *Broadcaster {
+	return &Broadcaster{
+		subscribers: make(map[chan []byte]struct{}),
+	}
+}
+
+// Subscribe returns a channel receiving every published message and a
+// function that unsubscribes and closes the channel
+func (b *Broadcaster) Subscribe() (<-chan []byte, func()) {
+	ch := make(chan []byte, BroadcastBufferSize)
+
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Publish encodes the value once and sends it to every subscriber
+func (b *Broadcaster) Publish(value any) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.subscribers) == 0 {
+		return
+	}
+
+	message, err := json.Marshal(value)
+	if err != nil {
+		slog.Error("Failed to encode broadcast message", "error", err)
+		return
+	}
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- message:
+		default:
+			slog.Warn("Subscriber too slow, dropping message")
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package app
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/ui"
+)
+
+// exportCharts writes the series of every chart of every battery to its own
+// timestamped CSV file in the export directory
+func (a *Application) exportCharts() {
+	base := filepath.Join(a.config.ExportDir, "battop-"+time.Now().Format("20060102-150405"))
+	var saved []string
+	for _, history := range a.ui.History() {
+		path := fmt.Sprintf("%s-battery%d-%s.csv", base, history.Battery, history.Chart)
+		if err := writeChartCSVFile(path, history); err != nil {
+			slog.Error("Failed to export chart", "chart", history.Chart, "battery", history.Battery, "error", err)
+			a.showMessage(fmt.Sprintf("[red]Export failed: %v[-]", err))
+			return
+		}
+		saved = append(saved, path)
+	}
+
+	slog.Info("Exported charts", "files", saved)
+	a.showMessage(fmt.Sprintf("Exported %d series to %s", len(saved), a.config.ExportDir))
+}
+
+// writeChartCSVFile writes the series of a chart to a CSV file
+func writeChartCSVFile(path string, history ui.ChartHistory) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := writeChartCSV(file, history); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// writeChartCSV writes the points of a chart as CSV rows of an RFC 3339
+// timestamp and the value in the chart's unit. Gaps such as a suspend are
+// written as rows without a value.
+func writeChartCSV(w io.Writer, history ui.ChartHistory) error {
+	out := csv.NewWriter(w)
+	if err := out.Write([]string{"time", fmt.Sprintf("%s (%s)", history.Chart, history.Unit)}); err != nil {
+		return err
+	}
+	for _, point := range history.Points {
+		value := ""
+		if !math.IsNaN(point.Value) {
+			value = strconv.FormatFloat(point.Value, 'f', -1, 64)
+		}
+		if err := out.Write([]string{point.Time.Format(time.RFC3339Nano), value}); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
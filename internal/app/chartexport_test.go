@@ -0,0 +1,37 @@
+package app
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/ui"
+	"github.com/xsikor/go-battop/pkg/plot"
+)
+
+func TestWriteChartCSV(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	history := ui.ChartHistory{
+		Battery: 0,
+		Chart:   "power",
+		Unit:    "W",
+		Points: []plot.Point{
+			{Time: start, Value: 12.5},
+			{Time: start.Add(30 * time.Minute), Value: math.NaN(), Gap: time.Hour},
+			{Time: start.Add(time.Hour), Value: 8},
+		},
+	}
+
+	var out strings.Builder
+	if err := writeChartCSV(&out, history); err != nil {
+		t.Fatal(err)
+	}
+	want := "time,power (W)\n" +
+		"2024-03-01T12:00:00Z,12.5\n" +
+		"2024-03-01T12:30:00Z,\n" +
+		"2024-03-01T13:00:00Z,8\n"
+	if out.String() != want {
+		t.Errorf("writeChartCSV() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
@@ -3,19 +3,50 @@ package app
 import (
 	"flag"
 	"fmt"
+	"maps"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/xsikor/go-battop/internal/battery"
 	"github.com/xsikor/go-battop/internal/errors"
+	"github.com/xsikor/go-battop/internal/export"
+	"github.com/xsikor/go-battop/internal/format"
+	"github.com/xsikor/go-battop/internal/session"
+	"github.com/xsikor/go-battop/internal/stats"
+	"github.com/xsikor/go-battop/internal/store"
+	"github.com/xsikor/go-battop/internal/ui"
 )
 
-// Units defines the measurement unit system for displaying battery values
-type Units string
+// Output modes selected with -output
+const (
+	// OutputTUI renders the interactive terminal UI
+	OutputTUI = "tui"
+	// OutputJSON writes one JSON object per sampling tick to stdout
+	OutputJSON = "json"
+)
 
+// Subcommands accepted as the first positional argument
 const (
-	// UnitsHuman displays values in human-readable units (W, Wh)
-	UnitsHuman Units = "human"
-	// UnitsRaw displays values in raw units (mW, mWh)
-	UnitsRaw Units = "raw"
+	// CommandInfo prints a diagnostic report and exits
+	CommandInfo = "info"
+	// CommandVersion prints the version and exits
+	CommandVersion = "version"
+	// CommandShare prints a snapshot and uploads it when sharing is enabled
+	CommandShare = "share"
+	// CommandStatusline prints a single formatted line for status bars
+	CommandStatusline = "statusline"
+	// CommandDaemon samples without a UI, recording history and running alerts
+	CommandDaemon = "daemon"
+	// CommandDemo runs the UI against the battery simulator with a guided tour
+	CommandDemo = "demo"
+	// CommandReplay runs the UI against a session recorded with -record
+	CommandReplay = "replay"
+	// CommandDiff records a battery snapshot or compares against one
+	CommandDiff = "diff"
+	// CommandReport writes a battery report for support tickets
+	CommandReport = "report"
 )
 
 // Config defines the application configuration parameters
@@ -23,23 +54,251 @@ type Config struct {
 	// Delay between updates
 	Delay time.Duration
 
+	// Adaptive lengthens the delay while the batteries are idle or full
+	Adaptive bool
+
 	// Units to use for display
-	Units Units
+	Units format.Units
+
+	// TempUnit is the unit temperatures are displayed in
+	TempUnit format.TemperatureUnit
+
+	// Locale selects the decimal separator (e.g., "de_DE"); empty uses the
+	// LC_ALL, LC_NUMERIC or LANG environment variable
+	Locale string
 
 	// Verbose enables debug logging
 	Verbose bool
 
 	// Version flag
 	Version bool
+
+	// Estimate selects which time estimates are displayed
+	Estimate ui.EstimateMode
+
+	// ThemeName selects the color theme, including the color-blind palettes
+	ThemeName string
+
+	// ConfigFile is the path of the loaded configuration file (empty if none)
+	ConfigFile string
+
+	// Layout selects how charts are arranged
+	Layout ui.ChartLayout
+
+	// ShareEndpoint is the paste service URL snapshots are uploaded to
+	// (empty disables uploading)
+	ShareEndpoint string
+
+	// StatuslineFormat is the template printed by the statusline command
+	StatuslineFormat string
+
+	// DiffFiles are the snapshot files of the diff command: the snapshot to
+	// record or compare against, and optionally a later one to compare
+	DiffFiles []string
+
+	// ReportFile is the file the report command writes, "" for stdout
+	ReportFile string
+
+	// ReplayFile is the recorded session played back by the replay command
+	ReplayFile string
+
+	// ReplaySpeed is how many times faster than recorded the session is
+	// played back, 0 for one sample per update
+	ReplaySpeed float64
+
+	// Compact starts with the compact layout (gauges and a single chart)
+	Compact bool
+
+	// Icon shows a large battery graphic above the gauges
+	Icon bool
+
+	// Panel configures the width of the left info panel
+	Panel ui.PanelSize
+
+	// ChartSettings holds the chart settings from the configuration file,
+	// keyed by setting name (e.g., "charts.power.color")
+	ChartSettings map[string]string
+
+	// Smoothing is the number of samples the charge rate estimator averages over
+	Smoothing int
+
+	// IdleSource selects how session idle state is detected
+	IdleSource session.IdleSource
+
+	// IdleFile is the marker file whose presence means the session is idle
+	IdleFile string
+
+	// DataDir is the directory for persistent data
+	DataDir string
+
+	// RecordDischarge is the profile name to record the next discharge into
+	RecordDischarge string
+
+	// CompareDischarge is the profile name to overlay on the charge chart
+	CompareDischarge string
+
+	// Command is the optional subcommand (e.g. "info")
+	Command string
+
+	// Reserve is the upcoming unplugged period to plan for (nil when not set)
+	Reserve *stats.Reserve
+
+	// Goal is the time the batteries should last until (nil when not set)
+	Goal *stats.Goal
+
+	// Connect is the API address of a remote battop instance to monitor
+	// instead of the local batteries (empty uses the local batteries)
+	Connect string
+
+	// Source selects where the batteries are read from: "local"
+	// (distatus/battery), "sysfs", "upower", "termux", "sim", a
+	// nut://host[:port][/ups] or apcupsd://host[:port] URL to monitor UPS
+	// batteries or the http(s):// URL of another battop instance (empty
+	// picks the local batteries, or Android within Termux)
+	Source string
+
+	// APIListen is the address of the HTTP API (empty disables it)
+	APIListen string
+
+	// APIOrigins are comma-separated origins (e.g., https://dash.example.com)
+	// whose pages may open the WebSocket besides pages served from loopback
+	APIOrigins string
+
+	// InfluxURL is the InfluxDB write endpoint every sample is written to
+	// (empty disables it)
+	InfluxURL string
+
+	// InfluxToken is the InfluxDB 2.x API token (empty for 1.x without authentication)
+	InfluxToken string
+
+	// InfluxTags are comma-separated key=value tags added to every point
+	// written to InfluxDB
+	InfluxTags string
+
+	// MQTTBroker is the mqtt:// or mqtts:// URL of the broker every sample is
+	// published to (empty disables it)
+	MQTTBroker string
+
+	// MQTTPrefix is the first level of the published topics
+	MQTTPrefix string
+
+	// HomeAssistant is the Home Assistant discovery prefix the batteries are
+	// announced under (empty disables the discovery)
+	HomeAssistant string
+
+	// Record is the CSV file every sample is appended to (empty disables it)
+	Record string
+
+	// ControlSocket is the path of the control socket (empty disables it)
+	ControlSocket string
+
+	// Output selects the terminal UI or the JSON stream
+	Output string
+
+	// Ticker enables the single-line ticker mode
+	Ticker bool
+
+	// Plain enables the screen reader friendly mode printing labeled lines
+	Plain bool
+
+	// TickerInterval is the delay between ticker metric rotations
+	TickerInterval time.Duration
+
+	// Hooks maps battery events to shell commands
+	Hooks map[HookEvent]string
+
+	// HookTimeout is the maximum run time for a hook command
+	HookTimeout time.Duration
+
+	// Basis selects what the charge gauge percentage is relative to
+	Basis ui.ChargeBasis
+
+	// Warmup is the number of readings after launch during which
+	// estimates and alerts are withheld
+	Warmup int
+
+	// ChargeTarget is the charge percentage at which to unplug for longevity (0 disables it)
+	ChargeTarget float64
+
+	// LowThreshold is the charge percentage that triggers the on-low hook
+	LowThreshold float64
+
+	// CriticalThreshold is the charge percentage that triggers the on-critical hook
+	CriticalThreshold float64
+
+	// Alarm selects how to sound the alarm when the charge drops to the
+	// critical threshold
+	Alarm AlarmMode
+
+	// Quiet are the daily windows without desktop notifications and alarms
+	Quiet QuietHours
+
+	// ReduceMotion disables toasts and limits how often the visuals change
+	ReduceMotion bool
+
+	// Tariff prices the charged energy and its carbon emissions
+	Tariff stats.Tariff
+
+	// Monochrome draws the UI without colors (-no-color or NO_COLOR)
+	Monochrome bool
+
+	// ScreenshotDir is the directory screenshots are saved to
+	ScreenshotDir string
+
+	// ScreenshotPNG also saves the charts as a PNG image with each screenshot
+	ScreenshotPNG bool
+
+	// ScreenshotSVG also saves the charts as an SVG image with each screenshot
+	ScreenshotSVG bool
+
+	// ExportDir is the directory chart series are exported to as CSV
+	ExportDir string
+
+	// configPath is the configuration file Reload reads (empty if none);
+	// explicitConfig reports whether it was given with -config
+	configPath     string
+	explicitConfig bool
+
+	// flags applies the settings given on the command line over the file
+	flags func(c *Config) error
+
+	// source is the replay or -source opened by ParseFlags, which validates
+	// it, and handed to the application so it is only opened once
+	source battery.Source
 }
 
 // DefaultConfig returns default configuration
 func DefaultConfig() *Config {
 	return &Config{
-		Delay:   1 * time.Second,
-		Units:   UnitsHuman,
-		Verbose: false,
-		Version: false,
+		Delay:             1 * time.Second,
+		Units:             format.UnitsHuman,
+		TempUnit:          format.Celsius,
+		Estimate:          ui.EstimateSmoothed,
+		ThemeName:         ui.DefaultThemeName,
+		Layout:            ui.ChartLayoutStacked,
+		Basis:             ui.ChargeBasisFull,
+		Warmup:            DefaultWarmupSamples,
+		Panel:             ui.DefaultPanelSize,
+		StatuslineFormat:  ui.DefaultStatuslineFormat,
+		ChartSettings:     make(map[string]string),
+		Smoothing:         battery.DefaultSmoothingSamples,
+		IdleSource:        session.IdleSourceAuto,
+		IdleFile:          session.DefaultMarkerFile(),
+		DataDir:           store.DefaultDir(),
+		Verbose:           false,
+		Version:           false,
+		Output:            OutputTUI,
+		Ticker:            false,
+		TickerInterval:    3 * time.Second,
+		Plain:             false,
+		Hooks:             make(map[HookEvent]string),
+		HookTimeout:       10 * time.Second,
+		LowThreshold:      20,
+		CriticalThreshold: 5,
+		Alarm:             AlarmOff,
+		ScreenshotDir:     ".",
+		ExportDir:         ".",
+		MQTTPrefix:        "battop",
 	}
 }
 
@@ -47,58 +306,577 @@ func DefaultConfig() *Config {
 func ParseFlags() (*Config, error) {
 	config := DefaultConfig()
 
+	var configPath string
+	var shareEndpoint string
+	var reducedMotion bool
+	var alarmStr string
+	var quietStr string
+	var speedStr string
+	var tariff stats.Tariff
 	var delayStr string
 	var unitsStr string
+	var themeStr string
+	var localeStr string
+	var tempUnitStr string
+	var estimateStr string
+	var basisStr string
+	var idleSourceStr string
+	var tickerIntervalStr string
+	var hookTimeoutStr string
+	var reserveStr string
+	var goalStr string
 
+	hookCommands := make(map[HookEvent]*string, len(HookEvents))
+	for _, event := range HookEvents {
+		hookCommands[event] = flag.String(string(event), "", hookUsage(event))
+	}
+
+	flag.StringVar(&configPath, "config", DefaultConfigFile(), "Configuration file with key=value settings")
 	flag.StringVar(&delayStr, "delay", "1s", "Delay between updates (e.g., 1s, 500ms)")
-	flag.StringVar(&unitsStr, "units", "human", "Units to use (human: W/Wh, raw: mW/mWh)")
+	flag.BoolVar(&config.Adaptive, "adaptive", false, "Lengthen the delay up to "+AdaptiveMaxDelay.String()+" while the batteries are idle or full and steady")
+	flag.StringVar(&unitsStr, "units", "human", "Units to use (human: W/Wh, raw: mW/mWh, si: scaled from µW to kW, mah: capacities in mAh/Ah)")
+	flag.StringVar(&tempUnitStr, "temp-units", string(format.Celsius), "Temperature unit (celsius, fahrenheit, kelvin)")
+	flag.StringVar(&localeStr, "locale", "", "Locale of the decimal separator (e.g., de_DE, C; default from LC_ALL, LC_NUMERIC or LANG)")
+	flag.StringVar(&basisStr, "charge-basis", string(config.Basis), "Show charge as a percentage of the last full charge or of the design capacity (full, design)")
+	flag.StringVar(&estimateStr, "estimate", "smoothed", "Time estimates to show (smoothed, instant, both)")
+	flag.StringVar(&themeStr, "theme", config.ThemeName, "Color theme ("+strings.Join(ui.ThemeNames(), ", ")+")")
+	flag.IntVar(&config.Smoothing, "smoothing", config.Smoothing, "Number of samples the smoothed charge rate averages over")
+	flag.StringVar(&idleSourceStr, "idle-source", "auto", "Session idle detection (auto, logind, file, none)")
+	flag.StringVar(&config.IdleFile, "idle-file", config.IdleFile, "Marker file that signals an idle session (e.g., created by swayidle)")
+	flag.StringVar(&config.DataDir, "data-dir", config.DataDir, "Directory for recorded data")
+	flag.StringVar(&config.RecordDischarge, "record-discharge", "", "Record the next full discharge as a named profile")
+	flag.StringVar(&config.CompareDischarge, "compare-discharge", "", "Overlay a recorded discharge profile on the charge chart")
 	flag.BoolVar(&config.Verbose, "verbose", false, "Enable verbose logging")
 	flag.BoolVar(&config.Version, "version", false, "Show version and exit")
+	flag.StringVar(&shareEndpoint, "share-endpoint", "", "Paste service URL the share command uploads snapshots to (opt-in)")
+	flag.StringVar(&goalStr, "goal", "", "Time the batteries should last until, rating the power against the budget (e.g., 18:00, 3h)")
+	flag.StringVar(&reserveStr, "reserve", "", "Upcoming unplugged period to plan for (e.g., 15:30, \"flight 4h\")")
+	flag.BoolVar(&config.Compact, "compact", false, "Show only the gauges and a single chart, for small panes")
+	flag.BoolVar(&config.Icon, "battery-icon", false, "Show a large battery graphic above the gauges")
+	flag.StringVar(&config.ScreenshotDir, "screenshot-dir", config.ScreenshotDir, "Directory the s key saves screenshots to")
+	flag.BoolVar(&config.ScreenshotPNG, "screenshot-png", false, "Also save the charts as a PNG image with each screenshot")
+	flag.BoolVar(&config.ScreenshotSVG, "screenshot-svg", false, "Also save the charts as an SVG image with each screenshot")
+	flag.StringVar(&config.ExportDir, "export-dir", config.ExportDir, "Directory the x key exports the chart series to as CSV files")
+	flag.BoolVar(&config.Monochrome, "no-color", false, "Draw without colors (also set by a non-empty NO_COLOR)")
+	flag.BoolVar(&reducedMotion, "reduced-motion", false, "Disable toasts and update the visuals at most every "+ui.ReducedMotionInterval.String())
+	flag.StringVar(&config.Connect, "connect", "", "Monitor another battop instance through its -api-listen address (host:port)")
+	flag.StringVar(&config.Source, "source", "", "Battery source (local, sysfs, upower, termux, sim[:quirk,...], nut://host[:port][/ups], apcupsd://host[:port], or http://host:port of another battop)")
+	flag.StringVar(&config.APIListen, "api-listen", "", "Serve battery data as JSON over HTTP on this address (e.g., 127.0.0.1:8080)")
+	flag.StringVar(&config.APIOrigins, "api-origins", "", "Comma-separated origins whose web pages may open the WebSocket besides loopback pages (e.g., https://dash.example.com)")
+	flag.StringVar(&config.InfluxURL, "influx-url", "", "Write every sample to this InfluxDB write endpoint (e.g., http://localhost:8086/write?db=battop or .../api/v2/write?org=home&bucket=battop)")
+	flag.StringVar(&config.InfluxToken, "influx-token", "", "InfluxDB 2.x API token")
+	flag.StringVar(&config.InfluxTags, "influx-tags", "", "Extra tags of the InfluxDB points besides host and battery (e.g., site=office,owner=ann)")
+	flag.StringVar(&config.MQTTBroker, "mqtt-broker", "", "Publish every sample to this MQTT broker (mqtt://[user:pass@]host[:port] or mqtts://...)")
+	flag.StringVar(&config.MQTTPrefix, "mqtt-prefix", config.MQTTPrefix, "First level of the published MQTT topics")
+	flag.StringVar(&config.HomeAssistant, "homeassistant", "", "Announce the batteries to Home Assistant through MQTT discovery under this prefix (usually homeassistant)")
+	flag.StringVar(&config.Record, "record", "", "Append every sample to this CSV file")
+	flag.StringVar(&speedStr, "speed", "1x", "Playback speed of the replay command (e.g., 1x, 10x or max)")
+	flag.StringVar(&config.ControlSocket, "control-socket", "", "Accept commands (status, json, set-delay, pause, resume, quit) on this Unix socket (e.g., "+DefaultControlSocket()+")")
+	flag.StringVar(&config.Output, "output", config.Output, "Output mode (tui, json: one JSON object per update on stdout)")
+	flag.BoolVar(&config.Ticker, "ticker", false, "Show a single-line ticker instead of the full UI")
+	flag.BoolVar(&config.Plain, "plain", false, "Print plain labeled lines with trend summaries instead of the full UI, for screen readers")
+	flag.StringVar(&tickerIntervalStr, "ticker-interval", "3s", "Delay between ticker metric rotations (e.g., 3s, 5s)")
+	flag.StringVar(&hookTimeoutStr, "hook-timeout", "10s", "Maximum run time for hook commands")
+	flag.IntVar(&config.Warmup, "warmup", config.Warmup, "Readings after launch during which estimates and alerts are withheld")
+	flag.Float64Var(&config.ChargeTarget, "charge-target", 0, "Notify once per charge when charging passes this percentage (e.g., 80; 0 disables)")
+	flag.Float64Var(&config.LowThreshold, "low-threshold", config.LowThreshold, "Charge percentage that triggers the on-low hook")
+	flag.Float64Var(&config.CriticalThreshold, "critical-threshold", config.CriticalThreshold, "Charge percentage that triggers the on-critical hook")
+	flag.StringVar(&alarmStr, "alarm", string(AlarmOff), "Alarm when discharging to the critical threshold (off, bell, sound)")
+	flag.Float64Var(&tariff.Price, "energy-price", 0, "Electricity price per kWh to estimate the cost of charging (0 disables)")
+	flag.StringVar(&tariff.Currency, "energy-currency", "", "Currency shown with the energy cost (e.g., EUR)")
+	flag.Float64Var(&tariff.Carbon, "carbon-intensity", 0, "Carbon intensity of the electricity in g CO2/kWh to estimate charging emissions (0 disables)")
+	flag.StringVar(&quietStr, "quiet-hours", "", "Daily windows without desktop notifications and alarms (e.g., 22:00-08:00,12:30-13:00)")
 
+	flag.Usage = usage
 	flag.Parse()
 
-	// Parse delay
-	if delayStr != "" {
-		delay, err := time.ParseDuration(delayStr)
+	// Parse subcommand
+	switch command := flag.Arg(0); command {
+	case "":
+	case CommandInfo, CommandShare, CommandDemo, CommandDaemon:
+		config.Command = command
+	case CommandStatusline:
+		config.Command = command
+		if template := flag.Arg(1); template != "" {
+			config.StatuslineFormat = template
+		}
+	case CommandReplay:
+		config.Command = command
+		config.ReplayFile = flag.Arg(1)
+		if config.ReplayFile == "" {
+			return nil, errors.NewConfigError("command", command, fmt.Errorf("missing the recorded session, e.g. battop replay session.csv"))
+		}
+	case CommandDiff:
+		config.Command = command
+		config.DiffFiles = flag.Args()[1:]
+		if len(config.DiffFiles) == 0 || len(config.DiffFiles) > 2 {
+			return nil, errors.NewConfigError("command", command, fmt.Errorf("expected one or two snapshot files, e.g. battop diff battery.json"))
+		}
+	case CommandReport:
+		config.Command = command
+		config.ReportFile = flag.Arg(1)
+	case CommandVersion:
+		config.Version = true
+	default:
+		return nil, errors.NewConfigError("command", command, fmt.Errorf("unknown command: must be 'daemon', 'demo', 'diff', 'info', 'replay', 'report', 'share', 'statusline' or 'version'"))
+	}
+
+	// Flags given on the command line take precedence over persisted settings
+	explicit := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+
+	// https://no-color.org: a non-empty NO_COLOR disables colors unless overridden
+	if !explicit["no-color"] && os.Getenv("NO_COLOR") != "" {
+		config.Monochrome = true
+	}
+
+	// Load configuration file. The demo looks the same everywhere, so it skips
+	// the file and persisted settings; command line flags still apply.
+	if config.Command != CommandDemo {
+		config.configPath, config.explicitConfig = configPath, explicit["config"]
+		if err := config.loadConfigFile(configPath, explicit["config"]); err != nil {
+			return nil, err
+		}
+		loadSettings(store.New(config.DataDir), config, explicit)
+	}
+
+	// Flags given on the command line also take precedence when the file is reloaded
+	config.flags = func(c *Config) error {
+		if explicit["share-endpoint"] {
+			c.ShareEndpoint = shareEndpoint
+		}
+		if explicit["reduced-motion"] {
+			c.ReduceMotion = reducedMotion
+		}
+		if explicit["theme"] {
+			c.ThemeName = themeStr
+		}
+		if explicit["alarm"] {
+			alarm, err := ParseAlarmMode(alarmStr)
+			if err != nil {
+				return errors.NewConfigError("alarm", alarmStr, err)
+			}
+			c.Alarm = alarm
+		}
+		if explicit["quiet-hours"] {
+			quiet, err := ParseQuietHours(quietStr)
+			if err != nil {
+				return errors.NewConfigError("quiet-hours", quietStr, err)
+			}
+			c.Quiet = quiet
+		}
+		if explicit["energy-price"] {
+			if err := validateRate(tariff.Price); err != nil {
+				return errors.NewConfigError("energy-price", tariff.Price, err)
+			}
+			c.Tariff.Price = tariff.Price
+		}
+		if explicit["energy-currency"] {
+			c.Tariff.Currency = tariff.Currency
+		}
+		if explicit["carbon-intensity"] {
+			if err := validateRate(tariff.Carbon); err != nil {
+				return errors.NewConfigError("carbon-intensity", tariff.Carbon, err)
+			}
+			c.Tariff.Carbon = tariff.Carbon
+		}
+		if explicit["delay"] {
+			delay, err := parseDelay(delayStr)
+			if err != nil {
+				return errors.NewConfigError("delay", delayStr, err)
+			}
+			c.Delay = delay
+		}
+		if explicit["temp-units"] {
+			unit, err := format.ParseTemperatureUnit(tempUnitStr)
+			if err != nil {
+				return errors.NewConfigError("temp-units", tempUnitStr, err)
+			}
+			c.TempUnit = unit
+		}
+		if explicit["locale"] {
+			c.Locale = localeStr
+		}
+		if explicit["units"] {
+			units, err := parseUnits(unitsStr)
+			if err != nil {
+				return errors.NewConfigError("units", unitsStr, err)
+			}
+			c.Units = units
+		}
+		return nil
+	}
+	if err := config.flags(config); err != nil {
+		return nil, err
+	}
+
+	// Parse ticker interval
+	if tickerIntervalStr != "" {
+		interval, err := time.ParseDuration(tickerIntervalStr)
 		if err != nil {
-			return nil, errors.NewConfigError("delay", delayStr, err)
+			return nil, errors.NewConfigError("ticker-interval", tickerIntervalStr, err)
 		}
-		if delay < 100*time.Millisecond {
-			return nil, errors.NewConfigError("delay", delay, fmt.Errorf("delay must be at least 100ms"))
+		if interval < 100*time.Millisecond {
+			return nil, errors.NewConfigError("ticker-interval", interval, fmt.Errorf("ticker interval must be at least 100ms"))
 		}
-		config.Delay = delay
+		config.TickerInterval = interval
 	}
 
-	// Parse units
-	switch unitsStr {
-	case "human", "h":
-		config.Units = UnitsHuman
-	case "raw", "r":
-		config.Units = UnitsRaw
+	// Parse charge basis
+	switch basis := ui.ChargeBasis(basisStr); basis {
+	case ui.ChargeBasisFull, ui.ChargeBasisDesign:
+		config.Basis = basis
 	default:
-		return nil, errors.NewConfigError("units", unitsStr, fmt.Errorf("invalid units: must be 'human' or 'raw'"))
+		return nil, errors.NewConfigError("charge-basis", basisStr, fmt.Errorf("invalid charge basis: must be 'full' or 'design'"))
+	}
+
+	// Parse time estimate mode
+	switch mode := ui.EstimateMode(estimateStr); mode {
+	case ui.EstimateSmoothed, ui.EstimateInstant, ui.EstimateBoth:
+		config.Estimate = mode
+	default:
+		return nil, errors.NewConfigError("estimate", estimateStr, fmt.Errorf("invalid estimate: must be 'smoothed', 'instant' or 'both'"))
+	}
+	if config.ShareEndpoint != "" && !strings.HasPrefix(config.ShareEndpoint, "https://") && !strings.HasPrefix(config.ShareEndpoint, "http://") {
+		return nil, errors.NewConfigError("share-endpoint", config.ShareEndpoint, fmt.Errorf("endpoint must be an http(s) URL"))
+	}
+	if _, ok := ui.ThemeByName(config.ThemeName); !ok {
+		return nil, errors.NewConfigError("theme", config.ThemeName, fmt.Errorf("unknown theme: must be one of %s", strings.Join(ui.ThemeNames(), ", ")))
+	}
+	if config.Command == CommandReplay {
+		speed, err := parseReplaySpeed(speedStr)
+		if err != nil {
+			return nil, errors.NewConfigError("speed", speedStr, err)
+		}
+		config.ReplaySpeed = speed
+		// At full speed every update plays the next sample
+		if speed == 0 {
+			config.Delay = MinDelay
+		}
+	}
+	if config.Source != "" && config.Connect != "" {
+		return nil, errors.NewConfigError("source", config.Source, fmt.Errorf("cannot be combined with -connect"))
+	}
+	if config.Source != "" && config.Command == CommandReplay {
+		return nil, errors.NewConfigError("source", config.Source, fmt.Errorf("cannot be combined with replay"))
+	}
+	if config.InfluxURL != "" {
+		if err := export.ValidateInfluxURL(config.InfluxURL); err != nil {
+			return nil, errors.NewConfigError("influx-url", config.InfluxURL, err)
+		}
+	}
+	if config.MQTTBroker != "" {
+		if _, err := export.ParseMQTTBroker(config.MQTTBroker); err != nil {
+			return nil, errors.NewConfigError("mqtt-broker", config.MQTTBroker, err)
+		}
+	}
+	if config.HomeAssistant != "" && config.MQTTBroker == "" {
+		return nil, errors.NewConfigError("homeassistant", config.HomeAssistant, fmt.Errorf("requires -mqtt-broker"))
+	}
+	if _, err := export.ParseInfluxTags(config.InfluxTags); err != nil {
+		return nil, errors.NewConfigError("influx-tags", config.InfluxTags, err)
+	}
+	if reserveStr != "" {
+		reserve, err := stats.ParseReserve(reserveStr, time.Now())
+		if err != nil {
+			return nil, errors.NewConfigError("reserve", reserveStr, err)
+		}
+		config.Reserve = &reserve
+	}
+	if goalStr != "" {
+		goal, err := stats.ParseGoal(goalStr, time.Now())
+		if err != nil {
+			return nil, errors.NewConfigError("goal", goalStr, err)
+		}
+		config.Goal = &goal
+	}
+	if config.Output != OutputTUI && config.Output != OutputJSON {
+		return nil, errors.NewConfigError("output", config.Output, fmt.Errorf("invalid output: must be 'tui' or 'json'"))
+	}
+	if config.Smoothing < 1 {
+		return nil, errors.NewConfigError("smoothing", config.Smoothing, fmt.Errorf("smoothing must be at least 1 sample"))
+	}
+
+	// Parse idle source
+	switch source := session.IdleSource(idleSourceStr); source {
+	case session.IdleSourceAuto, session.IdleSourceLogind, session.IdleSourceFile, session.IdleSourceNone:
+		config.IdleSource = source
+	default:
+		return nil, errors.NewConfigError("idle-source", idleSourceStr, fmt.Errorf("invalid idle source: must be 'auto', 'logind', 'file' or 'none'"))
+	}
+
+	// Parse hooks
+	for event, command := range hookCommands {
+		if *command != "" {
+			config.Hooks[event] = *command
+		}
+	}
+	if hookTimeoutStr != "" {
+		timeout, err := time.ParseDuration(hookTimeoutStr)
+		if err != nil {
+			return nil, errors.NewConfigError("hook-timeout", hookTimeoutStr, err)
+		}
+		if timeout <= 0 {
+			return nil, errors.NewConfigError("hook-timeout", timeout, fmt.Errorf("hook timeout must be positive"))
+		}
+		config.HookTimeout = timeout
+	}
+	if config.Warmup < 0 {
+		return nil, errors.NewConfigError("warmup", config.Warmup, fmt.Errorf("warm-up must not be negative"))
+	}
+	if config.ChargeTarget < 0 || config.ChargeTarget > 100 {
+		return nil, errors.NewConfigError("charge-target", config.ChargeTarget, fmt.Errorf("target must be between 0 and 100"))
+	}
+	if config.LowThreshold < 0 || config.LowThreshold > 100 {
+		return nil, errors.NewConfigError("low-threshold", config.LowThreshold, fmt.Errorf("threshold must be between 0 and 100"))
+	}
+	if config.CriticalThreshold < 0 || config.CriticalThreshold > config.LowThreshold {
+		return nil, errors.NewConfigError("critical-threshold", config.CriticalThreshold, fmt.Errorf("threshold must be between 0 and the low threshold"))
+	}
+
+	// The replay and -source are opened last, once every other setting is
+	// valid, so a failing flag leaves nothing open
+	if config.Command == CommandReplay {
+		source, err := battery.OpenReplay(config.ReplayFile, config.ReplayStep())
+		if err != nil {
+			return nil, errors.NewConfigError("replay", config.ReplayFile, err)
+		}
+		config.source = source
+	}
+	if config.Source != "" {
+		source, err := battery.OpenSource(config.Source)
+		if err != nil {
+			return nil, errors.NewConfigError("source", config.Source, err)
+		}
+		config.source = source
 	}
 
 	return config, nil
 }
 
-// FormatPower formats power value according to units setting
-func (c *Config) FormatPower(mW float64) string {
-	if c.Units == UnitsHuman {
-		return fmt.Sprintf("%.2f W", mW/1000.0)
+// Reload re-reads the configuration file into a copy of the configuration.
+// Settings removed from the file return to their defaults, except the theme,
+// which keeps its runtime choice even when the file names one; command line
+// flags keep precedence. The
+// copy shares no maps with the current configuration, which the hooks keep
+// reading while the file is loaded.
+func (c *Config) Reload() (*Config, error) {
+	next := *c
+	next.Hooks = maps.Clone(c.Hooks)
+	defaults := DefaultConfig()
+	next.Delay, next.Units, next.Locale = defaults.Delay, defaults.Units, defaults.Locale
+	next.TempUnit = defaults.TempUnit
+	next.Layout, next.Panel = defaults.Layout, defaults.Panel
+	next.ShareEndpoint, next.Alarm, next.Quiet = defaults.ShareEndpoint, defaults.Alarm, defaults.Quiet
+	next.ReduceMotion = defaults.ReduceMotion
+	next.Tariff = defaults.Tariff
+	next.ChartSettings = make(map[string]string)
+	next.ConfigFile = ""
+
+	if err := next.loadConfigFile(c.configPath, c.explicitConfig); err != nil {
+		return nil, err
+	}
+	// A theme picked with t outlives the file's theme key
+	next.ThemeName = c.ThemeName
+	if c.flags != nil {
+		if err := c.flags(&next); err != nil {
+			return nil, err
+		}
+	}
+	return &next, nil
+}
+
+// AllowedOrigins returns the origins of -api-origins
+func (c *Config) AllowedOrigins() []string {
+	var origins []string
+	for _, origin := range strings.Split(c.APIOrigins, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+	return origins
+}
+
+// ExportSettings returns the exporter options by flag name
+func (c *Config) ExportSettings() export.Settings {
+	return export.Settings{
+		"influx-url":    c.InfluxURL,
+		"influx-token":  c.InfluxToken,
+		"influx-tags":   c.InfluxTags,
+		"mqtt-broker":   c.MQTTBroker,
+		"mqtt-prefix":   c.MQTTPrefix,
+		"homeassistant": c.HomeAssistant,
+		"record":        c.Record,
 	}
-	return fmt.Sprintf("%.0f mW", mW)
 }
 
-// FormatEnergy formats energy value according to units setting
-func (c *Config) FormatEnergy(mWh float64) string {
-	if c.Units == UnitsHuman {
-		return fmt.Sprintf("%.2f Wh", mWh/1000.0)
+// ReplayStep returns the recorded time played back on every update, 0 for
+// one sample per update
+func (c *Config) ReplayStep() time.Duration {
+	return time.Duration(c.ReplaySpeed * float64(c.Delay))
+}
+
+// parseReplaySpeed parses a playback speed: "max", or a factor such as "1x",
+// "10x" or "2.5"
+func parseReplaySpeed(s string) (float64, error) {
+	if s == "max" {
+		return 0, nil
 	}
-	return fmt.Sprintf("%.0f mWh", mWh)
+	speed, err := strconv.ParseFloat(strings.TrimSuffix(s, "x"), 64)
+	if err != nil || speed <= 0 {
+		return 0, fmt.Errorf("invalid speed: must be a factor such as 1x or 10x, or max")
+	}
+	return speed, nil
+}
+
+// parseUnits parses a unit system name; human and raw may be abbreviated to
+// their first letter
+func parseUnits(s string) (format.Units, error) {
+	switch s {
+	case "human", "h":
+		return format.UnitsHuman, nil
+	case "raw", "r":
+		return format.UnitsRaw, nil
+	case string(format.UnitsSI):
+		return format.UnitsSI, nil
+	case string(format.UnitsCharge):
+		return format.UnitsCharge, nil
+	default:
+		return "", fmt.Errorf("invalid units: must be 'human', 'raw', 'si' or 'mah'")
+	}
+}
+
+// parseDelay parses an update delay of at least MinDelay
+func parseDelay(s string) (time.Duration, error) {
+	delay, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if delay < MinDelay {
+		return 0, fmt.Errorf("delay must be at least %s", MinDelay)
+	}
+	return delay, nil
+}
+
+// usage prints the command line help
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage: %s [flags] [command]\n\n", os.Args[0])
+	fmt.Fprintln(out, "Commands:")
+	fmt.Fprintln(out, "  daemon     Sample in the background: history, hooks, alerts and -api-listen")
+	fmt.Fprintln(out, "  demo       Show a guided tour of the UI on a simulated battery")
+	fmt.Fprintln(out, "  diff old.json [new.json]")
+	fmt.Fprintln(out, "             Record a health snapshot, or compare the batteries against one")
+	fmt.Fprintln(out, "  info       Print version, platform and configuration diagnostics")
+	fmt.Fprintln(out, "  replay file.csv")
+	fmt.Fprintln(out, "             Play back a session recorded with -record at -speed")
+	fmt.Fprintln(out, "  report [file.md|file.html]")
+	fmt.Fprintln(out, "             Write a health and discharge report for a support ticket")
+	fmt.Fprintln(out, "  share      Print a battery snapshot and upload it to -share-endpoint")
+	fmt.Fprintln(out, "  statusline [format]")
+	fmt.Fprintf(out, "             Print one line for tmux/polybar/waybar (default %q)\n", ui.DefaultStatuslineFormat)
+	fmt.Fprintf(out, "             Placeholders: %s\n", strings.Join(ui.StatuslinePlaceholders, " "))
+	fmt.Fprintln(out, "  version    Print version and exit")
+	fmt.Fprintln(out, "\nFlags:")
+	flag.PrintDefaults()
+}
+
+// hookUsage returns the flag usage text for a hook event
+func hookUsage(event HookEvent) string {
+	switch event {
+	case HookLow:
+		return "Shell command to run when the charge drops below -low-threshold"
+	case HookCritical:
+		return "Shell command to run when the charge drops below -critical-threshold"
+	case HookFull:
+		return "Shell command to run when the battery becomes full"
+	case HookACPlugged:
+		return "Shell command to run when external power is connected"
+	case HookACUnplugged:
+		return "Shell command to run when external power is disconnected"
+	case HookChargeTarget:
+		return "Shell command to run when charging passes -charge-target"
+	default:
+		return "Shell command to run on " + string(event)
+	}
+}
+
+// Formatter returns the formatter for the configured units and locale
+func (c *Config) Formatter() format.Formatter {
+	return format.New(c.Units).
+		SetDecimalSeparator(c.DecimalSeparator()).
+		SetTemperatureUnit(c.TempUnit)
+}
+
+// DecimalSeparator returns the decimal separator of the configured locale
+func (c *Config) DecimalSeparator() string {
+	return format.DecimalSeparator(battery.Coalesce(c.Locale, format.EnvLocale()))
+}
+
+// EstimateMode returns which time estimates the UI displays
+func (c *Config) EstimateMode() ui.EstimateMode {
+	return c.Estimate
+}
+
+// Theme returns the name of the selected color theme
+func (c *Config) Theme() string {
+	return c.ThemeName
+}
+
+// ChartLayout returns how charts are arranged
+func (c *Config) ChartLayout() ui.ChartLayout {
+	return c.Layout
+}
+
+// PanelSize returns the configured left panel width
+func (c *Config) PanelSize() ui.PanelSize {
+	return c.Panel
+}
+
+// Muted reports whether notifications and alarms are suppressed at the given
+// time by the quiet hours
+func (c *Config) Muted(now time.Time) bool {
+	return c.Quiet.Contains(now)
+}
+
+// BatteryIcon reports whether the battery graphic is shown above the gauges
+func (c *Config) BatteryIcon() bool {
+	return c.Icon
+}
+
+// CompactLayout reports whether the UI starts in the compact layout
+func (c *Config) CompactLayout() bool {
+	return c.Compact
+}
+
+// ChargeTargetPercent returns the charge percentage at which to unplug (0 when disabled)
+func (c *Config) ChargeTargetPercent() float64 {
+	return c.ChargeTarget
+}
+
+// ChargeBasis returns what the charge gauge percentage is relative to
+func (c *Config) ChargeBasis() ui.ChargeBasis {
+	return c.Basis
+}
+
+// WarmupSamples returns the number of readings after launch during which estimates are withheld
+func (c *Config) WarmupSamples() int {
+	return c.Warmup
+}
+
+// NoColor reports whether the UI is drawn without colors
+func (c *Config) NoColor() bool {
+	return c.Monochrome
+}
+
+// EnergyTariff returns the price and carbon intensity of the charged energy
+func (c *Config) EnergyTariff() stats.Tariff {
+	return c.Tariff
 }
 
-// FormatVoltage formats voltage value
-func (c *Config) FormatVoltage(v float64) string {
-	return fmt.Sprintf("%.2f V", v)
+// ReducedMotion reports whether toasts are disabled and visual updates are limited
+func (c *Config) ReducedMotion() bool {
+	return c.ReduceMotion
 }
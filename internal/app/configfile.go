@@ -0,0 +1,248 @@
+package app
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/xsikor/go-battop/internal/errors"
+	"github.com/xsikor/go-battop/internal/format"
+	"github.com/xsikor/go-battop/internal/ui"
+)
+
+// chartSettingPattern matches chart settings, optionally scoped to one battery
+// (e.g., "charts.power.color" or "battery1.charts.voltage.hidden")
+var chartSettingPattern = regexp.MustCompile(`^(?:battery\d+\.)?charts\.([a-z]+)\.([a-z]+)$`)
+
+// DefaultConfigFile returns the default configuration file path
+func DefaultConfigFile() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "battop", "config")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "battop", "config")
+}
+
+// readConfigFile parses a configuration file of key=value lines.
+// Blank lines and lines starting with '#' are ignored.
+func readConfigFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	settings := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(text, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected key=value", path, line)
+		}
+		settings[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// loadConfigFile reads the configuration file and applies its settings. A
+// missing file is only an error when the path was given explicitly.
+func (c *Config) loadConfigFile(path string, explicit bool) error {
+	if path == "" {
+		return nil
+	}
+
+	settings, err := readConfigFile(path)
+	if os.IsNotExist(err) && !explicit {
+		return nil
+	}
+	if err != nil {
+		return errors.NewConfigError("config", path, err)
+	}
+
+	for key, value := range settings {
+		if err := c.applySetting(key, value); err != nil {
+			return errors.NewConfigError(key, value, err)
+		}
+	}
+
+	c.ConfigFile = path
+	return nil
+}
+
+// applySetting applies a single configuration file setting
+func (c *Config) applySetting(key, value string) error {
+	switch key {
+	case "charts.layout":
+		switch layout := ui.ChartLayout(value); layout {
+		case ui.ChartLayoutStacked, ui.ChartLayoutColumns:
+			c.Layout = layout
+			return nil
+		default:
+			return fmt.Errorf("invalid layout: must be 'stacked' or 'columns'")
+		}
+	case "delay":
+		delay, err := parseDelay(value)
+		if err != nil {
+			return err
+		}
+		c.Delay = delay
+		return nil
+	case "units":
+		units, err := parseUnits(value)
+		if err != nil {
+			return err
+		}
+		c.Units = units
+		return nil
+	case "temp-units":
+		unit, err := format.ParseTemperatureUnit(value)
+		if err != nil {
+			return err
+		}
+		c.TempUnit = unit
+		return nil
+	case "locale":
+		c.Locale = value
+		return nil
+	case "theme":
+		if _, ok := ui.ThemeByName(value); !ok {
+			return fmt.Errorf("unknown theme: must be one of %s", strings.Join(ui.ThemeNames(), ", "))
+		}
+		c.ThemeName = value
+		return nil
+	case "share.endpoint":
+		c.ShareEndpoint = value
+		return nil
+	case "alarm":
+		alarm, err := ParseAlarmMode(value)
+		if err != nil {
+			return err
+		}
+		c.Alarm = alarm
+		return nil
+	case "quiet-hours":
+		quiet, err := ParseQuietHours(value)
+		if err != nil {
+			return err
+		}
+		c.Quiet = quiet
+		return nil
+	case "reduced-motion":
+		if value != "true" && value != "false" {
+			return fmt.Errorf("reduced-motion must be 'true' or 'false'")
+		}
+		c.ReduceMotion = value == "true"
+		return nil
+	case "energy-price", "carbon-intensity":
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", value)
+		}
+		if err := validateRate(rate); err != nil {
+			return err
+		}
+		if key == "energy-price" {
+			c.Tariff.Price = rate
+		} else {
+			c.Tariff.Carbon = rate
+		}
+		return nil
+	case "energy-currency":
+		c.Tariff.Currency = value
+		return nil
+	case "panel.mode":
+		switch mode := ui.PanelMode(value); mode {
+		case ui.PanelProportional, ui.PanelFixed:
+			c.Panel.Mode = mode
+			return nil
+		default:
+			return fmt.Errorf("invalid panel mode: must be 'proportional' or 'fixed'")
+		}
+	case "panel.ratio":
+		left, right, ok := strings.Cut(value, ":")
+		l, errLeft := strconv.Atoi(left)
+		r, errRight := strconv.Atoi(right)
+		if !ok || errLeft != nil || errRight != nil || l < 1 || r < 1 {
+			return fmt.Errorf("invalid panel ratio: must be left:right, e.g. 1:4")
+		}
+		c.Panel.Left, c.Panel.Right = l, r
+		return nil
+	case "panel.width":
+		width, err := strconv.Atoi(value)
+		if err != nil || width < MinPanelWidth {
+			return fmt.Errorf("invalid panel width: must be at least %d columns", MinPanelWidth)
+		}
+		c.Panel.Width = width
+		return nil
+	}
+
+	match := chartSettingPattern.FindStringSubmatch(key)
+	if match == nil {
+		return fmt.Errorf("unknown setting")
+	}
+	if err := ui.ValidateChartOption(match[1], match[2], value); err != nil {
+		return err
+	}
+
+	c.ChartSettings[key] = value
+	return nil
+}
+
+// validateRate checks an energy price or carbon intensity
+func validateRate(rate float64) error {
+	if rate < 0 || math.IsNaN(rate) || math.IsInf(rate, 0) {
+		return fmt.Errorf("must be a number of at least 0 (0 disables it)")
+	}
+	return nil
+}
+
+// ChartOptions returns the configured options of a chart for a battery.
+// Battery-scoped settings override the settings for all batteries.
+func (c *Config) ChartOptions(index int, chart string) ui.ChartOptions {
+	var options ui.ChartOptions
+	for _, prefix := range []string{"", fmt.Sprintf("battery%d.", index)} {
+		base := prefix + "charts." + chart + "."
+		if color, ok := c.ChartSettings[base+"color"]; ok {
+			options.Color = color
+		}
+		if unit, ok := c.ChartSettings[base+"unit"]; ok {
+			options.Unit = unit
+		}
+		if hidden, ok := c.ChartSettings[base+"hidden"]; ok {
+			value := hidden == "true"
+			options.Hidden = &value
+		}
+		if overlays, ok := c.ChartSettings[base+"overlays"]; ok {
+			options.Overlays = overlays == "true"
+		}
+		if scale, ok := c.ChartSettings[base+"scale"]; ok {
+			options.Scale = scale
+		}
+		if lines, ok := c.ChartSettings[base+"lines"]; ok {
+			// Validated when the setting was read
+			options.Lines, _ = ui.ParseChartLines(lines)
+		}
+	}
+	// The temperature chart follows -temp-units unless its unit is configured
+	if chart == "temperature" && options.Unit == "" {
+		options.Unit = c.TempUnit.Symbol()
+	}
+	return options
+}
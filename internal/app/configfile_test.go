@@ -0,0 +1,120 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/format"
+	"github.com/xsikor/go-battop/internal/ui"
+)
+
+func FuzzConfigFile(f *testing.F) {
+	for _, seed := range []string{
+		"# battop\ncharts.layout = columns\npanel.ratio = 1:4\n",
+		"battery1.charts.voltage.hidden = true\ncharts.power.color = #ff8800\n",
+		"charts.charge.lines = 20:low,80:target\ncharts.power.scale = symlog\ncharts.voltage.lines = 11.55\n",
+		"charts.charge.lines = ,:\n",
+		"panel.width = 0\npanel.ratio = :\n",
+		"alarm = sound\nreduced-motion = true\n",
+		"quiet-hours = 22:00-08:00,12:30-13:00\n",
+		"energy-price = 0.30\nenergy-currency = EUR\ncarbon-intensity = 400\n",
+		"energy-price = -1\n",
+		"delay = 2s\nunits = raw\ntheme = default\n",
+		"temp-units = f\ncharts.temperature.unit = K\nlocale = de_DE\n",
+		"charts.power.unit = C\ncharts.power.lines = 0.5:fast charge\n",
+		"no separator\n",
+		"charts..=\n=\n",
+		"",
+	} {
+		f.Add(seed)
+	}
+	path := filepath.Join(f.TempDir(), "config")
+
+	f.Fuzz(func(t *testing.T, content string) {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		config := DefaultConfig()
+		if err := config.loadConfigFile(path, true); err != nil {
+			return
+		}
+		for _, chart := range []string{"charge", "power", "voltage"} {
+			_ = config.ChartOptions(1, chart)
+		}
+	})
+}
+
+func TestConfigReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("delay = 2s\nunits = raw\ncharts.power.unit = mW\n")
+
+	config := DefaultConfig()
+	config.configPath = path
+	if err := config.loadConfigFile(path, false); err != nil {
+		t.Fatal(err)
+	}
+	// -delay 5s on the command line
+	config.flags = func(c *Config) error {
+		c.Delay = 5 * time.Second
+		return nil
+	}
+	if err := config.flags(config); err != nil {
+		t.Fatal(err)
+	}
+
+	// Removed settings return to their defaults, the flag keeps precedence
+	write("charts.layout = columns\n")
+	next, err := config.Reload()
+	if err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if next.Delay != 5*time.Second {
+		t.Errorf("delay = %v, want the flag's 5s", next.Delay)
+	}
+	if next.Units != format.UnitsHuman {
+		t.Errorf("units = %v, want the default", next.Units)
+	}
+	if next.Layout != ui.ChartLayoutColumns || len(next.ChartSettings) != 0 {
+		t.Errorf("layout = %v, chart settings = %v, want columns and none", next.Layout, next.ChartSettings)
+	}
+	if config.Units != format.UnitsRaw {
+		t.Error("Reload() changed the current configuration")
+	}
+
+	// The copy shares no maps with the running configuration
+	config.Hooks[HookLow] = "notify-send low"
+	next, err = config.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	next.Hooks[HookLow] = "true"
+	next.ChartSettings["charts.power.unit"] = "W"
+	if config.Hooks[HookLow] != "notify-send low" || len(config.ChartSettings) != 1 {
+		t.Errorf("hooks = %v, chart settings = %v after changing the reloaded copy", config.Hooks, config.ChartSettings)
+	}
+
+	// The theme keeps its runtime choice over the file's
+	write("theme = deuteranopia\n")
+	config.ThemeName = ui.DefaultThemeName
+	next, err = config.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.ThemeName != ui.DefaultThemeName {
+		t.Errorf("theme = %q after reload, want the runtime %q", next.ThemeName, ui.DefaultThemeName)
+	}
+
+	// An invalid file is an error and changes nothing
+	write("units = metric\n")
+	if _, err := next.Reload(); err == nil {
+		t.Error("Reload() of an invalid file succeeded")
+	}
+}
@@ -1,7 +1,63 @@
 package app
 
+import "time"
+
 // Event system constants
 const (
 	// EventChannelBufferSize is the buffer size for the event channel
 	EventChannelBufferSize = 100
 )
+
+// Terminal constants
+const (
+	// TrueColorCount is the color count reported by 24-bit color terminals
+	TrueColorCount = 1 << 24
+)
+
+// Sampling constants
+const (
+	// MinDelay is the shortest update delay
+	MinDelay = 100 * time.Millisecond
+
+	// MaxKeyDelay is the longest update delay the ] key doubles up to
+	MaxKeyDelay = time.Minute
+
+	// DefaultWarmupSamples is the number of readings after launch during which
+	// estimates and alerts are withheld
+	DefaultWarmupSamples = 3
+
+	// AdaptiveMaxDelay is the longest update delay of the adaptive sampling
+	AdaptiveMaxDelay = 10 * time.Second
+
+	// AdaptiveRateChange is the charge rate change between two readings, in mW,
+	// that returns the adaptive sampling to the base delay
+	AdaptiveRateChange = 1000.0
+
+	// DaemonLogInterval is the longest time between two readings logged by
+	// the daemon; state changes are logged right away
+	DaemonLogInterval = 5 * time.Minute
+)
+
+// Layout constants
+const (
+	// MinPanelWidth is the smallest fixed width of the left info panel
+	MinPanelWidth = 20
+)
+
+// HTTP API constants
+const (
+	// APIReadHeaderTimeout limits how long clients may take to send request headers
+	APIReadHeaderTimeout = 5 * time.Second
+
+	// BroadcastBufferSize is the number of samples queued per WebSocket client
+	BroadcastBufferSize = 16
+
+	// ControlIdleTimeout closes control socket connections without commands
+	ControlIdleTimeout = time.Minute
+
+	// WebSocketWriteTimeout limits how long a sample may take to reach a client
+	WebSocketWriteTimeout = 10 * time.Second
+
+	// MaxWebSocketFrameSize is the largest client frame accepted
+	MaxWebSocketFrameSize = 64 * 1024
+)
@@ -0,0 +1,248 @@
+package app
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/ui"
+)
+
+// Control socket commands
+const (
+	// ControlStatus answers with a statusline of the first battery
+	ControlStatus = "status"
+	// ControlJSON answers with the current readings as one JSON line
+	ControlJSON = "json"
+	// ControlSetDelay changes the update delay (e.g., "set-delay 5s")
+	ControlSetDelay = "set-delay"
+	// ControlPause stops sampling until resumed
+	ControlPause = "pause"
+	// ControlResume restarts sampling after a pause
+	ControlResume = "resume"
+	// ControlQuit stops the instance
+	ControlQuit = "quit"
+)
+
+// ControlCommands lists the commands the control socket accepts
+var ControlCommands = []string{ControlStatus, ControlJSON, ControlSetDelay, ControlPause, ControlResume, ControlQuit}
+
+// ControlCommand is one command received on the control socket
+type ControlCommand struct {
+	Name  string
+	Args  []string
+	reply chan string
+}
+
+// Reply answers the command; the client waits for the answer
+func (c *ControlCommand) Reply(text string) {
+	c.reply <- text
+}
+
+// DefaultControlSocket returns the suggested control socket path,
+// $XDG_RUNTIME_DIR/battop.sock or a per-user file in the temp directory
+func DefaultControlSocket() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "battop.sock")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("battop-%d.sock", os.Getuid()))
+}
+
+// ControlServer accepts one command per line on a Unix domain socket and
+// passes them to the application, which answers each with one line
+type ControlServer struct {
+	path     string
+	listener net.Listener
+	commands chan *ControlCommand
+	done     chan struct{}
+	once     sync.Once
+}
+
+// NewControlServer creates a control server for the socket at path
+func NewControlServer(path string) *ControlServer {
+	return &ControlServer{
+		path:     path,
+		commands: make(chan *ControlCommand),
+		done:     make(chan struct{}),
+	}
+}
+
+// Commands returns the channel of received commands
+func (s *ControlServer) Commands() <-chan *ControlCommand {
+	return s.commands
+}
+
+// Start listens on the socket and serves clients in the background. A stale
+// socket left by a crashed instance is replaced; a live one is an error.
+func (s *ControlServer) Start() error {
+	if _, err := os.Stat(s.path); err == nil {
+		if conn, err := net.Dial("unix", s.path); err == nil {
+			conn.Close()
+			return fmt.Errorf("control socket %s is in use by another instance", s.path)
+		}
+		if err := os.Remove(s.path); err != nil {
+			return fmt.Errorf("failed to remove stale control socket: %w", err)
+		}
+	}
+
+	listener, err := net.Listen("unix", s.path)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.path, err)
+	}
+	// Only the user may control the instance
+	if err := os.Chmod(s.path, 0600); err != nil {
+		listener.Close()
+		return fmt.Errorf("failed to restrict control socket: %w", err)
+	}
+	s.listener = listener
+	slog.Info("Control socket listening", "path", s.path)
+
+	go s.accept()
+	return nil
+}
+
+// Close stops accepting clients and removes the socket
+func (s *ControlServer) Close() {
+	s.once.Do(func() {
+		close(s.done)
+		if s.listener != nil {
+			s.listener.Close()
+		}
+	})
+}
+
+// accept serves every client in its own goroutine until closed
+func (s *ControlServer) accept() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				slog.Error("Control socket stopped", "error", err)
+			}
+			return
+		}
+		go s.serve(conn)
+	}
+}
+
+// serve answers the commands of one client until it disconnects or idles
+func (s *ControlServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	for {
+		conn.SetReadDeadline(time.Now().Add(ControlIdleTimeout))
+		if !scanner.Scan() {
+			return
+		}
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		command := &ControlCommand{Name: fields[0], Args: fields[1:], reply: make(chan string, 1)}
+		select {
+		case s.commands <- command:
+		case <-s.done:
+			return
+		}
+
+		var reply string
+		select {
+		case reply = <-command.reply:
+		case <-s.done:
+			return
+		}
+		if _, err := fmt.Fprintln(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+// controlCommands returns the commands of the control socket, nil (blocking
+// forever) without one
+func (a *Application) controlCommands() <-chan *ControlCommand {
+	if a.control == nil {
+		return nil
+	}
+	return a.control.Commands()
+}
+
+// handleControl runs a control socket command and answers it; it reports
+// whether the instance should quit. A changed delay is applied by the caller
+// from config.Delay.
+func (a *Application) handleControl(command *ControlCommand) (quit bool) {
+	slog.Info("Control command", "command", command.Name, "args", command.Args)
+
+	switch command.Name {
+	case ControlStatus:
+		statusline, err := ui.NewStatusline(a.manager, a.config)
+		if err != nil {
+			command.Reply(controlError(err))
+			return false
+		}
+		line, err := statusline.Render(a.config.StatuslineFormat)
+		if err != nil {
+			command.Reply(controlError(err))
+			return false
+		}
+		if a.paused {
+			line += " (paused)"
+		}
+		command.Reply(line)
+
+	case ControlJSON:
+		sample, err := a.currentSample()
+		if err != nil {
+			command.Reply(controlError(err))
+			return false
+		}
+		data, err := json.Marshal(sample)
+		if err != nil {
+			command.Reply(controlError(err))
+			return false
+		}
+		command.Reply(string(data))
+
+	case ControlSetDelay:
+		if len(command.Args) != 1 {
+			command.Reply(controlError(fmt.Errorf("usage: set-delay <duration>")))
+			return false
+		}
+		delay, err := parseDelay(command.Args[0])
+		if err != nil {
+			command.Reply(controlError(err))
+			return false
+		}
+		a.config.Delay = delay
+		if a.sampler != nil {
+			a.sampler = NewAdaptiveSampler(delay)
+		}
+		command.Reply("ok")
+
+	case ControlPause, ControlResume:
+		a.paused = command.Name == ControlPause
+		command.Reply("ok")
+
+	case ControlQuit:
+		command.Reply("ok")
+		return true
+
+	default:
+		command.Reply(controlError(fmt.Errorf("unknown command %q, expected one of: %s", command.Name, strings.Join(ControlCommands, ", "))))
+	}
+	return false
+}
+
+// controlError formats an error answer
+func controlError(err error) string {
+	return "error: " + err.Error()
+}
@@ -0,0 +1,96 @@
+package app
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestControlServer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "battop.sock")
+
+	// A stale socket file is replaced
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	server := NewControlServer(path)
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer server.Close()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("socket permissions = %o, want 600", perm)
+	}
+
+	// A second instance must not take over the socket
+	if err := NewControlServer(path).Start(); err == nil {
+		t.Error("Start() on a live socket succeeded")
+	}
+
+	go func() {
+		for command := range server.Commands() {
+			command.Reply(fmt.Sprintf("%s %v", command.Name, command.Args))
+		}
+	}()
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"status", "status []"},
+		{"  set-delay   5s ", "set-delay [5s]"},
+		{"\njson", "json []"},
+	}
+	for _, tt := range tests {
+		if _, err := fmt.Fprintln(conn, tt.line); err != nil {
+			t.Fatal(err)
+		}
+		reply, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading reply to %q: %v", tt.line, err)
+		}
+		if got := strings.TrimSuffix(reply, "\n"); got != tt.want {
+			t.Errorf("reply to %q = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestParseDelay(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"1s", "1s", false},
+		{"100ms", "100ms", false},
+		{"99ms", "", true},
+		{"fast", "", true},
+	}
+	for _, tt := range tests {
+		got, err := parseDelay(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseDelay(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if err == nil && got.String() != tt.want {
+			t.Errorf("parseDelay(%q) = %v, want %s", tt.input, got, tt.want)
+		}
+	}
+}
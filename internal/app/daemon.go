@@ -0,0 +1,115 @@
+package app
+
+import (
+	"log/slog"
+	"math"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/battery"
+)
+
+// runDaemon samples the batteries without a UI, recording history and
+// running alerts and hooks, and blocks until interrupted
+func (a *Application) runDaemon() error {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	if a.config.Adaptive {
+		a.sampler = NewAdaptiveSampler(a.config.Delay)
+	}
+	base, delay := a.config.Delay, a.config.Delay
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	slog.Info("Starting daemon", "delay", a.config.Delay, "adaptive", a.config.Adaptive, "api", a.config.APIListen,
+		"subsystems", strings.Join(enabledSubsystems(a.config), ", "))
+	states := a.logDaemonSample(nil)
+	lastLog := time.Now()
+
+	for {
+		select {
+		case <-timer.C:
+			if a.paused {
+				timer.Reset(delay)
+				continue
+			}
+			if err := a.manager.Update(); err != nil {
+				slog.Error("Failed to update batteries",
+					"error", err,
+					"battery_count", a.manager.Count(),
+					"update_interval", delay,
+				)
+			}
+			a.onBatteryUpdate()
+
+			// Log state changes right away and a summary every few minutes
+			if a.daemonStatesChanged(states) || time.Since(lastLog) >= DaemonLogInterval {
+				states = a.logDaemonSample(states)
+				lastLog = time.Now()
+			}
+
+			if a.sampler != nil {
+				if batteries, err := a.manager.GetAll(); err == nil {
+					delay = a.sampler.Next(batteries)
+				}
+			}
+			timer.Reset(delay)
+
+		case command := <-a.controlCommands():
+			if a.handleControl(command) {
+				return nil
+			}
+			if a.config.Delay != base {
+				base, delay = a.config.Delay, a.config.Delay
+				timer.Reset(delay)
+			}
+
+		case <-sigChan:
+			slog.Info("Exit signal received")
+			return nil
+		}
+	}
+}
+
+// daemonStatesChanged reports whether any battery changed its state since
+// states was recorded
+func (a *Application) daemonStatesChanged(states []battery.State) bool {
+	batteries, err := a.manager.GetAll()
+	if err != nil || len(batteries) != len(states) {
+		return err == nil
+	}
+	for i, info := range batteries {
+		if info.State.Base() != states[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// logDaemonSample logs the current readings and returns the battery states,
+// or previous when the batteries are unavailable
+func (a *Application) logDaemonSample(previous []battery.State) []battery.State {
+	batteries, err := a.manager.GetAll()
+	if err != nil {
+		slog.Warn("Batteries unavailable", "error", err)
+		return previous
+	}
+
+	states := make([]battery.State, len(batteries))
+	for i, info := range batteries {
+		states[i] = info.State.Base()
+		slog.Info("Battery",
+			"index", info.Index,
+			"state", info.State,
+			"percent", math.Round(info.ChargePercent()*10)/10,
+			"rate_mw", info.ChargeRate,
+			"on_ac", a.manager.PowerSource().OnAC,
+		)
+	}
+	return states
+}
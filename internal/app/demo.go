@@ -0,0 +1,54 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/battery"
+)
+
+// DemoTourInterval is how long every message of the demo tour stays up
+const DemoTourInterval = 6 * time.Second
+
+// demoTour is the scripted tour of the demo command, one toast per entry
+var demoTour = []string{
+	"Welcome to battop! This is a simulated battery, a minute passes every second.",
+	"The left panel shows charge, state, power and time estimates.\n[gray]~ and ≈ mark estimates with low and medium confidence.[-]",
+	"The charts plot charge, power, voltage and temperature.\nPress [yellow]1[-]-[yellow]4[-] to hide or show them and [yellow]|[-] to switch the layout.",
+	"Press [yellow]w[-] for the health history and [yellow]p[-] for the power timeline.",
+	"Press [yellow]t[-] to cycle the themes and [yellow]m[-] for the compact layout.",
+	"Press [yellow]u[-] to check whether the charge covers a trip away from the charger.",
+	"That's the tour! Press [yellow]q[-] to quit, or run battop without demo for your own battery.",
+}
+
+// newDemoSource creates a source for a simulated battery starting now. The
+// demo keeps the model exact, so every run looks the same.
+func newDemoSource() *battery.SimulatedSource {
+	return battery.NewSimulatedSource(battery.NewSimulator(time.Now()), battery.SimulatorStep)
+}
+
+// runTour shows the demo tour one toast at a time and hides the last one
+// after DemoTourInterval. It returns early when done is closed.
+func (a *Application) runTour(done <-chan struct{}) {
+	for _, message := range append(demoTour, "") {
+		a.tviewApp.QueueUpdateDraw(func() {
+			a.ui.ShowToast(message)
+		})
+
+		select {
+		case <-time.After(DemoTourInterval):
+		case <-done:
+			return
+		}
+	}
+}
+
+// announceReplayEnd shows a toast once the replay reached the last sample
+func (a *Application) announceReplayEnd(replay *battery.ReplaySource) {
+	<-replay.Done()
+	first, last := replay.Span()
+	message := fmt.Sprintf("Replay finished: %s recorded from %s", last.Sub(first).Round(time.Second), first.Local().Format("2006-01-02 15:04"))
+	a.tviewApp.QueueUpdateDraw(func() {
+		a.ui.ShowToast(message)
+	})
+}
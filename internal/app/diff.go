@@ -0,0 +1,138 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/stats"
+)
+
+// Diff records the batteries to a snapshot file or compares them against
+// one: with a file that doesn't exist yet it records the current batteries,
+// with an existing file it compares the current batteries against it, and
+// with two files it compares the second against the first
+func Diff(w io.Writer, config *Config) error {
+	old, err := stats.LoadSnapshot(config.DiffFiles[0])
+	if errors.Is(err, fs.ErrNotExist) && len(config.DiffFiles) == 1 {
+		snapshot, err := currentSnapshot(config)
+		if err != nil {
+			return err
+		}
+		if err := snapshot.Save(config.DiffFiles[0]); err != nil {
+			return fmt.Errorf("failed to record the snapshot: %w", err)
+		}
+		writeSnapshotSummary(w, config, snapshot)
+		fmt.Fprintf(w, "\nRecorded to %s. Run battop diff %s again later to see what changed.\n", config.DiffFiles[0], config.DiffFiles[0])
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var current stats.Snapshot
+	if len(config.DiffFiles) > 1 {
+		current, err = stats.LoadSnapshot(config.DiffFiles[1])
+	} else {
+		current, err = currentSnapshot(config)
+	}
+	if err != nil {
+		return err
+	}
+
+	writeSnapshotDiff(w, config, old, current)
+	return nil
+}
+
+// currentSnapshot reads the batteries from the source the UI would use
+func currentSnapshot(config *Config) (stats.Snapshot, error) {
+	source, err := newBatterySource(config, nil)
+	if err != nil {
+		return stats.Snapshot{}, err
+	}
+	if closer, ok := source.(io.Closer); ok {
+		defer closer.Close()
+	}
+	if err := source.Update(); err != nil {
+		return stats.Snapshot{}, fmt.Errorf("failed to read batteries: %w", err)
+	}
+	batteries, err := source.GetAll()
+	if err != nil {
+		return stats.Snapshot{}, fmt.Errorf("failed to get batteries: %w", err)
+	}
+	return stats.NewSnapshot(batteries, time.Now()), nil
+}
+
+// writeSnapshotSummary prints the recorded batteries
+func writeSnapshotSummary(w io.Writer, config *Config, snapshot stats.Snapshot) {
+	f := config.Formatter()
+	fmt.Fprintf(w, "Snapshot of %s\n", snapshot.Time.Format("2006-01-02 15:04"))
+	for _, b := range snapshot.Batteries {
+		fmt.Fprintf(w, "\n%s\n", batteryTitle(b))
+		fmt.Fprintf(w, "  Full capacity: %s (design %s)\n", f.Capacity(b.Full, b.Voltage), f.Capacity(b.Design, b.Voltage))
+		fmt.Fprintf(w, "  Health:        %s\n", f.Percent(b.Health()))
+		if b.HasCycles {
+			fmt.Fprintf(w, "  Cycles:        %d\n", b.Cycles)
+		}
+	}
+}
+
+// writeSnapshotDiff prints the capacity fade, cycle delta and projected
+// lifetime of every battery found in both snapshots
+func writeSnapshotDiff(w io.Writer, config *Config, old, current stats.Snapshot) {
+	f := config.Formatter()
+	fmt.Fprintf(w, "Comparing %s with %s (%d days apart)\n",
+		current.Time.Format("2006-01-02"), old.Time.Format("2006-01-02"), int(current.Time.Sub(old.Time).Hours()/24))
+
+	diffs := stats.DiffSnapshots(old, current)
+	if len(diffs) == 0 {
+		fmt.Fprintln(w, "\nNo battery of the snapshot was found; was it replaced?")
+		return
+	}
+	for _, d := range diffs {
+		fmt.Fprintf(w, "\n%s\n", batteryTitle(d.New))
+		fmt.Fprintf(w, "  Full capacity: %s → %s (design %s)\n",
+			f.Capacity(d.Old.Full, d.New.Voltage), f.Capacity(d.New.Full, d.New.Voltage), f.Capacity(d.New.Design, d.New.Voltage))
+		fmt.Fprintf(w, "  Health:        %s → %s (%+.1f points)\n", f.Percent(d.Old.Health()), f.Percent(d.New.Health()), d.New.Health()-d.Old.Health())
+		if d.HasCycles {
+			fmt.Fprintf(w, "  Cycles:        %d → %d (%+d)\n", d.Old.Cycles, d.New.Cycles, d.Cycles)
+		}
+		if d.Fade > 0 {
+			fade := fmt.Sprintf("%.1f points per year", d.FadePerYear())
+			if perCycle := d.FadePerCycle(); perCycle > 0 {
+				fade += fmt.Sprintf(", %.3f per cycle", perCycle)
+			}
+			fmt.Fprintf(w, "  Fade:          %s\n", fade)
+		}
+		fmt.Fprintf(w, "  Projected:     %s\n", projectedLifetime(d))
+	}
+}
+
+// projectedLifetime describes when the battery reaches stats.HealthThreshold
+func projectedLifetime(d stats.SnapshotDiff) string {
+	switch {
+	case d.New.Health() <= stats.HealthThreshold:
+		return fmt.Sprintf("already below %.0f%% health, commonly considered worn out", stats.HealthThreshold)
+	case d.EndOfLife.IsZero():
+		return "no capacity lost, nothing to project"
+	}
+	projection := fmt.Sprintf("%.0f%% health around %s", stats.HealthThreshold, d.EndOfLife.Format("2006-01"))
+	if d.CyclesLeft >= 0 {
+		projection += fmt.Sprintf(", about %d cycles from now", d.CyclesLeft)
+	}
+	return projection
+}
+
+// batteryTitle names a battery by index, model and serial number
+func batteryTitle(b stats.BatterySnapshot) string {
+	title := fmt.Sprintf("Battery %d", b.Index)
+	if name := b.Manufacturer + " " + b.Model; name != " " {
+		title += ": " + name
+	}
+	if b.Serial != "" {
+		title += " (serial " + b.Serial + ")"
+	}
+	return title
+}
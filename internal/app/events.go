@@ -26,11 +26,125 @@ const (
 
 	// EventResize signals terminal resize
 	EventResize
+
+	// EventToggleHealth switches between the battery and health history pages
+	EventToggleHealth
+
+	// EventToggleChart shows or hides the chart given by Event.Chart
+	EventToggleChart
+
+	// EventToggleLayout switches between the stacked and columns chart layouts
+	EventToggleLayout
+
+	// EventToggleInspect shows or hides the chart inspection cursor
+	EventToggleInspect
+
+	// EventArrow is a left or right arrow key (Event.Step), which moves the
+	// inspection cursor or switches batteries
+	EventArrow
+
+	// EventScreenshot saves the screen to a file
+	EventScreenshot
+
+	// EventExportCharts saves the series of every chart as CSV files
+	EventExportCharts
+
+	// EventClick is a left mouse click at Event.X, Event.Y
+	EventClick
+
+	// EventScroll is a mouse wheel step (Event.Step) at a screen position,
+	// which scrolls the info panel under the pointer and zooms the charts out
+	// (1) or in (-1) elsewhere
+	EventScroll
+
+	// EventScrollInfo scrolls the info panel by Event.Step rows
+	EventScrollInfo
+
+	// EventPageInfo scrolls the info panel by Event.Step pages
+	EventPageInfo
+
+	// EventCycleScale switches the charts to the next value axis scale
+	EventCycleScale
+
+	// EventCycleZoom switches the charts to the next zoom level
+	EventCycleZoom
+
+	// EventCycleTheme switches to the next installed theme
+	EventCycleTheme
+
+	// EventToggleCompact switches between the full and compact layouts
+	EventToggleCompact
+
+	// EventToggleHistogram switches between the charts and the discharge power histogram
+	EventToggleHistogram
+
+	// EventReload re-reads the configuration file and applies it
+	EventReload
+
+	// EventChangeDelay halves (Step -1) or doubles (Step 1) the update delay
+	EventChangeDelay
+
+	// EventToggleTimeline switches between the battery and power timeline pages
+	EventToggleTimeline
+
+	// EventPromptReserve asks for an upcoming unplugged period
+	EventPromptReserve
+
+	// EventPromptGoal asks for the time the batteries should last until
+	EventPromptGoal
+
+	// EventToggleChargeBasis switches the charge gauge between percent of full and of design capacity
+	EventToggleChargeBasis
+
+	// EventToggleDetails expands or collapses the battery details section
+	EventToggleDetails
+
+	// EventTogglePeripherals switches between the battery and peripherals pages
+	EventTogglePeripherals
+
+	// EventToggleBreakdown switches between the battery and power breakdown pages
+	EventToggleBreakdown
+
+	// EventToggleConsumers switches between the battery and top consumers pages
+	EventToggleConsumers
+
+	// EventToggleEventLog switches between the battery and event log pages
+	EventToggleEventLog
+
+	// EventSearchEventLog asks for the text to search the event log for
+	EventSearchEventLog
+
+	// EventCycleEventSeverity switches the event log to the next minimum severity
+	EventCycleEventSeverity
+
+	// EventPromptNote asks for a note tagging the current moment
+	EventPromptNote
+
+	// EventSourceChanged signals that the battery source reported new readings between ticks
+	EventSourceChanged
+
+	// EventControl carries a command received on the control socket
+	EventControl
 )
 
 // Event represents an application event
 type Event struct {
 	Type EventType
+
+	// Chart is the 0-based chart position for EventToggleChart
+	Chart int
+
+	// Step is the direction of EventArrow (-1 for left, 1 for right),
+	// EventScroll, EventScrollInfo and EventPageInfo (-1 for up, 1 for down)
+	// and EventChangeDelay
+	Step int
+
+	// X and Y are the screen position of EventClick and the terminal size
+	// of EventResize
+	X, Y int
+
+	// Command is the control socket command of EventControl
+	Command *ControlCommand
 }
 
 // EventManager manages application events
@@ -38,6 +152,7 @@ type EventManager struct {
 	app       *tview.Application
 	eventChan chan Event
 	stopChan  chan struct{}
+	delays    chan time.Duration
 	config    *Config
 }
 
@@ -47,6 +162,7 @@ func NewEventManager(app *tview.Application, config *Config) *EventManager {
 		app:       app,
 		eventChan: make(chan Event, EventChannelBufferSize),
 		stopChan:  make(chan struct{}),
+		delays:    make(chan time.Duration),
 		config:    config,
 	}
 }
@@ -56,8 +172,10 @@ func (em *EventManager) Start() {
 	// Start tick timer
 	go em.tickLoop()
 
-	// Set up keyboard handlers
+	// Set up keyboard, mouse and resize handlers
 	em.setupKeyboardHandlers()
+	em.setupMouseHandlers()
+	em.setupResizeHandler()
 }
 
 // Stop stops the event manager
@@ -70,13 +188,28 @@ func (em *EventManager) Events() <-chan Event {
 	return em.eventChan
 }
 
+// SetDelay changes the interval between tick events
+func (em *EventManager) SetDelay(delay time.Duration) {
+	select {
+	case em.delays <- delay:
+	case <-em.stopChan:
+	}
+}
+
 // tickLoop generates periodic tick events
 func (em *EventManager) tickLoop() {
-	ticker := time.NewTicker(em.config.Delay)
+	delay := em.config.Delay
+	ticker := time.NewTicker(delay)
 	defer ticker.Stop()
 
 	for {
 		select {
+		case next := <-em.delays:
+			if next != delay {
+				slog.Debug("Tick delay changed", "delay", next)
+				delay = next
+				ticker.Reset(delay)
+			}
 		case <-ticker.C:
 			select {
 			case em.eventChan <- Event{Type: EventTick}:
@@ -90,19 +223,108 @@ func (em *EventManager) tickLoop() {
 	}
 }
 
+// Watch sends EventSourceChanged whenever the channel is signalled
+func (em *EventManager) Watch(changes <-chan struct{}) {
+	go func() {
+		for {
+			select {
+			case <-changes:
+				em.sendEvent(Event{Type: EventSourceChanged})
+			case <-em.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// Control sends EventControl for every command on the channel. Commands are
+// never dropped, since their clients wait for an answer.
+func (em *EventManager) Control(commands <-chan *ControlCommand) {
+	go func() {
+		for {
+			select {
+			case command := <-commands:
+				select {
+				case em.eventChan <- Event{Type: EventControl, Command: command}:
+				case <-em.stopChan:
+					return
+				}
+			case <-em.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// setupMouseHandlers turns clicks and wheel steps into events. Other mouse
+// actions are left to the primitives, e.g. to focus an input field.
+func (em *EventManager) setupMouseHandlers() {
+	em.app.SetMouseCapture(func(event *tcell.EventMouse, action tview.MouseAction) (*tcell.EventMouse, tview.MouseAction) {
+		x, y := event.Position()
+		switch action {
+		case tview.MouseLeftClick:
+			em.sendEvent(Event{Type: EventClick, X: x, Y: y})
+		case tview.MouseScrollUp:
+			em.sendEvent(Event{Type: EventScroll, Step: -1, X: x, Y: y})
+			return nil, action
+		case tview.MouseScrollDown:
+			em.sendEvent(Event{Type: EventScroll, Step: 1, X: x, Y: y})
+			return nil, action
+		}
+		return event, action
+	})
+}
+
+// setupResizeHandler sends EventResize after the first draw at a new
+// terminal size, when the primitives got their new sizes
+func (em *EventManager) setupResizeHandler() {
+	width, height := 0, 0
+	em.app.SetAfterDrawFunc(func(screen tcell.Screen) {
+		w, h := screen.Size()
+		if w == width && h == height {
+			return
+		}
+		width, height = w, h
+		em.sendEvent(Event{Type: EventResize, X: w, Y: h})
+	})
+}
+
 // setupKeyboardHandlers sets up keyboard event handlers
 func (em *EventManager) setupKeyboardHandlers() {
 	em.app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		// Leave keys to input fields while one has focus
+		if _, ok := em.app.GetFocus().(*tview.InputField); ok && event.Key() != tcell.KeyCtrlC {
+			return event
+		}
+
 		switch event.Key() {
 		case tcell.KeyEscape, tcell.KeyCtrlC:
 			em.sendEvent(Event{Type: EventExit})
 			return nil
-		case tcell.KeyTab, tcell.KeyRight:
+		case tcell.KeyTab:
 			em.sendEvent(Event{Type: EventNextTab})
 			return nil
-		case tcell.KeyBacktab, tcell.KeyLeft:
+		case tcell.KeyBacktab:
 			em.sendEvent(Event{Type: EventPreviousTab})
 			return nil
+		case tcell.KeyRight:
+			em.sendEvent(Event{Type: EventArrow, Step: 1})
+			return nil
+		case tcell.KeyLeft:
+			em.sendEvent(Event{Type: EventArrow, Step: -1})
+			return nil
+		case tcell.KeyDown:
+			em.sendEvent(Event{Type: EventScrollInfo, Step: 1})
+			return nil
+		case tcell.KeyUp:
+			em.sendEvent(Event{Type: EventScrollInfo, Step: -1})
+			return nil
+		case tcell.KeyPgDn:
+			em.sendEvent(Event{Type: EventPageInfo, Step: 1})
+			return nil
+		case tcell.KeyPgUp:
+			em.sendEvent(Event{Type: EventPageInfo, Step: -1})
+			return nil
 		case tcell.KeyRune:
 			switch event.Rune() {
 			case 'q', 'Q':
@@ -114,6 +336,90 @@ func (em *EventManager) setupKeyboardHandlers() {
 			case 'l', 'L':
 				em.sendEvent(Event{Type: EventNextTab})
 				return nil
+			case 'j', 'J':
+				em.sendEvent(Event{Type: EventScrollInfo, Step: 1})
+				return nil
+			case 'k', 'K':
+				em.sendEvent(Event{Type: EventScrollInfo, Step: -1})
+				return nil
+			case '|':
+				em.sendEvent(Event{Type: EventToggleLayout})
+				return nil
+			case 'i', 'I':
+				em.sendEvent(Event{Type: EventToggleInspect})
+				return nil
+			case 's', 'S':
+				em.sendEvent(Event{Type: EventScreenshot})
+				return nil
+			case 'x', 'X':
+				em.sendEvent(Event{Type: EventExportCharts})
+				return nil
+			case 'y', 'Y':
+				em.sendEvent(Event{Type: EventCycleScale})
+				return nil
+			case 'z', 'Z':
+				em.sendEvent(Event{Type: EventCycleZoom})
+				return nil
+			case 't', 'T':
+				em.sendEvent(Event{Type: EventCycleTheme})
+				return nil
+			case 'm', 'M':
+				em.sendEvent(Event{Type: EventToggleCompact})
+				return nil
+			case 'g', 'G':
+				em.sendEvent(Event{Type: EventToggleHistogram})
+				return nil
+			case 'r', 'R':
+				em.sendEvent(Event{Type: EventReload})
+				return nil
+			case '[':
+				em.sendEvent(Event{Type: EventChangeDelay, Step: -1})
+				return nil
+			case ']':
+				em.sendEvent(Event{Type: EventChangeDelay, Step: 1})
+				return nil
+			case 'p', 'P':
+				em.sendEvent(Event{Type: EventToggleTimeline})
+				return nil
+			case 'b', 'B':
+				em.sendEvent(Event{Type: EventTogglePeripherals})
+				return nil
+			case 'n', 'N':
+				em.sendEvent(Event{Type: EventToggleBreakdown})
+				return nil
+			case 'c', 'C':
+				em.sendEvent(Event{Type: EventToggleConsumers})
+				return nil
+			case 'e', 'E':
+				em.sendEvent(Event{Type: EventToggleEventLog})
+				return nil
+			case '/':
+				em.sendEvent(Event{Type: EventSearchEventLog})
+				return nil
+			case 'f', 'F':
+				em.sendEvent(Event{Type: EventCycleEventSeverity})
+				return nil
+			case 'a', 'A':
+				em.sendEvent(Event{Type: EventPromptNote})
+				return nil
+			case 'd':
+				em.sendEvent(Event{Type: EventToggleDetails})
+				return nil
+			case 'D':
+				em.sendEvent(Event{Type: EventToggleChargeBasis})
+				return nil
+			case 'u', 'U':
+				em.sendEvent(Event{Type: EventPromptReserve})
+				return nil
+			case 'o', 'O':
+				em.sendEvent(Event{Type: EventPromptGoal})
+				return nil
+			case 'w', 'W':
+				em.sendEvent(Event{Type: EventToggleHealth})
+				return nil
+			case '1', '2', '3', '4':
+				em.sendEvent(Event{Type: EventToggleChart, Chart: int(event.Rune() - '1')})
+				return nil
 			}
 		}
 		return event
@@ -0,0 +1,213 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/xsikor/go-battop/internal/battery"
+)
+
+// HookEvent identifies a battery event that can trigger a hook command
+type HookEvent string
+
+const (
+	// HookLow fires when the charge drops below the low threshold while discharging
+	HookLow HookEvent = "on-low"
+	// HookCritical fires when the charge drops below the critical threshold while discharging
+	HookCritical HookEvent = "on-critical"
+	// HookFull fires when the battery becomes full
+	HookFull HookEvent = "on-full"
+	// HookACPlugged fires when external power is connected
+	HookACPlugged HookEvent = "on-ac-plugged"
+	// HookACUnplugged fires when external power is disconnected
+	HookACUnplugged HookEvent = "on-ac-unplugged"
+	// HookChargeTarget fires when charging passes the charge target
+	HookChargeTarget HookEvent = "on-charge-target"
+)
+
+// HookEvents lists all supported hook events in display order
+var HookEvents = []HookEvent{HookLow, HookCritical, HookFull, HookACPlugged, HookACUnplugged, HookChargeTarget}
+
+// HookHysteresis is how many percentage points the charge must move back
+// past a threshold before its hook fires again
+const HookHysteresis = 2.0
+
+// HookRunner executes configured shell commands on battery events. Each event
+// fires once when its condition starts to hold; a condition that already
+// holds at the first observation counts as starting then, so a battery that
+// is critical or a charger that is connected when battop starts runs its hook
+// right away.
+type HookRunner struct {
+	config *Config
+
+	// start runs a hook command, in the background by default
+	start func(event HookEvent, command string, env []string)
+
+	// Per-battery edge tracking so each event fires once per crossing
+	lowFired      map[int]bool
+	criticalFired map[int]bool
+	fullFired     map[int]bool
+
+	// AC state from the previous check; nil until the first observation
+	onAC *bool
+}
+
+// NewHookRunner creates a new hook runner for the given configuration
+func NewHookRunner(config *Config) *HookRunner {
+	h := &HookRunner{
+		config:        config,
+		lowFired:      make(map[int]bool),
+		criticalFired: make(map[int]bool),
+		fullFired:     make(map[int]bool),
+	}
+	h.start = func(event HookEvent, command string, env []string) {
+		go h.execute(event, command, env)
+	}
+	return h
+}
+
+// Enabled reports whether any hook command is configured
+func (h *HookRunner) Enabled() bool {
+	return len(h.config.Hooks) > 0
+}
+
+// Check compares the latest battery readings against the previous ones and
+// runs the hooks for every event that occurred since the last check
+func (h *HookRunner) Check(batteries []*battery.Info, source battery.PowerSource) {
+	if !h.Enabled() || len(batteries) == 0 {
+		return
+	}
+
+	for _, info := range batteries {
+		h.checkBattery(info)
+	}
+
+	h.checkPowerSource(batteries, source)
+}
+
+// checkBattery detects low, critical and full events for a single battery.
+// The low and critical hooks re-arm once the charge is HookHysteresis points
+// above their threshold, and the full hook once it is that far below 100%,
+// so a charge hovering around a threshold doesn't fire every tick.
+func (h *HookRunner) checkBattery(info *battery.Info) {
+	percent := info.ChargePercent()
+	discharging := info.State.Base() == battery.StateDischarging
+
+	if percent > h.config.CriticalThreshold+HookHysteresis {
+		h.criticalFired[info.Index] = false
+	} else if discharging && percent <= h.config.CriticalThreshold && !h.criticalFired[info.Index] {
+		h.criticalFired[info.Index] = true
+		h.run(HookCritical, info)
+	}
+
+	if percent > h.config.LowThreshold+HookHysteresis {
+		h.lowFired[info.Index] = false
+	} else if discharging && percent <= h.config.LowThreshold && percent > h.config.CriticalThreshold && !h.lowFired[info.Index] {
+		h.lowFired[info.Index] = true
+		h.run(HookLow, info)
+	}
+
+	if info.State.Base() == battery.StateFull {
+		if !h.fullFired[info.Index] {
+			h.fullFired[info.Index] = true
+			h.run(HookFull, info)
+		}
+	} else if percent < 100-HookHysteresis {
+		h.fullFired[info.Index] = false
+	}
+}
+
+// checkPowerSource detects external power being connected or disconnected
+func (h *HookRunner) checkPowerSource(batteries []*battery.Info, source battery.PowerSource) {
+	onAC := source.OnAC
+
+	previous := h.onAC
+	h.onAC = &onAC
+
+	if previous != nil && *previous == onAC {
+		return
+	}
+
+	if onAC {
+		h.run(HookACPlugged, batteries[0])
+		return
+	}
+	h.run(HookACUnplugged, batteries[0])
+}
+
+// run executes the hook command for the event in the background
+func (h *HookRunner) run(event HookEvent, info *battery.Info) {
+	command, ok := h.config.Hooks[event]
+	if !ok || command == "" {
+		return
+	}
+
+	slog.Info("Running hook",
+		"event", event,
+		"command", command,
+		"index", info.Index,
+		"percent", info.ChargePercent(),
+	)
+
+	env := append(os.Environ(),
+		"BATTOP_EVENT="+string(event),
+		fmt.Sprintf("BATTOP_BATTERY=%d", info.Index),
+		fmt.Sprintf("BATTOP_PERCENT=%.0f", info.ChargePercent()),
+		"BATTOP_STATE="+info.State.String(),
+	)
+
+	h.start(event, command, env)
+}
+
+// execute runs a shell command with the configured timeout and logs the outcome
+func (h *HookRunner) execute(event HookEvent, command string, env []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.config.HookTimeout)
+	defer cancel()
+
+	cmd := shellCommand(ctx, command)
+	cmd.Env = env
+
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		slog.Error("Hook timed out",
+			"event", event,
+			"command", command,
+			"timeout", h.config.HookTimeout,
+		)
+		return
+	}
+	if err != nil {
+		slog.Error("Hook failed",
+			"event", event,
+			"command", command,
+			"error", err,
+			"output", strings.TrimSpace(output.String()),
+		)
+		return
+	}
+
+	slog.Debug("Hook completed",
+		"event", event,
+		"command", command,
+		"output", strings.TrimSpace(output.String()),
+	)
+}
+
+// shellCommand returns the command running a hook command line through the
+// shell of the OS: cmd.exe on Windows and /bin/sh elsewhere
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "/bin/sh", "-c", command)
+}
@@ -0,0 +1,107 @@
+package app
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/xsikor/go-battop/internal/battery"
+)
+
+func TestHookRunner(t *testing.T) {
+	type step struct {
+		state   battery.State
+		percent float64
+		onAC    bool
+		fired   []HookEvent
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{"first observation while critical", []step{
+			{battery.StateDischarging, 4, false, []HookEvent{HookCritical, HookACUnplugged}},
+			{battery.StateDischarging, 3, false, nil},
+		}},
+		{"first observation while plugged in", []step{
+			{battery.StateCharging, 50, true, []HookEvent{HookACPlugged}},
+			{battery.StateCharging, 51, true, nil},
+		}},
+		{"first observation while full", []step{
+			{battery.StateFull, 100, true, []HookEvent{HookFull, HookACPlugged}},
+		}},
+		{"edge-triggered crossings", []step{
+			{battery.StateDischarging, 30, false, []HookEvent{HookACUnplugged}},
+			{battery.StateDischarging, 20, false, []HookEvent{HookLow}},
+			{battery.StateDischarging, 10, false, nil},
+			{battery.StateDischarging, 5, false, []HookEvent{HookCritical}},
+			{battery.StateDischarging, 4, false, nil},
+			{battery.StateCharging, 4, true, []HookEvent{HookACPlugged}},
+			{battery.StateCharging, 60, true, nil},
+			{battery.StateDischarging, 60, false, []HookEvent{HookACUnplugged}},
+			{battery.StateDischarging, 20, false, []HookEvent{HookLow}},
+		}},
+		{"hysteresis", []step{
+			{battery.StateDischarging, 21, false, []HookEvent{HookACUnplugged}},
+			{battery.StateDischarging, 20, false, []HookEvent{HookLow}},
+			// Hovering around the threshold doesn't fire again
+			{battery.StateDischarging, 21, false, nil},
+			{battery.StateDischarging, 20, false, nil},
+			{battery.StateDischarging, 22, false, nil},
+			{battery.StateDischarging, 19, false, nil},
+			// Rising past the hysteresis re-arms it
+			{battery.StateDischarging, 23, false, nil},
+			{battery.StateDischarging, 20, false, []HookEvent{HookLow}},
+		}},
+		{"full flickering at the top", []step{
+			{battery.StateCharging, 99, true, []HookEvent{HookACPlugged}},
+			{battery.StateFull, 100, true, []HookEvent{HookFull}},
+			{battery.StateCharging, 99, true, nil},
+			{battery.StateFull, 100, true, nil},
+			{battery.StateDischarging, 97, false, []HookEvent{HookACUnplugged}},
+			{battery.StateFull, 100, true, []HookEvent{HookFull, HookACPlugged}},
+		}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			config := DefaultConfig()
+			config.Hooks = make(map[HookEvent]string)
+			for _, event := range HookEvents {
+				config.Hooks[event] = "echo " + string(event)
+			}
+
+			var fired []HookEvent
+			hooks := NewHookRunner(config)
+			hooks.start = func(event HookEvent, command string, env []string) {
+				if command != config.Hooks[event] {
+					t.Errorf("%s ran %q", event, command)
+				}
+				fired = append(fired, event)
+			}
+
+			for i, step := range tc.steps {
+				fired = nil
+				info := &battery.Info{State: step.state, Current: step.percent, Full: 100}
+				hooks.Check([]*battery.Info{info}, battery.PowerSource{OnAC: step.onAC, Detected: true})
+				if !reflect.DeepEqual(fired, step.fired) {
+					t.Errorf("step %d: fired %v, want %v", i, fired, step.fired)
+				}
+			}
+		})
+	}
+}
+
+func TestHookRunnerWithoutCommand(t *testing.T) {
+	config := DefaultConfig()
+	config.Hooks = map[HookEvent]string{HookLow: "echo low"}
+
+	var fired []HookEvent
+	hooks := NewHookRunner(config)
+	hooks.start = func(event HookEvent, command string, env []string) {
+		fired = append(fired, event)
+	}
+	hooks.Check([]*battery.Info{{State: battery.StateDischarging, Current: 3, Full: 100}}, battery.PowerSource{})
+	if len(fired) != 0 {
+		t.Errorf("fired %v without on-critical and on-ac-unplugged commands", fired)
+	}
+}
@@ -0,0 +1,232 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/xsikor/go-battop/internal/battery"
+	"github.com/xsikor/go-battop/internal/session"
+)
+
+// BuildInfo describes how the running binary was built
+type BuildInfo struct {
+	Version string
+	Commit  string
+	Date    string
+}
+
+// String returns the one-line version banner
+func (b BuildInfo) String() string {
+	return fmt.Sprintf("battop %s (%s) built on %s", b.Version, b.Commit, b.Date)
+}
+
+// Subsystem describes an optional component and whether it is available
+type Subsystem struct {
+	// Name of the subsystem
+	Name string
+
+	// Compiled reports whether the subsystem is part of this binary
+	Compiled bool
+
+	// Enabled reports whether the subsystem is active with the current configuration
+	Enabled bool
+}
+
+// Subsystems returns the optional subsystems and their state for the given configuration
+func Subsystems(config *Config) []Subsystem {
+	remote := config.Connect != "" || isRemote(config.source)
+	return []Subsystem{
+		{Name: "Hooks", Compiled: true, Enabled: len(config.Hooks) > 0},
+		{Name: "Charge target", Compiled: true, Enabled: config.ChargeTarget > 0},
+		{Name: "Ticker", Compiled: true, Enabled: config.Ticker},
+		{Name: "Idle detection", Compiled: true, Enabled: config.IdleSource != session.IdleSourceNone},
+		{Name: "External source", Compiled: true, Enabled: config.Source != ""},
+		{Name: "Peripherals", Compiled: true, Enabled: !remote},
+		{Name: "Power breakdown", Compiled: true, Enabled: !remote},
+		{Name: "Top consumers", Compiled: true, Enabled: !remote},
+		{Name: "Remote source", Compiled: true, Enabled: remote},
+		{Name: "HTTP API", Compiled: true, Enabled: config.APIListen != ""},
+		{Name: "WebSocket", Compiled: true, Enabled: config.APIListen != ""},
+		{Name: "Control socket", Compiled: true, Enabled: config.ControlSocket != ""},
+		{Name: "Daemon", Compiled: true, Enabled: config.Command == CommandDaemon},
+		{Name: "InfluxDB", Compiled: true, Enabled: config.InfluxURL != ""},
+		{Name: "MQTT", Compiled: true, Enabled: config.MQTTBroker != ""},
+		{Name: "Home Assistant", Compiled: true, Enabled: config.HomeAssistant != ""},
+		{Name: "CSV recording", Compiled: true, Enabled: config.Record != ""},
+		{Name: "Share", Compiled: true, Enabled: config.ShareEndpoint != ""},
+		{Name: "Store", Compiled: true, Enabled: true},
+	}
+}
+
+// enabledSubsystems returns the names of the subsystems active with the
+// given configuration
+func enabledSubsystems(config *Config) []string {
+	var names []string
+	for _, s := range Subsystems(config) {
+		if s.Compiled && s.Enabled {
+			names = append(names, s.Name)
+		}
+	}
+	return names
+}
+
+// LogPath returns the path of the application log file
+func LogPath() string {
+	return filepath.Join(os.TempDir(), "go-battop.log")
+}
+
+// PrintInfo writes a diagnostic report suitable for attaching to bug reports
+func PrintInfo(w io.Writer, config *Config, build BuildInfo) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, build.String())
+	writeBuildSection(tw)
+	writePlatformSection(tw, config)
+	writeConfigSection(tw, config)
+	writeSubsystemSection(tw, config)
+
+	return tw.Flush()
+}
+
+// writeBuildSection writes Go toolchain and VCS details
+func writeBuildSection(w io.Writer) {
+	fmt.Fprintln(w, "\nBuild")
+	fmt.Fprintf(w, "  Go:\t%s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		fmt.Fprintln(w, "  Module:\tunknown")
+		return
+	}
+	fmt.Fprintf(w, "  Module:\t%s %s\n", info.Main.Path, info.Main.Version)
+
+	settings := make(map[string]string)
+	for _, s := range info.Settings {
+		settings[s.Key] = s.Value
+	}
+	if rev := settings["vcs.revision"]; rev != "" {
+		fmt.Fprintf(w, "  Revision:\t%s (modified: %s)\n", rev, battery.Coalesce(settings["vcs.modified"], "unknown"))
+	}
+	if settings["-trimpath"] == "true" {
+		fmt.Fprintln(w, "  Trimpath:\tyes")
+	}
+}
+
+// writePlatformSection writes the battery source the terminal UI would use
+// with the configuration and its batteries
+func writePlatformSection(w io.Writer, config *Config) {
+	fmt.Fprintln(w, "\nPlatform")
+	source, err := newBatterySource(config, nil)
+	if err != nil {
+		fmt.Fprintf(w, "  Reader:\tnone (%v)\n", err)
+		return
+	}
+	if closer, ok := source.(io.Closer); ok {
+		defer closer.Close()
+	}
+	fmt.Fprintf(w, "  Reader:\t%s\n", sourceName(source, config))
+
+	if err := source.Update(); err != nil {
+		fmt.Fprintf(w, "  Batteries:\tnone (%v)\n", err)
+		return
+	}
+
+	batteries, err := source.GetAll()
+	if err != nil {
+		fmt.Fprintf(w, "  Batteries:\tnone (%v)\n", err)
+		return
+	}
+
+	power := source.PowerSource()
+	detection := "detected"
+	if !power.Detected {
+		detection = "inferred from battery state"
+	}
+	fmt.Fprintf(w, "  Power source:\t%s (%s)\n", power, detection)
+	for _, adapter := range power.Adapters {
+		fmt.Fprintf(w, "  Adapter %s:\t%s (%s), online: %t, profile: %s, max power: %.0f mW\n",
+			adapter.Name, adapter.Kind, adapter.Type, adapter.Online, battery.Coalesce(adapter.Profile(), "unknown"), adapter.MaxPower)
+	}
+
+	fmt.Fprintf(w, "  Batteries:\t%d\n", len(batteries))
+	for _, info := range batteries {
+		if info.State == battery.StateNotPresent {
+			fmt.Fprintf(w, "  Battery %d:\tempty bay\n", info.Index)
+			continue
+		}
+		fmt.Fprintf(w, "  Battery %d:\t%s, %.1f%%\n", info.Index, info.State, info.ChargePercent())
+		writeCapabilities(w, info.Capabilities)
+	}
+}
+
+// sourceName describes a battery source: the platform reader of the local
+// batteries, or where the batteries of other sources come from
+func sourceName(source battery.Source, config *Config) string {
+	if manager, ok := source.(*battery.Manager); ok {
+		return manager.PlatformName()
+	}
+	switch {
+	case config.Command == CommandDemo:
+		return "demo"
+	case config.Command == CommandReplay:
+		return "replay of " + config.ReplayFile
+	case config.Source != "":
+		return config.Source
+	case config.Connect != "":
+		return "remote " + config.Connect
+	default:
+		return "termux"
+	}
+}
+
+// writeCapabilities writes the supported and missing capabilities of a battery
+func writeCapabilities(w io.Writer, caps battery.Capabilities) {
+	supported := strings.Join(caps.Supported(), ", ")
+	if supported == "" {
+		supported = "none"
+	}
+	fmt.Fprintf(w, "    Supported:\t%s\n", supported)
+
+	if missing := caps.Missing(); len(missing) > 0 {
+		fmt.Fprintf(w, "    Missing:\t%s (not reported by the %s platform reader)\n", strings.Join(missing, ", "), runtime.GOOS)
+	}
+}
+
+// writeConfigSection writes configuration sources and effective values
+func writeConfigSection(w io.Writer, config *Config) {
+	fmt.Fprintln(w, "\nConfiguration")
+	fmt.Fprintf(w, "  Config file:\t%s\n", battery.Coalesce(config.ConfigFile, "none (command-line flags only)"))
+	fmt.Fprintf(w, "  Log file:\t%s\n", LogPath())
+	fmt.Fprintf(w, "  Data dir:\t%s\n", config.DataDir)
+	fmt.Fprintf(w, "  Delay:\t%s\n", config.Delay)
+	fmt.Fprintf(w, "  Units:\t%s, %s\n", config.Units, config.TempUnit.Symbol())
+	fmt.Fprintf(w, "  Decimal separator:\t%q\n", config.DecimalSeparator())
+
+	idle := session.NewIdleDetector(config.IdleSource, config.IdleFile)
+	sources := strings.Join(idle.Sources(), ", ")
+	if sources == "" {
+		sources = "none"
+	}
+	fmt.Fprintf(w, "  Idle sources:\t%s\n", sources)
+}
+
+// writeSubsystemSection writes the state of optional subsystems
+func writeSubsystemSection(w io.Writer, config *Config) {
+	fmt.Fprintln(w, "\nSubsystems")
+	for _, s := range Subsystems(config) {
+		state := "not compiled"
+		if s.Compiled {
+			state = "disabled"
+			if s.Enabled {
+				state = "enabled"
+			}
+		}
+		fmt.Fprintf(w, "  %s:\t%s\n", s.Name, state)
+	}
+}
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/export"
+)
+
+// runJSON writes one JSON object per sampling tick (ndjson) to stdout and
+// blocks until interrupted
+func (a *Application) runJSON() error {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	updateTicker := time.NewTicker(a.config.Delay)
+	defer updateTicker.Stop()
+
+	slog.Info("Starting JSON output", "delay", a.config.Delay)
+	encoder := json.NewEncoder(os.Stdout)
+	if err := a.writeJSONSample(encoder); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-updateTicker.C:
+			if a.paused {
+				continue
+			}
+			if err := a.manager.Update(); err != nil {
+				slog.Error("Failed to update batteries",
+					"error", err,
+					"battery_count", a.manager.Count(),
+					"update_interval", a.config.Delay,
+				)
+			}
+			a.onBatteryUpdate()
+			if err := a.writeJSONSample(encoder); err != nil {
+				return err
+			}
+
+		case command := <-a.controlCommands():
+			delay := a.config.Delay
+			if a.handleControl(command) {
+				return nil
+			}
+			if a.config.Delay != delay {
+				updateTicker.Reset(a.config.Delay)
+			}
+
+		case <-sigChan:
+			slog.Info("Exit signal received")
+			return nil
+		}
+	}
+}
+
+// writeJSONSample encodes the current battery readings as one line
+func (a *Application) writeJSONSample(encoder *json.Encoder) error {
+	sample, err := a.currentSample()
+	if err != nil {
+		slog.Error("Failed to get batteries", "error", err)
+		return nil
+	}
+
+	if err := encoder.Encode(sample); err != nil {
+		return fmt.Errorf("failed to write JSON sample: %w", err)
+	}
+	return nil
+}
+
+// currentSample returns the latest battery readings
+func (a *Application) currentSample() (export.Sample, error) {
+	batteries, err := a.manager.GetAll()
+	if err != nil {
+		return export.Sample{}, err
+	}
+
+	return export.Sample{
+		Time:      time.Now(),
+		OnAC:      a.manager.PowerSource().OnAC,
+		Warmup:    a.warmingUp(),
+		Batteries: batteries,
+	}, nil
+}
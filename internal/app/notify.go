@@ -0,0 +1,67 @@
+package app
+
+import (
+	"log/slog"
+	"os/exec"
+	"runtime"
+)
+
+// notifyDesktop shows a desktop notification with notify-send, or osascript
+// on macOS, and reports whether one was shown
+func notifyDesktop(title, message string) bool {
+	cmd := exec.Command("notify-send", "--app-name=battop", title, message)
+	if runtime.GOOS == "darwin" {
+		cmd = exec.Command("osascript", "-e", "display notification "+appleScriptString(message)+" with title "+appleScriptString(title))
+	}
+	if _, err := exec.LookPath(cmd.Args[0]); err != nil {
+		slog.Debug("No desktop notifier found", "command", cmd.Args[0])
+		return false
+	}
+
+	if err := cmd.Run(); err != nil {
+		slog.Warn("Failed to show desktop notification", "error", err)
+		return false
+	}
+	return true
+}
+
+// appleScriptString quotes s as an AppleScript string literal
+func appleScriptString(s string) string {
+	quoted := make([]rune, 0, len(s)+2)
+	quoted = append(quoted, '"')
+	for _, r := range s {
+		if r == '"' || r == '\\' {
+			quoted = append(quoted, '\\')
+		}
+		quoted = append(quoted, r)
+	}
+	return string(append(quoted, '"'))
+}
+
+// alertSounds are the commands tried in order to play the system alert sound
+var alertSounds = map[string][][]string{
+	"linux": {
+		{"canberra-gtk-play", "--id=battery-caution"},
+		{"paplay", "/usr/share/sounds/freedesktop/stereo/alarm-clock-elapsed.oga"},
+	},
+	"darwin": {
+		{"afplay", "/System/Library/Sounds/Sosumi.aiff"},
+	},
+}
+
+// playAlertSound plays the system alert sound with the first available
+// player and reports whether one was played
+func playAlertSound() bool {
+	for _, args := range alertSounds[runtime.GOOS] {
+		if _, err := exec.LookPath(args[0]); err != nil {
+			continue
+		}
+		if err := exec.Command(args[0], args[1:]...).Run(); err != nil {
+			slog.Warn("Failed to play the alert sound", "command", args[0], "error", err)
+			continue
+		}
+		return true
+	}
+	slog.Debug("No alert sound player found")
+	return false
+}
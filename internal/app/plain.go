@@ -0,0 +1,107 @@
+package app
+
+import (
+	"bufio"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/ui"
+)
+
+// runPlain runs the screen reader friendly mode and blocks until interrupted.
+// It prints a full report at start and then one line per change worth
+// announcing; Enter on stdin prints the full report again and "q" quits.
+func (a *Application) runPlain() error {
+	plain, err := ui.NewPlain(a.manager, a.config)
+	if err != nil {
+		return fmt.Errorf("failed to create plain renderer: %w", err)
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	updateTicker := time.NewTicker(a.config.Delay)
+	defer updateTicker.Stop()
+
+	// stdin is read in the background; it is closed at EOF and nil afterwards,
+	// so a detached stdin keeps the mode running
+	input := make(chan string)
+	go func() {
+		defer close(input)
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			input <- strings.TrimSpace(scanner.Text())
+		}
+	}()
+
+	slog.Info("Starting plain mode")
+	if _, err := plain.Record(time.Now()); err != nil {
+		slog.Error("Failed to record readings", "error", err)
+	}
+	fmt.Println("Press Enter for a full report, or q and Enter to quit.")
+	a.printPlainReport(plain)
+
+	for {
+		select {
+		case <-updateTicker.C:
+			if a.paused {
+				continue
+			}
+			if err := a.manager.Update(); err != nil {
+				slog.Error("Failed to update batteries",
+					"error", err,
+					"battery_count", a.manager.Count(),
+					"update_interval", a.config.Delay,
+				)
+			}
+			a.onBatteryUpdate()
+			lines, err := plain.Record(time.Now())
+			if err != nil {
+				slog.Error("Failed to record readings", "error", err)
+				continue
+			}
+			for _, line := range lines {
+				fmt.Println(line)
+			}
+
+		case line, ok := <-input:
+			if !ok {
+				input = nil
+				continue
+			}
+			if line == "q" {
+				return nil
+			}
+			a.printPlainReport(plain)
+
+		case command := <-a.controlCommands():
+			delay := a.config.Delay
+			if a.handleControl(command) {
+				return nil
+			}
+			if a.config.Delay != delay {
+				updateTicker.Reset(a.config.Delay)
+			}
+
+		case <-sigChan:
+			slog.Info("Exit signal received")
+			return nil
+		}
+	}
+}
+
+// printPlainReport prints the full plain report followed by a blank line
+func (a *Application) printPlainReport(plain *ui.Plain) {
+	report, err := plain.Report(time.Now())
+	if err != nil {
+		slog.Error("Failed to build plain report", "error", err)
+		return
+	}
+	fmt.Printf("%s\n\n", report)
+}
@@ -0,0 +1,91 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// QuietWindow is a daily time window, which wraps around midnight when it
+// ends before it starts
+type QuietWindow struct {
+	// Start and End are offsets from midnight
+	Start, End time.Duration
+}
+
+// QuietHours are the daily windows during which desktop notifications and
+// alarms are suppressed
+type QuietHours []QuietWindow
+
+// ParseQuietHours parses comma separated windows like "22:00-08:00,12:30-13:00"
+func ParseQuietHours(text string) (QuietHours, error) {
+	var hours QuietHours
+	for _, field := range strings.Split(text, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		from, to, ok := strings.Cut(strings.ReplaceAll(field, "–", "-"), "-")
+		if !ok {
+			return nil, fmt.Errorf("quiet window %q: expected HH:MM-HH:MM", field)
+		}
+		start, err := parseTimeOfDay(from)
+		if err != nil {
+			return nil, fmt.Errorf("quiet window %q: %w", field, err)
+		}
+		end, err := parseTimeOfDay(to)
+		if err != nil {
+			return nil, fmt.Errorf("quiet window %q: %w", field, err)
+		}
+		if start == end {
+			return nil, fmt.Errorf("quiet window %q is empty", field)
+		}
+		hours = append(hours, QuietWindow{Start: start, End: end})
+	}
+	return hours, nil
+}
+
+// parseTimeOfDay parses HH:MM as an offset from midnight
+func parseTimeOfDay(text string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(text))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q: expected HH:MM", text)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Contains reports whether the time of day of t is within a window
+func (w QuietWindow) Contains(t time.Time) bool {
+	offset := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	if w.Start < w.End {
+		return offset >= w.Start && offset < w.End
+	}
+	return offset >= w.Start || offset < w.End
+}
+
+// String formats the window as HH:MM-HH:MM
+func (w QuietWindow) String() string {
+	clock := func(d time.Duration) string {
+		return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return clock(w.Start) + "-" + clock(w.End)
+}
+
+// Contains reports whether t is within any of the windows
+func (h QuietHours) Contains(t time.Time) bool {
+	for _, w := range h {
+		if w.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// String formats the windows as they are parsed
+func (h QuietHours) String() string {
+	windows := make([]string, len(h))
+	for i, w := range h {
+		windows[i] = w.String()
+	}
+	return strings.Join(windows, ",")
+}
@@ -0,0 +1,44 @@
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+func FuzzParseQuietHours(f *testing.F) {
+	for _, seed := range []string{"22:00-08:00", "12:30-13:00, 22:00–07:00", "08:00-08:00", "25:00-01:00", "22:00", "-", ",", ""} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, text string) {
+		hours, err := ParseQuietHours(text)
+		if err != nil {
+			return
+		}
+		again, err := ParseQuietHours(hours.String())
+		if err != nil || again.String() != hours.String() {
+			t.Errorf("ParseQuietHours(%q) = %q, which parses as %q, %v", text, hours, again, err)
+		}
+	})
+}
+
+func TestQuietHoursContains(t *testing.T) {
+	hours, err := ParseQuietHours("22:00-08:00,12:30-13:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for clock, want := range map[string]bool{
+		"21:59": false,
+		"22:00": true,
+		"03:00": true,
+		"07:59": true,
+		"08:00": false,
+		"12:45": true,
+		"13:00": false,
+	} {
+		at, _ := time.Parse("15:04", clock)
+		if got := hours.Contains(at); got != want {
+			t.Errorf("Contains(%s) = %v, want %v", clock, got, want)
+		}
+	}
+}
@@ -0,0 +1,373 @@
+package app
+
+import (
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/battery"
+	"github.com/xsikor/go-battop/internal/format"
+	"github.com/xsikor/go-battop/internal/stats"
+	"github.com/xsikor/go-battop/internal/store"
+	"github.com/xsikor/go-battop/internal/ui"
+	"github.com/xsikor/go-battop/pkg/plot"
+)
+
+// Report contents
+const (
+	// ReportDischarges is the number of recent discharge profiles listed
+	ReportDischarges = 5
+
+	// ReportDays is the number of days of the power timeline summarized
+	ReportDays = 7
+
+	// ReportChartWidth and ReportChartHeight are the size of the report charts
+	ReportChartWidth  = 640
+	ReportChartHeight = 200
+)
+
+// report is a battery report as sections of notes, a table and a chart,
+// written as Markdown or HTML
+type report struct {
+	Title    string
+	Notes    []string
+	Sections []reportSection
+}
+
+// reportSection is one headed part of a report; Level 2 sections start a
+// battery and level 3 sections are its parts
+type reportSection struct {
+	Level  int
+	Title  string
+	Notes  []string
+	Header []string
+	Rows   [][]string
+
+	// Chart is an SVG image, "" for none, shown below its Caption
+	Chart   string
+	Caption string
+}
+
+// Report writes a report of the batteries, their health history and recent
+// discharges to the report file, as HTML for .html files and Markdown
+// otherwise, or as Markdown to w without a file
+func Report(w io.Writer, config *Config, build BuildInfo) error {
+	source, err := newBatterySource(config, nil)
+	if err != nil {
+		return err
+	}
+	if closer, ok := source.(io.Closer); ok {
+		defer closer.Close()
+	}
+	if err := source.Update(); err != nil {
+		return fmt.Errorf("failed to read batteries: %w", err)
+	}
+	batteries, err := source.GetAll()
+	if err != nil {
+		return fmt.Errorf("failed to get batteries: %w", err)
+	}
+
+	r := buildReport(config.Formatter(), store.New(config.DataDir), batteries, time.Now())
+	r.Notes = append([]string{build.String()}, r.Notes...)
+
+	if config.ReportFile == "" {
+		return writeMarkdownReport(w, r)
+	}
+	file, err := os.Create(config.ReportFile)
+	if err != nil {
+		return err
+	}
+	write := writeMarkdownReport
+	if ext := strings.ToLower(filepath.Ext(config.ReportFile)); ext == ".html" || ext == ".htm" {
+		write = writeHTMLReport
+	}
+	if err := write(file, r); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Wrote the report to %s\n", config.ReportFile)
+	return nil
+}
+
+// buildReport collects the report of the batteries from their readings and
+// the history recorded in the store
+func buildReport(f format.Formatter, st *store.Store, batteries []*battery.Info, now time.Time) report {
+	r := report{Title: "Battery report"}
+	hostname, _ := os.Hostname()
+	r.Notes = append(r.Notes, fmt.Sprintf("Generated %s on %s", now.Format("2006-01-02 15:04 MST"), battery.Coalesce(hostname, "an unknown host")))
+
+	profiles := loadDischargeProfiles(st)
+	for _, info := range batteries {
+		if info.State == battery.StateNotPresent {
+			continue
+		}
+		r.Sections = append(r.Sections,
+			identitySection(f, info),
+			healthSection(stats.LoadHealthHistory(st, info), info),
+			dischargeSection(f, profiles, info),
+		)
+	}
+	if days := timelineSection(stats.NewTimeline(st), now); len(days.Rows) > 0 {
+		r.Sections = append(r.Sections, days)
+	}
+	return r
+}
+
+// identitySection describes a battery and its current reading
+func identitySection(f format.Formatter, info *battery.Info) reportSection {
+	volts := info.NominalVoltage()
+	section := reportSection{
+		Level:  2,
+		Title:  fmt.Sprintf("Battery %d", info.Index),
+		Header: []string{"Field", "Value"},
+	}
+	add := func(field, value string) {
+		if value != "" {
+			section.Rows = append(section.Rows, []string{field, value})
+		}
+	}
+	add("Manufacturer", info.Manufacturer)
+	add("Model", info.Model)
+	add("Serial number", info.Serial)
+	add("Technology", battery.Coalesce(info.Chemistry, info.Technology))
+	add("Firmware", info.Firmware)
+	add("Manufactured", info.ManufactureDate)
+	add("State", fmt.Sprintf("%s, %s", info.State, f.Percent(info.ChargePercent())))
+	add("Full capacity", f.Capacity(info.Full, volts))
+	add("Design capacity", f.Capacity(info.Design, volts))
+	add("Health", f.Percent(info.Health()))
+	if info.Capabilities.HasCycles {
+		add("Cycles", fmt.Sprintf("%d", info.CycleCount))
+	}
+	if info.Capabilities.HasTemperature {
+		add("Temperature", f.Temperature(info.Temperature))
+	}
+	return section
+}
+
+// healthSection summarizes the recorded capacity fade and its projection
+func healthSection(history *stats.HealthHistory, info *battery.Info) reportSection {
+	section := reportSection{Level: 3, Title: "Health history"}
+	records := history.Entries()
+	if len(records) == 0 {
+		section.Notes = append(section.Notes, "No health history was recorded yet; battop records one reading per day while it runs.")
+		return section
+	}
+
+	first, last := records[0], records[len(records)-1]
+	section.Notes = append(section.Notes, fmt.Sprintf("%d daily readings from %s to %s: health %.1f%% → %.1f%%.",
+		len(records), first.Date, last.Date, first.Health(), last.Health()))
+	if info.Capabilities.HasCycles && last.Cycles > first.Cycles {
+		section.Notes = append(section.Notes, fmt.Sprintf("%d cycles over the period.", last.Cycles-first.Cycles))
+	}
+	if at, ok := history.Projection(stats.HealthThreshold); ok {
+		section.Notes = append(section.Notes, fmt.Sprintf("%.0f%% health is projected around %s (%s confidence).",
+			stats.HealthThreshold, at.Format("2006-01"), history.ProjectionConfidence()))
+	} else {
+		section.Notes = append(section.Notes, "Not enough declining history for a lifetime projection.")
+	}
+
+	data := plot.NewData(len(records))
+	for _, record := range records {
+		data.AddAt(record.Time(), record.Health())
+	}
+	section.Chart = reportChart(data, plot.Threshold{
+		Value: stats.HealthThreshold,
+		Label: fmt.Sprintf("%.0f%%", stats.HealthThreshold),
+		Style: plot.Style{Color: "red"},
+	})
+	return section
+}
+
+// dischargeSection lists the battery's most recent discharge profiles and
+// charts the latest one
+func dischargeSection(f format.Formatter, profiles []*stats.DischargeProfile, info *battery.Info) reportSection {
+	section := reportSection{
+		Level:  3,
+		Title:  "Recent discharges",
+		Header: []string{"Profile", "Started", "Duration", "Charge", "Average power", "Active power"},
+	}
+
+	var latest *stats.DischargeProfile
+	for _, profile := range profiles {
+		if profile.BatteryIndex != info.Index || len(profile.Samples) < 2 {
+			continue
+		}
+		if latest == nil {
+			latest = profile
+		}
+		first, last := profile.Samples[0], profile.Samples[len(profile.Samples)-1]
+		average := "-"
+		if hours := profile.Duration().Hours(); hours > 0 {
+			average = f.Power((first.Current - last.Current) / hours)
+		}
+		active := "-"
+		if rate := profile.ActiveDrain().AverageRate(); rate > 0 {
+			active = f.Power(rate)
+		}
+		name := profile.Name
+		if !profile.Complete {
+			name += " (partial)"
+		}
+		section.Rows = append(section.Rows, []string{
+			name,
+			profile.StartedAt.Format("2006-01-02 15:04"),
+			f.Duration(profile.Duration()),
+			fmt.Sprintf("%.0f%% → %.0f%%", first.Percent, last.Percent),
+			average,
+			active,
+		})
+		if len(section.Rows) == ReportDischarges {
+			break
+		}
+	}
+
+	if latest == nil {
+		section.Notes = append(section.Notes, "No discharge was recorded; record one with -record-discharge and a profile name.")
+		return section
+	}
+	data := plot.NewData(len(latest.Samples))
+	for _, sample := range latest.Samples {
+		data.AddAt(latest.StartedAt.Add(sample.Elapsed()), sample.Percent)
+	}
+	section.Caption = fmt.Sprintf("Charge during the %q discharge", latest.Name)
+	section.Chart = reportChart(data)
+	return section
+}
+
+// reportChart renders a percentage series as SVG, "" for fewer than two points
+func reportChart(data *plot.Data, thresholds ...plot.Threshold) string {
+	if data.Len() < 2 {
+		return ""
+	}
+	chart := plot.NewChart()
+	chart.AddSeries("charge", data, plot.Style{Color: "blue"})
+	chart.SetLabelFormat(func(value float64) string { return fmt.Sprintf("%.1f%%", value) })
+	for _, threshold := range thresholds {
+		chart.AddThreshold(threshold)
+	}
+
+	var svg strings.Builder
+	if err := chart.RenderSVG(&svg, ReportChartWidth, ReportChartHeight, ui.ImagePalette); err != nil {
+		return ""
+	}
+	return svg.String()
+}
+
+// timelineSection sums the power states of the last ReportDays days
+func timelineSection(timeline *stats.Timeline, now time.Time) reportSection {
+	section := reportSection{
+		Level:  2,
+		Title:  "Power timeline",
+		Notes:  []string{fmt.Sprintf("Hours per power state over the last %d days, while battop was running.", ReportDays)},
+		Header: []string{"Date", "On battery", "Charging", "AC idle", "Sleep"},
+	}
+	for n := ReportDays - 1; n >= 0; n-- {
+		day := timeline.Day(now.AddDate(0, 0, -n))
+		minutes := map[stats.PowerState]int{}
+		for m := 0; m < stats.MinutesPerDay; m++ {
+			minutes[day.At(m)]++
+		}
+		if minutes[stats.PowerNone] == stats.MinutesPerDay {
+			continue
+		}
+		hours := func(state stats.PowerState) string {
+			return fmt.Sprintf("%.1f h", float64(minutes[state])/60)
+		}
+		section.Rows = append(section.Rows, []string{
+			day.Date, hours(stats.PowerDischarging), hours(stats.PowerCharging), hours(stats.PowerACIdle), hours(stats.PowerSleep),
+		})
+	}
+	return section
+}
+
+// loadDischargeProfiles loads the recorded discharge profiles, newest first
+func loadDischargeProfiles(st *store.Store) []*stats.DischargeProfile {
+	names, _ := st.List(stats.DischargeCollection)
+	var profiles []*stats.DischargeProfile
+	for _, name := range names {
+		if profile, err := stats.LoadDischargeProfile(st, name); err == nil {
+			profiles = append(profiles, profile)
+		}
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].StartedAt.After(profiles[j].StartedAt)
+	})
+	return profiles
+}
+
+// writeMarkdownReport writes the report as Markdown with the charts as
+// embedded SVG images
+func writeMarkdownReport(w io.Writer, r report) error {
+	var md strings.Builder
+	fmt.Fprintf(&md, "# %s\n\n", r.Title)
+	for _, note := range r.Notes {
+		fmt.Fprintf(&md, "%s  \n", note)
+	}
+	for _, section := range r.Sections {
+		fmt.Fprintf(&md, "\n%s %s\n", strings.Repeat("#", section.Level), section.Title)
+		for _, note := range section.Notes {
+			fmt.Fprintf(&md, "\n%s\n", note)
+		}
+		if len(section.Rows) > 0 {
+			md.WriteString("\n| " + strings.Join(section.Header, " | ") + " |\n")
+			md.WriteString("|" + strings.Repeat(" --- |", len(section.Header)) + "\n")
+			for _, row := range section.Rows {
+				md.WriteString("| " + strings.Join(row, " | ") + " |\n")
+			}
+		}
+		if section.Caption != "" && section.Chart != "" {
+			fmt.Fprintf(&md, "\n%s:\n", section.Caption)
+		}
+		if section.Chart != "" {
+			fmt.Fprintf(&md, "\n![%s](data:image/svg+xml;base64,%s)\n", section.Title, base64.StdEncoding.EncodeToString([]byte(section.Chart)))
+		}
+	}
+	_, err := io.WriteString(w, md.String())
+	return err
+}
+
+// reportTemplate lays out the HTML report with the charts inline
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"svg": func(s string) template.HTML { return template.HTML(s) },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; max-width: 48em; margin: 2em auto; color: #202020; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { border: 1px solid #c0c0c0; padding: 0.25em 0.75em; text-align: left; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{range .Notes}}<p>{{.}}</p>
+{{end}}{{range .Sections}}
+{{if eq .Level 2}}<h2>{{.Title}}</h2>{{else}}<h3>{{.Title}}</h3>{{end}}
+{{range .Notes}}<p>{{.}}</p>
+{{end}}{{if .Rows}}<table>
+<tr>{{range .Header}}<th>{{.}}</th>{{end}}</tr>
+{{range .Rows}}<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{end}}</table>
+{{end}}{{if .Chart}}{{with .Caption}}<p>{{.}}:</p>
+{{end}}{{svg .Chart}}
+{{end}}{{end}}</body>
+</html>
+`))
+
+// writeHTMLReport writes the report as a standalone HTML page with the
+// charts as inline SVG
+func writeHTMLReport(w io.Writer, r report) error {
+	return reportTemplate.Execute(w, r)
+}
@@ -0,0 +1,85 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/battery"
+	"github.com/xsikor/go-battop/internal/format"
+	"github.com/xsikor/go-battop/internal/stats"
+	"github.com/xsikor/go-battop/internal/store"
+)
+
+func TestBuildReport(t *testing.T) {
+	st := store.New(t.TempDir())
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.Local)
+	info := &battery.Info{
+		Index:        0,
+		State:        battery.StateDischarging,
+		Current:      30000,
+		Full:         45000,
+		Design:       50000,
+		Manufacturer: "SMP",
+		Model:        "5B10W13930",
+		Serial:       "1234",
+		CycleCount:   300,
+		Capabilities: battery.Capabilities{HasCycles: true},
+	}
+
+	// Two months of fading readings and one recorded discharge
+	history := stats.LoadHealthHistory(st, info)
+	for day := 60; day >= 0; day-- {
+		reading := *info
+		reading.Full = 45000 + float64(day)*20
+		reading.CycleCount = 300 - day/2
+		if _, err := history.Record(&reading, now.AddDate(0, 0, -day)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	profile := &stats.DischargeProfile{
+		Name:      "january",
+		StartedAt: now.Add(-5 * time.Hour),
+		Complete:  true,
+		Samples: []stats.DischargeSample{
+			{ElapsedSeconds: 0, Percent: 100, Current: 45000, Rate: -9000},
+			{ElapsedSeconds: 3600, Percent: 80, Current: 36000, Rate: -9000},
+			{ElapsedSeconds: 7200, Percent: 60, Current: 27000, Rate: -9000},
+		},
+	}
+	if err := st.Save(stats.DischargeCollection, profile.Name, profile); err != nil {
+		t.Fatal(err)
+	}
+
+	r := buildReport(format.New(format.UnitsHuman), st, []*battery.Info{info}, now)
+
+	var md strings.Builder
+	if err := writeMarkdownReport(&md, r); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"## Battery 0",
+		"| Serial number | 1234 |",
+		"| Health | 90.0% |",
+		"61 daily readings from 2025-04-02 to 2025-06-01: health 92.4% → 90.0%.",
+		"30 cycles over the period.",
+		"80% health is projected around",
+		"| january | 2025-06-01 07:00 | 02:00 | 100% → 60% | 9.00 W | - |",
+		"![Health history](data:image/svg+xml;base64,",
+	} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("Markdown report lacks %q:\n%s", want, md.String())
+		}
+	}
+
+	var html strings.Builder
+	if err := writeHTMLReport(&html, r); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(html.String(), "<svg "); got != 2 {
+		t.Errorf("HTML report has %d inline charts, want 2", got)
+	}
+	if !strings.Contains(html.String(), "<td>SMP</td>") {
+		t.Errorf("HTML report lacks the manufacturer:\n%s", html.String())
+	}
+}
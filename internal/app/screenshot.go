@@ -0,0 +1,93 @@
+package app
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/ui"
+)
+
+// MessageToastDuration is how long results such as the saved screenshot
+// files are shown
+const MessageToastDuration = 4 * time.Second
+
+// saveScreenshot writes the screen as ANSI text and, with -screenshot-png
+// and -screenshot-svg, the charts of the active battery as PNG and SVG to
+// timestamped files
+func (a *Application) saveScreenshot() {
+	if a.screen == nil {
+		return
+	}
+
+	base := filepath.Join(a.config.ScreenshotDir, "battop-"+time.Now().Format("20060102-150405"))
+	var saved []string
+	if err := os.WriteFile(base+".txt", []byte(ui.ScreenText(a.screen)), 0o644); err != nil {
+		a.showMessage(fmt.Sprintf("[red]Screenshot failed: %v[-]", err))
+		return
+	}
+	saved = append(saved, base+".txt")
+
+	if a.config.ScreenshotPNG {
+		if err := writeChartImage(base+".png", a.ui.ChartImage()); err != nil {
+			slog.Error("Failed to save chart image", "error", err)
+		} else {
+			saved = append(saved, base+".png")
+		}
+	}
+
+	if a.config.ScreenshotSVG {
+		if err := writeChartSVG(base+".svg", a.ui.WriteChartSVG); err != nil {
+			slog.Error("Failed to save chart SVG", "error", err)
+		} else {
+			saved = append(saved, base+".svg")
+		}
+	}
+
+	slog.Info("Saved screenshot", "files", saved)
+	a.showMessage("Saved " + strings.Join(saved, ", "))
+}
+
+// writeChartImage encodes the chart image as PNG
+func writeChartImage(path string, img image.Image) error {
+	if img == nil {
+		return fmt.Errorf("no visible charts")
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(file, img); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// writeChartSVG writes the charts of the active battery as SVG
+func writeChartSVG(path string, write func(w io.Writer) error) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		os.Remove(path)
+		return err
+	}
+	return file.Close()
+}
+
+// showMessage shows a message in a toast for MessageToastDuration
+func (a *Application) showMessage(message string) {
+	a.ui.ShowToast(message)
+	time.AfterFunc(MessageToastDuration, func() {
+		a.tviewApp.QueueUpdateDraw(func() { a.ui.ShowToast("") })
+	})
+}
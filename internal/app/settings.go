@@ -0,0 +1,37 @@
+package app
+
+import (
+	"github.com/xsikor/go-battop/internal/store"
+	"github.com/xsikor/go-battop/internal/ui"
+)
+
+// Settings persistence
+const (
+	// SettingsCollection is the store collection holding persisted settings
+	SettingsCollection = "settings"
+
+	// settingsName is the store name of the UI settings
+	settingsName = "ui"
+)
+
+// settings holds choices made at runtime that persist between runs
+type settings struct {
+	Theme string `json:"theme,omitempty"`
+}
+
+// loadSettings applies persisted settings that weren't given on the command line
+func loadSettings(st *store.Store, config *Config, explicit map[string]bool) {
+	var saved settings
+	if err := st.Load(SettingsCollection, settingsName, &saved); err != nil {
+		return
+	}
+
+	if _, ok := ui.ThemeByName(saved.Theme); ok && !explicit["theme"] {
+		config.ThemeName = saved.Theme
+	}
+}
+
+// saveSettings persists the runtime settings
+func saveSettings(st *store.Store, config *Config) error {
+	return st.Save(SettingsCollection, settingsName, settings{Theme: config.ThemeName})
+}
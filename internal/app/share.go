@@ -0,0 +1,149 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/battery"
+	"github.com/xsikor/go-battop/internal/ui"
+)
+
+// ShareTimeout is the maximum time an upload to the paste service may take
+const ShareTimeout = 15 * time.Second
+
+// clipboardCommands are tried in order to copy the shared URL
+var clipboardCommands = [][]string{
+	{"wl-copy"},
+	{"xclip", "-selection", "clipboard"},
+	{"xsel", "--clipboard", "--input"},
+	{"pbcopy"},
+}
+
+// Share writes a text snapshot of the batteries and, when a paste endpoint is
+// configured, uploads it and prints the resulting URL. Nothing is uploaded
+// unless the user opted in by setting an endpoint.
+func Share(w io.Writer, config *Config, build BuildInfo) error {
+	var snapshot strings.Builder
+	if err := writeSnapshot(&snapshot, config, build); err != nil {
+		return err
+	}
+
+	if config.ShareEndpoint == "" {
+		fmt.Fprint(w, snapshot.String())
+		fmt.Fprintln(w, "\nUploading is disabled. Set -share-endpoint or share.endpoint in the config file to share this snapshot.")
+		return nil
+	}
+
+	url, err := upload(config.ShareEndpoint, snapshot.String())
+	if err != nil {
+		return fmt.Errorf("failed to upload snapshot: %w", err)
+	}
+
+	fmt.Fprintln(w, url)
+	if copyToClipboard(url) {
+		fmt.Fprintln(w, "(copied to clipboard)")
+	}
+	return nil
+}
+
+// writeSnapshot writes a plain-text summary of the current battery readings
+func writeSnapshot(w io.Writer, config *Config, build BuildInfo) error {
+	manager := battery.NewManager()
+	manager.SetSmoothing(config.Smoothing)
+	if err := manager.Update(); err != nil {
+		return fmt.Errorf("failed to read batteries: %w", err)
+	}
+	batteries, err := manager.GetAll()
+	if err != nil {
+		return fmt.Errorf("failed to get batteries: %w", err)
+	}
+
+	f := config.Formatter()
+	fmt.Fprintf(w, "%s\n", build.String())
+	fmt.Fprintf(w, "Snapshot taken %s on %s\n", time.Now().Format(time.RFC3339), manager.PlatformName())
+	fmt.Fprintf(w, "Power source: %s\n", manager.PowerSource())
+
+	for _, info := range batteries {
+		volts := info.NominalVoltage()
+		fmt.Fprintf(w, "\nBattery %d: %s %s (%s)\n", info.Index, info.Manufacturer, info.Model, info.Technology)
+		fmt.Fprintf(w, "  State:    %s, %s\n", info.State, f.Percent(info.ChargePercent()))
+		fmt.Fprintf(w, "  Power:    %s\n", f.Power(math.Abs(info.ChargeRate)))
+		fmt.Fprintf(w, "  Voltage:  %s\n", f.Voltage(info.Voltage))
+		fmt.Fprintf(w, "  Capacity: %s / %s (design %s, health %s)\n",
+			f.Capacity(info.Current, volts), f.Capacity(info.Full, volts), f.Capacity(info.Design, volts), f.Percent(info.Health()))
+
+		tte, ttf := info.TimeToEmpty(), info.TimeToFull()
+		confidence := battery.ConfidenceLow
+		if config.EstimateMode() != ui.EstimateInstant {
+			tte, ttf = info.SmoothedTimeToEmpty(), info.SmoothedTimeToFull()
+			confidence = info.EstimateConfidence
+		}
+		if info.State.Base() == battery.StateDischarging && tte > 0 {
+			fmt.Fprintf(w, "  Remaining: %s (%s confidence)\n", confidence.Mark(f.Duration(tte)), confidence)
+		}
+		if info.State.Base() == battery.StateCharging && ttf > 0 {
+			fmt.Fprintf(w, "  To full:  %s (%s confidence)\n", confidence.Mark(f.Duration(ttf)), confidence)
+		}
+		if info.Capabilities.HasCycles {
+			fmt.Fprintf(w, "  Cycles:   %d\n", info.CycleCount)
+		}
+		if info.Capabilities.HasTemperature {
+			fmt.Fprintf(w, "  Temp:     %s\n", f.Temperature(info.Temperature))
+		}
+	}
+
+	return nil
+}
+
+// upload posts the text to the paste endpoint and returns the URL from the response body
+func upload(endpoint, text string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), ShareTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(text))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("paste service returned %s", resp.Status)
+	}
+
+	url := strings.TrimSpace(string(body))
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return "", fmt.Errorf("paste service did not return a URL")
+	}
+	return url, nil
+}
+
+// copyToClipboard copies text with the first available clipboard tool
+func copyToClipboard(text string) bool {
+	for _, command := range clipboardCommands {
+		if _, err := exec.LookPath(command[0]); err != nil {
+			continue
+		}
+		cmd := exec.Command(command[0], command[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if cmd.Run() == nil {
+			return true
+		}
+	}
+	return false
+}
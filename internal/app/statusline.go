@@ -0,0 +1,36 @@
+package app
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/xsikor/go-battop/internal/ui"
+)
+
+// PrintStatusline prints one statusline for the template and exits, so it can
+// be called periodically by tmux, polybar or waybar
+func PrintStatusline(w io.Writer, config *Config) error {
+	manager, err := newBatterySource(config, nil)
+	if err != nil {
+		return err
+	}
+	if closer, ok := manager.(io.Closer); ok {
+		defer closer.Close()
+	}
+	if err := manager.Update(); err != nil {
+		return fmt.Errorf("failed to read batteries: %w", err)
+	}
+
+	statusline, err := ui.NewStatusline(manager, config)
+	if err != nil {
+		return err
+	}
+
+	line, err := statusline.Render(config.StatuslineFormat)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(w, line)
+	return err
+}
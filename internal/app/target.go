@@ -0,0 +1,61 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/battery"
+)
+
+// ChargeTargetNotifier notifies once per charge when a battery passes the
+// charge target, for users who unplug manually to reduce wear
+type ChargeTargetNotifier struct {
+	config *Config
+	hooks  *HookRunner
+
+	// Per-battery edge tracking so each charge notifies once
+	fired map[int]bool
+}
+
+// NewChargeTargetNotifier creates a notifier running the on-charge-target hook through hooks
+func NewChargeTargetNotifier(config *Config, hooks *HookRunner) *ChargeTargetNotifier {
+	return &ChargeTargetNotifier{
+		config: config,
+		hooks:  hooks,
+		fired:  make(map[int]bool),
+	}
+}
+
+// Enabled reports whether a charge target is configured
+func (n *ChargeTargetNotifier) Enabled() bool {
+	return n.config.ChargeTarget > 0
+}
+
+// Check notifies for every charging battery that passed the target since the last check
+func (n *ChargeTargetNotifier) Check(batteries []*battery.Info) {
+	if !n.Enabled() {
+		return
+	}
+
+	for _, info := range batteries {
+		percent := info.ChargePercent()
+		if info.State.Base() != battery.StateCharging {
+			// Re-arm once the charger is removed below the target
+			if percent < n.config.ChargeTarget {
+				n.fired[info.Index] = false
+			}
+			continue
+		}
+		if percent < n.config.ChargeTarget || n.fired[info.Index] {
+			continue
+		}
+
+		n.fired[info.Index] = true
+		slog.Info("Charge target reached", "index", info.Index, "percent", percent, "target", n.config.ChargeTarget)
+		if !n.config.Muted(time.Now()) {
+			go notifyDesktop("battop", fmt.Sprintf("Battery %d reached %.0f%% (target %.0f%%), unplug the charger", info.Index, percent, n.config.ChargeTarget))
+		}
+		n.hooks.run(HookChargeTarget, info)
+	}
+}
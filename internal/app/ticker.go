@@ -0,0 +1,87 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/ui"
+)
+
+// runTicker runs the single-line ticker mode and blocks until interrupted
+func (a *Application) runTicker() error {
+	ticker, err := ui.NewTicker(a.manager, a.config)
+	if err != nil {
+		return fmt.Errorf("failed to create ticker: %w", err)
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	updateTicker := time.NewTicker(a.config.Delay)
+	defer updateTicker.Stop()
+
+	// Reduced motion rotates slowly and only rewrites the line on rotation
+	interval := a.config.TickerInterval
+	if a.config.ReduceMotion {
+		interval = max(interval, ui.ReducedMotionInterval)
+	}
+	rotateTicker := time.NewTicker(interval)
+	defer rotateTicker.Stop()
+
+	slog.Info("Starting ticker mode", "rotation_interval", interval)
+	a.printTickerLine(ticker)
+
+	for {
+		select {
+		case <-updateTicker.C:
+			if a.paused {
+				continue
+			}
+			if err := a.manager.Update(); err != nil {
+				slog.Error("Failed to update batteries",
+					"error", err,
+					"battery_count", a.manager.Count(),
+					"update_interval", a.config.Delay,
+				)
+			}
+			a.onBatteryUpdate()
+			if !a.config.ReduceMotion {
+				a.printTickerLine(ticker)
+			}
+
+		case <-rotateTicker.C:
+			ticker.Rotate()
+			a.printTickerLine(ticker)
+
+		case command := <-a.controlCommands():
+			delay := a.config.Delay
+			if a.handleControl(command) {
+				fmt.Println()
+				return nil
+			}
+			if a.config.Delay != delay {
+				updateTicker.Reset(a.config.Delay)
+			}
+
+		case <-sigChan:
+			slog.Info("Exit signal received")
+			fmt.Println()
+			return nil
+		}
+	}
+}
+
+// printTickerLine overwrites the current terminal line with the ticker text
+func (a *Application) printTickerLine(ticker *ui.Ticker) {
+	line, err := ticker.Line()
+	if err != nil {
+		slog.Error("Failed to build ticker line", "error", err)
+		return
+	}
+	fmt.Printf("\r\033[K%s", line)
+}
@@ -0,0 +1,204 @@
+package app
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// websocketGUID is the fixed key suffix of the WebSocket handshake (RFC 6455)
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket frame opcodes
+const (
+	wsOpText  = 0x1
+	wsOpClose = 0x8
+	wsOpPing  = 0x9
+	wsOpPong  = 0xA
+)
+
+// handleWebSocket upgrades the connection and streams every battery sample
+// as a JSON text message until the client disconnects
+func (s *APIServer) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") || key == "" {
+		writeAPIError(w, http.StatusBadRequest, fmt.Errorf("expected a WebSocket upgrade request"))
+		return
+	}
+	if origin := r.Header.Get("Origin"); !s.originAllowed(origin) {
+		slog.Warn("WebSocket upgrade from a foreign origin refused", "origin", origin, "remote", r.RemoteAddr)
+		writeAPIError(w, http.StatusForbidden, fmt.Errorf("origin %q is not allowed, see -api-origins", origin))
+		return
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		writeAPIError(w, http.StatusInternalServerError, fmt.Errorf("connection cannot be upgraded"))
+		return
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		slog.Warn("WebSocket upgrade failed", "error", err)
+		return
+	}
+	defer conn.Close()
+
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", websocketAccept(key))
+	if err := rw.Flush(); err != nil {
+		return
+	}
+
+	samples, unsubscribe := s.broadcaster.Subscribe()
+	defer unsubscribe()
+	slog.Info("WebSocket client connected", "remote", r.RemoteAddr)
+
+	// Pings are answered by the writer so frames never interleave
+	pings := make(chan []byte, 1)
+	closed := make(chan struct{})
+	go readWebSocket(rw.Reader, pings, closed)
+
+	for {
+		select {
+		case message := <-samples:
+			if err := writeWebSocketFrame(conn, wsOpText, message); err != nil {
+				slog.Debug("WebSocket write failed", "error", err)
+				return
+			}
+		case payload := <-pings:
+			if err := writeWebSocketFrame(conn, wsOpPong, payload); err != nil {
+				return
+			}
+		case <-closed:
+			writeWebSocketFrame(conn, wsOpClose, nil)
+			slog.Info("WebSocket client disconnected", "remote", r.RemoteAddr)
+			return
+		}
+	}
+}
+
+// originAllowed reports whether a page of the origin may open the WebSocket:
+// requests without an origin come from other programs than browsers, and
+// browsers send the origin of the page so another site can't read the
+// batteries through a visitor's browser
+func (s *APIServer) originAllowed(origin string) bool {
+	if origin == "" {
+		return true
+	}
+	if u, err := url.Parse(origin); err == nil {
+		host := u.Hostname()
+		if ip := net.ParseIP(host); host == "localhost" || ip != nil && ip.IsLoopback() {
+			return true
+		}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, allowed := range s.origins {
+		if allowed == "*" || strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// websocketAccept returns the Sec-WebSocket-Accept value for a client key
+func websocketAccept(key string) string {
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// readWebSocket reads client frames, forwarding ping payloads, and closes
+// closed when the client sends a close frame or the connection fails
+func readWebSocket(r *bufio.Reader, pings chan<- []byte, closed chan<- struct{}) {
+	defer close(closed)
+
+	for {
+		opcode, payload, err := readWebSocketFrame(r)
+		if err != nil || opcode == wsOpClose {
+			return
+		}
+		if opcode == wsOpPing {
+			select {
+			case pings <- payload:
+			default:
+			}
+		}
+	}
+}
+
+// readWebSocketFrame reads a single frame and unmasks its payload
+func readWebSocketFrame(r *bufio.Reader) (byte, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	opcode := header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > MaxWebSocketFrameSize {
+		return 0, nil, fmt.Errorf("frame of %d bytes exceeds the limit", length)
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(r, mask[:]); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return opcode, payload, nil
+}
+
+// writeWebSocketFrame writes a single unmasked, unfragmented frame
+func writeWebSocketFrame(conn net.Conn, opcode byte, payload []byte) error {
+	header := []byte{0x80 | opcode}
+	switch n := len(payload); {
+	case n < 126:
+		header = append(header, byte(n))
+	case n <= 0xFFFF:
+		header = append(header, 126)
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header = append(header, 127)
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+
+	if err := conn.SetWriteDeadline(time.Now().Add(WebSocketWriteTimeout)); err != nil {
+		return err
+	}
+	_, err := conn.Write(append(header, payload...))
+	return err
+}
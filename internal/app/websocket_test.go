@@ -0,0 +1,127 @@
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func FuzzReadWebSocketFrame(f *testing.F) {
+	// Masked "hi" text frame, ping, close and oversized length headers
+	f.Add([]byte{0x81, 0x82, 1, 2, 3, 4, 'h' ^ 1, 'i' ^ 2})
+	f.Add([]byte{0x89, 0x00})
+	f.Add([]byte{0x88, 0x00})
+	f.Add([]byte{0x82, 0x7e, 0xff, 0xff})
+	f.Add([]byte{0x82, 0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, payload, err := readWebSocketFrame(bufio.NewReader(bytes.NewReader(data)))
+		if err == nil && len(payload) > MaxWebSocketFrameSize {
+			t.Errorf("payload of %d bytes exceeds the limit", len(payload))
+		}
+	})
+}
+
+func TestWebSocketStream(t *testing.T) {
+	api, server := newTestAPI(t, &apiSource{})
+	conn, reader := dialWebSocket(t, server.URL, "http://127.0.0.1:8080")
+
+	// Samples published before the client subscribed are not delivered
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(time.Millisecond) {
+		api.broadcaster.mu.Lock()
+		subscribed := len(api.broadcaster.subscribers) > 0
+		api.broadcaster.mu.Unlock()
+		if subscribed {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("client never subscribed")
+		}
+	}
+	api.Publish(map[string]float64{"percent": 42})
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	opcode, payload, err := readWebSocketFrame(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opcode != wsOpText || string(payload) != `{"percent":42}` {
+		t.Errorf("frame %#x %s", opcode, payload)
+	}
+}
+
+func TestWebSocketOrigin(t *testing.T) {
+	api, server := newTestAPI(t, &apiSource{})
+	api.AllowOrigins("https://dash.example.com/")
+
+	tests := []struct {
+		origin string
+		status int
+	}{
+		{"", http.StatusSwitchingProtocols},
+		{"http://localhost:3000", http.StatusSwitchingProtocols},
+		{"http://127.0.0.1:8080", http.StatusSwitchingProtocols},
+		{"http://[::1]:8080", http.StatusSwitchingProtocols},
+		{"https://dash.example.com", http.StatusSwitchingProtocols},
+		{"https://evil.example.com", http.StatusForbidden},
+		{"http://localhost.evil.example.com", http.StatusForbidden},
+		{"null", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.origin, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodGet, server.URL+"/ws", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			request.Header.Set("Connection", "Upgrade")
+			request.Header.Set("Upgrade", "websocket")
+			request.Header.Set("Sec-WebSocket-Version", "13")
+			request.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+			if tt.origin != "" {
+				request.Header.Set("Origin", tt.origin)
+			}
+			resp, err := http.DefaultClient.Do(request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.status {
+				t.Errorf("status %d, want %d", resp.StatusCode, tt.status)
+			}
+		})
+	}
+}
+
+// dialWebSocket opens a WebSocket to the test server from a page of the
+// origin and checks the handshake
+func dialWebSocket(t *testing.T, serverURL, origin string) (net.Conn, *bufio.Reader) {
+	t.Helper()
+	conn, err := net.Dial("tcp", strings.TrimPrefix(serverURL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	key := "dGhlIHNhbXBsZSBub25jZQ=="
+	fmt.Fprintf(conn, "GET /ws HTTP/1.1\r\nHost: %s\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Key: %s\r\nSec-WebSocket-Version: 13\r\nOrigin: %s\r\n\r\n",
+		strings.TrimPrefix(serverURL, "http://"), key, origin)
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("handshake answered %s", resp.Status)
+	}
+	// The accept value of the key from RFC 6455
+	if accept := resp.Header.Get("Sec-WebSocket-Accept"); accept != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("Sec-WebSocket-Accept %q", accept)
+	}
+	return conn, reader
+}
@@ -0,0 +1,208 @@
+package battery
+
+import (
+	"fmt"
+	"math"
+)
+
+// ChargerKind classifies an external power supply
+type ChargerKind int
+
+const (
+	// ChargerUnknown indicates the charger type cannot be determined
+	ChargerUnknown ChargerKind = iota
+	// ChargerBarrel indicates a dedicated mains adapter (barrel or proprietary connector)
+	ChargerBarrel
+	// ChargerUSB indicates a low-power USB charger without Power Delivery
+	ChargerUSB
+	// ChargerUSBPD indicates a USB Power Delivery charger
+	ChargerUSBPD
+)
+
+// String returns string representation of the charger kind
+func (k ChargerKind) String() string {
+	switch k {
+	case ChargerBarrel:
+		return "Barrel"
+	case ChargerUSB:
+		return "USB (low power)"
+	case ChargerUSBPD:
+		return "USB-PD"
+	default:
+		return "Unknown"
+	}
+}
+
+// MarshalText encodes the charger kind by name
+func (k ChargerKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText decodes a charger kind name, falling back to ChargerUnknown
+func (k *ChargerKind) UnmarshalText(text []byte) error {
+	*k = ChargerUnknown
+	for _, kind := range []ChargerKind{ChargerBarrel, ChargerUSB, ChargerUSBPD} {
+		if kind.String() == string(text) {
+			*k = kind
+		}
+	}
+	return nil
+}
+
+// ACAdapter represents an external power supply (mains adapter or USB charger)
+type ACAdapter struct {
+	// Name is the platform identifier of the adapter (e.g., "AC", "ADP1")
+	Name string `json:"name"`
+
+	// Type is the supply type reported by the platform (e.g., "Mains", "USB")
+	Type string `json:"type"`
+
+	// Online is true when the adapter is connected and supplying power
+	Online bool `json:"online"`
+
+	// Kind is the charger classification
+	Kind ChargerKind `json:"kind"`
+
+	// Voltage is the negotiated voltage in V (0 if unknown)
+	Voltage float64 `json:"voltage_v"`
+
+	// Current is the negotiated maximum current in A (0 if unknown)
+	Current float64 `json:"current_a"`
+
+	// MaxPower is the negotiated maximum power in mW (0 if unknown)
+	MaxPower float64 `json:"max_power_mw"`
+}
+
+// Profile returns the negotiated voltage/current profile (e.g., "20.0 V / 3.25 A")
+func (a ACAdapter) Profile() string {
+	if a.Voltage <= 0 || a.Current <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.1f V / %.2f A", a.Voltage, a.Current)
+}
+
+// PowerSource summarizes where the system is currently drawing power from
+type PowerSource struct {
+	// OnAC is true when the system runs on external power
+	OnAC bool `json:"on_ac"`
+
+	// Detected is true when OnAC comes from adapter readings rather than
+	// being inferred from battery states
+	Detected bool `json:"detected"`
+
+	// Adapters lists all external power supplies found on the system
+	Adapters []ACAdapter `json:"adapters"`
+}
+
+// MaxPower returns the combined maximum power of online adapters in mW
+func (p PowerSource) MaxPower() float64 {
+	total := 0.0
+	for _, adapter := range p.Adapters {
+		if adapter.Online {
+			total += adapter.MaxPower
+		}
+	}
+	return total
+}
+
+// Primary returns the online adapter with the highest maximum power
+func (p PowerSource) Primary() (ACAdapter, bool) {
+	var primary ACAdapter
+	found := false
+	for _, adapter := range p.Adapters {
+		if !adapter.Online {
+			continue
+		}
+		if !found || adapter.MaxPower > primary.MaxPower {
+			primary = adapter
+			found = true
+		}
+	}
+	return primary, found
+}
+
+// chargerRatings are common charger power ratings in W
+var chargerRatings = []float64{18, 20, 30, 45, 60, 65, 90, 100, 140, 180, 240}
+
+// MinUnderpoweredDeficit is the smallest loss in mW on AC power that counts
+// as an underpowered charger, so the quantized capacity drift of a battery
+// held at a charge limit doesn't
+const MinUnderpoweredDeficit = 500.0
+
+// PowerBalance compares the external power supply against the system draw
+type PowerBalance struct {
+	// Underpowered is true when the batteries lose energy despite external power
+	Underpowered bool
+
+	// Deficit is the power in mW the batteries supply on top of the charger
+	Deficit float64
+
+	// Required is the estimated charger power in mW needed to cover the draw
+	// (0 if it cannot be estimated)
+	Required float64
+}
+
+// RecommendedWatts rounds the required power up to a common charger rating in W
+func (b PowerBalance) RecommendedWatts() float64 {
+	if b.Required <= 0 {
+		return 0
+	}
+
+	watts := b.Required / 1000.0
+	for _, rating := range chargerRatings {
+		if rating >= watts {
+			return rating
+		}
+	}
+	return math.Ceil(watts)
+}
+
+// Balance assesses whether the external power covers the system draw. A
+// charger is underpowered when the batteries discharge or their stored energy
+// decreases by at least MinUnderpoweredDeficit while on AC power. measuredDraw is the average on-battery draw in
+// mW, used to estimate the required power when the adapter rating is unknown.
+func (p PowerSource) Balance(infos []*Info, measuredDraw float64) PowerBalance {
+	if !p.OnAC {
+		return PowerBalance{}
+	}
+
+	deficit := 0.0
+	for _, info := range infos {
+		switch state := info.State.Base(); {
+		case state == StateDischarging && info.ChargeRate < 0:
+			deficit += -info.ChargeRate
+		case (state == StateCharging || state == StateNotCharging) && info.NetChargeRate < 0:
+			deficit += -info.NetChargeRate
+		}
+	}
+	if deficit < MinUnderpoweredDeficit {
+		return PowerBalance{}
+	}
+
+	balance := PowerBalance{Underpowered: true, Deficit: deficit}
+	if supply := p.MaxPower(); supply > 0 {
+		balance.Required = supply + deficit
+	} else if measuredDraw > deficit {
+		balance.Required = measuredDraw
+	}
+	return balance
+}
+
+// String returns a short description of the power source
+func (p PowerSource) String() string {
+	if p.OnAC {
+		return "On AC power"
+	}
+	return "On battery"
+}
+
+// inferPowerSource guesses the power source from battery states when no
+// adapter information is available: any discharging battery means battery power
+func inferPowerSource(infos []*Info) PowerSource {
+	for _, info := range infos {
+		if info.State.Base() == StateDischarging {
+			return PowerSource{OnAC: false}
+		}
+	}
+	return PowerSource{OnAC: true}
+}
@@ -0,0 +1,125 @@
+package battery
+
+import "testing"
+
+func TestPowerSourceBalance(t *testing.T) {
+	charger := func(online bool, maxPower float64) ACAdapter {
+		return ACAdapter{Name: "AC", Online: online, MaxPower: maxPower}
+	}
+
+	tests := []struct {
+		name         string
+		source       PowerSource
+		infos        []*Info
+		measuredDraw float64
+		want         PowerBalance
+		watts        float64
+	}{
+		{
+			name:   "on battery",
+			source: PowerSource{OnAC: false, Adapters: []ACAdapter{charger(false, 65000)}},
+			infos:  []*Info{{State: StateDischarging, ChargeRate: -12000}},
+			want:   PowerBalance{},
+		},
+		{
+			name:   "charging",
+			source: PowerSource{OnAC: true, Adapters: []ACAdapter{charger(true, 65000)}},
+			infos:  []*Info{{State: StateCharging, ChargeRate: 30000, NetChargeRate: 30000}},
+			want:   PowerBalance{},
+		},
+		{
+			name:   "discharging on AC",
+			source: PowerSource{OnAC: true, Adapters: []ACAdapter{charger(true, 45000)}},
+			infos:  []*Info{{State: StateDischarging, ChargeRate: -8000}},
+			want:   PowerBalance{Underpowered: true, Deficit: 8000, Required: 53000},
+			watts:  60,
+		},
+		{
+			name:   "charging state losing energy",
+			source: PowerSource{OnAC: true, Adapters: []ACAdapter{charger(true, 30000)}},
+			infos:  []*Info{{State: StateCharging, ChargeRate: 500, NetChargeRate: -4000}},
+			want:   PowerBalance{Underpowered: true, Deficit: 4000, Required: 34000},
+			watts:  45,
+		},
+		{
+			name:   "not charging losing energy",
+			source: PowerSource{OnAC: true, Adapters: []ACAdapter{charger(true, 60000)}},
+			infos:  []*Info{{State: StateNotCharging, NetChargeRate: -2000}},
+			want:   PowerBalance{Underpowered: true, Deficit: 2000, Required: 62000},
+			watts:  65,
+		},
+		{
+			name:   "drift at a charge limit",
+			source: PowerSource{OnAC: true, Adapters: []ACAdapter{charger(true, 65000)}},
+			infos:  []*Info{{State: StateNotCharging, NetChargeRate: -40}},
+			want:   PowerBalance{},
+		},
+		{
+			name:   "drift of two batteries below the minimum",
+			source: PowerSource{OnAC: true, Adapters: []ACAdapter{charger(true, 65000)}},
+			infos: []*Info{
+				{State: StateNotCharging, NetChargeRate: -200},
+				{State: StateCharging, ChargeRate: 100, NetChargeRate: -250},
+			},
+			want: PowerBalance{},
+		},
+		{
+			name:   "deficit at the minimum",
+			source: PowerSource{OnAC: true, Adapters: []ACAdapter{charger(true, 65000)}},
+			infos:  []*Info{{State: StateNotCharging, NetChargeRate: -MinUnderpoweredDeficit}},
+			want:   PowerBalance{Underpowered: true, Deficit: MinUnderpoweredDeficit, Required: 65000 + MinUnderpoweredDeficit},
+			watts:  90,
+		},
+		{
+			name:   "two batteries",
+			source: PowerSource{OnAC: true, Adapters: []ACAdapter{charger(true, 65000)}},
+			infos: []*Info{
+				{State: StateDischarging, ChargeRate: -5000},
+				{State: StateCharging, ChargeRate: 0, NetChargeRate: -3000},
+			},
+			want:  PowerBalance{Underpowered: true, Deficit: 8000, Required: 73000},
+			watts: 90,
+		},
+		{
+			name:   "offline adapters are not counted",
+			source: PowerSource{OnAC: true, Adapters: []ACAdapter{charger(true, 20000), charger(false, 100000)}},
+			infos:  []*Info{{State: StateDischarging, ChargeRate: -2000}},
+			want:   PowerBalance{Underpowered: true, Deficit: 2000, Required: 22000},
+			watts:  30,
+		},
+		{
+			name:         "unknown rating from the measured draw",
+			source:       PowerSource{OnAC: true},
+			infos:        []*Info{{State: StateDischarging, ChargeRate: -6000}},
+			measuredDraw: 25000,
+			want:         PowerBalance{Underpowered: true, Deficit: 6000, Required: 25000},
+			watts:        30,
+		},
+		{
+			name:         "unknown rating and draw",
+			source:       PowerSource{OnAC: true},
+			infos:        []*Info{{State: StateDischarging, ChargeRate: -6000}},
+			measuredDraw: 4000,
+			want:         PowerBalance{Underpowered: true, Deficit: 6000},
+		},
+		{
+			name:   "beyond the largest rating",
+			source: PowerSource{OnAC: true, Adapters: []ACAdapter{charger(true, 240000)}},
+			infos:  []*Info{{State: StateDischarging, ChargeRate: -10500}},
+			want:   PowerBalance{Underpowered: true, Deficit: 10500, Required: 250500},
+			watts:  251,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.source.Balance(tt.infos, tt.measuredDraw)
+			if got != tt.want {
+				t.Fatalf("balance %+v, want %+v", got, tt.want)
+			}
+			if watts := got.RecommendedWatts(); watts != tt.watts {
+				t.Errorf("recommended %.0f W, want %.0f W", watts, tt.watts)
+			}
+		})
+	}
+}
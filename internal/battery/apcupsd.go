@@ -0,0 +1,184 @@
+package battery
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// apcupsd network information server (NIS) defaults
+const (
+	// APCUPSDDefaultPort is the port the apcupsd NIS listens on
+	APCUPSDDefaultPort = "3551"
+
+	// APCUPSDTimeout limits each exchange with apcupsd
+	APCUPSDTimeout = 5 * time.Second
+)
+
+// APCUPSDSource reads an APC UPS from the apcupsd network information server
+type APCUPSDSource struct {
+	snapshot
+	addr string
+}
+
+// NewAPCUPSDSource creates a source for an apcupsd://host[:port] URL
+func NewAPCUPSDSource(rawURL string) (*APCUPSDSource, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "apcupsd" || u.Hostname() == "" {
+		return nil, fmt.Errorf("expected apcupsd://host[:port]")
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = APCUPSDDefaultPort
+	}
+
+	return &APCUPSDSource{addr: net.JoinHostPort(u.Hostname(), port)}, nil
+}
+
+// Update reads the status of the UPS from apcupsd
+func (s *APCUPSDSource) Update() error {
+	status, err := s.read()
+	if err != nil {
+		return s.setLastError(err)
+	}
+
+	infos := []*Info{apcupsdInfo(0, status, time.Now())}
+	s.set(infos, upsPowerSource(infos))
+	return nil
+}
+
+// read sends the status command over a fresh connection and returns the
+// reported fields
+func (s *APCUPSDSource) read() (map[string]string, error) {
+	conn, err := net.DialTimeout("tcp", s.addr, APCUPSDTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach apcupsd at %s: %w", s.addr, err)
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(APCUPSDTimeout)); err != nil {
+		return nil, err
+	}
+
+	if err := writeNISRecord(conn, "status"); err != nil {
+		return nil, err
+	}
+
+	status := make(map[string]string)
+	for {
+		record, err := readNISRecord(conn)
+		if err != nil {
+			return nil, fmt.Errorf("apcupsd closed the connection: %w", err)
+		}
+		if record == "" {
+			break
+		}
+		// Records look like "BCHARGE  : 100.0 Percent"
+		key, value, ok := strings.Cut(record, ":")
+		if ok {
+			status[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	if len(status) == 0 {
+		return nil, fmt.Errorf("apcupsd returned no status")
+	}
+	return status, nil
+}
+
+// writeNISRecord writes a record prefixed by its big-endian 16-bit length
+func writeNISRecord(w io.Writer, record string) error {
+	buf := make([]byte, 2+len(record))
+	binary.BigEndian.PutUint16(buf, uint16(len(record)))
+	copy(buf[2:], record)
+	_, err := w.Write(buf)
+	return err
+}
+
+// readNISRecord reads one length-prefixed record; an empty record ends the reply
+func readNISRecord(r io.Reader) (string, error) {
+	var size [2]byte
+	if _, err := io.ReadFull(r, size[:]); err != nil {
+		return "", err
+	}
+	buf := make([]byte, binary.BigEndian.Uint16(size[:]))
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(buf), "\n"), nil
+}
+
+// apcupsdInfo maps the apcupsd status fields of a UPS to battery information
+func apcupsdInfo(index int, status map[string]string, now time.Time) *Info {
+	// Values carry a unit suffix, e.g. "12.0 Minutes" or "27.5 C"
+	number := func(key string) (float64, bool) {
+		fields := strings.Fields(status[key])
+		if len(fields) == 0 {
+			return 0, false
+		}
+		v, err := strconv.ParseFloat(fields[0], 64)
+		return v, err == nil
+	}
+
+	info := &Info{
+		Index:        index,
+		Model:        status["MODEL"],
+		Manufacturer: "APC",
+		Serial:       status["SERIALNO"],
+		Capabilities: Capabilities{HasExtendedStats: true},
+		UpdatedAt:    now,
+	}
+	info.Voltage, _ = number("BATTV")
+	info.DesignVoltage, _ = number("NOMBATTV")
+	if temp, ok := number("ITEMP"); ok {
+		info.Temperature = temp
+		info.Capabilities.HasTemperature = true
+	}
+
+	charge, _ := number("BCHARGE")
+	info.State = apcupsdState(strings.Fields(status["STATUS"]), charge)
+
+	// TIMELEFT is in minutes and the load a percentage of the nominal power
+	minutes, _ := number("TIMELEFT")
+	percent, _ := number("LOADPCT")
+	nominal, _ := number("NOMPOWER")
+	setUPSEnergy(info, charge, minutes*60, percent/100*nominal*1000)
+
+	return info
+}
+
+// apcupsdState maps STATUS flags (e.g., "ONLINE", "ONBATT LOWBATT") to a battery state
+func apcupsdState(status []string, charge float64) State {
+	flags := make(map[string]bool, len(status))
+	for _, flag := range status {
+		flags[flag] = true
+	}
+
+	switch {
+	case flags["NOBATT"]:
+		return StateNotPresent
+	case flags["CAL"]:
+		return StateCalibrating
+	case flags["ONBATT"]:
+		return StateOnBattery
+	case flags["CHARGING"]:
+		return StateCharging
+	case flags["BOOST"]:
+		return StateBoost
+	case flags["TRIM"]:
+		return StateTrim
+	case flags["ONLINE"] && charge >= 100:
+		return StateFull
+	case flags["ONLINE"]:
+		return StateNotCharging
+	default:
+		return StateUnknown
+	}
+}
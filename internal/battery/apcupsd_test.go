@@ -0,0 +1,208 @@
+package battery
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func FuzzReadNISRecord(f *testing.F) {
+	var reply bytes.Buffer
+	for _, record := range []string{"STATUS   : ONLINE \n", "BCHARGE  : 100.0 Percent\n", ""} {
+		if err := writeNISRecord(&reply, record); err != nil {
+			f.Fatal(err)
+		}
+	}
+	f.Add(reply.Bytes())
+	f.Add([]byte{0x00})
+	f.Add([]byte{0xff, 0xff, 'x'})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		r := bytes.NewReader(data)
+		for {
+			record, err := readNISRecord(r)
+			if err != nil || record == "" {
+				return
+			}
+		}
+	})
+}
+
+func FuzzAPCUPSDInfo(f *testing.F) {
+	f.Add("ONLINE", "100.0 Percent", "30.0 Minutes", "25.0 Percent", "600 Watts")
+	f.Add("ONBATT LOWBATT", "12.5 Percent", "2.1 Minutes", "80.0 Percent", "")
+	f.Add("COMMLOST", "", "", "", "")
+	f.Add("ONBATT", "NaN", "-Inf", "1e308", "1e308")
+
+	f.Fuzz(func(t *testing.T, status, charge, timeLeft, load, nominal string) {
+		info := apcupsdInfo(0, map[string]string{
+			"STATUS":   status,
+			"BCHARGE":  charge,
+			"TIMELEFT": timeLeft,
+			"LOADPCT":  load,
+			"NOMPOWER": nominal,
+		}, time.Now())
+		_ = info.ChargePercent()
+		_ = info.TimeToEmpty()
+	})
+}
+
+func TestAPCUPSDInfo(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		apcaccess string
+		state     State
+		current   float64
+		full      float64
+		rate      float64
+		tte       time.Duration
+	}{
+		{
+			name: "on battery",
+			apcaccess: `STATUS   : ONBATT
+BCHARGE  : 80.0 Percent
+TIMELEFT : 20.0 Minutes
+LOADPCT  : 25.0 Percent
+NOMPOWER : 600 Watts
+BATTV    : 26.8 Volts
+NOMBATTV : 24.0 Volts
+ITEMP    : 29.2 C
+MODEL    : Back-UPS RS 1500G
+SERIALNO : 3B1234X56789`,
+			state:   StateOnBattery,
+			current: 50000,
+			full:    62500,
+			rate:    -150000,
+			tte:     20 * time.Minute,
+		},
+		{
+			name: "charging",
+			apcaccess: `STATUS   : ONLINE CHARGING
+BCHARGE  : 60.0 Percent
+TIMELEFT : 40.0 Minutes
+LOADPCT  : 20.0 Percent
+NOMPOWER : 900 Watts`,
+			state:   StateCharging,
+			current: 120000,
+			full:    200000,
+		},
+		{
+			name: "full",
+			apcaccess: `STATUS   : ONLINE
+BCHARGE  : 100.0 Percent`,
+			state:   StateFull,
+			current: 100000,
+			full:    100000,
+		},
+		{
+			name: "low battery",
+			apcaccess: `STATUS   : ONBATT LOWBATT
+BCHARGE  : 10.0 Percent
+TIMELEFT : 2.0 Minutes
+LOADPCT  : 50.0 Percent
+NOMPOWER : 600 Watts`,
+			state:   StateOnBattery,
+			current: 10000,
+			full:    100000,
+			rate:    -300000,
+			tte:     2 * time.Minute,
+		},
+		{
+			name: "no battery",
+			apcaccess: `STATUS   : ONLINE NOBATT
+BCHARGE  : 0.0 Percent`,
+			state:   StateNotPresent,
+			current: 0,
+			full:    100000,
+		},
+		{
+			name:      "communication lost",
+			apcaccess: `STATUS   : COMMLOST`,
+			state:     StateUnknown,
+			current:   0,
+			full:      100000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := apcupsdInfo(0, apcaccessStatus(tt.apcaccess), now)
+			if info.State != tt.state {
+				t.Errorf("state %s, want %s", info.State, tt.state)
+			}
+			if info.Current != tt.current || info.Full != tt.full || info.Design != tt.full {
+				t.Errorf("capacity %.0f/%.0f/%.0f mWh, want %.0f/%.0f mWh", info.Current, info.Full, info.Design, tt.current, tt.full)
+			}
+			if info.ChargeRate != tt.rate || info.SmoothedChargeRate != tt.rate {
+				t.Errorf("rate %.0f mW, want %.0f mW", info.ChargeRate, tt.rate)
+			}
+			if got := info.TimeToEmpty(); got != tt.tte {
+				t.Errorf("time to empty %v, want %v", got, tt.tte)
+			}
+		})
+	}
+
+	info := apcupsdInfo(0, apcaccessStatus(tests[0].apcaccess), now)
+	if info.Manufacturer != "APC" || info.Model != "Back-UPS RS 1500G" || info.Serial != "3B1234X56789" {
+		t.Errorf("identity %q %q %q", info.Manufacturer, info.Model, info.Serial)
+	}
+	if info.Voltage != 26.8 || info.DesignVoltage != 24 || !info.Capabilities.HasTemperature || info.Temperature != 29.2 {
+		t.Errorf("voltage %.1f/%.1f V, temperature %.1f °C (%t)", info.Voltage, info.DesignVoltage, info.Temperature, info.Capabilities.HasTemperature)
+	}
+}
+
+func TestAPCUPSDSourceUpdate(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	// An apcupsd answering the status command with one record per line
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		if command, err := readNISRecord(conn); err != nil || command != "status" {
+			return
+		}
+		for _, line := range []string{"STATUS   : ONBATT \n", "BCHARGE  : 50.0 Percent\n", "TIMELEFT : 15.0 Minutes\n",
+			"LOADPCT  : 40.0 Percent\n", "NOMPOWER : 500 Watts\n", ""} {
+			writeNISRecord(conn, line)
+		}
+	}()
+
+	source, err := NewAPCUPSDSource("apcupsd://" + listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := source.Update(); err != nil {
+		t.Fatal(err)
+	}
+	info, err := source.Get(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.State != StateOnBattery || info.ChargePercent() != 50 || info.TimeToEmpty() != 15*time.Minute {
+		t.Errorf("UPS %s at %.0f%%, %v left", info.State, info.ChargePercent(), info.TimeToEmpty())
+	}
+	if power := source.PowerSource(); power.OnAC || !power.Detected {
+		t.Errorf("power source %+v", power)
+	}
+}
+
+// apcaccessStatus parses the "KEY : value" lines printed by apcaccess
+func apcaccessStatus(output string) map[string]string {
+	status := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		if key, value, ok := strings.Cut(line, ":"); ok {
+			status[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return status
+}
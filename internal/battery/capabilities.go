@@ -0,0 +1,72 @@
+package battery
+
+import "strings"
+
+// Capabilities describes which optional battery data the platform can provide.
+// The UI uses it to hide unsupported widgets, and diagnostics use it to explain
+// what is missing on the current machine.
+type Capabilities struct {
+	// HasExtendedStats is true when the platform reader provides any data
+	// beyond what distatus/battery reports (cycles, identity, technology)
+	HasExtendedStats bool `json:"has_extended_stats"`
+
+	// HasTemperature is true when the battery temperature is reported
+	HasTemperature bool `json:"has_temperature"`
+
+	// HasCycles is true when the charge cycle count is reported
+	HasCycles bool `json:"has_cycles"`
+
+	// HasCellVoltages is true when individual cell voltages are reported
+	HasCellVoltages bool `json:"has_cell_voltages"`
+
+	// CanSetThreshold is true when the charge stop threshold can be configured
+	CanSetThreshold bool `json:"can_set_threshold"`
+
+	// HasACAdapters is true when external power supplies can be detected
+	HasACAdapters bool `json:"has_ac_adapters"`
+}
+
+// capability pairs a human-readable name with its availability
+type capability struct {
+	Name      string
+	Available bool
+}
+
+// list returns all capabilities in display order
+func (c Capabilities) list() []capability {
+	return []capability{
+		{"extended stats", c.HasExtendedStats},
+		{"temperature", c.HasTemperature},
+		{"cycles", c.HasCycles},
+		{"cell voltages", c.HasCellVoltages},
+		{"charge threshold", c.CanSetThreshold},
+		{"AC adapters", c.HasACAdapters},
+	}
+}
+
+// Supported returns the names of the available capabilities
+func (c Capabilities) Supported() []string {
+	names := make([]string, 0)
+	for _, item := range c.list() {
+		if item.Available {
+			names = append(names, item.Name)
+		}
+	}
+	return names
+}
+
+// Missing returns the names of the unavailable capabilities
+func (c Capabilities) Missing() []string {
+	names := make([]string, 0)
+	for _, item := range c.list() {
+		if !item.Available {
+			names = append(names, item.Name)
+		}
+	}
+	return names
+}
+
+// String returns a short summary of the capabilities
+func (c Capabilities) String() string {
+	return "has [" + strings.Join(c.Supported(), ", ") + "], missing [" + strings.Join(c.Missing(), ", ") + "]"
+}
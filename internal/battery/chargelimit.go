@@ -0,0 +1,81 @@
+package battery
+
+import (
+	"bufio"
+	"strconv"
+	"strings"
+)
+
+// Vendor charge limit modes
+const (
+	// ChargeLimitConservation is Lenovo IdeaPad conservation mode
+	ChargeLimitConservation = "conservation mode"
+
+	// ChargeLimitThreshold is a charge stop threshold (ThinkPad, ASUS, and
+	// other laptops exposing charge_control_end_threshold)
+	ChargeLimitThreshold = "charge threshold"
+
+	// ChargeLimitPrimarilyAC is Dell "Primarily AC use" charging
+	ChargeLimitPrimarilyAC = "primarily AC use"
+
+	// ChargeLimitCustom is Dell custom charging with a start and stop level
+	ChargeLimitCustom = "custom charging"
+)
+
+// ConservationLimit is the level IdeaPad conservation mode holds the charge at
+const ConservationLimit = 60.0
+
+// DellPrimarilyACLimit is the level Dell "Primarily AC use" stops charging at
+const DellPrimarilyACLimit = 80.0
+
+// ChargeLimit describes a vendor setting that stops charging below 100% to
+// extend the battery life
+type ChargeLimit struct {
+	// Percent is the charge level at which charging stops
+	Percent float64 `json:"percent"`
+
+	// Mode names the vendor setting (e.g., "conservation mode")
+	Mode string `json:"mode"`
+}
+
+// parseDellChargingConfig parses the output of
+// `smbios-battery-ctl --get-charging-cfg`:
+//
+//	Charging mode: custom
+//	Charging interval: (50, 80)
+//
+// It returns nil for modes that charge to 100% (standard, express, adaptive).
+func parseDellChargingConfig(out string) *ChargeLimit {
+	var mode string
+	var stop float64
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "Charging mode":
+			mode = strings.ToLower(value)
+		case "Charging interval":
+			_, end, ok := strings.Cut(strings.Trim(value, "()"), ",")
+			if !ok {
+				continue
+			}
+			if v, err := strconv.ParseFloat(strings.TrimSpace(end), 64); err == nil && v > 0 && v < 100 {
+				stop = v
+			}
+		}
+	}
+
+	switch mode {
+	case "primarily ac":
+		return &ChargeLimit{Percent: DellPrimarilyACLimit, Mode: ChargeLimitPrimarilyAC}
+	case "custom":
+		if stop > 0 {
+			return &ChargeLimit{Percent: stop, Mode: ChargeLimitCustom}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package battery
+
+import "testing"
+
+func FuzzDellChargingConfig(f *testing.F) {
+	for _, seed := range []string{
+		"Charging mode: custom\nCharging interval: (50, 80)\n",
+		"Charging mode: primarily ac\n",
+		"Charging mode: adaptive\nCharging interval: (50, 100)\n",
+		"Charging mode: custom\nCharging interval: (50)\n",
+		"Charging interval: (, NaN)",
+		"",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, out string) {
+		limit := parseDellChargingConfig(out)
+		if limit != nil && (limit.Percent <= 0 || limit.Percent >= 100) {
+			t.Errorf("parseDellChargingConfig(%q) = %.1f%%, want a limit below 100%%", out, limit.Percent)
+		}
+	})
+}
@@ -0,0 +1,112 @@
+package battery
+
+import (
+	"math"
+	"strings"
+)
+
+// Chemistry describes the cells of a battery technology
+type Chemistry struct {
+	// Name is the display name of the chemistry
+	Name string
+
+	// NominalCellVoltage is the average cell voltage during discharge in V
+	NominalCellVoltage float64
+
+	// MaxCellVoltage is the cell voltage when fully charged in V
+	MaxCellVoltage float64
+
+	// MinCellVoltage is the cell voltage when empty in V
+	MinCellVoltage float64
+}
+
+// chemistries maps lowercase technology names, as reported by the platform
+// or vendor tools, to their cell chemistry
+var chemistries = map[string]Chemistry{
+	"li-ion":  {Name: "Lithium-ion", NominalCellVoltage: 3.7, MaxCellVoltage: 4.2, MinCellVoltage: 3.0},
+	"lion":    {Name: "Lithium-ion", NominalCellVoltage: 3.7, MaxCellVoltage: 4.2, MinCellVoltage: 3.0},
+	"li-poly": {Name: "Lithium-polymer", NominalCellVoltage: 3.85, MaxCellVoltage: 4.4, MinCellVoltage: 3.0},
+	"lipo":    {Name: "Lithium-polymer", NominalCellVoltage: 3.85, MaxCellVoltage: 4.4, MinCellVoltage: 3.0},
+	"life":    {Name: "Lithium iron phosphate", NominalCellVoltage: 3.2, MaxCellVoltage: 3.65, MinCellVoltage: 2.5},
+	"limn":    {Name: "Lithium manganese oxide", NominalCellVoltage: 3.7, MaxCellVoltage: 4.2, MinCellVoltage: 2.5},
+	"nimh":    {Name: "Nickel-metal hydride", NominalCellVoltage: 1.2, MaxCellVoltage: 1.45, MinCellVoltage: 1.0},
+	"nicd":    {Name: "Nickel-cadmium", NominalCellVoltage: 1.2, MaxCellVoltage: 1.45, MinCellVoltage: 1.0},
+	"pbac":    {Name: "Lead-acid", NominalCellVoltage: 2.0, MaxCellVoltage: 2.4, MinCellVoltage: 1.75},
+}
+
+// ChemistryOf returns the cell chemistry of a technology name such as
+// "Li-ion" or "LION", ignoring case
+func ChemistryOf(technology string) (Chemistry, bool) {
+	chemistry, ok := chemistries[strings.ToLower(strings.TrimSpace(technology))]
+	return chemistry, ok
+}
+
+// Cell voltage margins of CellEstimate.Status
+const (
+	// CellLowMargin is how close above the empty cell voltage a cell is low, in V
+	CellLowMargin = 0.2
+	// CellHighMargin is how far above the full cell voltage a cell is overcharged, in V
+	CellHighMargin = 0.05
+)
+
+// CellStatus rates the voltage of a cell
+type CellStatus int
+
+const (
+	// CellNormal is a cell voltage within the chemistry's range
+	CellNormal CellStatus = iota
+	// CellLow is a cell voltage close to the empty voltage
+	CellLow
+	// CellUndervoltage is a cell voltage below the empty voltage, which damages the cell
+	CellUndervoltage
+	// CellOvervoltage is a cell voltage above the full voltage
+	CellOvervoltage
+)
+
+// CellEstimate is the estimated number of cells in series of a pack and the
+// average voltage of each
+type CellEstimate struct {
+	Cells     int
+	Voltage   float64
+	Chemistry Chemistry
+}
+
+// EstimateCells estimates the cells in series from the pack voltage and the
+// technology. The count comes from the design voltage when known, else from
+// the present voltage, picking the count that puts each cell within the
+// chemistry's range. It returns false for unknown chemistries and voltages.
+func EstimateCells(info *Info) (CellEstimate, bool) {
+	chemistry, ok := ChemistryOf(Coalesce(info.Chemistry, info.Technology))
+	if !ok || info.Voltage <= 0 {
+		return CellEstimate{}, false
+	}
+
+	cells := int(math.Round(info.DesignVoltage / chemistry.NominalCellVoltage))
+	if cells < 1 {
+		cells = max(int(math.Round(info.Voltage/chemistry.NominalCellVoltage)), 1)
+		// An empty or full pack can round to the wrong count; prefer a neighbor
+		// that puts the cells in range
+		for _, n := range []int{cells, cells + 1, cells - 1} {
+			if v := info.Voltage / float64(n); n >= 1 && v >= chemistry.MinCellVoltage && v <= chemistry.MaxCellVoltage {
+				cells = n
+				break
+			}
+		}
+	}
+
+	return CellEstimate{Cells: cells, Voltage: info.Voltage / float64(cells), Chemistry: chemistry}, true
+}
+
+// Status rates the cell voltage against the chemistry's range
+func (e CellEstimate) Status() CellStatus {
+	switch {
+	case e.Voltage < e.Chemistry.MinCellVoltage:
+		return CellUndervoltage
+	case e.Voltage > e.Chemistry.MaxCellVoltage+CellHighMargin:
+		return CellOvervoltage
+	case e.Voltage < e.Chemistry.MinCellVoltage+CellLowMargin:
+		return CellLow
+	default:
+		return CellNormal
+	}
+}
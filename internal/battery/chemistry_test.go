@@ -0,0 +1,43 @@
+package battery
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEstimateCells(t *testing.T) {
+	tests := []struct {
+		name       string
+		info       Info
+		wantCells  int
+		wantVolts  float64
+		wantStatus CellStatus
+	}{
+		{"design voltage", Info{Technology: "Li-ion", Voltage: 11.4, DesignVoltage: 11.1}, 3, 3.8, CellNormal},
+		{"vendor chemistry", Info{Technology: "Unknown", Chemistry: "LION", Voltage: 15.2, DesignVoltage: 14.8}, 4, 3.8, CellNormal},
+		{"empty pack without design voltage", Info{Technology: "Li-ion", Voltage: 9.0}, 3, 3.0, CellLow},
+		{"full pack without design voltage", Info{Technology: "Li-ion", Voltage: 12.6}, 3, 4.2, CellNormal},
+		{"undervoltage", Info{Technology: "Li-poly", Voltage: 8.4, DesignVoltage: 11.55}, 3, 2.8, CellUndervoltage},
+		{"overvoltage", Info{Technology: "LiFe", Voltage: 15.2, DesignVoltage: 12.8}, 4, 3.8, CellOvervoltage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			estimate, ok := EstimateCells(&tt.info)
+			if !ok {
+				t.Fatal("EstimateCells() found no estimate")
+			}
+			if estimate.Cells != tt.wantCells || math.Abs(estimate.Voltage-tt.wantVolts) > 0.005 {
+				t.Errorf("EstimateCells() = %d×%.2f V, want %d×%.2f V", estimate.Cells, estimate.Voltage, tt.wantCells, tt.wantVolts)
+			}
+			if got := estimate.Status(); got != tt.wantStatus {
+				t.Errorf("Status() = %d, want %d", got, tt.wantStatus)
+			}
+		})
+	}
+
+	for _, info := range []Info{{Technology: "Unknown", Voltage: 12}, {Technology: "Li-ion"}} {
+		if _, ok := EstimateCells(&info); ok {
+			t.Errorf("EstimateCells(%+v) found an estimate", info)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package battery
+
+import "math"
+
+// Variation thresholds of the charge rate, relative to the rate, that make
+// an estimate medium or low confidence
+const (
+	ConfidenceMediumVariation = 0.1
+	ConfidenceLowVariation    = 0.25
+)
+
+// Confidence rates how far an estimate can be trusted
+type Confidence int
+
+const (
+	// ConfidenceUnknown means the source doesn't allow rating the estimate
+	ConfidenceUnknown Confidence = iota
+	// ConfidenceLow marks shaky estimates (few samples or a fluctuating load)
+	ConfidenceLow
+	// ConfidenceMedium marks estimates that are roughly right
+	ConfidenceMedium
+	// ConfidenceHigh marks estimates from a steady load and enough history
+	ConfidenceHigh
+)
+
+// String returns string representation of the confidence
+func (c Confidence) String() string {
+	switch c {
+	case ConfidenceLow:
+		return "low"
+	case ConfidenceMedium:
+		return "medium"
+	case ConfidenceHigh:
+		return "high"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText encodes the confidence by name
+func (c Confidence) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText decodes a confidence name, falling back to ConfidenceUnknown
+func (c *Confidence) UnmarshalText(text []byte) error {
+	*c = ConfidenceUnknown
+	for _, confidence := range []Confidence{ConfidenceLow, ConfidenceMedium, ConfidenceHigh} {
+		if confidence.String() == string(text) {
+			*c = confidence
+		}
+	}
+	return nil
+}
+
+// Prefix returns the marker shown before an estimate: "~" for low, "≈" for
+// medium and none for high or unknown confidence
+func (c Confidence) Prefix() string {
+	switch c {
+	case ConfidenceLow:
+		return "~"
+	case ConfidenceMedium:
+		return "≈"
+	default:
+		return ""
+	}
+}
+
+// Mark prefixes a formatted estimate with the confidence marker
+func (c Confidence) Mark(estimate string) string {
+	return c.Prefix() + estimate
+}
+
+// RateConfidence rates an estimate derived from an averaged rate, from the
+// number of samples against the averaging window and the standard deviation
+// of the rate relative to the rate itself
+func RateConfidence(samples, window int, rate, deviation float64) Confidence {
+	if samples < 2 || rate == 0 {
+		return ConfidenceLow
+	}
+	variation := deviation / math.Abs(rate)
+	switch {
+	case samples < window/2 || variation > ConfidenceLowVariation:
+		return ConfidenceLow
+	case samples < window || variation > ConfidenceMediumVariation:
+		return ConfidenceMedium
+	default:
+		return ConfidenceHigh
+	}
+}
@@ -0,0 +1,42 @@
+package battery
+
+import "testing"
+
+func TestRateEstimatorConfidence(t *testing.T) {
+	tests := []struct {
+		name  string
+		rates []float64
+		want  Confidence
+	}{
+		{"first reading", []float64{-9000}, ConfidenceLow},
+		{"short history", []float64{-9000, -9000, -9000}, ConfidenceLow},
+		{"filling window", []float64{-9000, -9000, -9000, -9000, -9000, -9000, -9000}, ConfidenceMedium},
+		{"steady load", []float64{-9000, -9000, -9000, -9000, -9000, -9000, -9000, -9000, -9000, -9000, -9000, -9000}, ConfidenceHigh},
+		{"fluctuating load", []float64{-4000, -16000, -4000, -16000, -4000, -16000, -4000, -16000, -4000, -16000, -4000, -16000}, ConfidenceLow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			estimator := NewRateEstimator(10)
+			for _, rate := range tt.rates {
+				estimator.Add(rate, StateDischarging)
+			}
+			if got := estimator.Confidence(); got != tt.want {
+				t.Fatalf("confidence %s, want %s (deviation %.0f mW)", got, tt.want, estimator.Deviation())
+			}
+		})
+	}
+}
+
+func TestConfidenceText(t *testing.T) {
+	for _, c := range []Confidence{ConfidenceUnknown, ConfidenceLow, ConfidenceMedium, ConfidenceHigh} {
+		text, err := c.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded Confidence
+		if err := decoded.UnmarshalText(text); err != nil || decoded != c {
+			t.Fatalf("round trip of %s gave %s (%v)", c, decoded, err)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package battery
+
+import (
+	"errors"
+
+	"github.com/distatus/battery"
+)
+
+// distatusReader reads the batteries through the distatus/battery library,
+// which supports Linux, macOS, Windows and the BSDs
+type distatusReader struct{}
+
+// Name returns the reader description
+func (distatusReader) Name() string {
+	return "distatus/battery"
+}
+
+// Read reads all batteries. Slots the library reports as fatally failed,
+// such as empty bays, become nil readings.
+func (distatusReader) Read() ([]*Reading, []error, error) {
+	batteries, err := battery.GetAll()
+	return fromDistatus(batteries, err)
+}
+
+// fromDistatus converts the batteries and errors of battery.GetAll
+func fromDistatus(batteries []*battery.Battery, err error) ([]*Reading, []error, error) {
+	// Errors holds one entry per battery; entries for empty bays or
+	// unreadable batteries are kept as slots instead of failing the read
+	var slotErrors battery.Errors
+	if err != nil && !errors.As(err, &slotErrors) {
+		return nil, nil, err
+	}
+
+	readings := make([]*Reading, len(batteries))
+	errs := make([]error, len(batteries))
+	for i, bat := range batteries {
+		if i < len(slotErrors) {
+			errs[i] = slotErrors[i]
+		}
+		var fatal battery.ErrFatal
+		if bat == nil || errors.As(errs[i], &fatal) {
+			continue
+		}
+		readings[i] = &Reading{
+			State:         convertState(bat.State),
+			Current:       bat.Current,
+			Full:          bat.Full,
+			Design:        bat.Design,
+			ChargeRate:    bat.ChargeRate,
+			Voltage:       bat.Voltage,
+			DesignVoltage: bat.DesignVoltage,
+		}
+	}
+	return readings, errs, nil
+}
+
+// convertState converts distatus/battery state to our state
+func convertState(s battery.State) State {
+	switch s.String() {
+	case "Empty":
+		return StateEmpty
+	case "Full":
+		return StateFull
+	case "Charging":
+		return StateCharging
+	case "Discharging":
+		return StateDischarging
+	case "Idle", "Not charging":
+		return StateNotCharging
+	default:
+		return StateUnknown
+	}
+}
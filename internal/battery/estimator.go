@@ -0,0 +1,125 @@
+package battery
+
+import (
+	"math"
+	"time"
+)
+
+// DefaultSmoothingSamples is the default number of samples the rate estimator averages over
+const DefaultSmoothingSamples = 10
+
+// Charge trend window settings
+const (
+	// ChargeTrendWindow is how far back capacity readings are kept for the trend
+	ChargeTrendWindow = 2 * time.Minute
+
+	// ChargeTrendMinSpan is the minimum time span needed before a trend is reported
+	ChargeTrendMinSpan = 30 * time.Second
+)
+
+// RateEstimator smooths charge rate readings with an exponential moving
+// average so time estimates don't jump with every load spike
+type RateEstimator struct {
+	alpha    float64
+	window   int
+	rate     float64
+	variance float64
+	state    State
+	samples  int
+}
+
+// NewRateEstimator creates an estimator averaging over roughly the last n samples.
+// Values of n below 2 disable smoothing.
+func NewRateEstimator(n int) *RateEstimator {
+	alpha := 1.0
+	if n > 1 {
+		alpha = 2.0 / float64(n+1)
+	}
+	return &RateEstimator{alpha: alpha, window: n}
+}
+
+// Add feeds a new charge rate reading into the estimator. The average is
+// reset whenever the battery state changes, since charge and discharge
+// rates are unrelated.
+func (e *RateEstimator) Add(rate float64, state State) {
+	if e.samples == 0 || state != e.state {
+		e.rate = rate
+		e.variance = 0
+		e.state = state
+		e.samples = 1
+		return
+	}
+
+	// Exponentially weighted variance alongside the moving average
+	delta := rate - e.rate
+	e.rate += e.alpha * delta
+	e.variance = (1 - e.alpha) * (e.variance + e.alpha*delta*delta)
+	e.samples++
+}
+
+// Rate returns the smoothed charge rate in mW
+func (e *RateEstimator) Rate() float64 {
+	return e.rate
+}
+
+// Deviation returns the standard deviation of the readings around the smoothed rate in mW
+func (e *RateEstimator) Deviation() float64 {
+	return math.Sqrt(e.variance)
+}
+
+// Confidence rates estimates derived from the smoothed rate
+func (e *RateEstimator) Confidence() Confidence {
+	return RateConfidence(e.samples, e.window, e.rate, e.Deviation())
+}
+
+// Samples returns the number of readings since the last reset
+func (e *RateEstimator) Samples() int {
+	return e.samples
+}
+
+// trendSample is a single capacity reading
+type trendSample struct {
+	at      time.Time
+	current float64
+}
+
+// ChargeTrend estimates the net charge rate from how the stored energy changes
+// over time. Unlike ChargeRate it is signed correctly even when the platform
+// reports an unsigned rate, e.g. in the "Not charging" state.
+type ChargeTrend struct {
+	samples []trendSample
+}
+
+// NewChargeTrend creates an empty charge trend
+func NewChargeTrend() *ChargeTrend {
+	return &ChargeTrend{
+		samples: make([]trendSample, 0),
+	}
+}
+
+// Add records a capacity reading in mWh and drops readings outside the window
+func (t *ChargeTrend) Add(at time.Time, current float64) {
+	t.samples = append(t.samples, trendSample{at: at, current: current})
+
+	cutoff := at.Add(-ChargeTrendWindow)
+	drop := 0
+	for drop < len(t.samples)-1 && t.samples[drop].at.Before(cutoff) {
+		drop++
+	}
+	t.samples = t.samples[drop:]
+}
+
+// Rate returns the net charge rate in mW over the window (negative when the
+// stored energy decreases), or 0 when the window is too short
+func (t *ChargeTrend) Rate() float64 {
+	if len(t.samples) < 2 {
+		return 0
+	}
+
+	first, last := t.samples[0], t.samples[len(t.samples)-1]
+	span := last.at.Sub(first.at)
+	if span < ChargeTrendMinSpan {
+		return 0
+	}
+	return (last.current - first.current) / span.Hours()
+}
@@ -0,0 +1,83 @@
+package battery
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestRateEstimator(t *testing.T) {
+	type reading struct {
+		rate  float64
+		state State
+	}
+	tests := []struct {
+		name      string
+		n         int
+		readings  []reading
+		rate      float64
+		deviation float64
+		samples   int
+	}{
+		{"empty", 3, nil, 0, 0, 0},
+		{"first reading", 3, []reading{{-9000, StateDischarging}}, -9000, 0, 1},
+		{"moving average", 3, []reading{{1000, StateCharging}, {2000, StateCharging}, {3000, StateCharging}}, 2250, math.Sqrt(687500), 3},
+		{"steady", 10, []reading{{-5000, StateDischarging}, {-5000, StateDischarging}, {-5000, StateDischarging}}, -5000, 0, 3},
+		{"reset on state change", 3, []reading{{-9000, StateDischarging}, {-7000, StateDischarging}, {20000, StateCharging}}, 20000, 0, 1},
+		{"smoothing disabled", 1, []reading{{-9000, StateDischarging}, {-3000, StateDischarging}}, -3000, 0, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			estimator := NewRateEstimator(tt.n)
+			for _, r := range tt.readings {
+				estimator.Add(r.rate, r.state)
+			}
+			if got := estimator.Rate(); math.Abs(got-tt.rate) > 1e-9 {
+				t.Errorf("rate %.1f mW, want %.1f mW", got, tt.rate)
+			}
+			if got := estimator.Deviation(); math.Abs(got-tt.deviation) > 1e-9 {
+				t.Errorf("deviation %.1f mW, want %.1f mW", got, tt.deviation)
+			}
+			if got := estimator.Samples(); got != tt.samples {
+				t.Errorf("%d samples, want %d", got, tt.samples)
+			}
+		})
+	}
+}
+
+func TestChargeTrend(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	type reading struct {
+		after   time.Duration
+		current float64
+	}
+	tests := []struct {
+		name     string
+		readings []reading
+		rate     float64
+	}{
+		{"empty", nil, 0},
+		{"single reading", []reading{{0, 50000}}, 0},
+		{"span too short", []reading{{0, 50000}, {20 * time.Second, 49900}}, 0},
+		{"discharging", []reading{{0, 50000}, {30 * time.Second, 49900}, {time.Minute, 49800}}, -12000},
+		{"charging", []reading{{0, 50000}, {36 * time.Second, 50300}}, 30000},
+		{"flat", []reading{{0, 50000}, {time.Minute, 50000}}, 0},
+		// The first reading falls out of the two minute window
+		{"window", []reading{{0, 40000}, {time.Minute, 50000}, {3 * time.Minute, 49600}}, -12000},
+		// A pause longer than the window restarts the trend
+		{"after a pause", []reading{{0, 50000}, {10 * time.Minute, 48000}}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trend := NewChargeTrend()
+			for _, r := range tt.readings {
+				trend.Add(start.Add(r.after), r.current)
+			}
+			if got := trend.Rate(); math.Abs(got-tt.rate) > 1e-6 {
+				t.Errorf("rate %.1f mW, want %.1f mW", got, tt.rate)
+			}
+		})
+	}
+}
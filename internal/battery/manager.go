@@ -1,36 +1,60 @@
 package battery
 
 import (
-	"errors"
 	"fmt"
 	"log/slog"
 	"sync"
 	"time"
 
-	"github.com/distatus/battery"
 	pkgErrors "github.com/xsikor/go-battop/internal/errors"
 )
 
+// IdleDetector reports whether the user session is idle or locked
+type IdleDetector interface {
+	Idle() bool
+}
+
 // Manager manages battery information
 type Manager struct {
 	mu             sync.RWMutex
 	batteries      []*Info
+	powerSource    PowerSource
+	capabilities   map[int]Capabilities
+	estimators     map[int]*RateEstimator
+	trends         map[int]*ChargeTrend
+	smoothing      int
+	idleDetector   IdleDetector
 	lastError      error
 	platformReader PlatformReader
+	reader         Reader
+	now            func() time.Time
 }
 
-// NewManager creates a new battery manager
+// NewManager creates a battery manager reading the local batteries through
+// the distatus/battery library
 func NewManager() *Manager {
+	return NewReaderManager(distatusReader{})
+}
+
+// NewReaderManager creates a battery manager reading the batteries with the
+// given reader, enriched by the platform reader
+func NewReaderManager(reader Reader) *Manager {
 	return &Manager{
 		batteries:      make([]*Info, 0),
+		capabilities:   make(map[int]Capabilities),
+		estimators:     make(map[int]*RateEstimator),
+		trends:         make(map[int]*ChargeTrend),
+		smoothing:      DefaultSmoothingSamples,
 		platformReader: GetPlatformReader(),
+		reader:         reader,
+		now:            time.Now,
 	}
 }
 
 // Update updates battery information
 func (m *Manager) Update() error {
 	// ATTN: Early validation reduces nesting and improves readability
-	batteries, err := battery.GetAll()
+	batteries, slotErrors, err := m.reader.Read()
 	if err != nil {
 		return m.setLastError(fmt.Errorf("failed to get batteries: %w", err))
 	}
@@ -40,25 +64,41 @@ func (m *Manager) Update() error {
 	}
 
 	// Happy path: convert and update battery information
-	infos := m.convertBatteriesToInfo(batteries)
+	infos := m.convertBatteriesToInfo(batteries, slotErrors)
+	source := m.readPowerSource(infos)
 
 	m.mu.Lock()
 	m.batteries = infos
+	m.powerSource = source
 	m.lastError = nil
 	m.mu.Unlock()
 
 	return nil
 }
 
-// convertBatteriesToInfo converts battery.Battery objects to our Info structs
-func (m *Manager) convertBatteriesToInfo(batteries []*battery.Battery) []*Info {
+// convertBatteriesToInfo converts the raw readings to our Info structs.
+// slotErrors holds the per-battery errors of a partially failed read.
+func (m *Manager) convertBatteriesToInfo(batteries []*Reading, slotErrors []error) []*Info {
 	infos := make([]*Info, 0, len(batteries))
-	now := time.Now()
+	now := m.now()
+	idle := m.isIdle()
 
 	for i, bat := range batteries {
+		var slotErr error
+		if i < len(slotErrors) {
+			slotErr = slotErrors[i]
+		}
+		if bat == nil {
+			infos = append(infos, m.emptySlot(i, now, slotErr))
+			continue
+		}
+		if slotErr != nil {
+			slog.Debug("Battery read partially failed", "index", i, "error", slotErr)
+		}
+
 		info := &Info{
 			Index:         i,
-			State:         convertState(bat.State),
+			State:         bat.State,
 			Current:       bat.Current,
 			Full:          bat.Full,
 			Design:        bat.Design,
@@ -66,15 +106,23 @@ func (m *Manager) convertBatteriesToInfo(batteries []*battery.Battery) []*Info {
 			Voltage:       bat.Voltage,
 			DesignVoltage: bat.DesignVoltage,
 			UpdatedAt:     now,
-			Temperature:   0, // Not directly available in distatus/battery
+			Idle:          idle,
+			Temperature:   0, // Filled in from platform stats when available
 		}
 
 		// Enrich with platform-specific data
 		m.enrichBatteryWithPlatformStats(info, i)
 
-		// Ensure charge rate sign is correct
+		// Drop firmware sentinels and ensure charge rate sign is correct
+		sanitizeReading(info)
 		m.normalizeChargeRate(info)
 
+		// Feed the capacity trend and rate estimator, falling back to the
+		// trend when the firmware never reports a rate
+		m.trackChargeTrend(info)
+		fallbackChargeRate(info)
+		m.smoothChargeRate(info)
+
 		infos = append(infos, info)
 
 		// Log the update
@@ -84,6 +132,20 @@ func (m *Manager) convertBatteriesToInfo(batteries []*battery.Battery) []*Info {
 	return infos
 }
 
+// emptySlot returns the info of a battery slot without readings: an empty bay
+// when the platform reports no battery inserted, otherwise an unknown state
+func (m *Manager) emptySlot(index int, now time.Time, err error) *Info {
+	info := &Info{Index: index, State: StateUnknown, UpdatedAt: now}
+	if !m.platformReader.BatteryPresent(index) {
+		info.State = StateNotPresent
+		slog.Debug("Battery slot is empty", "index", index)
+		return info
+	}
+
+	slog.Warn("Failed to read battery", "index", index, "error", err)
+	return info
+}
+
 // GetAll returns all battery information
 func (m *Manager) GetAll() ([]*Info, error) {
 	m.mu.RLock()
@@ -122,6 +184,22 @@ func (m *Manager) Get(index int) (*Info, error) {
 	return &batCopy, nil
 }
 
+// PowerSource returns the current power source
+func (m *Manager) PowerSource() PowerSource {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Return a copy to prevent data races
+	source := m.powerSource
+	source.Adapters = append([]ACAdapter(nil), m.powerSource.Adapters...)
+	return source
+}
+
+// Capabilities returns the platform capabilities for a battery
+func (m *Manager) Capabilities(index int) Capabilities {
+	return m.capabilitiesFor(index)
+}
+
 // Count returns the number of batteries
 func (m *Manager) Count() int {
 	m.mu.RLock()
@@ -129,6 +207,11 @@ func (m *Manager) Count() int {
 	return len(m.batteries)
 }
 
+// PlatformName returns the name of the platform-specific reader in use
+func (m *Manager) PlatformName() string {
+	return m.platformReader.Name()
+}
+
 // setLastError sets the last error with proper locking
 func (m *Manager) setLastError(err error) error {
 	m.mu.Lock()
@@ -137,13 +220,135 @@ func (m *Manager) setLastError(err error) error {
 	return err
 }
 
+// readPowerSource reads AC adapters, falling back to battery states when
+// the platform doesn't expose them
+func (m *Manager) readPowerSource(infos []*Info) PowerSource {
+	if !m.capabilitiesFor(0).HasACAdapters {
+		return inferPowerSource(infos)
+	}
+
+	adapters, err := m.platformReader.ReadACAdapters()
+	if err != nil || len(adapters) == 0 {
+		if err != nil {
+			slog.Warn("Failed to read AC adapters", "error", err)
+		}
+		return inferPowerSource(infos)
+	}
+
+	source := PowerSource{Detected: true, Adapters: adapters}
+	for _, adapter := range adapters {
+		if adapter.Online {
+			source.OnAC = true
+			break
+		}
+	}
+
+	slog.Debug("Updated power source",
+		"on_ac", source.OnAC,
+		"adapters", len(adapters),
+		"max_power", source.MaxPower(),
+	)
+	return source
+}
+
+// SetSmoothing sets the number of samples the charge rate estimator averages over
+func (m *Manager) SetSmoothing(samples int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.smoothing = samples
+	m.estimators = make(map[int]*RateEstimator)
+}
+
+// SetIdleDetector sets the detector used to tag samples as idle or active
+func (m *Manager) SetIdleDetector(detector IdleDetector) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.idleDetector = detector
+}
+
+// isIdle reports whether the session is idle according to the idle detector
+func (m *Manager) isIdle() bool {
+	m.mu.RLock()
+	detector := m.idleDetector
+	m.mu.RUnlock()
+
+	return detector != nil && detector.Idle()
+}
+
+// smoothChargeRate updates the battery's rate estimator and stores the smoothed rate
+func (m *Manager) smoothChargeRate(info *Info) {
+	m.mu.Lock()
+	estimator, ok := m.estimators[info.Index]
+	if !ok {
+		estimator = NewRateEstimator(m.smoothing)
+		m.estimators[info.Index] = estimator
+	}
+	m.mu.Unlock()
+
+	estimator.Add(info.ChargeRate, info.State)
+	info.SmoothedChargeRate = estimator.Rate()
+	info.EstimateConfidence = estimator.Confidence()
+}
+
+// trackChargeTrend records the capacity reading and stores the net charge rate
+func (m *Manager) trackChargeTrend(info *Info) {
+	m.mu.Lock()
+	trend, ok := m.trends[info.Index]
+	if !ok {
+		trend = NewChargeTrend()
+		m.trends[info.Index] = trend
+	}
+	m.mu.Unlock()
+
+	trend.Add(info.UpdatedAt, info.Current)
+	info.NetChargeRate = trend.Rate()
+}
+
 // normalizeChargeRate ensures charge rate sign matches battery state
 func (m *Manager) normalizeChargeRate(info *Info) {
-	if info.State == StateDischarging && info.ChargeRate > 0 {
+	if info.State.Base() == StateDischarging && info.ChargeRate > 0 {
 		info.ChargeRate = -info.ChargeRate
 	}
 }
 
+// sanitizeReading drops readings known-bad firmwares report instead of an
+// unknown value and clamps a current charge above the last full capacity
+func sanitizeReading(info *Info) {
+	if info.ChargeRate == sentinel || info.ChargeRate == -sentinel {
+		info.ChargeRate = 0
+	}
+	if info.Voltage == sentinel/1e3 {
+		info.Voltage = 0
+	}
+	if info.DesignVoltage == sentinel/1e3 {
+		info.DesignVoltage = 0
+	}
+	if info.CycleCount == sentinel {
+		info.CycleCount = 0
+		info.Capabilities.HasCycles = false
+	}
+	if info.Temperature == sentinel/10.0 {
+		info.Temperature = 0
+		info.Capabilities.HasTemperature = false
+	}
+	if info.Full > 0 && info.Current > info.Full {
+		info.Current = info.Full
+	}
+}
+
+// fallbackChargeRate uses the net charge rate when the firmware reports no
+// rate while the battery is charging or discharging
+func fallbackChargeRate(info *Info) {
+	if info.ChargeRate != 0 || info.NetChargeRate == 0 {
+		return
+	}
+	if state := info.State.Base(); state == StateCharging && info.NetChargeRate > 0 ||
+		state == StateDischarging && info.NetChargeRate < 0 {
+		info.ChargeRate = info.NetChargeRate
+	}
+}
+
 // logBatteryUpdate logs battery update information
 func (m *Manager) logBatteryUpdate(info *Info, index int) {
 	slog.Debug("Updated battery info",
@@ -152,26 +357,24 @@ func (m *Manager) logBatteryUpdate(info *Info, index int) {
 		"current", info.Current,
 		"full", info.Full,
 		"charge_rate", info.ChargeRate,
+		"smoothed_charge_rate", info.SmoothedChargeRate,
 		"voltage", info.Voltage,
 	)
 }
 
 // enrichBatteryWithPlatformStats applies platform-specific stats to battery info
 func (m *Manager) enrichBatteryWithPlatformStats(info *Info, index int) {
+	info.Capabilities = m.capabilitiesFor(index)
+
+	// Set defaults if platform stats not available
+	info.Technology = "Li-ion"
+
+	if !info.Capabilities.HasExtendedStats {
+		return
+	}
+
 	platformStats, err := m.platformReader.ReadBatteryStats(index)
 	if err != nil {
-		// Set defaults if platform stats not available
-		info.Technology = "Li-ion"
-
-		// Log appropriately based on error type
-		if errors.Is(err, pkgErrors.ErrPlatformNotSupported) {
-			slog.Debug("Platform-specific stats not available",
-				"index", index,
-				"platform", "non-linux",
-			)
-			return
-		}
-
 		slog.Warn("Failed to read platform battery stats",
 			"index", index,
 			"error", err,
@@ -183,7 +386,7 @@ func (m *Manager) enrichBatteryWithPlatformStats(info *Info, index int) {
 	info.CycleCount = platformStats.CycleCount
 
 	// Set technology with default fallback
-	info.Technology = coalesce(platformStats.Technology, "Li-ion")
+	info.Technology = Coalesce(platformStats.Technology, "Li-ion")
 
 	// Set other fields if available
 	if platformStats.Manufacturer != "" {
@@ -195,10 +398,37 @@ func (m *Manager) enrichBatteryWithPlatformStats(info *Info, index int) {
 	if platformStats.SerialNumber != "" {
 		info.Serial = platformStats.SerialNumber
 	}
+	info.Chemistry = platformStats.Chemistry
+	info.Firmware = platformStats.FirmwareVersion
+	info.ManufactureDate = platformStats.ManufactureDate
+	info.ManufactureDateFromSerial = platformStats.ManufactureDateFromSerial
+	if info.Capabilities.HasTemperature {
+		info.Temperature = platformStats.Temperature
+	}
+	info.ChargeLimit = platformStats.ChargeLimit
 }
 
-// coalesce returns the first non-empty string
-func coalesce(values ...string) string {
+// capabilitiesFor returns the cached platform capabilities for a battery,
+// querying the platform reader the first time the battery is seen
+func (m *Manager) capabilitiesFor(index int) Capabilities {
+	m.mu.RLock()
+	caps, ok := m.capabilities[index]
+	m.mu.RUnlock()
+	if ok {
+		return caps
+	}
+
+	caps = m.platformReader.Capabilities(index)
+	slog.Debug("Detected battery capabilities", "index", index, "capabilities", caps.String())
+
+	m.mu.Lock()
+	m.capabilities[index] = caps
+	m.mu.Unlock()
+	return caps
+}
+
+// Coalesce returns the first non-empty string
+func Coalesce(values ...string) string {
 	for _, v := range values {
 		if v != "" {
 			return v
@@ -206,21 +436,3 @@ func coalesce(values ...string) string {
 	}
 	return ""
 }
-
-// convertState converts distatus/battery state to our state
-func convertState(s battery.State) State {
-	switch s.String() {
-	case "Empty":
-		return StateEmpty
-	case "Full":
-		return StateFull
-	case "Charging":
-		return StateCharging
-	case "Discharging":
-		return StateDischarging
-	case "Not charging":
-		return StateNotCharging
-	default:
-		return StateUnknown
-	}
-}
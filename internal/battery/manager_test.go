@@ -0,0 +1,41 @@
+package battery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/distatus/battery"
+)
+
+// readerFunc adapts a function to the Reader interface
+type readerFunc func() ([]*Reading, []error, error)
+
+func (f readerFunc) Read() ([]*Reading, []error, error) { return f() }
+func (f readerFunc) Name() string                       { return "test" }
+
+func TestManagerKeepsEmptyBay(t *testing.T) {
+	sim := NewSimulator(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC))
+	manager := NewSimulatedManager(sim)
+	manager.reader = readerFunc(func() ([]*Reading, []error, error) {
+		readings, _, _ := sim.Read()
+		bay, errs, err := fromDistatus([]*battery.Battery{{}}, battery.Errors{battery.ErrFatal{Err: battery.ErrNotFound}})
+		return append(readings, bay...), append([]error{nil}, errs...), err
+	})
+
+	if err := manager.Update(); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	infos, err := manager.GetAll()
+	if err != nil {
+		t.Fatalf("get all: %v", err)
+	}
+	if len(infos) != 2 {
+		t.Fatalf("got %d batteries, want the battery and the empty bay", len(infos))
+	}
+	if infos[0].State != StateDischarging {
+		t.Errorf("battery state = %s, want Discharging", infos[0].State)
+	}
+	if infos[1].State != StateNotPresent || infos[1].Index != 1 {
+		t.Errorf("bay = %s (index %d), want Not present (index 1)", infos[1].State, infos[1].Index)
+	}
+}
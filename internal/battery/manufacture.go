@@ -0,0 +1,67 @@
+package battery
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ManufactureDateLayout is the layout of manufacture dates
+const ManufactureDateLayout = time.DateOnly
+
+// Smart Battery Data packs the manufacture date into 16 bits as
+// (year-1980)*512 + month*32 + day
+const (
+	sbsEpochYear  = 1980
+	sbsYearShift  = 9
+	sbsMonthShift = 5
+)
+
+// serialDatePattern matches a date embedded in a serial number, e.g.
+// "2021/03/04" or "20210304", not surrounded by other digits
+var serialDatePattern = regexp.MustCompile(`(?:^|\D)((?:19|20)\d\d)[-/.]?(0[1-9]|1[0-2])[-/.]?(0[1-9]|[12]\d|3[01])(?:\D|$)`)
+
+// ManufactureDate formats a manufacture date, or returns "" for an invalid one
+func ManufactureDate(year, month, day int) string {
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	// time.Date normalizes out of range values, so a date that changed is invalid
+	if year < sbsEpochYear || date.Year() != year || int(date.Month()) != month || date.Day() != day {
+		return ""
+	}
+	return date.Format(ManufactureDateLayout)
+}
+
+// ParseManufactureDate parses a manufacture date attribute, either a date
+// such as "2021-03-04" or "2021/03/04", or a packed Smart Battery Data value
+func ParseManufactureDate(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if packed, err := strconv.Atoi(value); err == nil && packed > 0 && packed <= 0xffff {
+		if date := ManufactureDate(sbsEpochYear+packed>>sbsYearShift, packed>>sbsMonthShift&0xf, packed&0x1f); date != "" {
+			return date, nil
+		}
+	}
+	if date := dateIn(strings.ReplaceAll(value, " ", "")); date != "" {
+		return date, nil
+	}
+	return "", fmt.Errorf("invalid manufacture date %q", value)
+}
+
+// ManufactureDateFromSerial decodes a manufacture date some vendors embed in
+// the serial number, or returns "" when there is none
+func ManufactureDateFromSerial(serial string) string {
+	return dateIn(serial)
+}
+
+// dateIn returns the first valid date embedded in text, or ""
+func dateIn(text string) string {
+	match := serialDatePattern.FindStringSubmatch(text)
+	if match == nil {
+		return ""
+	}
+	year, _ := strconv.Atoi(match[1])
+	month, _ := strconv.Atoi(match[2])
+	day, _ := strconv.Atoi(match[3])
+	return ManufactureDate(year, month, day)
+}
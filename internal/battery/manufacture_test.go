@@ -0,0 +1,46 @@
+package battery
+
+import "testing"
+
+func TestParseManufactureDate(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"2021-03-04", "2021-03-04", false},
+		{"2021/03/04", "2021-03-04", false},
+		{" 20210304\n", "2021-03-04", false},
+		// Smart Battery Data: (2021-1980)*512 + 3*32 + 4
+		{"21092", "2021-03-04", false},
+		{"2021-02-30", "", true},
+		{"0", "", true},
+		{"unknown", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseManufactureDate(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseManufactureDate(%q) = %q, %v, want %q, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestManufactureDateFromSerial(t *testing.T) {
+	tests := []struct {
+		serial string
+		want   string
+	}{
+		{"SMP-2021/03/04-0815", "2021-03-04"},
+		{"X20190715", "2019-07-15"},
+		{"1234", ""},
+		{"SIM-0001", ""},
+		// Longer digit runs are serial numbers, not dates
+		{"1202103045", ""},
+		{"20211399", ""},
+	}
+	for _, tt := range tests {
+		if got := ManufactureDateFromSerial(tt.serial); got != tt.want {
+			t.Errorf("ManufactureDateFromSerial(%q) = %q, want %q", tt.serial, got, tt.want)
+		}
+	}
+}
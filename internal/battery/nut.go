@@ -0,0 +1,282 @@
+package battery
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	pkgErrors "github.com/xsikor/go-battop/internal/errors"
+)
+
+// NUT (Network UPS Tools) defaults
+const (
+	// NUTDefaultPort is the port upsd listens on
+	NUTDefaultPort = "3493"
+
+	// NUTTimeout limits each exchange with upsd
+	NUTTimeout = 5 * time.Second
+)
+
+// NUTSource reads UPS batteries from a NUT upsd server, so battop can
+// monitor UPSes as well as laptops
+type NUTSource struct {
+	snapshot
+	addr string
+	ups  string
+}
+
+// NewNUTSource creates a source for a nut://host[:port][/ups] URL; without a
+// UPS name every UPS known to upsd is shown
+func NewNUTSource(rawURL string) (*NUTSource, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "nut" || u.Hostname() == "" {
+		return nil, fmt.Errorf("expected nut://host[:port][/ups]")
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = NUTDefaultPort
+	}
+
+	return &NUTSource{
+		addr: net.JoinHostPort(u.Hostname(), port),
+		ups:  strings.Trim(u.Path, "/"),
+	}, nil
+}
+
+// Update reads the variables of the UPSes from upsd
+func (n *NUTSource) Update() error {
+	infos, err := n.read()
+	if err != nil {
+		return n.setLastError(err)
+	}
+	if len(infos) == 0 {
+		return n.setLastError(pkgErrors.ErrNoBatteries)
+	}
+
+	n.set(infos, upsPowerSource(infos))
+	return nil
+}
+
+// read queries upsd over a fresh connection
+func (n *NUTSource) read() ([]*Info, error) {
+	conn, err := net.DialTimeout("tcp", n.addr, NUTTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach upsd at %s: %w", n.addr, err)
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(NUTTimeout)); err != nil {
+		return nil, err
+	}
+
+	client := &nutClient{conn: conn, reader: bufio.NewReader(conn)}
+	defer client.command("LOGOUT")
+
+	names := []string{n.ups}
+	if n.ups == "" {
+		if names, err = client.list("UPS"); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	infos := make([]*Info, 0, len(names))
+	for i, name := range names {
+		vars, err := client.vars(name)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, nutInfo(i, vars, now))
+	}
+	return infos, nil
+}
+
+// nutInfo maps the NUT variables of a UPS to battery information
+func nutInfo(index int, vars map[string]string, now time.Time) *Info {
+	number := func(names ...string) (float64, bool) {
+		for _, name := range names {
+			if v, err := strconv.ParseFloat(vars[name], 64); err == nil {
+				return v, true
+			}
+		}
+		return 0, false
+	}
+	text := func(names ...string) string {
+		for _, name := range names {
+			if v := vars[name]; v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+
+	info := &Info{
+		Index:        index,
+		Technology:   text("battery.type"),
+		Model:        text("ups.model", "device.model"),
+		Manufacturer: text("ups.mfr", "device.mfr"),
+		Serial:       text("ups.serial", "device.serial"),
+		Capabilities: Capabilities{HasExtendedStats: true},
+		UpdatedAt:    now,
+	}
+	info.Voltage, _ = number("battery.voltage")
+	info.DesignVoltage, _ = number("battery.voltage.nominal")
+	if temp, ok := number("battery.temperature", "ups.temperature"); ok {
+		info.Temperature = temp
+		info.Capabilities.HasTemperature = true
+	}
+
+	charge, _ := number("battery.charge")
+	status := strings.Fields(vars["ups.status"])
+	info.State = nutState(status, charge)
+
+	// Load in mW from the real power, or the load percentage of the nominal power
+	load, ok := number("ups.realpower")
+	if !ok {
+		percent, _ := number("ups.load")
+		nominal, _ := number("ups.realpower.nominal")
+		load = percent / 100 * nominal
+	}
+	runtime, _ := number("battery.runtime")
+	setUPSEnergy(info, charge, runtime, load*1000)
+
+	return info
+}
+
+// nutState maps ups.status flags (e.g., "OL CHRG", "OB LB") to a battery state
+func nutState(status []string, charge float64) State {
+	flags := make(map[string]bool, len(status))
+	for _, flag := range status {
+		flags[flag] = true
+	}
+
+	switch {
+	case flags["CAL"]:
+		return StateCalibrating
+	case flags["OB"]:
+		return StateOnBattery
+	case flags["DISCHRG"]:
+		return StateDischarging
+	case flags["CHRG"]:
+		return StateCharging
+	case flags["BYPASS"]:
+		return StateBypass
+	case flags["BOOST"]:
+		return StateBoost
+	case flags["TRIM"]:
+		return StateTrim
+	case flags["OL"] && charge >= 100:
+		return StateFull
+	case flags["OL"]:
+		return StateNotCharging
+	default:
+		return StateUnknown
+	}
+}
+
+// nutClient speaks the line-based upsd protocol
+type nutClient struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// command sends a single command line
+func (c *nutClient) command(line string) error {
+	_, err := fmt.Fprintf(c.conn, "%s\r\n", line)
+	return err
+}
+
+// listLines sends LIST <query> and returns the fields of every item line
+func (c *nutClient) listLines(query string) ([][]string, error) {
+	if err := c.command("LIST " + query); err != nil {
+		return nil, err
+	}
+
+	var lines [][]string
+	for {
+		line, err := c.reader.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("upsd closed the connection: %w", err)
+		}
+		fields := splitNUTLine(strings.TrimSpace(line))
+		switch {
+		case len(fields) > 0 && fields[0] == "ERR":
+			return nil, fmt.Errorf("upsd: LIST %s: %s", query, strings.Join(fields[1:], " "))
+		case len(fields) > 0 && fields[0] == "BEGIN":
+		case len(fields) > 0 && fields[0] == "END":
+			return lines, nil
+		default:
+			lines = append(lines, fields)
+		}
+	}
+}
+
+// list returns the UPS names known to upsd
+func (c *nutClient) list(query string) ([]string, error) {
+	lines, err := c.listLines(query)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(lines))
+	for _, fields := range lines {
+		if len(fields) >= 2 {
+			names = append(names, fields[1])
+		}
+	}
+	return names, nil
+}
+
+// vars returns the variables of a UPS
+func (c *nutClient) vars(ups string) (map[string]string, error) {
+	lines, err := c.listLines("VAR " + ups)
+	if err != nil {
+		return nil, err
+	}
+	vars := make(map[string]string, len(lines))
+	for _, fields := range lines {
+		// VAR <ups> <name> "<value>"
+		if len(fields) >= 4 {
+			vars[fields[2]] = fields[3]
+		}
+	}
+	return vars, nil
+}
+
+// splitNUTLine splits a protocol line into words, unquoting quoted values
+func splitNUTLine(line string) []string {
+	var fields []string
+	var current strings.Builder
+	inQuotes, escaped, hasField := false, false, false
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			inQuotes = !inQuotes
+			hasField = true
+		case r == ' ' && !inQuotes:
+			if hasField || current.Len() > 0 {
+				fields = append(fields, current.String())
+				current.Reset()
+				hasField = false
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if hasField || current.Len() > 0 {
+		fields = append(fields, current.String())
+	}
+	return fields
+}
@@ -0,0 +1,225 @@
+package battery
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func FuzzSplitNUTLine(f *testing.F) {
+	for _, seed := range []string{
+		`VAR ups battery.charge "100"`,
+		`VAR ups ups.status "OL CHRG"`,
+		`VAR ups ups.model "Smart \"UPS\" 1500"`,
+		`ERR UNKNOWN-UPS`,
+		`"unterminated`,
+		`trailing\`,
+		``,
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, line string) {
+		for _, field := range splitNUTLine(line) {
+			if !strings.Contains(line, "\"") && field == "" {
+				t.Errorf("splitNUTLine(%q) returned an empty unquoted field", line)
+			}
+		}
+	})
+}
+
+func FuzzNUTInfo(f *testing.F) {
+	f.Add("100", "1800", "OL", "120", "", "")
+	f.Add("42.5", "600", "OB LB", "", "35", "900")
+	f.Add("0", "0", "OB", "0", "", "")
+	f.Add("NaN", "-1", "", "Inf", "1e308", "1e308")
+
+	f.Fuzz(func(t *testing.T, charge, runtime, status, power, load, nominal string) {
+		vars := map[string]string{
+			"battery.charge":        charge,
+			"battery.runtime":       runtime,
+			"ups.status":            status,
+			"ups.realpower":         power,
+			"ups.load":              load,
+			"ups.realpower.nominal": nominal,
+		}
+		info := nutInfo(0, vars, time.Now())
+		_ = info.ChargePercent()
+		_ = info.TimeToEmpty()
+	})
+}
+
+func TestNUTInfo(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		upsc    string
+		state   State
+		current float64
+		full    float64
+		rate    float64
+		tte     time.Duration
+	}{
+		{
+			name: "on battery with real power",
+			upsc: `battery.charge: 80
+battery.runtime: 1200
+battery.voltage: 26.8
+battery.voltage.nominal: 24.0
+battery.temperature: 29.2
+device.mfr: Eaton
+device.model: 5E 1100i
+ups.serial: G123A45678
+ups.realpower: 150
+ups.status: OB DISCHRG`,
+			state:   StateOnBattery,
+			current: 50000,
+			full:    62500,
+			rate:    -150000,
+			tte:     20 * time.Minute,
+		},
+		{
+			name: "charging with load percentage",
+			upsc: `battery.charge: 60
+battery.runtime: 2400
+ups.load: 20
+ups.realpower.nominal: 900
+ups.status: OL CHRG`,
+			state:   StateCharging,
+			current: 120000,
+			full:    200000,
+		},
+		{
+			name: "full without load",
+			upsc: `battery.charge: 100
+ups.status: OL`,
+			state:   StateFull,
+			current: 100000,
+			full:    100000,
+		},
+		{
+			name: "online below full",
+			upsc: `battery.charge: 95
+ups.status: OL`,
+			state:   StateNotCharging,
+			current: 95000,
+			full:    100000,
+		},
+		{
+			name: "low battery",
+			upsc: `battery.charge: 10
+battery.runtime: 120
+ups.realpower: 300
+ups.status: OB LB`,
+			state:   StateOnBattery,
+			current: 10000,
+			full:    100000,
+			rate:    -300000,
+			tte:     2 * time.Minute,
+		},
+		{
+			name:    "no status",
+			upsc:    `battery.charge: 50`,
+			state:   StateUnknown,
+			current: 50000,
+			full:    100000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := nutInfo(2, upscVars(tt.upsc), now)
+			if info.Index != 2 || !info.UpdatedAt.Equal(now) {
+				t.Errorf("index %d updated at %v", info.Index, info.UpdatedAt)
+			}
+			if info.State != tt.state {
+				t.Errorf("state %s, want %s", info.State, tt.state)
+			}
+			if info.Current != tt.current || info.Full != tt.full || info.Design != tt.full {
+				t.Errorf("capacity %.0f/%.0f/%.0f mWh, want %.0f/%.0f mWh", info.Current, info.Full, info.Design, tt.current, tt.full)
+			}
+			if info.ChargeRate != tt.rate || info.SmoothedChargeRate != tt.rate {
+				t.Errorf("rate %.0f mW, want %.0f mW", info.ChargeRate, tt.rate)
+			}
+			if got := info.TimeToEmpty(); got != tt.tte {
+				t.Errorf("time to empty %v, want %v", got, tt.tte)
+			}
+		})
+	}
+
+	info := nutInfo(0, upscVars(tests[0].upsc), now)
+	if info.Manufacturer != "Eaton" || info.Model != "5E 1100i" || info.Serial != "G123A45678" {
+		t.Errorf("identity %q %q %q", info.Manufacturer, info.Model, info.Serial)
+	}
+	if info.Voltage != 26.8 || info.DesignVoltage != 24 || !info.Capabilities.HasTemperature || info.Temperature != 29.2 {
+		t.Errorf("voltage %.1f/%.1f V, temperature %.1f °C (%t)", info.Voltage, info.DesignVoltage, info.Temperature, info.Capabilities.HasTemperature)
+	}
+}
+
+func TestNUTSourceUpdate(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	// A upsd with two UPSes answering one connection
+	replies := map[string]string{
+		"LIST UPS": "BEGIN LIST UPS\nUPS rack \"Rack UPS\"\nUPS desk \"Desk UPS\"\nEND LIST UPS\n",
+		"LIST VAR rack": "BEGIN LIST VAR rack\nVAR rack battery.charge \"100\"\nVAR rack ups.status \"OL\"\n" +
+			"VAR rack ups.model \"Smart \\\"UPS\\\" 1500\"\nEND LIST VAR rack\n",
+		"LIST VAR desk": "BEGIN LIST VAR desk\nVAR desk battery.charge \"50\"\nVAR desk battery.runtime \"900\"\n" +
+			"VAR desk ups.realpower \"200\"\nVAR desk ups.status \"OB DISCHRG\"\nEND LIST VAR desk\n",
+		"LOGOUT": "OK Goodbye\n",
+	}
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			reply, ok := replies[strings.TrimSpace(scanner.Text())]
+			if !ok {
+				reply = "ERR UNKNOWN-COMMAND\n"
+			}
+			conn.Write([]byte(reply))
+		}
+	}()
+
+	source, err := NewNUTSource("nut://" + listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := source.Update(); err != nil {
+		t.Fatal(err)
+	}
+	infos, err := source.GetAll()
+	if err != nil || len(infos) != 2 {
+		t.Fatalf("batteries %v, %v", infos, err)
+	}
+	if infos[0].State != StateFull || infos[0].Model != `Smart "UPS" 1500` {
+		t.Errorf("rack UPS %s %q", infos[0].State, infos[0].Model)
+	}
+	if infos[1].Index != 1 || infos[1].State != StateOnBattery || infos[1].TimeToEmpty() != 15*time.Minute {
+		t.Errorf("desk UPS %d %s, %v left", infos[1].Index, infos[1].State, infos[1].TimeToEmpty())
+	}
+	// Mains power while any UPS is online
+	if power := source.PowerSource(); !power.OnAC || !power.Detected {
+		t.Errorf("power source %+v", power)
+	}
+}
+
+// upscVars parses the "name: value" lines printed by upsc
+func upscVars(output string) map[string]string {
+	vars := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		if name, value, ok := strings.Cut(line, ": "); ok {
+			vars[name] = value
+		}
+	}
+	return vars
+}
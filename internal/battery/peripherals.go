@@ -0,0 +1,74 @@
+package battery
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Peripheral is a device with its own battery, e.g. a wireless mouse,
+// keyboard, headset or a connected phone
+type Peripheral struct {
+	// Name is the model name, or the device path when the model is unknown
+	Name string `json:"name"`
+
+	// Kind is the UPower device kind (e.g., "mouse", "headset")
+	Kind string `json:"kind"`
+
+	// Percent is the charge percentage
+	Percent float64 `json:"percent"`
+
+	// State is the charging state (unknown for most peripherals)
+	State State `json:"state"`
+}
+
+// PeripheralReader reads peripheral batteries from UPower, which collects
+// them from Bluetooth, HID and connected phones
+type PeripheralReader struct{}
+
+// NewPeripheralReader creates a reader, failing when upower isn't installed
+func NewPeripheralReader() (*PeripheralReader, error) {
+	if _, err := exec.LookPath("upower"); err != nil {
+		return nil, fmt.Errorf("upower not found: %w", err)
+	}
+	return &PeripheralReader{}, nil
+}
+
+// Read returns the connected peripherals
+func (r *PeripheralReader) Read() ([]Peripheral, error) {
+	out, err := exec.Command("upower", "--dump").Output()
+	if err != nil {
+		return nil, fmt.Errorf("upower --dump failed: %w", err)
+	}
+	return parsePeripherals(string(out)), nil
+}
+
+// parsePeripherals returns the devices of upower --dump output that don't
+// power the system and report a charge
+func parsePeripherals(out string) []Peripheral {
+	devices, _ := splitUPowerDump(out)
+
+	peripherals := make([]Peripheral, 0)
+	for _, device := range devices {
+		kind := device.props["kind"]
+		if device.props["power supply"] == "yes" || device.display() || kind == "line-power" {
+			continue
+		}
+		percent, ok := upowerNumber(device.props, "percentage")
+		if !ok {
+			continue
+		}
+
+		name := device.props["model"]
+		if name == "" {
+			name = device.path[strings.LastIndex(device.path, "/")+1:]
+		}
+		peripherals = append(peripherals, Peripheral{
+			Name:    name,
+			Kind:    kind,
+			Percent: percent,
+			State:   upowerStateName(device.props["state"]),
+		})
+	}
+	return peripherals
+}
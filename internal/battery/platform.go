@@ -5,6 +5,19 @@ type PlatformReader interface {
 	// ReadBatteryStats reads additional battery statistics not provided by distatus/battery
 	// Returns cycle count and any errors encountered
 	ReadBatteryStats(batteryIndex int) (stats BatteryStats, err error)
+
+	// ReadACAdapters reads the external power supplies connected to the system
+	ReadACAdapters() ([]ACAdapter, error)
+
+	// Capabilities reports which optional data is available for the battery
+	Capabilities(batteryIndex int) Capabilities
+
+	// BatteryPresent reports whether a battery is inserted in the slot,
+	// or true when the platform can't tell
+	BatteryPresent(batteryIndex int) bool
+
+	// Name returns a short description of the reader for diagnostics
+	Name() string
 }
 
 // BatteryStats contains platform-specific battery statistics
@@ -23,6 +36,26 @@ type BatteryStats struct {
 
 	// Technology type (e.g., "Li-ion", "Li-poly")
 	Technology string
+
+	// Chemistry is the cell chemistry reported by vendor tools (e.g., "LION"),
+	// more specific than Technology
+	Chemistry string
+
+	// FirmwareVersion of the battery controller
+	FirmwareVersion string
+
+	// ManufactureDate is the date the battery was made (YYYY-MM-DD)
+	ManufactureDate string
+
+	// ManufactureDateFromSerial is true when ManufactureDate was decoded from
+	// the serial number rather than reported
+	ManufactureDateFromSerial bool
+
+	// Temperature in Celsius (0 if not available)
+	Temperature float64
+
+	// ChargeLimit is the active vendor charge limit (nil if none)
+	ChargeLimit *ChargeLimit
 }
 
 // GetPlatformReader returns a platform-specific battery reader
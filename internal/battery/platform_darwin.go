@@ -0,0 +1,104 @@
+//go:build darwin
+
+package battery
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	pkgErrors "github.com/xsikor/go-battop/internal/errors"
+)
+
+type darwinPlatformReader struct{}
+
+func newPlatformReader() PlatformReader {
+	return &darwinPlatformReader{}
+}
+
+// Name returns the reader description
+func (r *darwinPlatformReader) Name() string {
+	return "darwin pmset (adapter details only)"
+}
+
+// Capabilities reports that only adapter details are available on macOS
+func (r *darwinPlatformReader) Capabilities(batteryIndex int) Capabilities {
+	_, err := exec.LookPath("pmset")
+	return Capabilities{
+		HasACAdapters: err == nil,
+	}
+}
+
+// BatteryPresent assumes the built-in battery is inserted
+func (r *darwinPlatformReader) BatteryPresent(batteryIndex int) bool {
+	return true
+}
+
+// ReadBatteryStats returns empty stats on macOS
+func (r *darwinPlatformReader) ReadBatteryStats(batteryIndex int) (BatteryStats, error) {
+	return BatteryStats{}, pkgErrors.ErrPlatformNotSupported
+}
+
+// ReadACAdapters reads the connected adapter from `pmset -g ac`
+func (r *darwinPlatformReader) ReadACAdapters() ([]ACAdapter, error) {
+	out, err := exec.Command("pmset", "-g", "ac").Output()
+	if err != nil {
+		return nil, fmt.Errorf("pmset failed: %w", err)
+	}
+	return parsePmsetAdapter(out), nil
+}
+
+// parsePmsetAdapter parses the key/value output of `pmset -g ac`:
+//
+//	Wattage = 96W
+//	Current = 4700mA
+//	Voltage = 20000mV
+//	Description = pd charger
+func parsePmsetAdapter(out []byte) []ACAdapter {
+	fields := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+		fields[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	// "No adapters found" produces no key/value pairs
+	if len(fields) == 0 {
+		return []ACAdapter{}
+	}
+
+	adapter := ACAdapter{
+		Name:   Coalesce(fields["Name"], fields["AdapterID"], "AC"),
+		Type:   Coalesce(fields["Description"], "Mains"),
+		Online: true,
+	}
+
+	if mV, err := strconv.ParseFloat(strings.TrimSuffix(fields["Voltage"], "mV"), 64); err == nil {
+		adapter.Voltage = mV / 1000.0
+	}
+	if mA, err := strconv.ParseFloat(strings.TrimSuffix(fields["Current"], "mA"), 64); err == nil {
+		adapter.Current = mA / 1000.0
+	}
+	if watts, err := strconv.ParseFloat(strings.TrimSuffix(fields["Wattage"], "W"), 64); err == nil {
+		adapter.MaxPower = watts * 1000.0
+	}
+
+	// Apple USB-C adapters describe themselves as "pd charger"; anything
+	// negotiating above 5 V is Power Delivery as well
+	switch {
+	case strings.Contains(strings.ToLower(fields["Description"]), "pd"), adapter.Voltage > 5.5:
+		adapter.Kind = ChargerUSBPD
+	case adapter.Voltage > 0:
+		adapter.Kind = ChargerUSB
+	default:
+		adapter.Kind = ChargerBarrel
+	}
+
+	return []ACAdapter{adapter}
+}
@@ -4,18 +4,42 @@ package battery
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 )
 
-type linuxPlatformReader struct{}
+// powerSupplyPath is the sysfs directory listing all power supplies
+const powerSupplyPath = "/sys/class/power_supply"
+
+// typecPath is the sysfs directory listing USB Type-C ports
+const typecPath = "/sys/class/typec"
+
+// smapiPath is the sysfs directory of the tp_smapi driver of older ThinkPads,
+// which reports the chemistry and manufacture date the power_supply class lacks
+const smapiPath = "/sys/devices/platform/smapi"
+
+// ideapadConservationGlob matches the conservation mode switch of the ideapad_acpi driver
+const ideapadConservationGlob = "/sys/bus/platform/drivers/ideapad_acpi/*/conservation_mode"
+
+type linuxPlatformReader struct {
+	dellOnce  sync.Once
+	dellLimit *ChargeLimit
+}
 
 func newPlatformReader() PlatformReader {
 	return &linuxPlatformReader{}
 }
 
+// Name returns the reader description
+func (r *linuxPlatformReader) Name() string {
+	return "linux sysfs (/sys/class/power_supply)"
+}
+
 // ReadBatteryStats reads battery statistics from Linux sysfs
 func (r *linuxPlatformReader) ReadBatteryStats(batteryIndex int) (BatteryStats, error) {
 	stats := BatteryStats{}
@@ -57,9 +81,235 @@ func (r *linuxPlatformReader) ReadBatteryStats(batteryIndex int) (BatteryStats,
 		stats.Technology = technology
 	}
 
+	// Read temperature (reported in tenths of a degree Celsius)
+	if temp, err := readSysfsInt(filepath.Join(batteryPath, "temp")); err == nil {
+		stats.Temperature = float64(temp) / 10.0
+	}
+
+	// Read firmware version, which only few drivers report
+	if firmware, err := readSysfsString(filepath.Join(batteryPath, "firmware_version")); err == nil {
+		stats.FirmwareVersion = firmware
+	}
+
+	smapiBattery := fmt.Sprintf("%s/BAT%d", smapiPath, batteryIndex)
+	if chemistry, err := readSysfsString(filepath.Join(smapiBattery, "chemistry")); err == nil {
+		stats.Chemistry = chemistry
+	}
+	stats.ManufactureDate = readManufactureDate(batteryPath, smapiBattery)
+	if stats.ManufactureDate == "" {
+		stats.ManufactureDate = ManufactureDateFromSerial(stats.SerialNumber)
+		stats.ManufactureDateFromSerial = stats.ManufactureDate != ""
+	}
+
+	stats.ChargeLimit = r.readChargeLimit(batteryPath)
+
 	return stats, nil
 }
 
+// readManufactureDate reads the manufacture_year, _month and _day attributes,
+// or a manufacture_date attribute of the battery or tp_smapi, or returns ""
+func readManufactureDate(batteryPath, smapiBattery string) string {
+	year, yErr := readSysfsInt(filepath.Join(batteryPath, "manufacture_year"))
+	month, mErr := readSysfsInt(filepath.Join(batteryPath, "manufacture_month"))
+	day, dErr := readSysfsInt(filepath.Join(batteryPath, "manufacture_day"))
+	if yErr == nil && mErr == nil && dErr == nil {
+		if date := ManufactureDate(year, month, day); date != "" {
+			return date
+		}
+	}
+
+	for _, dir := range []string{batteryPath, smapiBattery} {
+		value, err := readSysfsString(filepath.Join(dir, "manufacture_date"))
+		if err != nil {
+			continue
+		}
+		if date, err := ParseManufactureDate(value); err == nil {
+			return date
+		}
+		slog.Debug("Ignoring manufacture date", "path", dir, "value", value)
+	}
+	return ""
+}
+
+// readChargeLimit detects an active vendor charge limit: IdeaPad conservation
+// mode, a charge stop threshold (ThinkPad, ASUS), or Dell charging settings
+func (r *linuxPlatformReader) readChargeLimit(batteryPath string) *ChargeLimit {
+	end, err := readSysfsInt(filepath.Join(batteryPath, "charge_control_end_threshold"))
+	if err != nil || end <= 0 || end >= 100 {
+		end = 0
+	}
+
+	matches, _ := filepath.Glob(ideapadConservationGlob)
+	conservation := false
+	if len(matches) > 0 {
+		on, err := readSysfsInt(matches[0])
+		conservation = err == nil && on == 1
+	}
+	if types, err := readSysfsString(filepath.Join(batteryPath, "charge_types")); err == nil {
+		conservation = conservation || selectedChoice(types) == "Long_Life"
+	}
+
+	switch {
+	case conservation && end > 0:
+		return &ChargeLimit{Percent: float64(end), Mode: ChargeLimitConservation}
+	case conservation:
+		return &ChargeLimit{Percent: ConservationLimit, Mode: ChargeLimitConservation}
+	case end > 0:
+		return &ChargeLimit{Percent: float64(end), Mode: ChargeLimitThreshold}
+	default:
+		return r.dellChargeLimit()
+	}
+}
+
+// dellChargeLimit reads the Dell BIOS charging settings through libsmbios
+// once, since running the tool on every update is too slow
+func (r *linuxPlatformReader) dellChargeLimit() *ChargeLimit {
+	r.dellOnce.Do(func() {
+		if _, err := exec.LookPath("smbios-battery-ctl"); err != nil {
+			return
+		}
+		out, err := exec.Command("smbios-battery-ctl", "--get-charging-cfg").Output()
+		if err != nil {
+			slog.Debug("Failed to read Dell charging settings", "error", err)
+			return
+		}
+		r.dellLimit = parseDellChargingConfig(string(out))
+	})
+	return r.dellLimit
+}
+
+// Capabilities reports which sysfs attributes exist for the battery
+func (r *linuxPlatformReader) Capabilities(batteryIndex int) Capabilities {
+	caps := Capabilities{
+		HasACAdapters: r.hasACAdapters(),
+	}
+
+	batteryPath := fmt.Sprintf("%s/BAT%d", powerSupplyPath, batteryIndex)
+	if !sysfsExists(batteryPath) {
+		return caps
+	}
+
+	caps.HasExtendedStats = true
+	caps.HasTemperature = sysfsExists(filepath.Join(batteryPath, "temp"))
+	caps.HasCycles = sysfsExists(filepath.Join(batteryPath, "cycle_count"))
+	caps.CanSetThreshold = sysfsExists(filepath.Join(batteryPath, "charge_control_end_threshold"))
+
+	// The generic power_supply class has no per-cell voltage attributes
+	caps.HasCellVoltages = false
+
+	return caps
+}
+
+// BatteryPresent reads the present attribute of the battery slot. Bays of
+// multi-bay laptops keep their power_supply entry with present=0 when empty.
+func (r *linuxPlatformReader) BatteryPresent(batteryIndex int) bool {
+	present, err := readSysfsInt(fmt.Sprintf("%s/BAT%d/present", powerSupplyPath, batteryIndex))
+	return err != nil || present != 0
+}
+
+// hasACAdapters reports whether any non-battery power supply is present
+func (r *linuxPlatformReader) hasACAdapters() bool {
+	adapters, err := r.ReadACAdapters()
+	return err == nil && len(adapters) > 0
+}
+
+// ReadACAdapters reads non-battery power supplies from Linux sysfs
+func (r *linuxPlatformReader) ReadACAdapters() ([]ACAdapter, error) {
+	entries, err := os.ReadDir(powerSupplyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list power supplies: %w", err)
+	}
+
+	adapters := make([]ACAdapter, 0)
+	for _, entry := range entries {
+		supplyPath := filepath.Join(powerSupplyPath, entry.Name())
+
+		supplyType, err := readSysfsString(filepath.Join(supplyPath, "type"))
+		if err != nil || supplyType == "Battery" {
+			continue
+		}
+
+		online, err := readSysfsInt(filepath.Join(supplyPath, "online"))
+		if err != nil {
+			continue
+		}
+
+		adapter := ACAdapter{
+			Name:   entry.Name(),
+			Type:   supplyType,
+			Online: online == 1,
+		}
+
+		// USB-PD supplies report the negotiated contract in µV and µA
+		voltageMax, vErr := readSysfsInt(filepath.Join(supplyPath, "voltage_max"))
+		currentMax, cErr := readSysfsInt(filepath.Join(supplyPath, "current_max"))
+		if vErr == nil && cErr == nil {
+			adapter.Voltage = float64(voltageMax) / 1e6
+			adapter.Current = float64(currentMax) / 1e6
+			adapter.MaxPower = float64(voltageMax) * float64(currentMax) / 1e9
+		}
+
+		adapter.Kind = classifyCharger(supplyPath, supplyType)
+
+		adapters = append(adapters, adapter)
+	}
+
+	return adapters, nil
+}
+
+// classifyCharger classifies a power supply from its sysfs type and usb_type attributes
+func classifyCharger(supplyPath, supplyType string) ChargerKind {
+	switch supplyType {
+	case "Mains":
+		return ChargerBarrel
+	case "USB":
+		// usb_type lists all supported types with the active one in brackets, e.g. "C [PD] PD_PPS"
+		if usbType, err := readSysfsString(filepath.Join(supplyPath, "usb_type")); err == nil {
+			if strings.HasPrefix(selectedChoice(usbType), "PD") {
+				return ChargerUSBPD
+			}
+		}
+		if typecPowerDelivery() {
+			return ChargerUSBPD
+		}
+		return ChargerUSB
+	default:
+		return ChargerUnknown
+	}
+}
+
+// selectedChoice returns the bracketed entry of a sysfs choice list such as
+// usb_type or charge_types ("C [PD] PD_PPS"), or the value itself
+func selectedChoice(value string) string {
+	for _, field := range strings.Fields(value) {
+		if strings.HasPrefix(field, "[") && strings.HasSuffix(field, "]") {
+			return strings.Trim(field, "[]")
+		}
+	}
+	return value
+}
+
+// typecPowerDelivery reports whether any USB Type-C port negotiated a Power Delivery contract
+func typecPowerDelivery() bool {
+	ports, err := filepath.Glob(filepath.Join(typecPath, "port*"))
+	if err != nil {
+		return false
+	}
+	for _, port := range ports {
+		mode, err := readSysfsString(filepath.Join(port, "power_operation_mode"))
+		if err == nil && mode == "usb_power_delivery" {
+			return true
+		}
+	}
+	return false
+}
+
+// sysfsExists reports whether a sysfs path exists
+func sysfsExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
 // readSysfsString reads a string value from a sysfs file
 func readSysfsString(path string) (string, error) {
 	data, err := os.ReadFile(path)
@@ -0,0 +1,41 @@
+//go:build linux
+
+package battery
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func FuzzReadSysfsInt(f *testing.F) {
+	for _, seed := range []string{"42\n", "-1", "", " 7 ", "abc", "99999999999999999999", "1\x00"} {
+		f.Add(seed)
+	}
+	path := filepath.Join(f.TempDir(), "value")
+
+	f.Fuzz(func(t *testing.T, content string) {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		value, err := readSysfsInt(path)
+		if err != nil {
+			return
+		}
+		text, _ := readSysfsString(path)
+		if parsed, _ := strconv.Atoi(text); parsed != value {
+			t.Errorf("readSysfsInt(%q) = %d, want %d", content, value, parsed)
+		}
+	})
+}
+
+func FuzzSelectedChoice(f *testing.F) {
+	for _, seed := range []string{"C [PD] PD_PPS", "[Standard] Long_Life", "[SDP] DCP CDP", "Unknown", "", "[]", "[ [PD"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, value string) {
+		_ = selectedChoice(value)
+	})
+}
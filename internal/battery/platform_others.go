@@ -1,4 +1,4 @@
-//go:build !linux
+//go:build !linux && !darwin
 
 package battery
 
@@ -10,8 +10,28 @@ func newPlatformReader() PlatformReader {
 	return &defaultPlatformReader{}
 }
 
+// Name returns the reader description
+func (r *defaultPlatformReader) Name() string {
+	return "none (platform-specific stats unavailable)"
+}
+
+// Capabilities reports that no optional data is available
+func (r *defaultPlatformReader) Capabilities(batteryIndex int) Capabilities {
+	return Capabilities{}
+}
+
+// BatteryPresent assumes every reported battery is inserted
+func (r *defaultPlatformReader) BatteryPresent(batteryIndex int) bool {
+	return true
+}
+
 // ReadBatteryStats returns empty stats on non-Linux platforms
 func (r *defaultPlatformReader) ReadBatteryStats(batteryIndex int) (BatteryStats, error) {
 	// Return error indicating platform is not supported
 	return BatteryStats{}, pkgErrors.ErrPlatformNotSupported
 }
+
+// ReadACAdapters is not supported on non-Linux platforms
+func (r *defaultPlatformReader) ReadACAdapters() ([]ACAdapter, error) {
+	return nil, pkgErrors.ErrPlatformNotSupported
+}
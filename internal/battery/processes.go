@@ -0,0 +1,214 @@
+package battery
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ProcPath is the mount point of procfs
+const ProcPath = "/proc"
+
+// ProcessPower is the estimated power draw of one process
+type ProcessPower struct {
+	PID int `json:"pid"`
+
+	// Name is the command name of the process
+	Name string `json:"name"`
+
+	// Unit is the last element of the process's cgroup (e.g., the systemd
+	// service or app scope), or "" in the root cgroup
+	Unit string `json:"unit,omitempty"`
+
+	// CPU is the share of the CPU time of all CPUs since the previous reading in percent
+	CPU float64 `json:"cpu_percent"`
+
+	// Power is the share of the CPU power matching the share of busy CPU time in mW
+	Power float64 `json:"power_mw"`
+}
+
+// cpuTimes are the aggregate CPU times from /proc/stat in clock ticks
+type cpuTimes struct {
+	total uint64
+	idle  uint64
+}
+
+// ProcessReader estimates the power of every process from its CPU time, the
+// way powertop attributes package power
+type ProcessReader struct {
+	mu    sync.Mutex
+	root  string
+	ticks map[int]uint64
+	last  cpuTimes
+}
+
+// NewProcessReader creates a reader for the procfs mounted at root
+func NewProcessReader(root string) (*ProcessReader, error) {
+	if _, err := readCPUTimes(root); err != nil {
+		return nil, err
+	}
+	return &ProcessReader{root: root}, nil
+}
+
+// Read returns the processes that used the CPU since the previous call,
+// ranked by estimated power. cpuPower is the power in mW drawn by the CPU
+// (e.g., the RAPL package power), split by the share of busy CPU time. The
+// first call only records the counters and returns no processes.
+func (r *ProcessReader) Read(cpuPower float64) ([]ProcessPower, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	times, err := readCPUTimes(r.root)
+	if err != nil {
+		return nil, err
+	}
+	dirs, err := os.ReadDir(r.root)
+	if err != nil {
+		return nil, err
+	}
+
+	first := r.ticks == nil
+	elapsed := times.total - r.last.total
+	busy := elapsed - min(times.idle-r.last.idle, elapsed)
+	r.last = times
+
+	ticks := make(map[int]uint64, len(r.ticks))
+	var processes []ProcessPower
+	for _, dir := range dirs {
+		pid, err := strconv.Atoi(dir.Name())
+		if err != nil {
+			continue
+		}
+		// Processes can exit between listing and reading
+		data, err := os.ReadFile(filepath.Join(r.root, dir.Name(), "stat"))
+		if err != nil {
+			continue
+		}
+		name, used, err := parseProcStat(data)
+		if err != nil {
+			continue
+		}
+		ticks[pid] = used
+
+		previous, seen := r.ticks[pid]
+		if first || !seen || used <= previous || elapsed == 0 {
+			continue
+		}
+		delta := used - previous
+		process := ProcessPower{
+			PID:  pid,
+			Name: name,
+			Unit: r.unit(dir.Name()),
+			CPU:  float64(delta) / float64(elapsed) * 100,
+		}
+		if busy > 0 {
+			process.Power = cpuPower * min(float64(delta)/float64(busy), 1)
+		}
+		processes = append(processes, process)
+	}
+	r.ticks = ticks
+
+	sort.SliceStable(processes, func(i, j int) bool {
+		if processes[i].Power != processes[j].Power {
+			return processes[i].Power > processes[j].Power
+		}
+		return processes[i].CPU > processes[j].CPU
+	})
+	return processes, nil
+}
+
+// unit returns the cgroup unit of a process, or "" when it can't be read
+func (r *ProcessReader) unit(pid string) string {
+	data, err := os.ReadFile(filepath.Join(r.root, pid, "cgroup"))
+	if err != nil {
+		return ""
+	}
+	return parseCgroupUnit(data)
+}
+
+// readCPUTimes reads the aggregate CPU times from /proc/stat
+func readCPUTimes(root string) (cpuTimes, error) {
+	data, err := os.ReadFile(filepath.Join(root, "stat"))
+	if err != nil {
+		return cpuTimes{}, err
+	}
+	return parseCPUTimes(data)
+}
+
+// parseCPUTimes parses the aggregate "cpu" line of /proc/stat. Guest time is
+// already part of user time, so only the first eight columns are summed;
+// iowait counts as idle.
+func parseCPUTimes(data []byte) (cpuTimes, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 || fields[0] != "cpu" {
+			continue
+		}
+
+		var times cpuTimes
+		for i, field := range fields[1:min(len(fields), 9)] {
+			value, err := strconv.ParseUint(field, 10, 64)
+			if err != nil {
+				return cpuTimes{}, fmt.Errorf("invalid cpu time %q: %w", field, err)
+			}
+			times.total += value
+			// Columns 4 and 5 are idle and iowait
+			if i == 3 || i == 4 {
+				times.idle += value
+			}
+		}
+		return times, nil
+	}
+	return cpuTimes{}, fmt.Errorf("no cpu line in stat")
+}
+
+// parseProcStat returns the command name and the user plus system time in
+// clock ticks from /proc/<pid>/stat. The name is in parentheses and may
+// itself contain spaces and parentheses.
+func parseProcStat(data []byte) (string, uint64, error) {
+	text := string(data)
+	open, end := strings.IndexByte(text, '('), strings.LastIndexByte(text, ')')
+	if open < 0 || end < open {
+		return "", 0, fmt.Errorf("no command name in stat")
+	}
+
+	// The fields after the name start at the state (field 3); utime and
+	// stime are fields 14 and 15
+	fields := strings.Fields(text[end+1:])
+	if len(fields) < 13 {
+		return "", 0, fmt.Errorf("short stat: %d fields", len(fields))
+	}
+	utime, err := strconv.ParseUint(fields[11], 10, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid utime %q: %w", fields[11], err)
+	}
+	stime, err := strconv.ParseUint(fields[12], 10, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid stime %q: %w", fields[12], err)
+	}
+	return text[open+1 : end], utime + stime, nil
+}
+
+// parseCgroupUnit returns the last element of the unified (v2) cgroup path
+// in /proc/<pid>/cgroup, or "" for the root cgroup and v1-only hierarchies
+func parseCgroupUnit(data []byte) string {
+	for _, line := range strings.Split(string(data), "\n") {
+		path, ok := strings.CutPrefix(line, "0::")
+		if !ok {
+			continue
+		}
+		path = strings.TrimRight(strings.TrimSpace(path), "/")
+		if i := strings.LastIndexByte(path, '/'); i >= 0 {
+			return path[i+1:]
+		}
+		return ""
+	}
+	return ""
+}
@@ -0,0 +1,88 @@
+package battery
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeProc writes a fake /proc/stat and one /proc/<pid> directory per process
+func writeProc(t *testing.T, root string, busy, idle int, processes map[int]int) {
+	t.Helper()
+	stat := fmt.Sprintf("cpu  %d 0 0 %d 0 0 0 0 0 0\ncpu0 %d 0 0 %d 0 0 0 0 0 0\n", busy, idle, busy, idle)
+	if err := os.WriteFile(filepath.Join(root, "stat"), []byte(stat), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for pid, ticks := range processes {
+		dir := filepath.Join(root, fmt.Sprint(pid))
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		line := fmt.Sprintf("%d (web (content)) S 1 1 1 0 -1 4194304 0 0 0 0 %d 0 0 0 20 0 1 0\n", pid, ticks)
+		if err := os.WriteFile(filepath.Join(dir, "stat"), []byte(line), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		cgroup := fmt.Sprintf("0::/user.slice/app-%d.scope\n", pid)
+		if err := os.WriteFile(filepath.Join(dir, "cgroup"), []byte(cgroup), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestProcessReader(t *testing.T) {
+	root := t.TempDir()
+	writeProc(t, root, 1000, 1000, map[int]int{10: 100, 20: 500})
+
+	reader, err := NewProcessReader(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if processes, err := reader.Read(8000); err != nil || len(processes) != 0 {
+		t.Fatalf("first read = %v, %v; want no processes", processes, err)
+	}
+
+	// 200 busy ticks out of 400: pid 20 used 150, pid 10 used 50, pid 30 is new
+	writeProc(t, root, 1200, 1200, map[int]int{10: 150, 20: 650, 30: 10})
+	processes, err := reader.Read(8000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(processes) != 2 {
+		t.Fatalf("got %d processes, want 2: %v", len(processes), processes)
+	}
+
+	top := processes[0]
+	if top.PID != 20 || top.Name != "web (content)" || top.Unit != "app-20.scope" {
+		t.Errorf("top process = %+v", top)
+	}
+	if top.Power != 6000 || top.CPU != 37.5 {
+		t.Errorf("pid 20 = %.0f mW at %.1f%%, want 6000 mW at 37.5%%", top.Power, top.CPU)
+	}
+	if processes[1].Power != 2000 {
+		t.Errorf("pid 10 = %.0f mW, want 2000 mW", processes[1].Power)
+	}
+}
+
+func FuzzProcStat(f *testing.F) {
+	for _, seed := range []string{
+		"1 (systemd) S 0 1 1 0 -1 4194560 0 0 0 0 52 96 0 0 20 0 1 0 5 0 0\n",
+		"42 (a) b) c) R 1 1 1 0 -1 0 0 0 0 0 1 2 0 0 20 0 1 0\n",
+		"7 (short) S 1 1\n",
+		"9 (neg) S 1 1 1 0 -1 0 0 0 0 0 -1 2 0\n",
+		")(",
+		"",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, data string) {
+		name, _, err := parseProcStat([]byte(data))
+		if err == nil && !strings.Contains(data, "("+name+")") {
+			t.Errorf("parseProcStat(%q) name %q not in parentheses", data, name)
+		}
+		_ = parseCgroupUnit([]byte(data))
+		_, _ = parseCPUTimes([]byte(data))
+	})
+}
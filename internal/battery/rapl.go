@@ -0,0 +1,196 @@
+package battery
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PowercapPath is the sysfs directory of the Linux powercap framework
+const PowercapPath = "/sys/class/powercap"
+
+// raplPrefix is the zone name prefix of the RAPL powercap driver. AMD CPUs
+// use it as well; the intel-rapl-mmio zones duplicate the package and are skipped.
+const raplPrefix = "intel-rapl:"
+
+// Power breakdown segments, in stacking order
+const (
+	SegmentCores   = "CPU cores"
+	SegmentGPU     = "GPU"
+	SegmentPackage = "Package (other)"
+	SegmentDRAM    = "DRAM"
+	SegmentRest    = "Rest of system"
+)
+
+// PowerDomain is the power draw of one RAPL domain
+type PowerDomain struct {
+	// Zone is the powercap zone id (e.g., "intel-rapl:0:1")
+	Zone string `json:"zone"`
+
+	// Name is the domain name reported by the kernel ("package-0", "core",
+	// "uncore", "dram", "psys")
+	Name string `json:"name"`
+
+	// Power is the average power since the previous reading in mW
+	Power float64 `json:"power_mw"`
+}
+
+// Parent returns the zone id of the enclosing domain, or "" for top-level domains
+func (d PowerDomain) Parent() string {
+	id := strings.TrimPrefix(d.Zone, raplPrefix)
+	if i := strings.LastIndex(id, ":"); i >= 0 {
+		return raplPrefix + id[:i]
+	}
+	return ""
+}
+
+// raplZone is one powercap zone with its last energy reading
+type raplZone struct {
+	id       string
+	name     string
+	path     string
+	maxRange float64
+	energy   float64
+}
+
+// RAPLReader turns the cumulative RAPL energy counters into power readings
+type RAPLReader struct {
+	mu     sync.Mutex
+	zones  []*raplZone
+	lastAt time.Time
+}
+
+// NewRAPLReader finds the RAPL zones below the powercap directory
+func NewRAPLReader(root string) (*RAPLReader, error) {
+	paths, err := filepath.Glob(filepath.Join(root, raplPrefix+"*"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	r := &RAPLReader{}
+	for _, path := range paths {
+		name, err := readTrimmed(filepath.Join(path, "name"))
+		if err != nil {
+			continue
+		}
+		maxRange, _ := readNumber(filepath.Join(path, "max_energy_range_uj"))
+		r.zones = append(r.zones, &raplZone{
+			id:       filepath.Base(path),
+			name:     name,
+			path:     filepath.Join(path, "energy_uj"),
+			maxRange: maxRange,
+		})
+	}
+	if len(r.zones) == 0 {
+		return nil, fmt.Errorf("no RAPL domains in %s", root)
+	}
+	return r, nil
+}
+
+// Read returns the average power of every domain since the previous call.
+// The first call only records the counters and returns no domains.
+func (r *RAPLReader) Read(now time.Time) ([]PowerDomain, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	energies := make([]float64, len(r.zones))
+	for i, zone := range r.zones {
+		energy, err := readNumber(zone.path)
+		if errors.Is(err, fs.ErrPermission) {
+			return nil, fmt.Errorf("%w (energy_uj is readable by root only)", err)
+		}
+		if err != nil {
+			return nil, err
+		}
+		energies[i] = energy
+	}
+
+	elapsed := now.Sub(r.lastAt).Seconds()
+	first := r.lastAt.IsZero()
+	r.lastAt = now
+
+	var domains []PowerDomain
+	for i, zone := range r.zones {
+		delta := energies[i] - zone.energy
+		zone.energy = energies[i]
+		if first || elapsed <= 0 {
+			continue
+		}
+		// The counter wraps around at max_energy_range_uj
+		if delta < 0 {
+			delta += zone.maxRange
+		}
+		if delta < 0 {
+			delta = 0
+		}
+		domains = append(domains, PowerDomain{
+			Zone:  zone.id,
+			Name:  zone.name,
+			Power: delta / elapsed / 1000, // µJ/s is µW
+		})
+	}
+	return domains, nil
+}
+
+// PowerSegment is one part of the system power draw
+type PowerSegment struct {
+	Name  string  `json:"name"`
+	Power float64 `json:"power_mw"`
+}
+
+// BreakdownPower splits the system power draw into the segments listed by
+// the Segment constants, in stacking order. Packages contain their core and
+// uncore (integrated GPU) subdomains, so the package segment is the remainder;
+// the platform-wide psys domain is not part of the stack. total is the battery
+// discharge power in mW; the part not covered by RAPL is the rest of the system.
+func BreakdownPower(domains []PowerDomain, total float64) []PowerSegment {
+	var cores, gpu, packages, dram float64
+	for _, domain := range domains {
+		switch {
+		case domain.Name == "core":
+			cores += domain.Power
+		case domain.Name == "uncore":
+			gpu += domain.Power
+		case domain.Name == "dram":
+			dram += domain.Power
+		case strings.HasPrefix(domain.Name, "package") && domain.Parent() == "":
+			packages += domain.Power
+		}
+	}
+
+	segments := []PowerSegment{
+		{Name: SegmentCores, Power: cores},
+		{Name: SegmentGPU, Power: gpu},
+		{Name: SegmentPackage, Power: max(packages-cores-gpu, 0)},
+		{Name: SegmentDRAM, Power: dram},
+	}
+	measured := max(packages, cores+gpu) + dram
+	segments = append(segments, PowerSegment{Name: SegmentRest, Power: max(total-measured, 0)})
+	return segments
+}
+
+// readTrimmed reads a sysfs file without surrounding whitespace
+func readTrimmed(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// readNumber reads a numeric sysfs file
+func readNumber(path string) (float64, error) {
+	text, err := readTrimmed(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(text, 64)
+}
@@ -0,0 +1,76 @@
+package battery
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeZone writes a fake powercap zone
+func writeZone(t *testing.T, root, id, name string, energy, maxRange string) {
+	t.Helper()
+	dir := filepath.Join(root, id)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for file, content := range map[string]string{"name": name + "\n", "energy_uj": energy + "\n", "max_energy_range_uj": maxRange + "\n"} {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRAPLReader(t *testing.T) {
+	root := t.TempDir()
+	writeZone(t, root, "intel-rapl:0", "package-0", "1000000", "262143328850")
+	writeZone(t, root, "intel-rapl:0:0", "core", "500000", "262143328850")
+	writeZone(t, root, "intel-rapl:0:1", "uncore", "262143000000", "262143328850")
+	writeZone(t, root, "intel-rapl:1", "psys", "0", "262143328850")
+	writeZone(t, root, "intel-rapl-mmio:0", "package-0", "0", "262143328850")
+
+	reader, err := NewRAPLReader(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	if domains, err := reader.Read(start); err != nil || len(domains) != 0 {
+		t.Fatalf("first read = %v, %v; want no domains", domains, err)
+	}
+
+	// Two seconds later: 12 J package, 6 J cores, uncore wrapped around
+	writeZone(t, root, "intel-rapl:0", "package-0", "13000000", "262143328850")
+	writeZone(t, root, "intel-rapl:0:0", "core", "6500000", "262143328850")
+	writeZone(t, root, "intel-rapl:0:1", "uncore", "1671150", "262143328850")
+	writeZone(t, root, "intel-rapl:1", "psys", "30000000", "262143328850")
+	domains, err := reader.Read(start.Add(2 * time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]float64{"package-0": 6000, "core": 3000, "uncore": 1000, "psys": 15000}
+	if len(domains) != len(want) {
+		t.Fatalf("got %d domains, want %d: %v", len(domains), len(want), domains)
+	}
+	for _, domain := range domains {
+		if domain.Power != want[domain.Name] {
+			t.Errorf("%s = %.0f mW, want %.0f mW", domain.Name, domain.Power, want[domain.Name])
+		}
+	}
+
+	segments := BreakdownPower(domains, 20000)
+	wantSegments := []PowerSegment{
+		{SegmentCores, 3000}, {SegmentGPU, 1000}, {SegmentPackage, 2000}, {SegmentDRAM, 0}, {SegmentRest, 14000},
+	}
+	for i, segment := range segments {
+		if segment != wantSegments[i] {
+			t.Errorf("segment %d = %+v, want %+v", i, segment, wantSegments[i])
+		}
+	}
+}
+
+func TestNewRAPLReaderWithoutDomains(t *testing.T) {
+	if _, err := NewRAPLReader(t.TempDir()); err == nil {
+		t.Fatal("expected an error without RAPL domains")
+	}
+}
@@ -0,0 +1,32 @@
+package battery
+
+// Reading is a raw battery reading as the platform reports it, before the
+// Manager sanitizes, signs and enriches it
+type Reading struct {
+	State State
+
+	// Current, Full and Design capacity in mWh
+	Current float64
+	Full    float64
+	Design  float64
+
+	// ChargeRate in mW; most platforms report it unsigned
+	ChargeRate float64
+
+	// Voltage and DesignVoltage in V
+	Voltage       float64
+	DesignVoltage float64
+}
+
+// Reader reads the raw batteries the Manager turns into Info: the
+// distatus/battery library (the default), sysfs alone or the simulator
+type Reader interface {
+	// Read returns one reading per battery slot. A nil reading marks an
+	// empty or unreadable slot with the reason in slotErrors; a non-nil
+	// slot error next to a reading is a partial failure. err is only set
+	// when nothing could be read.
+	Read() (readings []*Reading, slotErrors []error, err error)
+
+	// Name returns a short description of the reader for diagnostics
+	Name() string
+}
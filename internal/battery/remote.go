@@ -0,0 +1,103 @@
+package battery
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	pkgErrors "github.com/xsikor/go-battop/internal/errors"
+)
+
+// RemoteTimeout limits each request to the remote battop API
+const RemoteTimeout = 5 * time.Second
+
+// RemoteSource reads batteries from the HTTP API of another battop instance
+// (started with -api-listen), e.g. a headless laptop or a UPS server
+type RemoteSource struct {
+	snapshot
+	baseURL string
+	client  *http.Client
+
+	// started is set by the first update, which waits for its answer
+	started atomic.Bool
+
+	// fetching is set while a background fetch is in flight
+	fetching atomic.Bool
+}
+
+// NewRemoteSource creates a source for the API at addr ("host:port" or an http(s) URL)
+func NewRemoteSource(addr string) *RemoteSource {
+	baseURL := strings.TrimSuffix(addr, "/")
+	if !strings.HasPrefix(baseURL, "http://") && !strings.HasPrefix(baseURL, "https://") {
+		baseURL = "http://" + baseURL
+	}
+
+	return &RemoteSource{
+		baseURL: baseURL,
+		client:  &http.Client{Timeout: RemoteTimeout},
+	}
+}
+
+// URL returns the base URL of the remote API
+func (r *RemoteSource) URL() string {
+	return r.baseURL
+}
+
+// Update fetches the batteries and the power source from the remote API. The
+// first update waits for the answer so there is something to show; later
+// ones fetch in the background and return the outcome of the last completed
+// fetch, so a slow or unreachable remote never holds up the caller's tick.
+func (r *RemoteSource) Update() error {
+	if !r.started.Swap(true) {
+		return r.refresh()
+	}
+	if r.fetching.CompareAndSwap(false, true) {
+		go func() {
+			defer r.fetching.Store(false)
+			r.refresh()
+		}()
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.lastError
+}
+
+// refresh fetches the batteries and the power source and stores them, or the error
+func (r *RemoteSource) refresh() error {
+	var infos []*Info
+	if err := r.fetch("/batteries", &infos); err != nil {
+		return r.setLastError(err)
+	}
+	if len(infos) == 0 {
+		return r.setLastError(pkgErrors.ErrNoBatteries)
+	}
+
+	var source PowerSource
+	if err := r.fetch("/power", &source); err != nil {
+		return r.setLastError(err)
+	}
+
+	r.set(infos, source)
+	return nil
+}
+
+// fetch decodes the JSON response of a GET request to the path
+func (r *RemoteSource) fetch(path string, value any) error {
+	resp, err := r.client.Get(r.baseURL + path)
+	if err != nil {
+		return fmt.Errorf("failed to reach %s: %w", r.baseURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s%s answered %s", r.baseURL, path, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(value); err != nil {
+		return fmt.Errorf("invalid response from %s%s: %w", r.baseURL, path, err)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package battery
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRemoteSourceUpdateDoesNotBlock(t *testing.T) {
+	release := make(chan struct{})
+	var slow atomic.Bool
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /batteries", func(w http.ResponseWriter, r *http.Request) {
+		if slow.Load() {
+			<-release
+		}
+		json.NewEncoder(w).Encode([]*Info{{Index: 0, State: StateDischarging, Current: 25000, Full: 50000}})
+	})
+	mux.HandleFunc("GET /power", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(PowerSource{OnAC: false, Detected: true})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	defer close(release)
+
+	remote := NewRemoteSource(server.URL)
+	if err := remote.Update(); err != nil {
+		t.Fatal(err)
+	}
+	if batteries, err := remote.GetAll(); err != nil || len(batteries) != 1 {
+		t.Fatalf("first update gave %v, %v", batteries, err)
+	}
+
+	// A hanging remote keeps the last readings without holding up updates
+	slow.Store(true)
+	for range 3 {
+		started := time.Now()
+		if err := remote.Update(); err != nil {
+			t.Fatal(err)
+		}
+		if elapsed := time.Since(started); elapsed > 100*time.Millisecond {
+			t.Fatalf("update waited %v for the remote", elapsed)
+		}
+	}
+	if info, err := remote.Get(0); err != nil || info.ChargePercent() != 50 {
+		t.Errorf("last readings lost while the remote hangs: %v, %v", info, err)
+	}
+}
@@ -0,0 +1,205 @@
+package battery
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// replayFrame is one recorded sample of all batteries
+type replayFrame struct {
+	time      time.Time
+	onAC      bool
+	batteries []*Info
+}
+
+// ReplaySource plays back a session recorded with -record. It keeps a virtual
+// clock starting at the first sample that advances by a fixed step on every
+// update; the readings carry the recorded times, so charts and statistics
+// follow the recording rather than the wall clock.
+type ReplaySource struct {
+	snapshot
+	frames []replayFrame
+
+	// step is the virtual time per update, 0 plays one sample per update
+	step time.Duration
+
+	mu    sync.Mutex
+	next  int
+	clock time.Time
+	done  chan struct{}
+}
+
+// OpenReplay opens a recorded session, advancing step per update
+func OpenReplay(path string, step time.Duration) (*ReplaySource, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return NewReplaySource(file, step)
+}
+
+// NewReplaySource reads a recorded session, advancing step per update
+func NewReplaySource(r io.Reader, step time.Duration) (*ReplaySource, error) {
+	frames, err := readSession(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(frames) == 0 {
+		return nil, errors.New("the recording has no samples")
+	}
+	return &ReplaySource{frames: frames, step: step, done: make(chan struct{})}, nil
+}
+
+// Update advances the virtual clock and shows the last sample recorded by
+// then. At the end of the recording the last sample stays shown.
+func (s *ReplaySource) Update() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.next >= len(s.frames) {
+		return nil
+	}
+	if s.clock.IsZero() || s.step == 0 {
+		s.clock = s.frames[s.next].time
+	} else {
+		s.clock = s.clock.Add(s.step)
+	}
+
+	due := -1
+	for s.next < len(s.frames) && !s.frames[s.next].time.After(s.clock) {
+		due = s.next
+		s.next++
+	}
+	if due < 0 {
+		return nil
+	}
+	if s.next == len(s.frames) {
+		close(s.done)
+	}
+
+	frame := s.frames[due]
+	infos := make([]*Info, len(frame.batteries))
+	for i, info := range frame.batteries {
+		infoCopy := *info
+		infos[i] = &infoCopy
+	}
+	s.set(infos, PowerSource{OnAC: frame.onAC, Detected: true})
+	return nil
+}
+
+// Done is closed once the last sample was shown
+func (s *ReplaySource) Done() <-chan struct{} {
+	return s.done
+}
+
+// Span returns the times of the first and the last sample
+func (s *ReplaySource) Span() (time.Time, time.Time) {
+	return s.frames[0].time, s.frames[len(s.frames)-1].time
+}
+
+// readSession parses the CSV of -record into frames, one per sample time.
+// Columns are found by their header name, so columns added later are
+// ignored; time, battery, state, energy_mwh and full_mwh are required.
+func readSession(r io.Reader) ([]replayFrame, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[name] = i
+	}
+	for _, name := range []string{"time", "battery", "state", "energy_mwh", "full_mwh"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("not a recorded session: column %q missing", name)
+		}
+	}
+
+	var frames []replayFrame
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(row) {
+				return row[i]
+			}
+			return ""
+		}
+
+		at, err := time.Parse(time.RFC3339Nano, field("time"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid time: %w", line, err)
+		}
+		info, err := parseSessionRow(field)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		info.UpdatedAt = at
+
+		if len(frames) == 0 || !frames[len(frames)-1].time.Equal(at) {
+			frames = append(frames, replayFrame{time: at, onAC: field("on_ac") == "true"})
+		}
+		frame := &frames[len(frames)-1]
+		frame.batteries = append(frame.batteries, info)
+	}
+
+	sort.SliceStable(frames, func(i, j int) bool { return frames[i].time.Before(frames[j].time) })
+	for _, frame := range frames {
+		sort.Slice(frame.batteries, func(i, j int) bool { return frame.batteries[i].Index < frame.batteries[j].Index })
+	}
+	return frames, nil
+}
+
+// parseSessionRow parses the battery reading of a row; optional columns that
+// are missing or empty stay unset
+func parseSessionRow(field func(name string) string) (*Info, error) {
+	info := &Info{}
+	index, err := strconv.Atoi(field("battery"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid battery index: %w", err)
+	}
+	info.Index = index
+	info.State.UnmarshalText([]byte(field("state")))
+
+	for name, value := range map[string]*float64{
+		"energy_mwh":     &info.Current,
+		"full_mwh":       &info.Full,
+		"design_mwh":     &info.Design,
+		"charge_rate_mw": &info.ChargeRate,
+		"voltage_v":      &info.Voltage,
+		"temperature_c":  &info.Temperature,
+	} {
+		text := field(name)
+		if text == "" {
+			continue
+		}
+		if *value, err = strconv.ParseFloat(text, 64); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", name, err)
+		}
+	}
+	info.SmoothedChargeRate = info.ChargeRate
+	info.Capabilities.HasTemperature = field("temperature_c") != ""
+
+	if text := field("cycles"); text != "" {
+		if info.CycleCount, err = strconv.Atoi(text); err != nil {
+			return nil, fmt.Errorf("invalid cycles: %w", err)
+		}
+		info.Capabilities.HasCycles = true
+	}
+	return info, nil
+}
@@ -0,0 +1,92 @@
+package battery
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// session is a recording as written by -record
+const session = `time,on_ac,battery,state,percent,energy_mwh,full_mwh,design_mwh,charge_rate_mw,voltage_v,temperature_c,cycles
+2024-05-01T12:00:00Z,false,0,Discharging,80.0,40000,50000,57000,-8500,11.4,31.5,212
+2024-05-01T12:00:00Z,false,1,Charging,50.0,20000,40000,40000,5000,12,,
+2024-05-01T12:00:10Z,false,0,Discharging,79.9,39976,50000,57000,-8600,11.4,31.5,212
+2024-05-01T12:00:10Z,false,1,Charging,50.1,20014,40000,40000,5000,12,,
+2024-05-01T12:00:20Z,true,0,Charging,80.0,40000,50000,57000,20000,12.1,32,212
+2024-05-01T12:00:20Z,true,1,Charging,50.2,20028,40000,40000,5000,12,,
+`
+
+func TestReplaySource(t *testing.T) {
+	tests := []struct {
+		name string
+		step time.Duration
+		// want is the charge rate of battery 0 after each update
+		want []float64
+	}{
+		{name: "real time", step: 5 * time.Second, want: []float64{-8500, -8500, -8600, -8600, 20000, 20000}},
+		{name: "fast", step: 20 * time.Second, want: []float64{-8500, 20000, 20000}},
+		{name: "max", step: 0, want: []float64{-8500, -8600, 20000, 20000}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := NewReplaySource(strings.NewReader(session), tt.step)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, want := range tt.want {
+				if err := source.Update(); err != nil {
+					t.Fatal(err)
+				}
+				info, err := source.Get(0)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if info.ChargeRate != want {
+					t.Errorf("update %d: charge rate %v, want %v", i, info.ChargeRate, want)
+				}
+			}
+			select {
+			case <-source.Done():
+			default:
+				t.Error("not done after the last sample")
+			}
+		})
+	}
+}
+
+func TestReplaySourceReadings(t *testing.T) {
+	source, err := NewReplaySource(strings.NewReader(session), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source.Update()
+
+	infos, _ := source.GetAll()
+	if len(infos) != 2 {
+		t.Fatalf("got %d batteries, want 2", len(infos))
+	}
+	first, second := infos[0], infos[1]
+	if first.State != StateDischarging || first.Current != 40000 || first.Design != 57000 ||
+		first.Temperature != 31.5 || !first.Capabilities.HasTemperature ||
+		first.CycleCount != 212 || !first.Capabilities.HasCycles {
+		t.Errorf("battery 0 = %+v", first)
+	}
+	if want := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC); !first.UpdatedAt.Equal(want) {
+		t.Errorf("updated at %s, want the recorded %s", first.UpdatedAt, want)
+	}
+	if second.Capabilities.HasTemperature || second.Capabilities.HasCycles {
+		t.Errorf("battery 1 reports missing values: %+v", second.Capabilities)
+	}
+	if source.PowerSource().OnAC {
+		t.Error("on AC in the first sample")
+	}
+}
+
+func TestReplaySourceRejectsOtherFiles(t *testing.T) {
+	for _, input := range []string{"", "time,percent\n2024-05-01T12:00:00Z,80\n", strings.SplitAfter(session, "\n")[0]} {
+		if _, err := NewReplaySource(strings.NewReader(input), 0); err == nil {
+			t.Errorf("accepted %q", input)
+		}
+	}
+}
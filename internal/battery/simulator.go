@@ -0,0 +1,347 @@
+package battery
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Simulated battery model
+const (
+	// SimulatorDesign is the design capacity of the simulated battery in mWh
+	SimulatorDesign = 57000.0
+
+	// SimulatorFull is the last full capacity of the simulated battery in mWh
+	SimulatorFull = 51300.0
+
+	// SimulatorLoad is the power drawn while discharging in mW
+	SimulatorLoad = 9500.0
+
+	// SimulatorChargePower is the charge power below the taper threshold in mW
+	SimulatorChargePower = 30000.0
+
+	// simulatorTaper is the charge level above which the charge power tapers off
+	simulatorTaper = 0.8
+
+	// simulatorLow is the charge level at which the simulated user plugs in
+	simulatorLow = 0.1
+)
+
+// SimulatorSourceName selects the simulated battery in -source
+const SimulatorSourceName = "sim"
+
+// SimulatorStep is how far the simulated source advances on every update, so
+// a discharge and charge cycle plays out in a few minutes
+const SimulatorStep = time.Minute
+
+// sentinel is the all-ones 16-bit value firmwares report for unknown readings
+const sentinel = 0xFFFF
+
+// simReading is one raw reading of the simulator before it reaches the Manager
+type simReading struct {
+	battery Reading
+	stats   BatteryStats
+}
+
+// Quirk reproduces a known-bad firmware behaviour on top of the simulated readings
+type Quirk struct {
+	// Name identifies the preset
+	Name string
+
+	// Description explains the firmware behaviour being reproduced
+	Description string
+
+	apply func(r *simReading)
+}
+
+// Quirks lists the firmware quirk presets
+var Quirks = []Quirk{
+	{
+		Name:        "zero-rate",
+		Description: "charge rate always reported as 0",
+		apply: func(r *simReading) {
+			r.battery.ChargeRate = 0
+		},
+	},
+	{
+		Name:        "full-below-current",
+		Description: "last full capacity below the current charge near the top",
+		apply: func(r *simReading) {
+			r.battery.Full *= 0.9
+		},
+	},
+	{
+		Name:        "percent-cliff",
+		Description: "charge level drops from 30% to 5% at once",
+		apply: func(r *simReading) {
+			if level := r.battery.Current / r.battery.Full; level < 0.3 {
+				r.battery.Current = r.battery.Full * 0.05 * level / 0.3
+			}
+		},
+	},
+	{
+		Name:        "sentinel-65535",
+		Description: "unknown rate, voltage, cycles and temperature reported as 65535",
+		apply: func(r *simReading) {
+			r.battery.ChargeRate = sentinel
+			r.battery.Voltage = sentinel / 1e3
+			r.stats.CycleCount = sentinel
+			r.stats.Temperature = sentinel / 10.0
+		},
+	},
+}
+
+// QuirkByName returns the quirk preset with the given name
+func QuirkByName(name string) (Quirk, error) {
+	for _, quirk := range Quirks {
+		if quirk.Name == name {
+			return quirk, nil
+		}
+	}
+
+	names := make([]string, len(Quirks))
+	for i, quirk := range Quirks {
+		names[i] = quirk.Name
+	}
+	return Quirk{}, fmt.Errorf("unknown quirk %q (valid: %s)", name, strings.Join(names, ", "))
+}
+
+// Simulator models a single laptop battery in virtual time. It discharges at
+// a constant load down to 10%, then charges with a taper above 80% until
+// full, and starts over. Readings are passed through the configured quirks.
+type Simulator struct {
+	mu      sync.Mutex
+	now     time.Time
+	state   State
+	current float64
+	cycles  int
+	quirks  []Quirk
+
+	// load is the power drawn while discharging in mW
+	load float64
+
+	// noise varies the load and jitters the readings (nil keeps the model exact)
+	noise *rand.Rand
+}
+
+// NewSimulator creates a discharging simulator at 75% charge
+func NewSimulator(start time.Time, quirks ...Quirk) *Simulator {
+	return &Simulator{
+		now:     start,
+		state:   StateDischarging,
+		current: SimulatorFull * 0.75,
+		cycles:  210,
+		quirks:  quirks,
+		load:    SimulatorLoad,
+	}
+}
+
+// SetNoise makes the simulator plausible rather than exact: the load wanders
+// between half and twice SimulatorLoad like a user switching tasks, and the
+// rate and voltage readings jitter like a real fuel gauge
+func (s *Simulator) SetNoise(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.noise = rand.New(rand.NewSource(seed))
+}
+
+// NewSimulatedManager creates a manager that reads the simulator instead of the
+// platform, so the whole conversion pipeline runs on simulated readings
+func NewSimulatedManager(sim *Simulator) *Manager {
+	m := NewReaderManager(sim)
+	m.now = sim.Now
+	m.platformReader = sim
+	return m
+}
+
+// SimulatedSource reads a simulated battery through a Manager, advancing the
+// simulator by a fixed step of virtual time on every update
+type SimulatedSource struct {
+	*Manager
+	sim  *Simulator
+	step time.Duration
+}
+
+// NewSimulatedSource creates a source advancing the simulator by step on
+// every update
+func NewSimulatedSource(sim *Simulator, step time.Duration) *SimulatedSource {
+	return &SimulatedSource{Manager: NewSimulatedManager(sim), sim: sim, step: step}
+}
+
+// openSimulator creates the source of -source sim or sim:quirk,...: a noisy
+// simulator starting now with the given quirk presets
+func openSimulator(quirkNames string) (*SimulatedSource, error) {
+	var quirks []Quirk
+	for _, name := range strings.Split(quirkNames, ",") {
+		if name == "" {
+			continue
+		}
+		quirk, err := QuirkByName(name)
+		if err != nil {
+			return nil, err
+		}
+		quirks = append(quirks, quirk)
+	}
+
+	now := time.Now()
+	sim := NewSimulator(now, quirks...)
+	sim.SetNoise(now.UnixNano())
+	return NewSimulatedSource(sim, SimulatorStep), nil
+}
+
+// Update advances the simulator and reads it
+func (s *SimulatedSource) Update() error {
+	s.sim.Advance(s.step)
+	return s.Manager.Update()
+}
+
+// Now returns the virtual time of the simulator
+func (s *Simulator) Now() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.now
+}
+
+// Advance moves the virtual time forward and updates the charge
+func (s *Simulator) Advance(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.now = s.now.Add(d)
+	s.current += s.rate() * d.Hours()
+	if s.noise != nil {
+		s.load *= 1 + 0.15*s.noise.NormFloat64()
+		s.load = min(max(s.load, SimulatorLoad/2), SimulatorLoad*2)
+	}
+
+	switch {
+	case s.state == StateDischarging && s.current <= SimulatorFull*simulatorLow:
+		s.state = StateCharging
+	case s.state == StateCharging && s.current >= SimulatorFull:
+		s.current = SimulatorFull
+		s.state = StateFull
+		s.cycles++
+	case s.state == StateFull:
+		s.state = StateDischarging
+	}
+}
+
+// rate returns the signed charge rate of the model in mW
+func (s *Simulator) rate() float64 {
+	switch s.state {
+	case StateDischarging:
+		return -s.load
+	case StateCharging:
+		level := s.current / SimulatorFull
+		if level <= simulatorTaper {
+			return SimulatorChargePower
+		}
+		// Constant voltage phase: the power falls linearly towards full
+		return SimulatorChargePower * max((1-level)/(1-simulatorTaper), 0.05)
+	default:
+		return 0
+	}
+}
+
+// reading returns the current raw reading with quirks applied
+func (s *Simulator) reading() simReading {
+	rate := s.rate()
+	if rate < 0 {
+		rate = -rate
+	}
+
+	r := simReading{
+		battery: Reading{
+			State:         s.state,
+			Current:       s.current,
+			Full:          SimulatorFull,
+			Design:        SimulatorDesign,
+			ChargeRate:    rate,
+			Voltage:       11.1 + 1.5*s.current/SimulatorFull,
+			DesignVoltage: 11.55,
+		},
+		stats: BatteryStats{
+			CycleCount:   s.cycles,
+			Manufacturer: "battop",
+			ModelName:    "Simulator",
+			SerialNumber: "SIM-0001",
+			Technology:   "Li-poly",
+			Temperature:  30 + rate/SimulatorChargePower*8,
+
+			FirmwareVersion: "1.0.0",
+			ManufactureDate: "2024-01-15",
+		},
+	}
+	if s.noise != nil {
+		r.battery.ChargeRate *= 1 + 0.02*s.noise.NormFloat64()
+		r.battery.Voltage += 0.01 * s.noise.NormFloat64()
+	}
+	for _, quirk := range s.quirks {
+		quirk.apply(&r)
+	}
+	return r
+}
+
+// Read returns the simulated battery as the platform would
+func (s *Simulator) Read() ([]*Reading, []error, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.reading()
+	return []*Reading{&r.battery}, nil, nil
+}
+
+// ReadBatteryStats returns the simulated platform statistics
+func (s *Simulator) ReadBatteryStats(batteryIndex int) (BatteryStats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if batteryIndex != 0 {
+		return BatteryStats{}, fmt.Errorf("simulated battery %d not found", batteryIndex)
+	}
+	return s.reading().stats, nil
+}
+
+// ReadACAdapters returns a simulated mains adapter, online unless discharging
+func (s *Simulator) ReadACAdapters() ([]ACAdapter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return []ACAdapter{{
+		Name:     "AC",
+		Type:     "Mains",
+		Online:   s.state != StateDischarging,
+		Kind:     ChargerBarrel,
+		MaxPower: 65000,
+	}}, nil
+}
+
+// Capabilities reports the data the simulator provides
+func (s *Simulator) Capabilities(int) Capabilities {
+	return Capabilities{
+		HasExtendedStats: true,
+		HasTemperature:   true,
+		HasCycles:        true,
+		HasACAdapters:    true,
+	}
+}
+
+// BatteryPresent reports the simulated battery as inserted and any further
+// slot as an empty bay
+func (s *Simulator) BatteryPresent(batteryIndex int) bool {
+	return batteryIndex == 0
+}
+
+// Name returns the reader name including the active quirks
+func (s *Simulator) Name() string {
+	if len(s.quirks) == 0 {
+		return "simulator"
+	}
+	names := make([]string, len(s.quirks))
+	for i, quirk := range s.quirks {
+		names[i] = quirk.Name
+	}
+	return "simulator (" + strings.Join(names, ", ") + ")"
+}
@@ -0,0 +1,150 @@
+package battery
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// simulate runs a simulated manager over a full discharge and charge cycle
+// and calls check with every reading
+func simulate(t *testing.T, check func(info *Info), quirks ...Quirk) {
+	t.Helper()
+	sim := NewSimulator(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC), quirks...)
+	manager := NewSimulatedManager(sim)
+
+	for step := 0; step < 8*360; step++ {
+		if err := manager.Update(); err != nil {
+			t.Fatalf("update: %v", err)
+		}
+		info, err := manager.Get(0)
+		if err != nil {
+			t.Fatalf("get: %v", err)
+		}
+		check(info)
+		sim.Advance(10 * time.Second)
+	}
+}
+
+// assertSane fails on readings the UI can't display sensibly
+func assertSane(t *testing.T, info *Info) {
+	t.Helper()
+	for name, v := range map[string]float64{
+		"current":     info.Current,
+		"rate":        info.ChargeRate,
+		"smoothed":    info.SmoothedChargeRate,
+		"voltage":     info.Voltage,
+		"temperature": info.Temperature,
+	} {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			t.Fatalf("%s is %v at %s", name, v, info.UpdatedAt)
+		}
+	}
+	if p := info.ChargePercent(); p < 0 || p > 100 {
+		t.Fatalf("charge %.1f%% out of range at %s", p, info.UpdatedAt)
+	}
+	if info.Current > info.Full {
+		t.Fatalf("current %.0f above full %.0f at %s", info.Current, info.Full, info.UpdatedAt)
+	}
+	if info.TimeToEmpty() < 0 || info.TimeToFull() < 0 || info.SmoothedTimeToEmpty() < 0 || info.SmoothedTimeToFull() < 0 {
+		t.Fatalf("negative estimate at %s", info.UpdatedAt)
+	}
+}
+
+func TestSimulatorQuirks(t *testing.T) {
+	simulate(t, func(info *Info) { assertSane(t, info) })
+
+	for _, quirk := range Quirks {
+		t.Run(quirk.Name, func(t *testing.T) {
+			simulate(t, func(info *Info) { assertSane(t, info) }, quirk)
+		})
+	}
+}
+
+func TestQuirkZeroRateFallsBackToTrend(t *testing.T) {
+	quirk, err := QuirkByName("zero-rate")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	estimated := 0
+	simulate(t, func(info *Info) {
+		if info.NetChargeRate >= 0 || info.State != StateDischarging {
+			return
+		}
+		if info.TimeToEmpty() <= 0 {
+			t.Fatalf("no time to empty with a net rate of %.0f mW at %s", info.NetChargeRate, info.UpdatedAt)
+		}
+		estimated++
+	}, quirk)
+	if estimated == 0 {
+		t.Fatal("no discharging readings with a trend")
+	}
+}
+
+func TestQuirkSentinelsDropped(t *testing.T) {
+	quirk, err := QuirkByName("sentinel-65535")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	simulate(t, func(info *Info) {
+		if info.CycleCount == sentinel || info.Capabilities.HasCycles {
+			t.Fatalf("sentinel cycle count shown: %d", info.CycleCount)
+		}
+		if info.Temperature != 0 || info.Capabilities.HasTemperature {
+			t.Fatalf("sentinel temperature shown: %.1f", info.Temperature)
+		}
+		if info.Voltage != 0 {
+			t.Fatalf("sentinel voltage shown: %.3f", info.Voltage)
+		}
+		if math.Abs(info.ChargeRate) >= sentinel {
+			t.Fatalf("sentinel rate shown: %.0f", info.ChargeRate)
+		}
+	}, quirk)
+}
+
+func TestQuirkByNameUnknown(t *testing.T) {
+	if _, err := QuirkByName("flux-capacitor"); err == nil {
+		t.Fatal("expected an error for an unknown quirk")
+	}
+}
+
+func TestSimulatedSourceWithNoise(t *testing.T) {
+	source, err := OpenSource("sim:zero-rate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim := source.(*SimulatedSource)
+	sim.sim.SetNoise(1)
+
+	states := make(map[State]bool)
+	rates := make(map[float64]bool)
+	for step := 0; step < 8*60; step++ {
+		if err := source.Update(); err != nil {
+			t.Fatalf("update: %v", err)
+		}
+		info, err := source.Get(0)
+		if err != nil {
+			t.Fatalf("get: %v", err)
+		}
+		assertSane(t, info)
+		states[info.State] = true
+		if info.State == StateDischarging {
+			rates[info.NetChargeRate] = true
+		}
+	}
+
+	for _, state := range []State{StateDischarging, StateCharging, StateFull} {
+		if !states[state] {
+			t.Errorf("never %s", state)
+		}
+	}
+	if len(rates) < 10 {
+		t.Errorf("only %d distinct discharge rates, want a varying load", len(rates))
+	}
+
+	if _, err := OpenSource("sim:flux-capacitor"); err == nil {
+		t.Error("unknown quirk accepted")
+	}
+}
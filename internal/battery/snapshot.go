@@ -0,0 +1,94 @@
+package battery
+
+import (
+	"sync"
+	"time"
+
+	pkgErrors "github.com/xsikor/go-battop/internal/errors"
+)
+
+// snapshot holds the latest readings of a source and hands out copies, so
+// sources only need to implement Update
+type snapshot struct {
+	mu          sync.RWMutex
+	batteries   []*Info
+	powerSource PowerSource
+	lastError   error
+}
+
+// GetAll returns all battery information
+func (s *snapshot) GetAll() ([]*Info, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.lastError != nil {
+		return nil, s.lastError
+	}
+
+	// Return a copy to prevent data races
+	result := make([]*Info, len(s.batteries))
+	for i, bat := range s.batteries {
+		batCopy := *bat
+		result[i] = &batCopy
+	}
+	return result, nil
+}
+
+// Get returns battery information by index
+func (s *snapshot) Get(index int) (*Info, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.lastError != nil {
+		return nil, s.lastError
+	}
+	if index < 0 || index >= len(s.batteries) {
+		return nil, pkgErrors.ErrBatteryNotFound
+	}
+
+	batCopy := *s.batteries[index]
+	return &batCopy, nil
+}
+
+// PowerSource returns the current power source
+func (s *snapshot) PowerSource() PowerSource {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	source := s.powerSource
+	source.Adapters = append([]ACAdapter(nil), s.powerSource.Adapters...)
+	return source
+}
+
+// Count returns the number of batteries
+func (s *snapshot) Count() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.batteries)
+}
+
+// set stores new readings and clears the last error
+func (s *snapshot) set(infos []*Info, source PowerSource) {
+	s.mu.Lock()
+	s.batteries = infos
+	s.powerSource = source
+	s.lastError = nil
+	s.mu.Unlock()
+}
+
+// touch stamps the readings with the time of an update that found them unchanged
+func (s *snapshot) touch(now time.Time) {
+	s.mu.Lock()
+	for _, bat := range s.batteries {
+		bat.UpdatedAt = now
+	}
+	s.mu.Unlock()
+}
+
+// setLastError sets the last error with proper locking
+func (s *snapshot) setLastError(err error) error {
+	s.mu.Lock()
+	s.lastError = err
+	s.mu.Unlock()
+	return err
+}
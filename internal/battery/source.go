@@ -0,0 +1,107 @@
+package battery
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Source provides battery readings to the UI and the application from the
+// local batteries (Manager, reading through distatus/battery or sysfs),
+// another battop instance (RemoteSource), a UPS daemon (NUTSource,
+// APCUPSDSource), UPower (UPowerSource) or Android (TermuxSource)
+type Source interface {
+	// Update refreshes the readings
+	Update() error
+
+	// GetAll returns copies of all battery readings
+	GetAll() ([]*Info, error)
+
+	// Get returns a copy of the battery reading at the index
+	Get(index int) (*Info, error)
+
+	// PowerSource returns the current power source
+	PowerSource() PowerSource
+
+	// Count returns the number of batteries
+	Count() int
+}
+
+var (
+	_ Source = (*Manager)(nil)
+	_ Source = (*RemoteSource)(nil)
+	_ Source = (*NUTSource)(nil)
+	_ Source = (*APCUPSDSource)(nil)
+	_ Source = (*UPowerSource)(nil)
+	_ Source = (*TermuxSource)(nil)
+	_ Source = (*ReplaySource)(nil)
+	_ Source = (*SimulatedSource)(nil)
+
+	_ Notifier     = (*UPowerSource)(nil)
+	_ StateHistory = (*UPowerSource)(nil)
+)
+
+// Notifier is implemented by sources that signal changes as they happen, so
+// the UI can refresh without waiting for the next tick
+type Notifier interface {
+	// Changes is signalled after the readings changed; call Update to read them
+	Changes() <-chan struct{}
+}
+
+// StateRecord is the battery state at a point in time
+type StateRecord struct {
+	Time  time.Time
+	State State
+}
+
+// StateHistory is implemented by sources that keep a history of battery
+// states from before battop started
+type StateHistory interface {
+	// StateHistory returns the recorded states over the span, oldest first
+	StateHistory(index int, span time.Duration) ([]StateRecord, error)
+}
+
+// Names of the local sources in -source
+const (
+	// LocalSourceName selects the local batteries read through distatus/battery
+	LocalSourceName = "local"
+
+	// SysfsSourceName selects the local batteries read from sysfs alone
+	SysfsSourceName = "sysfs"
+)
+
+// OpenSource creates the source for a -source value: "local", "sysfs",
+// "upower", "termux", "sim" (optionally followed by :quirk,...), a nut:// or
+// apcupsd:// URL or the http(s):// URL of another battop instance
+func OpenSource(spec string) (Source, error) {
+	if name, quirks, _ := strings.Cut(spec, ":"); name == SimulatorSourceName {
+		return openSimulator(quirks)
+	}
+
+	switch spec {
+	case LocalSourceName:
+		return NewManager(), nil
+	case SysfsSourceName:
+		reader, err := newSysfsReader()
+		if err != nil {
+			return nil, err
+		}
+		return NewReaderManager(reader), nil
+	case UPowerSourceName:
+		return NewUPowerSource()
+	case TermuxSourceName:
+		return NewTermuxSource()
+	}
+
+	scheme, _, _ := strings.Cut(spec, "://")
+	switch scheme {
+	case "nut":
+		return NewNUTSource(spec)
+	case "apcupsd":
+		return NewAPCUPSDSource(spec)
+	case "http", "https":
+		return NewRemoteSource(spec), nil
+	default:
+		return nil, fmt.Errorf("unsupported source: expected local, sysfs, upower, termux, sim, nut://, apcupsd:// or http://")
+	}
+}
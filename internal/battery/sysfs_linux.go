@@ -0,0 +1,102 @@
+//go:build linux
+
+package battery
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// sysfsReader reads the batteries straight from the power_supply class,
+// without the distatus/battery library
+type sysfsReader struct {
+	root string
+}
+
+// newSysfsReader creates a reader of /sys/class/power_supply
+func newSysfsReader() (Reader, error) {
+	if _, err := os.Stat(powerSupplyPath); err != nil {
+		return nil, fmt.Errorf("sysfs power supplies unavailable: %w", err)
+	}
+	return &sysfsReader{root: powerSupplyPath}, nil
+}
+
+// Name returns the reader description
+func (r *sysfsReader) Name() string {
+	return "sysfs (" + r.root + ")"
+}
+
+// Read reads every system battery in name order; peripheral batteries (scope
+// Device) are left to the peripheral reader
+func (r *sysfsReader) Read() ([]*Reading, []error, error) {
+	entries, err := os.ReadDir(r.root)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var readings []*Reading
+	var slotErrors []error
+	for _, entry := range entries {
+		dir := filepath.Join(r.root, entry.Name())
+		if kind, _ := readSysfsString(filepath.Join(dir, "type")); kind != "Battery" {
+			continue
+		}
+		if scope, _ := readSysfsString(filepath.Join(dir, "scope")); scope == "Device" {
+			continue
+		}
+		reading, err := readSysfsBattery(dir)
+		readings = append(readings, reading)
+		slotErrors = append(slotErrors, err)
+	}
+	return readings, slotErrors, nil
+}
+
+// readSysfsBattery reads one battery directory. Drivers report either energy
+// (µWh, µW) or charge (µAh, µA), which is converted with the voltage.
+func readSysfsBattery(dir string) (*Reading, error) {
+	if present, err := readSysfsInt(filepath.Join(dir, "present")); err == nil && present == 0 {
+		return nil, errors.New("no battery inserted")
+	}
+
+	reading := &Reading{}
+	reading.State.UnmarshalText([]byte(readSysfsStringOr(dir, "status")))
+	reading.Voltage = float64(readSysfsIntOr(dir, "voltage_now")) / 1e6
+	reading.DesignVoltage = float64(readSysfsIntOr(dir, "voltage_min_design")) / 1e6
+
+	if now, err := readSysfsInt(filepath.Join(dir, "energy_now")); err == nil {
+		reading.Current = float64(now) / 1e3
+		reading.Full = float64(readSysfsIntOr(dir, "energy_full")) / 1e3
+		reading.Design = float64(readSysfsIntOr(dir, "energy_full_design")) / 1e3
+	} else if now, err := readSysfsInt(filepath.Join(dir, "charge_now")); err == nil {
+		voltage := reading.DesignVoltage
+		if voltage == 0 {
+			voltage = reading.Voltage
+		}
+		reading.Current = float64(now) * voltage / 1e3
+		reading.Full = float64(readSysfsIntOr(dir, "charge_full")) * voltage / 1e3
+		reading.Design = float64(readSysfsIntOr(dir, "charge_full_design")) * voltage / 1e3
+	} else {
+		return nil, fmt.Errorf("%s reports neither energy_now nor charge_now", filepath.Base(dir))
+	}
+
+	if power, err := readSysfsInt(filepath.Join(dir, "power_now")); err == nil {
+		reading.ChargeRate = float64(power) / 1e3
+	} else if current, err := readSysfsInt(filepath.Join(dir, "current_now")); err == nil {
+		reading.ChargeRate = float64(current) * reading.Voltage / 1e3
+	}
+	return reading, nil
+}
+
+// readSysfsStringOr reads an attribute of dir, or "" when it is missing
+func readSysfsStringOr(dir, name string) string {
+	value, _ := readSysfsString(filepath.Join(dir, name))
+	return value
+}
+
+// readSysfsIntOr reads an integer attribute of dir, or 0 when it is missing
+func readSysfsIntOr(dir, name string) int {
+	value, _ := readSysfsInt(filepath.Join(dir, name))
+	return value
+}
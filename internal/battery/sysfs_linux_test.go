@@ -0,0 +1,61 @@
+//go:build linux
+
+package battery
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeSysfs creates a power supply directory with the given attributes
+func writeSysfs(t *testing.T, root, name string, attributes map[string]string) {
+	t.Helper()
+	dir := filepath.Join(root, name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for attribute, value := range attributes {
+		if err := os.WriteFile(filepath.Join(dir, attribute), []byte(value+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSysfsReader(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, "AC", map[string]string{"type": "Mains", "online": "1"})
+	writeSysfs(t, root, "BAT0", map[string]string{
+		"type": "Battery", "status": "Discharging",
+		"energy_now": "40000000", "energy_full": "50000000", "energy_full_design": "57000000",
+		"power_now": "8500000", "voltage_now": "11400000", "voltage_min_design": "11100000",
+	})
+	writeSysfs(t, root, "BAT1", map[string]string{
+		"type": "Battery", "status": "Charging",
+		"charge_now": "2000000", "charge_full": "4000000", "charge_full_design": "4000000",
+		"current_now": "1000000", "voltage_now": "12000000", "voltage_min_design": "10000000",
+	})
+	writeSysfs(t, root, "BAT2", map[string]string{"type": "Battery", "present": "0"})
+	writeSysfs(t, root, "hid-mouse-battery", map[string]string{"type": "Battery", "scope": "Device", "capacity": "50"})
+
+	readings, slotErrors, err := (&sysfsReader{root: root}).Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(readings) != 3 {
+		t.Fatalf("read %d batteries, want 3", len(readings))
+	}
+
+	want := Reading{State: StateDischarging, Current: 40000, Full: 50000, Design: 57000, ChargeRate: 8500, Voltage: 11.4, DesignVoltage: 11.1}
+	if readings[0] == nil || *readings[0] != want {
+		t.Errorf("energy battery = %+v, want %+v", readings[0], want)
+	}
+	// Charge is converted with the design voltage, the current with the voltage
+	want = Reading{State: StateCharging, Current: 20000, Full: 40000, Design: 40000, ChargeRate: 12000, Voltage: 12, DesignVoltage: 10}
+	if readings[1] == nil || *readings[1] != want {
+		t.Errorf("charge battery = %+v, want %+v", readings[1], want)
+	}
+	if readings[2] != nil || slotErrors[2] == nil {
+		t.Errorf("empty bay = %+v (%v), want an empty slot", readings[2], slotErrors[2])
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package battery
+
+import "errors"
+
+// newSysfsReader fails: sysfs only exists on Linux
+func newSysfsReader() (Reader, error) {
+	return nil, errors.New("the sysfs source is only available on Linux")
+}
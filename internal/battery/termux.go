@@ -0,0 +1,246 @@
+package battery
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TermuxSourceName selects the Android source in -source
+const TermuxSourceName = "termux"
+
+// androidBatteryPath is the sysfs directory of the Android battery
+const androidBatteryPath = "/sys/class/power_supply/battery"
+
+// Android defaults for values the device doesn't expose
+const (
+	// AndroidNominalVoltage is the nominal voltage of a single Li-ion phone cell in V
+	AndroidNominalVoltage = 3.85
+
+	// AndroidNominalCapacity is the capacity in mWh assumed when Android
+	// exposes neither the charge counter nor the full charge (~4000 mAh)
+	AndroidNominalCapacity = 15000.0
+)
+
+// TermuxSource reads the battery of an Android device running battop in
+// Termux, from sysfs when readable and otherwise from termux-battery-status
+// (Termux:API), since newer Android versions hide power_supply from apps
+type TermuxSource struct {
+	snapshot
+	estimator *RateEstimator
+}
+
+// NewTermuxSource creates a source for the Android battery
+func NewTermuxSource() (*TermuxSource, error) {
+	if !sysfsReadable(androidBatteryPath) {
+		if _, err := exec.LookPath("termux-battery-status"); err != nil {
+			return nil, fmt.Errorf("%s isn't readable and termux-battery-status isn't installed (pkg install termux-api)", androidBatteryPath)
+		}
+	}
+	return &TermuxSource{estimator: NewRateEstimator(DefaultSmoothingSamples)}, nil
+}
+
+// InTermux reports whether battop runs inside Termux on Android
+func InTermux() bool {
+	return os.Getenv("TERMUX_VERSION") != "" || strings.HasPrefix(os.Getenv("PREFIX"), "/data/data/com.termux")
+}
+
+// Update reads the battery from sysfs or termux-battery-status
+func (s *TermuxSource) Update() error {
+	now := time.Now()
+
+	var info *Info
+	var source PowerSource
+	if sysfsReadable(androidBatteryPath) {
+		info, source = androidSysfsInfo(func(name string) (string, bool) {
+			data, err := os.ReadFile(filepath.Join(androidBatteryPath, name))
+			return strings.TrimSpace(string(data)), err == nil
+		}, now)
+	} else {
+		out, err := exec.Command("termux-battery-status").Output()
+		if err != nil {
+			return s.setLastError(fmt.Errorf("termux-battery-status failed: %w", err))
+		}
+		if info, source, err = termuxInfo(out, now); err != nil {
+			return s.setLastError(err)
+		}
+	}
+
+	s.estimator.Add(info.ChargeRate, info.State)
+	info.SmoothedChargeRate = s.estimator.Rate()
+	info.EstimateConfidence = s.estimator.Confidence()
+
+	s.set([]*Info{info}, source)
+	return nil
+}
+
+// sysfsReadable reports whether the battery capacity can be read, which
+// SELinux denies to apps on most Android versions
+func sysfsReadable(path string) bool {
+	_, err := os.ReadFile(filepath.Join(path, "capacity"))
+	return err == nil
+}
+
+// termuxStatus is the JSON printed by termux-battery-status
+type termuxStatus struct {
+	Percentage  float64 `json:"percentage"`
+	Status      string  `json:"status"`
+	Plugged     string  `json:"plugged"`
+	Health      string  `json:"health"`
+	Temperature float64 `json:"temperature"`
+
+	// Current is in µA; its sign differs between devices
+	Current float64 `json:"current"`
+
+	// Voltage is in mV (reported by newer Termux:API versions)
+	Voltage float64 `json:"voltage"`
+}
+
+// termuxInfo maps termux-battery-status output to battery information
+func termuxInfo(out []byte, now time.Time) (*Info, PowerSource, error) {
+	var status termuxStatus
+	if err := json.Unmarshal(out, &status); err != nil {
+		return nil, PowerSource{}, fmt.Errorf("invalid termux-battery-status output: %w", err)
+	}
+
+	info := &Info{
+		State:        androidState(status.Status),
+		Technology:   "Li-ion",
+		Temperature:  status.Temperature,
+		Capabilities: Capabilities{HasTemperature: true, HasACAdapters: true},
+		UpdatedAt:    now,
+	}
+	if status.Voltage > 0 {
+		info.Voltage = status.Voltage / 1000
+	}
+	setAndroidEnergy(info, status.Percentage, 0, 0, math.Abs(status.Current))
+
+	return info, androidPowerSource(strings.TrimPrefix(status.Plugged, "PLUGGED_")), nil
+}
+
+// androidSysfsInfo maps the Android power_supply attributes returned by read
+// to battery information. Android reports currents in µA, voltages in µV and
+// charges in µAh.
+func androidSysfsInfo(read func(name string) (string, bool), now time.Time) (*Info, PowerSource) {
+	number := func(name string) (float64, bool) {
+		value, ok := read(name)
+		if !ok {
+			return 0, false
+		}
+		v, err := strconv.ParseFloat(value, 64)
+		return v, err == nil && !math.IsNaN(v) && !math.IsInf(v, 0)
+	}
+
+	status, _ := read("status")
+	technology, _ := read("technology")
+	info := &Info{
+		State:        androidState(status),
+		Technology:   Coalesce(technology, "Li-ion"),
+		Capabilities: Capabilities{HasExtendedStats: true, HasACAdapters: true},
+		UpdatedAt:    now,
+	}
+	if microvolts, ok := number("voltage_now"); ok && microvolts > 0 {
+		info.Voltage = microvolts / 1e6
+	}
+	if temp, ok := number("temp"); ok {
+		info.Temperature = temp / 10
+		info.Capabilities.HasTemperature = true
+	}
+	if cycles, ok := number("cycle_count"); ok && cycles > 0 {
+		info.CycleCount = int(cycles)
+		info.Capabilities.HasCycles = true
+	}
+
+	percent, _ := number("capacity")
+	full, _ := number("charge_full")
+	design, _ := number("charge_full_design")
+	current, _ := number("current_now")
+	setAndroidEnergy(info, percent, full, design, math.Abs(current))
+
+	// The charger type is reported by sibling supplies (ac, usb, wireless)
+	plugged := ""
+	for _, kind := range []string{"ac", "usb", "wireless"} {
+		if online, ok := read(filepath.Join("..", kind, "online")); ok && online == "1" {
+			plugged = strings.ToUpper(kind)
+			break
+		}
+	}
+	if plugged == "" && info.State != StateDischarging {
+		plugged = "AC"
+	}
+	return info, androidPowerSource(plugged)
+}
+
+// setAndroidEnergy converts Android's charge readings to the mWh and mW
+// conventions of Info. full and design are in µAh and microamps in µA (0
+// when unknown); charges are converted to energy at the cell voltage.
+func setAndroidEnergy(info *Info, percent, full, design, microamps float64) {
+	volts := info.Voltage
+	if volts <= 0 {
+		volts = AndroidNominalVoltage
+	}
+	nominal := AndroidNominalVoltage
+
+	info.Full = AndroidNominalCapacity
+	if full > 0 {
+		info.Full = full / 1000 * nominal
+	}
+	if design > 0 {
+		info.Design = design / 1000 * nominal
+	}
+	info.Current = math.Min(math.Max(percent, 0), 100) / 100 * info.Full
+
+	// mA × V = mW
+	info.ChargeRate = microamps / 1000 * volts
+	if info.State == StateDischarging {
+		info.ChargeRate = -info.ChargeRate
+	}
+	if info.State == StateFull || info.State == StateNotCharging {
+		info.ChargeRate = 0
+	}
+}
+
+// androidState maps an Android battery status ("Charging", "DISCHARGING",
+// "Not charging", ...) to a battery state
+func androidState(status string) State {
+	switch strings.ToUpper(strings.ReplaceAll(status, " ", "_")) {
+	case "CHARGING":
+		return StateCharging
+	case "DISCHARGING":
+		return StateDischarging
+	case "FULL":
+		return StateFull
+	case "NOT_CHARGING":
+		return StateNotCharging
+	default:
+		return StateUnknown
+	}
+}
+
+// androidPowerSource describes the charger from the plug type ("AC", "USB",
+// "WIRELESS", or empty when unplugged)
+func androidPowerSource(plugged string) PowerSource {
+	switch plugged {
+	case "AC", "USB", "WIRELESS":
+	default:
+		return PowerSource{Detected: true}
+	}
+
+	// Android's "AC" is any wall charger, usually USB, so only a computer's
+	// USB port is known to be low power
+	kind := ChargerUnknown
+	if plugged == "USB" {
+		kind = ChargerUSB
+	}
+	return PowerSource{
+		Detected: true,
+		OnAC:     true,
+		Adapters: []ACAdapter{{Name: strings.ToLower(plugged), Type: plugged, Online: true, Kind: kind}},
+	}
+}
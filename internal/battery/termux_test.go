@@ -0,0 +1,57 @@
+package battery
+
+import (
+	"math"
+	"path"
+	"testing"
+	"time"
+)
+
+func FuzzTermuxInfo(f *testing.F) {
+	f.Add([]byte(`{"health":"GOOD","percentage":85,"plugged":"UNPLUGGED","status":"DISCHARGING","temperature":29.5,"current":-412000}`))
+	f.Add([]byte(`{"health":"GOOD","percentage":40,"plugged":"PLUGGED_USB","status":"CHARGING","temperature":33.1,"current":950000,"voltage":4120}`))
+	f.Add([]byte(`{"percentage":-5,"status":"FULL","current":1e308}`))
+	f.Add([]byte(`[]`))
+
+	f.Fuzz(func(t *testing.T, out []byte) {
+		info, source, err := termuxInfo(out, time.Now())
+		if err != nil {
+			return
+		}
+		if percent := info.ChargePercent(); percent < 0 || percent > 100 {
+			t.Errorf("charge %v%% out of range", percent)
+		}
+		if info.State == StateDischarging && info.ChargeRate > 0 {
+			t.Errorf("discharging at a positive rate %v", info.ChargeRate)
+		}
+		_ = source.MaxPower()
+	})
+}
+
+func FuzzAndroidSysfsInfo(f *testing.F) {
+	f.Add("Discharging", "76", "-350000", "3870000", "4410000", "5000000", "285")
+	f.Add("Charging", "40", "1200000", "4100000", "", "", "")
+	f.Add("Not charging", "100", "0", "0", "0", "0", "-40")
+	f.Add("", "x", "NaN", "Inf", "-1", "1e308", "")
+
+	f.Fuzz(func(t *testing.T, status, capacity, current, voltage, full, design, temp string) {
+		attrs := map[string]string{
+			"status":             status,
+			"capacity":           capacity,
+			"current_now":        current,
+			"voltage_now":        voltage,
+			"charge_full":        full,
+			"charge_full_design": design,
+			"temp":               temp,
+			"../usb/online":      "1",
+		}
+		read := func(name string) (string, bool) {
+			value, ok := attrs[path.Clean(name)]
+			return value, ok && value != ""
+		}
+		info, _ := androidSysfsInfo(read, time.Now())
+		if percent := info.ChargePercent(); math.IsNaN(percent) || percent < 0 || percent > 100 {
+			t.Errorf("charge %v%% out of range", percent)
+		}
+	})
+}
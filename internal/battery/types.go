@@ -1,6 +1,7 @@
 package battery
 
 import (
+	"math"
 	"time"
 )
 
@@ -20,8 +21,35 @@ const (
 	StateDischarging
 	// StateNotCharging indicates the battery is not charging (but not necessarily discharging)
 	StateNotCharging
+
+	// Extended states reported by UPS, BMS and daemon sources. Each behaves
+	// like its Base state for estimates and statistics.
+
+	// StateNotPresent indicates an empty battery slot or a UPS without a battery
+	StateNotPresent
+	// StatePendingCharge indicates the battery waits to start charging
+	StatePendingCharge
+	// StatePendingDischarge indicates the battery waits to start discharging
+	StatePendingDischarge
+	// StateOnBattery indicates a UPS supplying the load from its battery
+	StateOnBattery
+	// StateBoost indicates a UPS raising a low mains voltage
+	StateBoost
+	// StateTrim indicates a UPS lowering a high mains voltage
+	StateTrim
+	// StateBypass indicates a UPS passing mains power through without protection
+	StateBypass
+	// StateCalibrating indicates a UPS runtime calibration discharging the battery
+	StateCalibrating
 )
 
+// states lists every state in declaration order
+var states = []State{
+	StateUnknown, StateEmpty, StateFull, StateCharging, StateDischarging, StateNotCharging,
+	StateNotPresent, StatePendingCharge, StatePendingDischarge,
+	StateOnBattery, StateBoost, StateTrim, StateBypass, StateCalibrating,
+}
+
 // String returns string representation of battery state
 func (s State) String() string {
 	switch s {
@@ -35,57 +63,138 @@ func (s State) String() string {
 		return "Discharging"
 	case StateNotCharging:
 		return "Not charging"
+	case StateNotPresent:
+		return "Not present"
+	case StatePendingCharge:
+		return "Pending charge"
+	case StatePendingDischarge:
+		return "Pending discharge"
+	case StateOnBattery:
+		return "On battery"
+	case StateBoost:
+		return "Boost"
+	case StateTrim:
+		return "Trim"
+	case StateBypass:
+		return "Bypass"
+	case StateCalibrating:
+		return "Calibrating"
 	default:
 		return "Unknown"
 	}
 }
 
+// Base returns the basic state an extended state behaves like
+func (s State) Base() State {
+	switch s {
+	case StateNotPresent:
+		return StateUnknown
+	case StatePendingCharge, StatePendingDischarge, StateBoost, StateTrim, StateBypass:
+		return StateNotCharging
+	case StateOnBattery, StateCalibrating:
+		return StateDischarging
+	default:
+		return s
+	}
+}
+
+// MarshalText encodes the state by name so JSON output stays readable
+func (s State) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a state name, falling back to StateUnknown
+func (s *State) UnmarshalText(text []byte) error {
+	*s = StateUnknown
+	for _, state := range states {
+		if state.String() == string(text) {
+			*s = state
+		}
+	}
+	return nil
+}
+
 // Info represents comprehensive battery information including state, capacity, and health metrics
 type Info struct {
 	// Index is the battery index (0-based)
-	Index int
+	Index int `json:"index"`
 
 	// State is the current battery state
-	State State
+	State State `json:"state"`
 
 	// Current capacity in mWh
-	Current float64
+	Current float64 `json:"current_mwh"`
 
 	// Full capacity in mWh (last full charge)
-	Full float64
+	Full float64 `json:"full_mwh"`
 
 	// Design capacity in mWh
-	Design float64
+	Design float64 `json:"design_mwh"`
 
 	// Charge rate in mW (positive = charging, negative = discharging)
-	ChargeRate float64
+	ChargeRate float64 `json:"charge_rate_mw"`
+
+	// Smoothed charge rate in mW (exponential moving average of ChargeRate)
+	SmoothedChargeRate float64 `json:"smoothed_charge_rate_mw"`
+
+	// Net charge rate in mW derived from capacity changes over the last
+	// minutes (0 until enough readings are available)
+	NetChargeRate float64 `json:"net_charge_rate_mw"`
 
 	// Voltage in V
-	Voltage float64
+	Voltage float64 `json:"voltage_v"`
 
 	// Design voltage in V
-	DesignVoltage float64
+	DesignVoltage float64 `json:"design_voltage_v"`
 
 	// Cycle count (if available)
-	CycleCount int
+	CycleCount int `json:"cycle_count"`
 
 	// Technology (e.g., "Li-ion")
-	Technology string
+	Technology string `json:"technology"`
 
 	// Serial number
-	Serial string
+	Serial string `json:"serial"`
 
 	// Model name
-	Model string
+	Model string `json:"model"`
 
 	// Manufacturer
-	Manufacturer string
+	Manufacturer string `json:"manufacturer"`
+
+	// Chemistry is the cell chemistry reported by vendor tools (e.g., "LION")
+	Chemistry string `json:"chemistry,omitempty"`
+
+	// Firmware is the firmware version of the battery controller
+	Firmware string `json:"firmware,omitempty"`
+
+	// ManufactureDate is the date the battery was made (YYYY-MM-DD)
+	ManufactureDate string `json:"manufacture_date,omitempty"`
+
+	// ManufactureDateFromSerial is true when ManufactureDate was decoded from the serial number
+	ManufactureDateFromSerial bool `json:"manufacture_date_from_serial,omitempty"`
 
 	// Temperature in Celsius (if available)
-	Temperature float64
+	Temperature float64 `json:"temperature_c"`
+
+	// Capabilities reports which optional fields the platform provides
+	Capabilities Capabilities `json:"capabilities"`
+
+	// EstimateConfidence rates the time estimates derived from the smoothed charge rate
+	EstimateConfidence Confidence `json:"estimate_confidence"`
+
+	// ChargeLimit is the vendor charge limit stopping the charge early, nil when none is active
+	ChargeLimit *ChargeLimit `json:"charge_limit,omitempty"`
+
+	// Warning is the low battery warning level reported by the source
+	// (e.g., "low", "critical"), empty when there is none
+	Warning string `json:"warning,omitempty"`
+
+	// Idle is true when the sample was taken while the user session was idle or locked
+	Idle bool `json:"idle"`
 
 	// Last update time
-	UpdatedAt time.Time
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 // ChargePercent returns the current charge percentage
@@ -103,6 +212,24 @@ func (b *Info) ChargePercent() float64 {
 	return percent
 }
 
+// DesignPercent returns the current capacity as a percentage of the design
+// capacity, which shows how much energy a worn battery really holds
+func (b *Info) DesignPercent() float64 {
+	if b.Design <= 0 {
+		return b.ChargePercent()
+	}
+	return math.Min(math.Max(b.Current/b.Design*100, 0), 100)
+}
+
+// NominalVoltage returns the voltage capacities convert to charge (mAh) at:
+// the design voltage, or the present voltage when it isn't known
+func (b *Info) NominalVoltage() float64 {
+	if b.DesignVoltage > 0 {
+		return b.DesignVoltage
+	}
+	return b.Voltage
+}
+
 // Health returns battery health percentage (full capacity vs design capacity)
 func (b *Info) Health() float64 {
 	if b.Design <= 0 {
@@ -118,20 +245,66 @@ func (b *Info) Health() float64 {
 	return health
 }
 
+// Cold derating of usable Li-ion capacity: below ColdDeratingStart the
+// usable capacity shrinks by ColdDeratingPerDegree per °C, down to MinColdFactor
+const (
+	ColdDeratingStart     = 20.0
+	ColdDeratingPerDegree = 0.01
+	MinColdFactor         = 0.5
+)
+
+// TemperatureFactor returns the share of the remaining energy that is usable
+// at the battery temperature: 1 when warm or unknown, less when cold
+func (b *Info) TemperatureFactor() float64 {
+	if !b.Capabilities.HasTemperature || b.Temperature >= ColdDeratingStart {
+		return 1
+	}
+	factor := 1 - (ColdDeratingStart-b.Temperature)*ColdDeratingPerDegree
+	return math.Max(factor, MinColdFactor)
+}
+
+// UsableEnergy returns the remaining energy in mWh that can be drawn at the
+// battery temperature, which is what the time to empty is estimated from
+func (b *Info) UsableEnergy() float64 {
+	return b.Current * b.TemperatureFactor()
+}
+
 // TimeToEmpty estimates time until battery is empty (during discharge)
+// using the instantaneous charge rate
 func (b *Info) TimeToEmpty() time.Duration {
-	if b.ChargeRate >= 0 || b.Current <= 0 {
+	return b.timeToEmpty(b.ChargeRate)
+}
+
+// TimeToFull estimates time until battery is full (during charge)
+// using the instantaneous charge rate
+func (b *Info) TimeToFull() time.Duration {
+	return b.timeToFull(b.ChargeRate)
+}
+
+// SmoothedTimeToEmpty estimates time until battery is empty using the smoothed charge rate
+func (b *Info) SmoothedTimeToEmpty() time.Duration {
+	return b.timeToEmpty(b.SmoothedChargeRate)
+}
+
+// SmoothedTimeToFull estimates time until battery is full using the smoothed charge rate
+func (b *Info) SmoothedTimeToFull() time.Duration {
+	return b.timeToFull(b.SmoothedChargeRate)
+}
+
+// timeToEmpty estimates time until empty for the given charge rate
+func (b *Info) timeToEmpty(rate float64) time.Duration {
+	if rate >= 0 || b.Current <= 0 {
 		return 0
 	}
-	hours := b.Current / (-b.ChargeRate)
+	hours := b.UsableEnergy() / (-rate)
 	return time.Duration(hours * float64(time.Hour))
 }
 
-// TimeToFull estimates time until battery is full (during charge)
-func (b *Info) TimeToFull() time.Duration {
-	if b.ChargeRate <= 0 || b.Full <= b.Current {
+// timeToFull estimates time until full for the given charge rate
+func (b *Info) timeToFull(rate float64) time.Duration {
+	if rate <= 0 || b.Full <= b.Current {
 		return 0
 	}
-	hours := (b.Full - b.Current) / b.ChargeRate
+	hours := (b.Full - b.Current) / rate
 	return time.Duration(hours * float64(time.Hour))
 }
@@ -0,0 +1,52 @@
+package battery
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeToEmptyCold(t *testing.T) {
+	tests := []struct {
+		name        string
+		temperature float64
+		reported    bool
+		energy      float64
+		tte         time.Duration
+	}{
+		{"warm", 25, true, 40000, 4 * time.Hour},
+		{"at the derating start", ColdDeratingStart, true, 40000, 4 * time.Hour},
+		{"cool", 10, true, 36000, 3*time.Hour + 36*time.Minute},
+		{"freezing", -40, true, 20000, 2 * time.Hour},
+		{"temperature unknown", -10, false, 40000, 4 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := &Info{
+				State:              StateDischarging,
+				Current:            40000,
+				Full:               50000,
+				ChargeRate:         -10000,
+				SmoothedChargeRate: -10000,
+				Temperature:        tt.temperature,
+				Capabilities:       Capabilities{HasTemperature: tt.reported},
+			}
+			if got := info.UsableEnergy(); got != tt.energy {
+				t.Errorf("usable energy %.0f mWh, want %.0f mWh", got, tt.energy)
+			}
+			if got := info.TimeToEmpty(); got != tt.tte {
+				t.Errorf("time to empty %v, want %v", got, tt.tte)
+			}
+			if got := info.SmoothedTimeToEmpty(); got != tt.tte {
+				t.Errorf("smoothed time to empty %v, want %v", got, tt.tte)
+			}
+		})
+	}
+
+	// The charge still to be stored is not affected by the cold
+	charging := &Info{State: StateCharging, Current: 40000, Full: 50000, ChargeRate: 10000, Temperature: 0,
+		Capabilities: Capabilities{HasTemperature: true}}
+	if got := charging.TimeToFull(); got != time.Hour {
+		t.Errorf("time to full %v while cold, want 1h", got)
+	}
+}
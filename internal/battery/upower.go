@@ -0,0 +1,364 @@
+package battery
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	pkgErrors "github.com/xsikor/go-battop/internal/errors"
+)
+
+// UPowerSourceName selects the UPower source in -source
+const UPowerSourceName = "upower"
+
+// upowerObjectPrefix is the D-Bus object path prefix of UPower devices
+const upowerObjectPrefix = "/org/freedesktop/UPower/devices/"
+
+// UPowerSource reads the batteries known to the UPower daemon. Instead of
+// polling, it follows UPower's change notifications (upower --monitor) and
+// only reads the devices again after UPower reported a change.
+//
+// It talks to UPower through its command line tools rather than D-Bus
+// directly, keeping battop free of a D-Bus dependency: upower --dump and
+// --monitor for the devices and their PropertiesChanged signals, and busctl
+// for the history. It depends on the text layout of their output.
+type UPowerSource struct {
+	snapshot
+	start   sync.Once
+	cancel  context.CancelFunc
+	watch   atomic.Bool
+	stale   atomic.Bool
+	changes chan struct{}
+
+	mu    sync.Mutex
+	paths []string
+
+	// estimators smooth the charge rate of each battery by object path, fed
+	// with every fresh reading from UPower
+	estimators map[string]*RateEstimator
+}
+
+// NewUPowerSource creates a source reading from UPower through the upower
+// command line tool
+func NewUPowerSource() (*UPowerSource, error) {
+	if _, err := exec.LookPath("upower"); err != nil {
+		return nil, fmt.Errorf("the upower source needs the upower command line tool (usually in the upower package): %w", err)
+	}
+	s := &UPowerSource{changes: make(chan struct{}, 1), estimators: make(map[string]*RateEstimator)}
+	s.stale.Store(true)
+	return s, nil
+}
+
+// Update reads the devices from UPower when they changed since the last read.
+// Unchanged readings are still stamped with the time of the update, so the
+// statistics and charts see a fresh sample on every tick.
+func (s *UPowerSource) Update() error {
+	s.start.Do(s.startMonitor)
+	if s.watch.Load() && !s.stale.Load() {
+		s.touch(time.Now())
+		return nil
+	}
+	s.stale.Store(false)
+
+	out, err := exec.Command("upower", "--dump").Output()
+	if err != nil {
+		return s.setLastError(fmt.Errorf("upower --dump failed: %w", err))
+	}
+
+	infos, paths, source := parseUPowerDump(string(out), time.Now())
+	if len(infos) == 0 {
+		return s.setLastError(pkgErrors.ErrNoBatteries)
+	}
+
+	s.mu.Lock()
+	s.paths = paths
+	s.smoothChargeRates(infos, paths)
+	s.mu.Unlock()
+	s.set(infos, source)
+	return nil
+}
+
+// smoothChargeRates feeds the batteries' rate estimators and stores the
+// smoothed rates and their confidence; the caller holds s.mu
+func (s *UPowerSource) smoothChargeRates(infos []*Info, paths []string) {
+	for i, info := range infos {
+		estimator, ok := s.estimators[paths[i]]
+		if !ok {
+			estimator = NewRateEstimator(DefaultSmoothingSamples)
+			s.estimators[paths[i]] = estimator
+		}
+		estimator.Add(info.ChargeRate, info.State)
+		info.SmoothedChargeRate = estimator.Rate()
+		info.EstimateConfidence = estimator.Confidence()
+	}
+}
+
+// Changes returns a channel signalled whenever UPower reports a change
+func (s *UPowerSource) Changes() <-chan struct{} {
+	return s.changes
+}
+
+// Close stops following UPower's change notifications
+func (s *UPowerSource) Close() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	return nil
+}
+
+// startMonitor follows upower --monitor; without it every Update reads the
+// devices again
+func (s *UPowerSource) startMonitor() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := exec.CommandContext(ctx, "upower", "--monitor")
+	stdout, err := cmd.StdoutPipe()
+	if err == nil {
+		err = cmd.Start()
+	}
+	if err != nil {
+		cancel()
+		slog.Warn("Failed to follow UPower changes, polling instead", "error", err)
+		return
+	}
+	s.cancel = cancel
+	s.watch.Store(true)
+
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			// Every line reports a device added, changed or removed
+			s.stale.Store(true)
+			select {
+			case s.changes <- struct{}{}:
+			default:
+			}
+		}
+		s.watch.Store(false)
+		if err := cmd.Wait(); err != nil && ctx.Err() == nil {
+			slog.Warn("UPower monitor stopped, polling instead", "error", err)
+		}
+	}()
+}
+
+// StateHistory returns the states UPower recorded for the battery over the span
+func (s *UPowerSource) StateHistory(index int, span time.Duration) ([]StateRecord, error) {
+	s.mu.Lock()
+	if index < 0 || index >= len(s.paths) {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("battery %d not found", index)
+	}
+	path := s.paths[index]
+	s.mu.Unlock()
+
+	if _, err := exec.LookPath("busctl"); err != nil {
+		return nil, fmt.Errorf("reading UPower's history needs busctl (part of systemd): %w", err)
+	}
+
+	// GetHistory returns (time, value, state) tuples, newest first
+	out, err := exec.Command("busctl", "call", "--system",
+		"org.freedesktop.UPower", path, "org.freedesktop.UPower.Device",
+		"GetHistory", "suu", "charge", strconv.Itoa(int(span.Seconds())), "0",
+	).Output()
+	if err != nil {
+		return nil, fmt.Errorf("busctl GetHistory failed: %w", err)
+	}
+	return parseUPowerHistory(string(out))
+}
+
+// parseUPowerHistory parses busctl output like "a(udu) 2 1700000000 80 2 1699999000 81 2"
+// into records sorted oldest first
+func parseUPowerHistory(out string) ([]StateRecord, error) {
+	fields := strings.Fields(out)
+	if len(fields) < 2 || fields[0] != "a(udu)" {
+		return nil, fmt.Errorf("unexpected GetHistory reply %q", out)
+	}
+	count, err := strconv.Atoi(fields[1])
+	if err != nil || count < 0 || count > len(fields) || len(fields) != 2+3*count {
+		return nil, fmt.Errorf("unexpected GetHistory reply %q", out)
+	}
+
+	records := make([]StateRecord, 0, count)
+	for i := count - 1; i >= 0; i-- {
+		entry := fields[2+3*i:]
+		at, errTime := strconv.ParseInt(entry[0], 10, 64)
+		state, errState := strconv.Atoi(entry[2])
+		if errTime != nil || errState != nil {
+			return nil, fmt.Errorf("unexpected GetHistory entry %q", strings.Join(entry[:3], " "))
+		}
+		records = append(records, StateRecord{Time: time.Unix(at, 0), State: upowerState(state)})
+	}
+	return records, nil
+}
+
+// upowerState maps the UPower device state enum to a battery state
+func upowerState(state int) State {
+	switch state {
+	case 1:
+		return StateCharging
+	case 2:
+		return StateDischarging
+	case 3:
+		return StateEmpty
+	case 4: // fully charged
+		return StateFull
+	case 5:
+		return StatePendingCharge
+	case 6:
+		return StatePendingDischarge
+	default:
+		return StateUnknown
+	}
+}
+
+// upowerDevice is one device block of upower --dump output
+type upowerDevice struct {
+	path  string
+	props map[string]string
+}
+
+// display reports whether the device is the DisplayDevice aggregate of all batteries
+func (d upowerDevice) display() bool {
+	return strings.HasSuffix(d.path, "/DisplayDevice")
+}
+
+// splitUPowerDump splits upower --dump output into its devices and the daemon properties
+func splitUPowerDump(out string) ([]upowerDevice, map[string]string) {
+	var devices []upowerDevice
+	daemon := make(map[string]string)
+
+	for _, block := range strings.Split(out, "\n\n") {
+		header, body, _ := strings.Cut(strings.TrimSpace(block), "\n")
+		switch {
+		case header == "Daemon:":
+			daemon = parseUPowerProperties(body)
+		case strings.HasPrefix(header, "Device: "+upowerObjectPrefix):
+			devices = append(devices, upowerDevice{
+				path:  strings.TrimPrefix(header, "Device: "),
+				props: parseUPowerProperties(body),
+			})
+		}
+	}
+	return devices, daemon
+}
+
+// upowerStateName maps a UPower state name (e.g., "pending-charge") to a battery state
+func upowerStateName(state string) State {
+	switch state {
+	case "charging":
+		return StateCharging
+	case "discharging":
+		return StateDischarging
+	case "empty":
+		return StateEmpty
+	case "fully-charged":
+		return StateFull
+	case "pending-charge":
+		return StatePendingCharge
+	case "pending-discharge":
+		return StatePendingDischarge
+	default:
+		return StateUnknown
+	}
+}
+
+// parseUPowerDump parses upower --dump output into the power supply batteries,
+// their object paths and the power source
+func parseUPowerDump(out string, now time.Time) ([]*Info, []string, PowerSource) {
+	var infos []*Info
+	var paths []string
+
+	devices, daemon := splitUPowerDump(out)
+	for _, device := range devices {
+		// Skip peripherals (mice, headsets), line power and the aggregate
+		if device.props["power supply"] != "yes" || device.props["kind"] != "battery" || device.display() {
+			continue
+		}
+		infos = append(infos, upowerInfo(len(infos), device.props, now))
+		paths = append(paths, device.path)
+	}
+	return infos, paths, PowerSource{Detected: true, OnAC: daemon["on-battery"] != "yes"}
+}
+
+// parseUPowerProperties parses the "key: value" lines of a device block. The
+// first line without a value (e.g., "battery") is recorded under "kind".
+func parseUPowerProperties(body string) map[string]string {
+	props := make(map[string]string)
+	for _, line := range strings.Split(body, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			if kind := strings.TrimSpace(line); kind != "" && props["kind"] == "" {
+				props["kind"] = kind
+			}
+			continue
+		}
+		props[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), "'")
+	}
+	return props
+}
+
+// upowerNumber parses a property carrying a unit suffix, e.g. "45.2 Wh" or "79%"
+func upowerNumber(props map[string]string, key string) (float64, bool) {
+	fields := strings.Fields(strings.TrimSuffix(props[key], "%"))
+	if len(fields) == 0 {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(fields[0], 64)
+	return v, err == nil
+}
+
+// upowerInfo maps the properties of a UPower battery to battery information
+func upowerInfo(index int, props map[string]string, now time.Time) *Info {
+	number := func(key string) (float64, bool) {
+		return upowerNumber(props, key)
+	}
+
+	info := &Info{
+		Index:        index,
+		Technology:   props["technology"],
+		Model:        props["model"],
+		Manufacturer: props["vendor"],
+		Serial:       props["serial"],
+		Capabilities: Capabilities{HasExtendedStats: true},
+		UpdatedAt:    now,
+	}
+
+	info.State = upowerStateName(props["state"])
+	if props["present"] == "no" {
+		info.State = StateNotPresent
+	}
+	if level := props["warning-level"]; level != "none" && level != "unknown" {
+		info.Warning = level
+	}
+
+	// UPower reports energy in Wh and the rate in W as an absolute value
+	energy, _ := number("energy")
+	full, _ := number("energy-full")
+	design, _ := number("energy-full-design")
+	rate, _ := number("energy-rate")
+	info.Current, info.Full, info.Design = energy*1000, full*1000, design*1000
+	info.ChargeRate = rate * 1000
+	if info.State.Base() == StateDischarging {
+		info.ChargeRate = -info.ChargeRate
+	}
+
+	info.Voltage, _ = number("voltage")
+	if cycles, ok := number("charge-cycles"); ok && cycles > 0 {
+		info.CycleCount = int(cycles)
+		info.Capabilities.HasCycles = true
+	}
+	if temp, ok := number("temperature"); ok {
+		info.Temperature = temp
+		info.Capabilities.HasTemperature = true
+	}
+	if end, ok := number("charge-end-threshold"); ok && props["charge-threshold-enabled"] == "yes" && end > 0 && end < 100 {
+		info.ChargeLimit = &ChargeLimit{Percent: end, Mode: ChargeLimitThreshold}
+	}
+	return info
+}
@@ -0,0 +1,161 @@
+package battery
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const upowerDumpSample = `Device: /org/freedesktop/UPower/devices/line_power_AC
+  native-path:          AC
+  power supply:         yes
+  updated:              Thu 01 Feb 2024 10:00:00 AM CET (5 seconds ago)
+  line-power
+    warning-level:       none
+    online:              no
+
+Device: /org/freedesktop/UPower/devices/battery_BAT0
+  native-path:          BAT0
+  vendor:               SMP
+  model:                5B10W13930
+  serial:               1234
+  power supply:         yes
+  battery
+    present:             yes
+    state:               discharging
+    warning-level:       low
+    energy:              40.12 Wh
+    energy-full:         50.3 Wh
+    energy-full-design:  57 Wh
+    energy-rate:         8.123 W
+    voltage:             11.9 V
+    charge-cycles:       120
+    percentage:          79%
+    technology:          lithium-polymer
+    icon-name:          'battery-good-symbolic'
+
+Device: /org/freedesktop/UPower/devices/mouse_dev_C4_A1_00_11_22_33
+  native-path:          /org/bluez/hci0/dev_C4_A1_00_11_22_33
+  model:                MX Master 3
+  power supply:         no
+  mouse
+    warning-level:       none
+    percentage:          65%
+
+Device: /org/freedesktop/UPower/devices/DisplayDevice
+  power supply:         yes
+  battery
+    state:               discharging
+
+Daemon:
+  daemon-version:  0.99.20
+  on-battery:      yes
+`
+
+func FuzzParseUPowerDump(f *testing.F) {
+	f.Add(upowerDumpSample)
+	f.Add("Device: /org/freedesktop/UPower/devices/battery_BAT1\n  power supply: yes\n  battery\n    energy: NaN Wh\n")
+	f.Add("Daemon:\n")
+	f.Add("")
+
+	f.Fuzz(func(t *testing.T, out string) {
+		infos, paths, _ := parseUPowerDump(out, time.Now())
+		if len(infos) != len(paths) {
+			t.Fatalf("%d batteries but %d paths", len(infos), len(paths))
+		}
+		for i, info := range infos {
+			if info.Index != i {
+				t.Errorf("battery %d has index %d", i, info.Index)
+			}
+			_ = info.ChargePercent()
+		}
+	})
+}
+
+func FuzzParseUPowerHistory(f *testing.F) {
+	f.Add("a(udu) 2 1700000600 79 2 1700000000 80 4\n")
+	f.Add("a(udu) 0\n")
+	f.Add("a(udu) 1 x 1 2")
+	f.Add("a(udu) 99999999999 1")
+	f.Add("")
+
+	f.Fuzz(func(t *testing.T, out string) {
+		records, err := parseUPowerHistory(out)
+		if err != nil {
+			return
+		}
+		for i := 1; i < len(records); i++ {
+			_ = records[i].Time.Sub(records[i-1].Time)
+		}
+	})
+}
+
+func FuzzParsePeripherals(f *testing.F) {
+	f.Add(upowerDumpSample)
+	f.Add("Device: /org/freedesktop/UPower/devices/headset\n  power supply: no\n  headset\n    percentage: 120%\n")
+	f.Add("Device: /org/freedesktop/UPower/devices/\n  percentage: 5\n")
+
+	f.Fuzz(func(t *testing.T, out string) {
+		for _, peripheral := range parsePeripherals(out) {
+			if peripheral.Kind == "line-power" {
+				t.Errorf("line power listed as a peripheral: %+v", peripheral)
+			}
+		}
+	})
+}
+
+func TestUPowerSourceStampsUnchangedReadings(t *testing.T) {
+	recorded := time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC)
+	s := &UPowerSource{changes: make(chan struct{}, 1)}
+	s.start.Do(func() {})
+	s.watch.Store(true)
+	s.set([]*Info{{Index: 0, State: StateDischarging, UpdatedAt: recorded}}, PowerSource{})
+
+	if err := s.Update(); err != nil {
+		t.Fatal(err)
+	}
+	info, err := s.Get(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.UpdatedAt.After(recorded) {
+		t.Errorf("updated at %s, want the time of the update", info.UpdatedAt)
+	}
+}
+
+func TestUPowerSourceSmoothsChargeRate(t *testing.T) {
+	s := &UPowerSource{estimators: make(map[string]*RateEstimator)}
+	var info *Info
+	for _, rate := range []string{"8.123 W", "12 W", "10 W", "9 W"} {
+		infos, paths, _ := parseUPowerDump(strings.Replace(upowerDumpSample, "8.123 W", rate, 1), time.Now())
+		s.smoothChargeRates(infos, paths)
+		info = infos[0]
+	}
+
+	if info.ChargeRate != -9000 {
+		t.Errorf("instant rate %.0f mW, want -9000 mW", info.ChargeRate)
+	}
+	if info.SmoothedChargeRate >= -9000 || info.SmoothedChargeRate <= -12000 {
+		t.Errorf("smoothed rate %.0f mW, want an average of the readings", info.SmoothedChargeRate)
+	}
+	if info.EstimateConfidence == ConfidenceUnknown {
+		t.Error("estimate confidence still unknown after four readings")
+	}
+	if len(s.estimators) != 1 {
+		t.Errorf("%d estimators for one battery", len(s.estimators))
+	}
+}
+
+func TestUPowerMissingTools(t *testing.T) {
+	// No upower or busctl on the path
+	t.Setenv("PATH", t.TempDir())
+
+	if _, err := NewUPowerSource(); err == nil || !strings.Contains(err.Error(), "upower command line tool") {
+		t.Errorf("NewUPowerSource() error = %v, want one naming the upower tool", err)
+	}
+
+	s := &UPowerSource{paths: []string{upowerObjectPrefix + "battery_BAT0"}}
+	if _, err := s.StateHistory(0, time.Hour); err == nil || !strings.Contains(err.Error(), "busctl") {
+		t.Errorf("StateHistory() error = %v, want one naming busctl", err)
+	}
+}
@@ -0,0 +1,37 @@
+package battery
+
+// upsNominalCapacity is the capacity in mWh assumed when a UPS reports
+// neither its load nor its runtime, so the charge is still shown
+const upsNominalCapacity = 100000.0
+
+// setUPSEnergy fills the capacities and the charge rate of a UPS battery from
+// the charge percentage, the runtime in seconds and the load in mW (0 when
+// unknown). UPS daemons rarely report energy, so it is derived from the
+// runtime at the current load and the time to empty matches the runtime.
+func setUPSEnergy(info *Info, charge, runtime, load float64) {
+	info.Current = charge / 100 * upsNominalCapacity
+	if runtime > 0 && load > 0 {
+		info.Current = load * runtime / 3600
+	}
+	info.Full = upsNominalCapacity
+	if charge > 0 {
+		info.Full = info.Current / (charge / 100)
+	}
+	info.Design = info.Full
+
+	if info.State.Base() == StateDischarging && runtime > 0 {
+		info.ChargeRate = -info.Current / (runtime / 3600)
+	}
+	info.SmoothedChargeRate = info.ChargeRate
+}
+
+// upsPowerSource reports mains power unless every UPS runs on battery
+func upsPowerSource(infos []*Info) PowerSource {
+	source := PowerSource{Detected: true}
+	for _, info := range infos {
+		if info.State.Base() != StateDischarging {
+			source.OnAC = true
+		}
+	}
+	return source
+}
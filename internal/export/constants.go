@@ -0,0 +1,23 @@
+package export
+
+import "time"
+
+const (
+	// InfluxWriteTimeout limits how long a write to InfluxDB may take
+	InfluxWriteTimeout = 5 * time.Second
+
+	// InfluxQueueSize is the number of samples queued while a write is in progress
+	InfluxQueueSize = 64
+
+	// MQTTTimeout limits connecting to the MQTT broker and each write
+	MQTTTimeout = 5 * time.Second
+
+	// MQTTKeepAlive is the keep-alive interval announced to the MQTT broker
+	MQTTKeepAlive = time.Minute
+
+	// MQTTRetryInterval is the shortest time between two connection attempts
+	MQTTRetryInterval = 30 * time.Second
+
+	// MQTTQueueSize is the number of samples queued while publishing
+	MQTTQueueSize = 64
+)
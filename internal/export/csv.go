@@ -0,0 +1,117 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/battery"
+)
+
+// CSVHeader is the header of a recorded session: one row per battery and
+// sample, with the temperature and cycle count empty when not reported.
+// battery.ReplaySource reads the columns back by name.
+var CSVHeader = []string{
+	"time", "on_ac", "battery", "state", "percent",
+	"energy_mwh", "full_mwh", "design_mwh", "charge_rate_mw", "voltage_v",
+	"temperature_c", "cycles",
+}
+
+func init() {
+	Register("csv", newCSVRecorder)
+}
+
+// newCSVRecorder creates the recorder for -record
+func newCSVRecorder(settings Settings) (Exporter, error) {
+	if settings["record"] == "" {
+		return nil, nil
+	}
+	return NewCSVRecorder(settings["record"]), nil
+}
+
+// CSVRecorder appends every sample to a CSV file. Writing a local file is
+// fast, so the samples are written as they are exported and flushed right
+// away, keeping the file complete if battop is killed.
+type CSVRecorder struct {
+	path   string
+	file   *os.File
+	writer *csv.Writer
+}
+
+// NewCSVRecorder creates a recorder appending to the file at path
+func NewCSVRecorder(path string) *CSVRecorder {
+	return &CSVRecorder{path: path}
+}
+
+// Start opens the file, writing the header when it is new or empty
+func (r *CSVRecorder) Start() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	r.file = file
+	r.writer = csv.NewWriter(file)
+	if info.Size() == 0 {
+		r.writer.Write(CSVHeader)
+	}
+	return nil
+}
+
+// Export writes the rows of a sample
+func (r *CSVRecorder) Export(sample Sample) {
+	for _, row := range csvRows(sample) {
+		r.writer.Write(row)
+	}
+	r.writer.Flush()
+	if err := r.writer.Error(); err != nil {
+		slog.Warn("Failed to record sample", "path", r.path, "error", err)
+	}
+}
+
+// Stop closes the file
+func (r *CSVRecorder) Stop() {
+	r.writer.Flush()
+	r.file.Close()
+}
+
+// csvRows encodes a sample as one row per battery. Empty battery slots are
+// skipped.
+func csvRows(sample Sample) [][]string {
+	var rows [][]string
+	for _, info := range sample.Batteries {
+		if info.State == battery.StateNotPresent {
+			continue
+		}
+		temperature, cycles := "", ""
+		if info.Capabilities.HasTemperature {
+			temperature = strconv.FormatFloat(info.Temperature, 'f', -1, 64)
+		}
+		if info.Capabilities.HasCycles {
+			cycles = strconv.Itoa(info.CycleCount)
+		}
+		rows = append(rows, []string{
+			sample.Time.Format(time.RFC3339Nano),
+			strconv.FormatBool(sample.OnAC),
+			strconv.Itoa(info.Index),
+			info.State.String(),
+			fmt.Sprintf("%.1f", info.ChargePercent()),
+			strconv.FormatFloat(info.Current, 'f', -1, 64),
+			strconv.FormatFloat(info.Full, 'f', -1, 64),
+			strconv.FormatFloat(info.Design, 'f', -1, 64),
+			strconv.FormatFloat(info.ChargeRate, 'f', -1, 64),
+			strconv.FormatFloat(info.Voltage, 'f', -1, 64),
+			temperature,
+			cycles,
+		})
+	}
+	return rows
+}
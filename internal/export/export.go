@@ -0,0 +1,128 @@
+// Package export sends the battery samples to external sinks such as
+// InfluxDB, an MQTT broker or a CSV file. Every sink implements Exporter and
+// registers a factory, so the application opens whatever the settings enable
+// without knowing the individual sinks.
+package export
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/battery"
+)
+
+// Sample is one reading of all batteries; it is also one line of the JSON
+// stream and one WebSocket message
+type Sample struct {
+	Time      time.Time       `json:"time"`
+	OnAC      bool            `json:"on_ac"`
+	Warmup    bool            `json:"warmup,omitempty"`
+	Batteries []*battery.Info `json:"batteries"`
+}
+
+// Exporter sends samples to a sink. Export is called on every update and
+// must not block; exporters talking to the network queue the samples and
+// drop them when the sink falls behind.
+type Exporter interface {
+	// Start starts the exporter
+	Start() error
+
+	// Export hands a sample to the exporter
+	Export(sample Sample)
+
+	// Stop flushes the queued samples and stops the exporter
+	Stop()
+}
+
+// Settings are the exporter options by flag name (e.g., "influx-url")
+type Settings map[string]string
+
+// Factory creates an exporter from the settings. It returns nil without an
+// error when the settings don't enable the exporter.
+type Factory func(settings Settings) (Exporter, error)
+
+var (
+	registryMu sync.Mutex
+	registry   = make(map[string]Factory)
+)
+
+// Register makes an exporter available under a name. It panics when the
+// name is registered twice.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, ok := registry[name]; ok {
+		panic("export: exporter registered twice: " + name)
+	}
+	registry[name] = factory
+}
+
+// Registered returns the names of the registered exporters, sorted
+func Registered() []string {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Set is the group of exporters enabled by the settings
+type Set struct {
+	names     []string
+	exporters []Exporter
+}
+
+// Open creates and starts every exporter the settings enable. When one fails
+// the started ones are stopped again.
+func Open(settings Settings) (*Set, error) {
+	set := &Set{}
+	for _, name := range Registered() {
+		registryMu.Lock()
+		factory := registry[name]
+		registryMu.Unlock()
+
+		exporter, err := factory(settings)
+		if err == nil && exporter != nil {
+			err = exporter.Start()
+		}
+		if err != nil {
+			set.Stop()
+			return nil, fmt.Errorf("failed to start the %s exporter: %w", name, err)
+		}
+		if exporter == nil {
+			continue
+		}
+		slog.Info("Exporter started", "exporter", name)
+		set.names = append(set.names, name)
+		set.exporters = append(set.exporters, exporter)
+	}
+	return set, nil
+}
+
+// Names returns the names of the started exporters
+func (s *Set) Names() []string {
+	return s.names
+}
+
+// Export hands a sample to every exporter
+func (s *Set) Export(sample Sample) {
+	for _, exporter := range s.exporters {
+		exporter.Export(sample)
+	}
+}
+
+// Stop stops every exporter
+func (s *Set) Stop() {
+	for _, exporter := range s.exporters {
+		exporter.Stop()
+	}
+	s.names, s.exporters = nil, nil
+}
@@ -0,0 +1,93 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/battery"
+)
+
+// fakeExporter records the calls it receives
+type fakeExporter struct {
+	started, stopped bool
+	samples          []Sample
+}
+
+func (f *fakeExporter) Start() error         { f.started = true; return nil }
+func (f *fakeExporter) Export(sample Sample) { f.samples = append(f.samples, sample) }
+func (f *fakeExporter) Stop()                { f.stopped = true }
+
+func TestOpen(t *testing.T) {
+	fake := &fakeExporter{}
+	Register("test", func(settings Settings) (Exporter, error) {
+		if settings["test"] == "" {
+			return nil, nil
+		}
+		return fake, nil
+	})
+	defer func() {
+		registryMu.Lock()
+		delete(registry, "test")
+		registryMu.Unlock()
+	}()
+
+	set, err := Open(Settings{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set.Names()) != 0 {
+		t.Errorf("started %v without settings", set.Names())
+	}
+
+	set, err = Open(Settings{"test": "on"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := set.Names(); len(names) != 1 || names[0] != "test" || !fake.started {
+		t.Fatalf("started %v", names)
+	}
+	set.Export(Sample{})
+	set.Stop()
+	if len(fake.samples) != 1 || !fake.stopped {
+		t.Errorf("exported %d samples, stopped %v", len(fake.samples), fake.stopped)
+	}
+
+	if _, err := Open(Settings{"influx-url": "ftp://example"}); err == nil {
+		t.Error("invalid influx-url accepted")
+	}
+}
+
+func TestCSVRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.csv")
+	sample := Sample{
+		Time: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Batteries: []*battery.Info{
+			{Index: 0, State: battery.StateDischarging, Current: 40000, Full: 50000, Design: 57000, ChargeRate: -8500, Voltage: 11.4,
+				Temperature: 31.5, Capabilities: battery.Capabilities{HasTemperature: true}},
+			{Index: 1, State: battery.StateNotPresent},
+		},
+	}
+
+	// A second run appends without repeating the header
+	for range 2 {
+		recorder := NewCSVRecorder(path)
+		if err := recorder.Start(); err != nil {
+			t.Fatal(err)
+		}
+		recorder.Export(sample)
+		recorder.Stop()
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	row := "2024-05-01T12:00:00Z,false,0,Discharging,80.0,40000,50000,57000,-8500,11.4,31.5,"
+	want := strings.Join(CSVHeader, ",") + "\n" + row + "\n" + row + "\n"
+	if string(data) != want {
+		t.Errorf("recorded\n%s\nwant\n%s", data, want)
+	}
+}
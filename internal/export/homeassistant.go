@@ -0,0 +1,144 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/xsikor/go-battop/internal/battery"
+)
+
+// Availability payloads of the Home Assistant availability topic
+const (
+	haOnline  = "online"
+	haOffline = "offline"
+)
+
+// haSensor is a sensor announced to Home Assistant for every battery
+type haSensor struct {
+	// Topic is the state topic below the battery topic
+	Topic       string
+	Name        string
+	DeviceClass string
+	Unit        string
+
+	// Available reports whether the battery provides the sensor
+	Available func(info *battery.Info) bool
+}
+
+// haSensors are the sensors of a battery
+var haSensors = []haSensor{
+	{Topic: "percent", Name: "Charge", DeviceClass: "battery", Unit: "%"},
+	{Topic: "power", Name: "Power", DeviceClass: "power", Unit: "W"},
+	{Topic: "state", Name: "State"},
+	{Topic: "temperature", Name: "Temperature", DeviceClass: "temperature", Unit: "°C",
+		Available: func(info *battery.Info) bool { return info.Capabilities.HasTemperature }},
+}
+
+// haDevice groups the sensors of one battery into a device
+type haDevice struct {
+	Identifiers  []string `json:"identifiers"`
+	Name         string   `json:"name"`
+	Manufacturer string   `json:"manufacturer,omitempty"`
+	Model        string   `json:"model,omitempty"`
+}
+
+// haConfig is the discovery config of a sensor
+type haConfig struct {
+	Name              string   `json:"name"`
+	UniqueID          string   `json:"unique_id"`
+	StateTopic        string   `json:"state_topic"`
+	AvailabilityTopic string   `json:"availability_topic"`
+	DeviceClass       string   `json:"device_class,omitempty"`
+	StateClass        string   `json:"state_class,omitempty"`
+	Unit              string   `json:"unit_of_measurement,omitempty"`
+	Device            haDevice `json:"device"`
+}
+
+// HomeAssistant announces the batteries to Home Assistant through MQTT
+// discovery, so each shows up as a device with its sensors without any
+// configuration. Its availability topic goes offline when battop stops or
+// loses the connection.
+type HomeAssistant struct {
+	// prefix is the discovery prefix Home Assistant subscribes to
+	prefix string
+
+	// announced are the batteries whose configs were published on the
+	// current connection
+	announced map[int]bool
+}
+
+// NewHomeAssistant creates the integration for the given discovery prefix
+func NewHomeAssistant(prefix string) *HomeAssistant {
+	return &HomeAssistant{prefix: prefix, announced: make(map[int]bool)}
+}
+
+// availabilityTopic returns the availability topic below the host topic
+func (h *HomeAssistant) availabilityTopic(base string) string {
+	return base + "/availability"
+}
+
+// Will returns the message the broker publishes when the connection is lost
+func (h *HomeAssistant) Will(base string) *mqttMessage {
+	return &mqttMessage{Topic: h.availabilityTopic(base), Payload: haOffline, Retain: true}
+}
+
+// Online returns the messages published after connecting; the batteries are
+// announced again with the next sample
+func (h *HomeAssistant) Online(base string) []mqttMessage {
+	h.announced = make(map[int]bool)
+	return []mqttMessage{{Topic: h.availabilityTopic(base), Payload: haOnline, Retain: true}}
+}
+
+// Offline returns the message published before disconnecting
+func (h *HomeAssistant) Offline(base string) mqttMessage {
+	return *h.Will(base)
+}
+
+// Announce returns the retained discovery configs of the batteries not yet
+// announced on this connection
+func (h *HomeAssistant) Announce(base, host string, sample Sample) []mqttMessage {
+	var messages []mqttMessage
+	for _, info := range sample.Batteries {
+		if info.State == battery.StateNotPresent || h.announced[info.Index] {
+			continue
+		}
+		h.announced[info.Index] = true
+
+		id := fmt.Sprintf("battop_%s_battery%d", host, info.Index)
+		device := haDevice{
+			Identifiers:  []string{id},
+			Name:         fmt.Sprintf("%s battery %d", host, info.Index),
+			Manufacturer: info.Manufacturer,
+			Model:        info.Model,
+		}
+		for _, sensor := range haSensors {
+			if sensor.Available != nil && !sensor.Available(info) {
+				continue
+			}
+			config := haConfig{
+				Name:              sensor.Name,
+				UniqueID:          id + "_" + sensor.Topic,
+				StateTopic:        fmt.Sprintf("%s/battery%d/%s", base, info.Index, sensor.Topic),
+				AvailabilityTopic: h.availabilityTopic(base),
+				DeviceClass:       sensor.DeviceClass,
+				Unit:              sensor.Unit,
+				Device:            device,
+			}
+			if sensor.Unit != "" {
+				config.StateClass = "measurement"
+			}
+			payload, err := json.Marshal(config)
+			if err != nil {
+				slog.Error("Failed to encode Home Assistant config", "error", err)
+				continue
+			}
+			messages = append(messages, mqttMessage{
+				Topic:   fmt.Sprintf("%s/sensor/%s_%s/config", h.prefix, id, sensor.Topic),
+				Payload: string(payload),
+				Retain:  true,
+			})
+		}
+	}
+	return messages
+}
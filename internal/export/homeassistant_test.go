@@ -0,0 +1,51 @@
+package export
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/xsikor/go-battop/internal/battery"
+)
+
+func TestHomeAssistantAnnounce(t *testing.T) {
+	homeAssistant := NewHomeAssistant("homeassistant")
+	homeAssistant.Online("battop/laptop")
+
+	info := &battery.Info{Index: 1, State: battery.StateDischarging, Model: "5B10W13930"}
+	sample := Sample{Batteries: []*battery.Info{info, {Index: 2, State: battery.StateNotPresent}}}
+	messages := homeAssistant.Announce("battop/laptop", "laptop", sample)
+
+	// Without a temperature reading only charge, power and state are announced
+	if len(messages) != 3 {
+		t.Fatalf("announced %d configs, want 3", len(messages))
+	}
+	message := messages[0]
+	if message.Topic != "homeassistant/sensor/battop_laptop_battery1_percent/config" || !message.Retain {
+		t.Errorf("config %q (retain %v)", message.Topic, message.Retain)
+	}
+	var config haConfig
+	if err := json.Unmarshal([]byte(message.Payload), &config); err != nil {
+		t.Fatal(err)
+	}
+	if config.StateTopic != "battop/laptop/battery1/percent" ||
+		config.AvailabilityTopic != "battop/laptop/availability" ||
+		config.DeviceClass != "battery" || config.Unit != "%" ||
+		config.Device.Model != "5B10W13930" {
+		t.Errorf("config %+v", config)
+	}
+
+	if again := homeAssistant.Announce("battop/laptop", "laptop", sample); len(again) != 0 {
+		t.Errorf("announced %d configs again on the same connection", len(again))
+	}
+
+	// A reconnect announces the batteries again
+	homeAssistant.Online("battop/laptop")
+	info.Capabilities.HasTemperature = true
+	if again := homeAssistant.Announce("battop/laptop", "laptop", sample); len(again) != 4 {
+		t.Errorf("announced %d configs after reconnecting, want 4", len(again))
+	}
+
+	if will := homeAssistant.Will("battop/laptop"); will.Payload != "offline" || !will.Retain {
+		t.Errorf("will %+v", will)
+	}
+}
@@ -0,0 +1,230 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xsikor/go-battop/internal/battery"
+)
+
+// InfluxMeasurement is the measurement the battery readings are written to
+const InfluxMeasurement = "battery"
+
+func init() {
+	Register("influx", newInfluxExporter)
+}
+
+// newInfluxExporter creates the exporter for -influx-url, -influx-token and
+// -influx-tags
+func newInfluxExporter(settings Settings) (Exporter, error) {
+	if settings["influx-url"] == "" {
+		return nil, nil
+	}
+	if err := ValidateInfluxURL(settings["influx-url"]); err != nil {
+		return nil, err
+	}
+	tags, err := ParseInfluxTags(settings["influx-tags"])
+	if err != nil {
+		return nil, err
+	}
+	return NewInfluxExporter(settings["influx-url"], settings["influx-token"], tags), nil
+}
+
+// InfluxExporter writes every sample to InfluxDB in line protocol. The
+// samples are written in the background, so a slow or unreachable server
+// drops samples instead of stalling the updates.
+type InfluxExporter struct {
+	url    string
+	token  string
+	tags   map[string]string
+	client *http.Client
+
+	samples chan Sample
+	done    chan struct{}
+}
+
+// NewInfluxExporter creates an exporter writing to the write endpoint of
+// InfluxDB 1.x (/write?db=...) or 2.x (/api/v2/write?org=...&bucket=...).
+// A non-empty token is sent as the 2.x API token; the tags are added to every
+// point besides the host name and the battery index.
+func NewInfluxExporter(endpoint, token string, tags map[string]string) *InfluxExporter {
+	all := map[string]string{"host": hostname()}
+	for key, value := range tags {
+		all[key] = value
+	}
+	return &InfluxExporter{
+		url:     endpoint,
+		token:   token,
+		tags:    all,
+		client:  &http.Client{Timeout: InfluxWriteTimeout},
+		samples: make(chan Sample, InfluxQueueSize),
+		done:    make(chan struct{}),
+	}
+}
+
+// Start starts writing the exported samples
+func (e *InfluxExporter) Start() error {
+	go func() {
+		defer close(e.done)
+		for sample := range e.samples {
+			if err := e.write(influxLines(sample, e.tags)); err != nil {
+				slog.Warn("Failed to write to InfluxDB", "error", err)
+			}
+		}
+	}()
+	return nil
+}
+
+// Export queues a sample for writing, dropping it when the queue is full
+func (e *InfluxExporter) Export(sample Sample) {
+	select {
+	case e.samples <- sample:
+	default:
+		slog.Warn("InfluxDB queue full, dropping sample")
+	}
+}
+
+// Stop writes the queued samples and stops the exporter
+func (e *InfluxExporter) Stop() {
+	close(e.samples)
+	<-e.done
+}
+
+// write posts line protocol to the write endpoint
+func (e *InfluxExporter) write(lines string) error {
+	if lines == "" {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), InfluxWriteTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, strings.NewReader(lines))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if e.token != "" {
+		req.Header.Set("Authorization", "Token "+e.token)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("InfluxDB returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// influxLines encodes a sample as one line per battery with nanosecond
+// timestamps. Empty battery slots are skipped.
+func influxLines(sample Sample, tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var lines strings.Builder
+	for _, info := range sample.Batteries {
+		if info.State == battery.StateNotPresent {
+			continue
+		}
+
+		lines.WriteString(InfluxMeasurement)
+		for _, key := range keys {
+			if tags[key] != "" {
+				fmt.Fprintf(&lines, ",%s=%s", influxEscape(key, ",= "), influxEscape(tags[key], ",= "))
+			}
+		}
+		fmt.Fprintf(&lines, ",battery=%d", info.Index)
+
+		fields := []string{
+			"percent=" + influxFloat(info.ChargePercent()),
+			"charge_rate_mw=" + influxFloat(info.ChargeRate),
+			"energy_mwh=" + influxFloat(info.Current),
+			"full_mwh=" + influxFloat(info.Full),
+			"voltage_v=" + influxFloat(info.Voltage),
+			"on_ac=" + strconv.FormatBool(sample.OnAC),
+			"state=\"" + influxEscape(info.State.String(), `"\`) + "\"",
+		}
+		if info.Design > 0 {
+			fields = append(fields, "health="+influxFloat(info.Health()))
+		}
+		if info.Capabilities.HasTemperature {
+			fields = append(fields, "temperature_c="+influxFloat(info.Temperature))
+		}
+		if info.Capabilities.HasCycles {
+			fields = append(fields, fmt.Sprintf("cycles=%di", info.CycleCount))
+		}
+		fmt.Fprintf(&lines, " %s %d\n", strings.Join(fields, ","), sample.Time.UnixNano())
+	}
+	return lines.String()
+}
+
+// influxFloat formats a field value
+func influxFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// influxEscape escapes the given special characters with a backslash
+func influxEscape(text, special string) string {
+	var escaped strings.Builder
+	for _, r := range text {
+		if strings.ContainsRune(special, r) {
+			escaped.WriteByte('\\')
+		}
+		escaped.WriteRune(r)
+	}
+	return escaped.String()
+}
+
+// ParseInfluxTags parses comma-separated key=value tags
+func ParseInfluxTags(text string) (map[string]string, error) {
+	tags := make(map[string]string)
+	for _, pair := range strings.Split(text, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || key == "" || value == "" {
+			return nil, fmt.Errorf("invalid tag %q: must be key=value", pair)
+		}
+		tags[key] = value
+	}
+	return tags, nil
+}
+
+// ValidateInfluxURL checks an InfluxDB write endpoint
+func ValidateInfluxURL(endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("must be an http(s) URL of the write endpoint, e.g. http://localhost:8086/write?db=battop")
+	}
+	return nil
+}
+
+// hostname returns the host name tagging exported samples, empty when unknown
+func hostname() string {
+	name, err := os.Hostname()
+	if err != nil {
+		return ""
+	}
+	return name
+}
@@ -0,0 +1,67 @@
+package export
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/battery"
+)
+
+func TestInfluxLines(t *testing.T) {
+	sample := Sample{
+		Time: time.Unix(1709294400, 0),
+		OnAC: false,
+		Batteries: []*battery.Info{
+			{Index: 0, State: battery.StateDischarging, Current: 40000, Full: 50000, Design: 50000, ChargeRate: -9000, Voltage: 12.1},
+			{Index: 1, State: battery.StateNotPresent},
+		},
+	}
+
+	got := influxLines(sample, map[string]string{"host": "my laptop", "site": "a,b"})
+	want := `battery,host=my\ laptop,site=a\,b,battery=0 percent=80,charge_rate_mw=-9000,energy_mwh=40000,full_mwh=50000,voltage_v=12.1,on_ac=false,state="Discharging",health=100 1709294400000000000` + "\n"
+	if got != want {
+		t.Errorf("influxLines() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestParseInfluxTags(t *testing.T) {
+	tags, err := ParseInfluxTags("site=office, owner=ann")
+	if err != nil || len(tags) != 2 || tags["site"] != "office" || tags["owner"] != "ann" {
+		t.Errorf("ParseInfluxTags() = %v, %v", tags, err)
+	}
+	for _, text := range []string{"site", "=office", "site="} {
+		if _, err := ParseInfluxTags(text); err == nil {
+			t.Errorf("ParseInfluxTags(%q) accepted", text)
+		}
+	}
+}
+
+func TestInfluxExporter(t *testing.T) {
+	bodies := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Token secret" {
+			t.Errorf("Authorization = %q", got)
+		}
+		body, _ := io.ReadAll(r.Body)
+		bodies <- string(body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	exporter := NewInfluxExporter(server.URL+"/api/v2/write?org=home&bucket=battop", "secret", nil)
+	exporter.Start()
+	exporter.Export(Sample{Time: time.Unix(1, 0), Batteries: []*battery.Info{{Index: 0, Full: 100, Current: 50}}})
+	exporter.Stop()
+
+	select {
+	case body := <-bodies:
+		if want := influxLines(Sample{Time: time.Unix(1, 0), Batteries: []*battery.Info{{Index: 0, Full: 100, Current: 50}}}, exporter.tags); body != want {
+			t.Errorf("body = %q, want %q", body, want)
+		}
+	default:
+		t.Error("nothing written")
+	}
+}
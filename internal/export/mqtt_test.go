@@ -0,0 +1,110 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/battery"
+)
+
+func TestMQTTPacket(t *testing.T) {
+	// Remaining lengths of 128 and more take more than one byte
+	packet := mqttPacket(mqttPublish, make([]byte, 321))
+	if !bytes.Equal(packet[:3], []byte{mqttPublish, 0xC1, 0x02}) || len(packet) != 324 {
+		t.Errorf("header = % x, length %d", packet[:3], len(packet))
+	}
+
+	publish := mqttPublishPacket(mqttMessage{Topic: "a/b", Payload: "42", Retain: true})
+	if want := []byte{0x31, 7, 0, 3, 'a', '/', 'b', '4', '2'}; !bytes.Equal(publish, want) {
+		t.Errorf("mqttPublishPacket() = % x, want % x", publish, want)
+	}
+
+	connect := mqttConnectPacket("battop", url.UserPassword("ann", "pw"), nil)
+	if flags := connect[9]; flags != mqttCleanSession|mqttUsernameFlag|mqttPasswordFlag {
+		t.Errorf("connect flags = %#x", flags)
+	}
+}
+
+// readMQTTPacket reads one packet of the test broker
+func readMQTTPacket(r *bufio.Reader) (byte, []byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	length, multiplier := 0, 1
+	for {
+		digit, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length += int(digit&0x7F) * multiplier
+		multiplier *= 128
+		if digit&0x80 == 0 {
+			break
+		}
+	}
+	body := make([]byte, length)
+	_, err = io.ReadFull(r, body)
+	return header, body, err
+}
+
+func TestMQTTPublisher(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	topics := make(chan string, 16)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		if header, _, err := readMQTTPacket(reader); err != nil || header != mqttConnect {
+			t.Errorf("first packet %#x, %v", header, err)
+			return
+		}
+		conn.Write([]byte{mqttConnAck, 2, 0, 0})
+		for {
+			header, body, err := readMQTTPacket(reader)
+			if err != nil || header == mqttDisconnect {
+				close(topics)
+				return
+			}
+			length := int(body[0])<<8 | int(body[1])
+			topics <- string(body[2:2+length]) + "=" + string(body[2+length:])
+		}
+	}()
+
+	broker, _ := url.Parse("mqtt://" + listener.Addr().String())
+	publisher := NewMQTTPublisher(broker, "home/")
+	publisher.host = "laptop"
+	publisher.Start()
+	publisher.Export(Sample{OnAC: true, Batteries: []*battery.Info{{Index: 0, State: battery.StateCharging, Current: 40, Full: 50, ChargeRate: 12500}}})
+	publisher.Stop()
+
+	want := []string{
+		"home/laptop/on_ac=true",
+		"home/laptop/battery0/state=Charging",
+		"home/laptop/battery0/percent=80.0",
+		"home/laptop/battery0/power=12.50",
+	}
+	for _, w := range want {
+		select {
+		case got := <-topics:
+			if got != w {
+				t.Errorf("published %q, want %q", got, w)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%q not published", w)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package format
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Formatter formats battery measurements for display. Views, widgets and
+// exporters share one Formatter so values look the same everywhere.
+type Formatter interface {
+	// Power formats a power value given in mW
+	Power(mW float64) string
+
+	// Energy formats an energy value given in mWh
+	Energy(mWh float64) string
+
+	// Capacity formats a battery capacity given in mWh, as charge (mAh) at
+	// the voltage given in V in the UnitsCharge system
+	Capacity(mWh, volts float64) string
+
+	// Voltage formats a voltage given in V
+	Voltage(v float64) string
+
+	// Current formats a current given in A
+	Current(a float64) string
+
+	// Percent formats a percentage
+	Percent(p float64) string
+
+	// Duration formats a time span such as a time estimate
+	Duration(d time.Duration) string
+
+	// Temperature formats a temperature given in °C
+	Temperature(c float64) string
+
+	// Timestamp formats a point in time
+	Timestamp(t time.Time) string
+}
+
+// Units defines the measurement unit system for displaying battery values
+type Units string
+
+const (
+	// UnitsHuman displays values in human-readable units (W, Wh)
+	UnitsHuman Units = "human"
+	// UnitsRaw displays values in raw units (mW, mWh)
+	UnitsRaw Units = "raw"
+	// UnitsSI scales values to the closest SI prefix (µW to kW, µWh to kWh)
+	UnitsSI Units = "si"
+	// UnitsCharge displays capacities as charge (mAh, Ah) at the battery
+	// voltage and scales the other values like UnitsSI
+	UnitsCharge Units = "mah"
+)
+
+// TemperatureUnit is the scale temperatures are displayed in
+type TemperatureUnit string
+
+const (
+	// Celsius displays temperatures in °C
+	Celsius TemperatureUnit = "celsius"
+	// Fahrenheit displays temperatures in °F
+	Fahrenheit TemperatureUnit = "fahrenheit"
+	// Kelvin displays temperatures in K
+	Kelvin TemperatureUnit = "kelvin"
+)
+
+// ParseTemperatureUnit parses a temperature unit name or its first letter
+func ParseTemperatureUnit(s string) (TemperatureUnit, error) {
+	switch strings.ToLower(s) {
+	case "celsius", "c":
+		return Celsius, nil
+	case "fahrenheit", "f":
+		return Fahrenheit, nil
+	case "kelvin", "k":
+		return Kelvin, nil
+	default:
+		return "", fmt.Errorf("invalid temperature unit: must be 'celsius', 'fahrenheit' or 'kelvin'")
+	}
+}
+
+// Symbol returns the unit symbol (e.g., "°F")
+func (u TemperatureUnit) Symbol() string {
+	switch u {
+	case Fahrenheit:
+		return "°F"
+	case Kelvin:
+		return "K"
+	default:
+		return "°C"
+	}
+}
+
+// FromCelsius converts a temperature given in °C to the unit
+func (u TemperatureUnit) FromCelsius(c float64) float64 {
+	switch u {
+	case Fahrenheit:
+		return c*1.8 + 32
+	case Kelvin:
+		return c + 273.15
+	default:
+		return c
+	}
+}
+
+// ChargeAhThreshold is the charge from which UnitsCharge shows Ah instead of mAh
+const ChargeAhThreshold = 10000.0
+
+// siPrefixes are the SI prefixes values are scaled to, largest first
+var siPrefixes = []struct {
+	factor float64
+	prefix string
+}{
+	{1e3, "k"},
+	{1, ""},
+	{1e-3, "m"},
+	{1e-6, "µ"},
+}
+
+// TimestampLayout is the layout of formatted timestamps
+const TimestampLayout = "15:04:05"
+
+// Standard is the default Formatter
+type Standard struct {
+	units       Units
+	decimal     string
+	temperature TemperatureUnit
+}
+
+// New creates a standard formatter for the given unit system
+func New(units Units) *Standard {
+	return &Standard{units: units, decimal: ".", temperature: Celsius}
+}
+
+// SetTemperatureUnit sets the unit temperatures are displayed in
+func (f *Standard) SetTemperatureUnit(unit TemperatureUnit) *Standard {
+	f.temperature = unit
+	return f
+}
+
+// SetDecimalSeparator sets the decimal separator (e.g., "," for German)
+func (f *Standard) SetDecimalSeparator(separator string) *Standard {
+	f.decimal = separator
+	return f
+}
+
+// Power formats power according to the unit system
+func (f *Standard) Power(mW float64) string {
+	switch f.units {
+	case UnitsHuman:
+		return f.number("%.2f W", mW/1000.0)
+	case UnitsSI, UnitsCharge:
+		return f.scaled(mW/1000.0, "W")
+	default:
+		return f.number("%.0f mW", mW)
+	}
+}
+
+// Energy formats energy according to the unit system
+func (f *Standard) Energy(mWh float64) string {
+	switch f.units {
+	case UnitsHuman:
+		return f.number("%.2f Wh", mWh/1000.0)
+	case UnitsSI, UnitsCharge:
+		return f.scaled(mWh/1000.0, "Wh")
+	default:
+		return f.number("%.0f mWh", mWh)
+	}
+}
+
+// Capacity formats a capacity as charge in the UnitsCharge system and as
+// energy otherwise, or when the voltage is unknown
+func (f *Standard) Capacity(mWh, volts float64) string {
+	if f.units != UnitsCharge || volts <= 0 {
+		return f.Energy(mWh)
+	}
+	mAh := mWh / volts
+	if math.Abs(mAh) >= ChargeAhThreshold {
+		return f.number("%.2f Ah", mAh/1000.0)
+	}
+	return f.number("%.0f mAh", mAh)
+}
+
+// Voltage formats voltage
+func (f *Standard) Voltage(v float64) string {
+	return f.number("%.2f V", v)
+}
+
+// Current formats current
+func (f *Standard) Current(a float64) string {
+	return f.number("%.2f A", a)
+}
+
+// Percent formats a percentage with one decimal
+func (f *Standard) Percent(p float64) string {
+	return f.number("%.1f%%", p)
+}
+
+// Duration formats a duration as hh:mm
+func (f *Standard) Duration(d time.Duration) string {
+	h := int(d.Hours())
+	m := int(d.Minutes()) % 60
+	return fmt.Sprintf("%02d:%02d", h, m)
+}
+
+// Temperature formats a temperature in the configured unit
+func (f *Standard) Temperature(c float64) string {
+	return f.number("%.1f %s", f.temperature.FromCelsius(c), f.temperature.Symbol())
+}
+
+// Timestamp formats a time of day
+func (f *Standard) Timestamp(t time.Time) string {
+	return t.Format(TimestampLayout)
+}
+
+// scaled formats a value in base units with the closest SI prefix and three
+// significant digits (e.g., 950 mW, 12.3 W, 1.50 kW)
+func (f *Standard) scaled(value float64, unit string) string {
+	factor, prefix := 1.0, ""
+	if abs := math.Abs(value); abs > 0 {
+		factor, prefix = siPrefixes[len(siPrefixes)-1].factor, siPrefixes[len(siPrefixes)-1].prefix
+		for _, p := range siPrefixes {
+			if abs >= p.factor {
+				factor, prefix = p.factor, p.prefix
+				break
+			}
+		}
+	}
+
+	value /= factor
+	switch abs := math.Abs(value); {
+	case abs == 0 || abs >= 100:
+		return f.number("%.0f %s%s", value, prefix, unit)
+	case abs >= 10:
+		return f.number("%.1f %s%s", value, prefix, unit)
+	default:
+		return f.number("%.2f %s%s", value, prefix, unit)
+	}
+}
+
+// number formats a measurement with the decimal separator
+func (f *Standard) number(format string, args ...any) string {
+	text := fmt.Sprintf(format, args...)
+	if f.decimal != "." {
+		text = strings.Replace(text, ".", f.decimal, 1)
+	}
+	return text
+}
@@ -0,0 +1,77 @@
+package format
+
+import "testing"
+
+func TestStandardUnits(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"human power", New(UnitsHuman).Power(12345), "12.35 W"},
+		{"raw energy", New(UnitsRaw).Energy(52000), "52000 mWh"},
+		{"si microwatts", New(UnitsSI).Power(0.25), "250 µW"},
+		{"si milliwatts", New(UnitsSI).Power(950), "950 mW"},
+		{"si watts", New(UnitsSI).Power(12345), "12.3 W"},
+		{"si kilowatts", New(UnitsSI).Power(1500000), "1.50 kW"},
+		{"si zero", New(UnitsSI).Power(0), "0 W"},
+		{"si negative", New(UnitsSI).Energy(-4200), "-4.20 Wh"},
+		{"mah capacity", New(UnitsCharge).Capacity(57750, 11.55), "5000 mAh"},
+		{"ah capacity", New(UnitsCharge).Capacity(1200000, 12), "100.00 Ah"},
+		{"mah without voltage", New(UnitsCharge).Capacity(57750, 0), "57.8 Wh"},
+		{"mah power", New(UnitsCharge).Power(8000), "8.00 W"},
+		{"human capacity", New(UnitsHuman).Capacity(57750, 11.55), "57.75 Wh"},
+		{"comma", New(UnitsHuman).SetDecimalSeparator(",").Voltage(11.55), "11,55 V"},
+		{"comma percent", New(UnitsRaw).SetDecimalSeparator(",").Percent(80.25), "80,2%"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestDecimalSeparator(t *testing.T) {
+	tests := []struct {
+		locale string
+		want   string
+	}{
+		{"", "."},
+		{"C", "."},
+		{"POSIX", "."},
+		{"en_US.UTF-8", "."},
+		{"de_DE.UTF-8", ","},
+		{"de_CH.UTF-8", "."},
+		{"fr_FR@euro", ","},
+		{"pt_BR", ","},
+		{"ja_JP.UTF-8", "."},
+	}
+	for _, tt := range tests {
+		if got := DecimalSeparator(tt.locale); got != tt.want {
+			t.Errorf("DecimalSeparator(%q) = %q, want %q", tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestTemperatureUnits(t *testing.T) {
+	tests := []struct {
+		unit string
+		want string
+	}{
+		{"celsius", "25.0 °C"},
+		{"F", "77.0 °F"},
+		{"kelvin", "298.1 K"},
+	}
+	for _, tt := range tests {
+		unit, err := ParseTemperatureUnit(tt.unit)
+		if err != nil {
+			t.Fatalf("ParseTemperatureUnit(%q) error = %v", tt.unit, err)
+		}
+		if got := New(UnitsHuman).SetTemperatureUnit(unit).Temperature(25); got != tt.want {
+			t.Errorf("Temperature(25) in %s = %q, want %q", tt.unit, got, tt.want)
+		}
+	}
+	if _, err := ParseTemperatureUnit("rankine"); err == nil {
+		t.Error("ParseTemperatureUnit(rankine) succeeded")
+	}
+}
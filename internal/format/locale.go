@@ -0,0 +1,49 @@
+package format
+
+import (
+	"os"
+	"strings"
+)
+
+// commaLanguages are the languages writing decimals with a comma
+var commaLanguages = map[string]bool{
+	"af": true, "az": true, "be": true, "bg": true, "bs": true, "ca": true,
+	"cs": true, "da": true, "de": true, "el": true, "es": true, "et": true,
+	"eu": true, "fi": true, "fo": true, "fr": true, "gl": true, "hr": true,
+	"hu": true, "hy": true, "id": true, "is": true, "it": true, "ka": true,
+	"kk": true, "ky": true, "lt": true, "lv": true, "mk": true, "mn": true,
+	"nb": true, "nl": true, "nn": true, "no": true, "pl": true, "pt": true,
+	"ro": true, "ru": true, "sk": true, "sl": true, "sq": true, "sr": true,
+	"sv": true, "tr": true, "uk": true, "uz": true, "vi": true,
+}
+
+// pointRegions are the regions of comma languages that write decimals with
+// a point
+var pointRegions = map[string]bool{
+	"de_CH": true, "de_LI": true, "it_CH": true, "es_MX": true, "es_US": true,
+}
+
+// EnvLocale returns the locale numbers are formatted in, from LC_ALL,
+// LC_NUMERIC or LANG like the C library
+func EnvLocale() string {
+	for _, name := range []string{"LC_ALL", "LC_NUMERIC", "LANG"} {
+		if locale := os.Getenv(name); locale != "" {
+			return locale
+		}
+	}
+	return ""
+}
+
+// DecimalSeparator returns the decimal separator of a locale such as
+// "de_DE.UTF-8"; unknown locales, "C" and "POSIX" use a point
+func DecimalSeparator(locale string) string {
+	// Strip the encoding and modifier
+	locale, _, _ = strings.Cut(locale, ".")
+	locale, _, _ = strings.Cut(locale, "@")
+	language, _, _ := strings.Cut(locale, "_")
+
+	if commaLanguages[strings.ToLower(language)] && !pointRegions[locale] {
+		return ","
+	}
+	return "."
+}
@@ -0,0 +1,143 @@
+package session
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	pkgErrors "github.com/xsikor/go-battop/internal/errors"
+)
+
+// IdleSource selects how session idle state is detected
+type IdleSource string
+
+const (
+	// IdleSourceAuto combines every detector available on the platform
+	IdleSourceAuto IdleSource = "auto"
+	// IdleSourceLogind reads the IdleHint/LockedHint of the logind session
+	IdleSourceLogind IdleSource = "logind"
+	// IdleSourceFile treats the presence of a marker file as idle (e.g., created by swayidle)
+	IdleSourceFile IdleSource = "file"
+	// IdleSourceNone disables idle detection
+	IdleSourceNone IdleSource = "none"
+)
+
+// idleCacheDuration limits how often detectors query the system
+const idleCacheDuration = 5 * time.Second
+
+// detector reports whether the session is currently idle or locked
+type detector interface {
+	idle() (bool, error)
+	name() string
+}
+
+// IdleDetector reports whether the user session is idle, caching results so
+// it can be polled on every battery update
+type IdleDetector struct {
+	mu        sync.Mutex
+	detectors []detector
+	lastCheck time.Time
+	lastIdle  bool
+}
+
+// NewIdleDetector creates an idle detector for the given source. The marker
+// file is only used by the file and auto sources.
+func NewIdleDetector(source IdleSource, markerFile string) *IdleDetector {
+	d := &IdleDetector{}
+
+	switch source {
+	case IdleSourceLogind:
+		d.detectors = append(d.detectors, newLogindDetector())
+	case IdleSourceFile:
+		d.detectors = append(d.detectors, &fileDetector{path: markerFile})
+	case IdleSourceAuto:
+		if logind := newLogindDetector(); logind.available() {
+			d.detectors = append(d.detectors, logind)
+		}
+		d.detectors = append(d.detectors, &fileDetector{path: markerFile})
+	}
+
+	return d
+}
+
+// DefaultMarkerFile returns the default idle marker file path
+func DefaultMarkerFile() string {
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "battop-idle")
+}
+
+// Enabled reports whether any detector is active
+func (d *IdleDetector) Enabled() bool {
+	return len(d.detectors) > 0
+}
+
+// Sources returns the names of the active detectors
+func (d *IdleDetector) Sources() []string {
+	names := make([]string, 0, len(d.detectors))
+	for _, det := range d.detectors {
+		names = append(names, det.name())
+	}
+	return names
+}
+
+// Idle reports whether the session is idle or locked according to any detector
+func (d *IdleDetector) Idle() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if time.Since(d.lastCheck) < idleCacheDuration {
+		return d.lastIdle
+	}
+
+	idle := false
+	for _, det := range d.detectors {
+		detected, err := det.idle()
+		if err != nil {
+			slog.Debug("Idle detection failed", "source", det.name(), "error", err)
+			continue
+		}
+		if detected {
+			idle = true
+			break
+		}
+	}
+
+	if idle != d.lastIdle {
+		slog.Debug("Session idle state changed", "idle", idle)
+	}
+
+	d.lastCheck = time.Now()
+	d.lastIdle = idle
+	return idle
+}
+
+// fileDetector treats the presence of a marker file as idle. It integrates
+// with idle daemons such as swayidle:
+//
+//	swayidle timeout 300 'touch $XDG_RUNTIME_DIR/battop-idle' resume 'rm -f $XDG_RUNTIME_DIR/battop-idle'
+type fileDetector struct {
+	path string
+}
+
+func (f *fileDetector) idle() (bool, error) {
+	if f.path == "" {
+		return false, pkgErrors.ErrFeatureNotAvailable
+	}
+	_, err := os.Stat(f.path)
+	if err == nil {
+		return true, nil
+	}
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return false, err
+}
+
+func (f *fileDetector) name() string {
+	return "file (" + f.path + ")"
+}
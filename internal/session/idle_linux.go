@@ -0,0 +1,54 @@
+//go:build linux
+
+package session
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// logindDetector reads the session idle and lock hints through loginctl
+type logindDetector struct {
+	sessionID string
+}
+
+func newLogindDetector() *logindDetector {
+	return &logindDetector{sessionID: os.Getenv("XDG_SESSION_ID")}
+}
+
+// available reports whether loginctl and a session ID are present
+func (l *logindDetector) available() bool {
+	if l.sessionID == "" {
+		return false
+	}
+	_, err := exec.LookPath("loginctl")
+	return err == nil
+}
+
+func (l *logindDetector) idle() (bool, error) {
+	if l.sessionID == "" {
+		return false, fmt.Errorf("XDG_SESSION_ID not set")
+	}
+
+	out, err := exec.Command("loginctl", "show-session", l.sessionID, "-p", "IdleHint", "-p", "LockedHint").Output()
+	if err != nil {
+		return false, fmt.Errorf("loginctl failed: %w", err)
+	}
+
+	for _, line := range strings.Split(string(out), "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok {
+			continue
+		}
+		if (key == "IdleHint" || key == "LockedHint") && value == "yes" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (l *logindDetector) name() string {
+	return "logind (session " + l.sessionID + ")"
+}
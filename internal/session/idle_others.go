@@ -0,0 +1,24 @@
+//go:build !linux
+
+package session
+
+import pkgErrors "github.com/xsikor/go-battop/internal/errors"
+
+// logindDetector is unavailable outside Linux
+type logindDetector struct{}
+
+func newLogindDetector() *logindDetector {
+	return &logindDetector{}
+}
+
+func (l *logindDetector) available() bool {
+	return false
+}
+
+func (l *logindDetector) idle() (bool, error) {
+	return false, pkgErrors.ErrPlatformNotSupported
+}
+
+func (l *logindDetector) name() string {
+	return "logind"
+}
@@ -0,0 +1,28 @@
+package stats
+
+// Tariff prices the energy charged into the batteries and its carbon emissions
+type Tariff struct {
+	// Price is the electricity price per kWh (0 when not set)
+	Price float64
+
+	// Currency is the display name of the price's currency (e.g., EUR), may be empty
+	Currency string
+
+	// Carbon is the carbon intensity of the electricity in g CO₂ per kWh (0 when not set)
+	Carbon float64
+}
+
+// Enabled reports whether a price or a carbon intensity is set
+func (t Tariff) Enabled() bool {
+	return t.Price > 0 || t.Carbon > 0
+}
+
+// Cost returns the price of an energy in mWh
+func (t Tariff) Cost(mWh float64) float64 {
+	return mWh / 1e6 * t.Price
+}
+
+// Emissions returns the carbon emissions of an energy in mWh in g CO₂
+func (t Tariff) Emissions(mWh float64) float64 {
+	return mWh / 1e6 * t.Carbon
+}
@@ -0,0 +1,20 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTariff(t *testing.T) {
+	tariff := Tariff{Price: 0.30, Carbon: 400}
+	if !tariff.Enabled() || (Tariff{Currency: "EUR"}).Enabled() {
+		t.Error("Enabled() should require a price or a carbon intensity")
+	}
+	// 50 Wh is 0.05 kWh
+	if cost := tariff.Cost(50000); math.Abs(cost-0.015) > 1e-12 {
+		t.Errorf("Cost(50 Wh) = %v, want 0.015", cost)
+	}
+	if grams := tariff.Emissions(50000); math.Abs(grams-20) > 1e-9 {
+		t.Errorf("Emissions(50 Wh) = %v, want 20", grams)
+	}
+}
@@ -0,0 +1,303 @@
+package stats
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/battery"
+	"github.com/xsikor/go-battop/internal/store"
+)
+
+// DischargeCollection is the store collection holding discharge profiles
+const DischargeCollection = "discharge"
+
+// dischargeSaveInterval is how often an in-progress recording is saved
+const dischargeSaveInterval = time.Minute
+
+// DischargeSample is one point of a recorded discharge curve
+type DischargeSample struct {
+	// ElapsedSeconds since the start of the discharge
+	ElapsedSeconds float64 `json:"elapsed_s"`
+
+	// Percent is the charge percentage
+	Percent float64 `json:"percent"`
+
+	// Current capacity in mWh
+	Current float64 `json:"current_mwh"`
+
+	// Rate is the charge rate in mW
+	Rate float64 `json:"rate_mw"`
+
+	// Idle is true when the sample was taken while the user session was idle
+	Idle bool `json:"idle,omitempty"`
+}
+
+// Elapsed returns the time since the start of the discharge
+func (s DischargeSample) Elapsed() time.Duration {
+	return time.Duration(s.ElapsedSeconds * float64(time.Second))
+}
+
+// DischargeProfile is a recorded discharge curve
+type DischargeProfile struct {
+	Name         string            `json:"name"`
+	BatteryIndex int               `json:"battery_index"`
+	StartedAt    time.Time         `json:"started_at"`
+	Complete     bool              `json:"complete"`
+	Full         float64           `json:"full_mwh"`
+	Design       float64           `json:"design_mwh"`
+	Samples      []DischargeSample `json:"samples"`
+}
+
+// Duration returns the length of the recorded discharge
+func (p *DischargeProfile) Duration() time.Duration {
+	if len(p.Samples) == 0 {
+		return 0
+	}
+	return p.Samples[len(p.Samples)-1].Elapsed()
+}
+
+// ActiveDrain returns the energy drawn between consecutive samples that were
+// both taken while the session was active
+func (p *DischargeProfile) ActiveDrain() Drain {
+	drain := Drain{}
+	for i := 1; i < len(p.Samples); i++ {
+		prev, next := p.Samples[i-1], p.Samples[i]
+		if prev.Idle || next.Idle {
+			continue
+		}
+		elapsed := next.Elapsed() - prev.Elapsed()
+		if elapsed <= 0 || elapsed > MaxSampleGap || prev.Current <= next.Current {
+			continue
+		}
+		drain.Energy += prev.Current - next.Current
+		drain.Duration += elapsed
+	}
+	return drain
+}
+
+// PercentAt returns the charge percentage at the elapsed time, linearly
+// interpolated between samples
+func (p *DischargeProfile) PercentAt(elapsed time.Duration) (float64, bool) {
+	if len(p.Samples) == 0 || elapsed < 0 || elapsed > p.Duration() {
+		return 0, false
+	}
+
+	seconds := elapsed.Seconds()
+	i := sort.Search(len(p.Samples), func(i int) bool {
+		return p.Samples[i].ElapsedSeconds >= seconds
+	})
+	if i == 0 {
+		return p.Samples[0].Percent, true
+	}
+
+	prev, next := p.Samples[i-1], p.Samples[i]
+	span := next.ElapsedSeconds - prev.ElapsedSeconds
+	if span <= 0 {
+		return next.Percent, true
+	}
+	ratio := (seconds - prev.ElapsedSeconds) / span
+	return prev.Percent + ratio*(next.Percent-prev.Percent), true
+}
+
+// ElapsedAt returns the elapsed time at which the charge first fell to the percentage
+func (p *DischargeProfile) ElapsedAt(percent float64) (time.Duration, bool) {
+	for _, sample := range p.Samples {
+		if sample.Percent <= percent {
+			return sample.Elapsed(), true
+		}
+	}
+	return 0, false
+}
+
+// LoadDischargeProfile loads a named discharge profile from the store
+func LoadDischargeProfile(st *store.Store, name string) (*DischargeProfile, error) {
+	profile := &DischargeProfile{}
+	if err := st.Load(DischargeCollection, name, profile); err != nil {
+		return nil, err
+	}
+	if len(profile.Samples) == 0 {
+		return nil, fmt.Errorf("discharge profile %q has no samples", name)
+	}
+	return profile, nil
+}
+
+// DischargeRecorder records a discharge run of the first battery into a
+// named profile. Recording starts with the first discharging sample and ends
+// when the charge reaches the threshold or the battery stops discharging.
+type DischargeRecorder struct {
+	mu        sync.Mutex
+	store     *store.Store
+	name      string
+	threshold float64
+	profile   *DischargeProfile
+	lastSave  time.Time
+	done      bool
+}
+
+// NewDischargeRecorder creates a recorder saving to the named profile
+func NewDischargeRecorder(st *store.Store, name string, threshold float64) *DischargeRecorder {
+	return &DischargeRecorder{
+		store:     st,
+		name:      name,
+		threshold: threshold,
+	}
+}
+
+// Add records a battery sample
+func (r *DischargeRecorder) Add(info *battery.Info) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.done {
+		return
+	}
+
+	discharging := info.State.Base() == battery.StateDischarging
+	if r.profile == nil {
+		if !discharging {
+			return
+		}
+		r.start(info)
+	}
+
+	if !discharging {
+		slog.Warn("Discharge stopped before reaching the threshold, saving incomplete profile",
+			"name", r.name,
+			"percent", info.ChargePercent(),
+		)
+		r.finish(false)
+		return
+	}
+
+	r.profile.Samples = append(r.profile.Samples, DischargeSample{
+		ElapsedSeconds: info.UpdatedAt.Sub(r.profile.StartedAt).Seconds(),
+		Percent:        info.ChargePercent(),
+		Current:        info.Current,
+		Rate:           info.ChargeRate,
+		Idle:           info.Idle,
+	})
+
+	if info.ChargePercent() <= r.threshold {
+		slog.Info("Discharge reached the threshold, saving profile", "name", r.name)
+		r.finish(true)
+		return
+	}
+
+	if time.Since(r.lastSave) >= dischargeSaveInterval {
+		r.save()
+	}
+}
+
+// Status returns a short description of the recording state
+func (r *DischargeRecorder) Status() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch {
+	case r.done && r.profile != nil && r.profile.Complete:
+		return fmt.Sprintf("Recorded %q", r.name)
+	case r.done:
+		return fmt.Sprintf("Recording %q stopped", r.name)
+	case r.profile == nil:
+		return fmt.Sprintf("Recording %q waits for discharge", r.name)
+	default:
+		return fmt.Sprintf("Recording %q", r.name)
+	}
+}
+
+// Close saves an in-progress recording
+func (r *DischargeRecorder) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.done || r.profile == nil {
+		return
+	}
+	r.finish(false)
+}
+
+// start begins a new profile at the sample
+func (r *DischargeRecorder) start(info *battery.Info) {
+	slog.Info("Discharge recording started", "name", r.name, "percent", info.ChargePercent())
+	r.profile = &DischargeProfile{
+		Name:         r.name,
+		BatteryIndex: info.Index,
+		StartedAt:    info.UpdatedAt,
+		Full:         info.Full,
+		Design:       info.Design,
+		Samples:      make([]DischargeSample, 0),
+	}
+}
+
+// finish marks the recording as done and saves it
+func (r *DischargeRecorder) finish(complete bool) {
+	r.done = true
+	r.profile.Complete = complete
+	r.save()
+}
+
+// save writes the profile to the store
+func (r *DischargeRecorder) save() {
+	r.lastSave = time.Now()
+	if err := r.store.Save(DischargeCollection, r.name, r.profile); err != nil {
+		slog.Error("Failed to save discharge profile", "name", r.name, "error", err)
+	}
+}
+
+// ReferenceCurve aligns a recorded discharge profile with the current
+// discharge run so it can be drawn alongside the live charge chart. The
+// profile is offset so that its charge matches the charge at which the
+// current run started.
+type ReferenceCurve struct {
+	mu       sync.Mutex
+	profile  *DischargeProfile
+	runStart time.Time
+	offset   time.Duration
+	active   bool
+}
+
+// NewReferenceCurve creates a reference curve for the profile
+func NewReferenceCurve(profile *DischargeProfile) *ReferenceCurve {
+	return &ReferenceCurve{profile: profile}
+}
+
+// Name returns the name of the reference profile
+func (c *ReferenceCurve) Name() string {
+	return c.profile.Name
+}
+
+// Observe updates the alignment with the latest battery sample
+func (c *ReferenceCurve) Observe(info *battery.Info) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if info.State.Base() != battery.StateDischarging {
+		c.active = false
+		return
+	}
+	if c.active {
+		return
+	}
+
+	offset, ok := c.profile.ElapsedAt(info.ChargePercent())
+	if !ok {
+		return
+	}
+	c.active = true
+	c.runStart = info.UpdatedAt
+	c.offset = offset
+}
+
+// At returns the reference charge percentage at the given time
+func (c *ReferenceCurve) At(t time.Time) (float64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.active || t.Before(c.runStart) {
+		return 0, false
+	}
+	return c.profile.PercentAt(c.offset + t.Sub(c.runStart))
+}
@@ -0,0 +1,152 @@
+package stats
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/battery"
+	"github.com/xsikor/go-battop/internal/store"
+)
+
+func TestDischargeRecorder(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	type sample struct {
+		state   battery.State
+		percent float64
+	}
+	tests := []struct {
+		name     string
+		samples  []sample
+		close    bool
+		saved    bool
+		complete bool
+		percents []float64
+		status   string
+	}{
+		{
+			name:    "waits for a discharge",
+			samples: []sample{{battery.StateCharging, 90}, {battery.StateFull, 100}},
+			status:  "waits for discharge",
+		},
+		{
+			name:     "reaches the threshold",
+			samples:  []sample{{battery.StateFull, 100}, {battery.StateDischarging, 99}, {battery.StateDischarging, 50}, {battery.StateDischarging, 10}, {battery.StateDischarging, 9}},
+			saved:    true,
+			complete: true,
+			percents: []float64{99, 50, 10},
+			status:   `Recorded "test"`,
+		},
+		{
+			name:     "stopped by the charger",
+			samples:  []sample{{battery.StateDischarging, 80}, {battery.StateDischarging, 70}, {battery.StateCharging, 70}, {battery.StateDischarging, 69}},
+			saved:    true,
+			percents: []float64{80, 70},
+			status:   `Recording "test" stopped`,
+		},
+		{
+			name:     "closed while recording",
+			samples:  []sample{{battery.StateDischarging, 80}, {battery.StateDischarging, 79}},
+			close:    true,
+			saved:    true,
+			percents: []float64{80, 79},
+			status:   `Recording "test" stopped`,
+		},
+		{
+			name:    "closed before a discharge",
+			samples: []sample{{battery.StateCharging, 80}},
+			close:   true,
+			status:  "waits for discharge",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := store.New(t.TempDir())
+			recorder := NewDischargeRecorder(st, "test", 10)
+			for i, s := range tt.samples {
+				recorder.Add(&battery.Info{
+					State:     s.state,
+					Current:   s.percent * 500,
+					Full:      50000,
+					Design:    60000,
+					UpdatedAt: start.Add(time.Duration(i) * time.Minute),
+				})
+			}
+			if tt.close {
+				recorder.Close()
+			}
+
+			if status := recorder.Status(); !strings.Contains(status, tt.status) {
+				t.Errorf("status %q, want %q", status, tt.status)
+			}
+			profile, err := LoadDischargeProfile(st, "test")
+			if !tt.saved {
+				if err == nil {
+					t.Errorf("profile saved with %d samples", len(profile.Samples))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if profile.Complete != tt.complete || profile.Full != 50000 || profile.Design != 60000 {
+				t.Errorf("profile complete %t, full %.0f, design %.0f", profile.Complete, profile.Full, profile.Design)
+			}
+			if len(profile.Samples) != len(tt.percents) {
+				t.Fatalf("%d samples, want %d", len(profile.Samples), len(tt.percents))
+			}
+			for i, sample := range profile.Samples {
+				if sample.Percent != tt.percents[i] {
+					t.Errorf("sample %d at %.0f%%, want %.0f%%", i, sample.Percent, tt.percents[i])
+				}
+			}
+			if first := profile.Samples[0]; first.ElapsedSeconds != 0 {
+				t.Errorf("first sample %.0f s after the start", first.ElapsedSeconds)
+			}
+		})
+	}
+}
+
+func TestDischargeProfile(t *testing.T) {
+	profile := &DischargeProfile{Samples: []DischargeSample{
+		{ElapsedSeconds: 0, Percent: 100, Current: 50000},
+		{ElapsedSeconds: 36, Percent: 99, Current: 49900},
+		{ElapsedSeconds: 72, Percent: 98, Current: 49800, Idle: true},
+		{ElapsedSeconds: 108, Percent: 97, Current: 49700},
+		// Suspended for an hour
+		{ElapsedSeconds: 3708, Percent: 90, Current: 45000},
+		{ElapsedSeconds: 3744, Percent: 80, Current: 44900},
+	}}
+
+	if drain := profile.ActiveDrain(); math.Abs(drain.Energy-200) > 1e-9 || drain.Duration != 72*time.Second {
+		t.Errorf("active drain %+v, want 200 mWh over 72s", drain)
+	}
+
+	percents := []struct {
+		elapsed time.Duration
+		percent float64
+		ok      bool
+	}{
+		{0, 100, true},
+		{18 * time.Second, 99.5, true},
+		{72 * time.Second, 98, true},
+		{3726 * time.Second, 85, true},
+		{-time.Second, 0, false},
+		{time.Hour + 3*time.Minute, 0, false},
+	}
+	for _, tt := range percents {
+		percent, ok := profile.PercentAt(tt.elapsed)
+		if ok != tt.ok || math.Abs(percent-tt.percent) > 1e-9 {
+			t.Errorf("PercentAt(%v) = %.2f, %t, want %.2f, %t", tt.elapsed, percent, ok, tt.percent, tt.ok)
+		}
+	}
+
+	if elapsed, ok := profile.ElapsedAt(90); !ok || elapsed != 3708*time.Second {
+		t.Errorf("ElapsedAt(90) = %v, %t", elapsed, ok)
+	}
+	if _, ok := profile.ElapsedAt(50); ok {
+		t.Error("ElapsedAt(50) found a charge the profile never reached")
+	}
+}
@@ -0,0 +1,237 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/battery"
+	"github.com/xsikor/go-battop/internal/store"
+)
+
+// MaxEvents is the number of events the event log keeps
+const MaxEvents = 500
+
+// EventGapFactor is how many times longer than the usual interval between
+// readings a pause must be, besides longer than MaxSampleGap, to be logged
+// as a suspend
+const EventGapFactor = 5
+
+// EventKind is the category of a logged event
+type EventKind string
+
+const (
+	// EventPower is external power being connected or disconnected
+	EventPower EventKind = "power"
+	// EventState is a battery changing its state (e.g., to charging)
+	EventState EventKind = "state"
+	// EventThreshold is the charge crossing a configured threshold
+	EventThreshold EventKind = "threshold"
+	// EventSuspend is a pause of the readings, e.g. by a suspend
+	EventSuspend EventKind = "suspend"
+	// EventNote is a note the user tagged a moment with (e.g., "started compile")
+	EventNote EventKind = "note"
+)
+
+// Severity rates how much attention an event needs
+type Severity string
+
+const (
+	// SeverityInfo is an event worth knowing about
+	SeverityInfo Severity = "info"
+	// SeverityWarn is an event that needs attention, e.g. a low charge
+	SeverityWarn Severity = "warn"
+	// SeverityCritical is an event that needs action, e.g. a critical charge
+	SeverityCritical Severity = "critical"
+)
+
+// severityRanks orders the severities
+var severityRanks = map[Severity]int{SeverityInfo: 0, SeverityWarn: 1, SeverityCritical: 2}
+
+// AtLeast reports whether the severity is at or above another one
+func (s Severity) AtLeast(other Severity) bool {
+	return severityRanks[s] >= severityRanks[other]
+}
+
+// ChargeThreshold is a charge percentage whose crossings are logged; drops
+// to it are logged with its severity, rises to it as info
+type ChargeThreshold struct {
+	Percent  float64
+	Severity Severity
+}
+
+// NotesCollection is the store collection holding the notes, one entry per day
+const NotesCollection = "notes"
+
+// Event is a notable moment of the battery history
+type Event struct {
+	Time time.Time `json:"time"`
+
+	// Battery is the index of the battery, -1 for the whole system
+	Battery int `json:"battery"`
+
+	Kind     EventKind `json:"kind"`
+	Severity Severity  `json:"severity"`
+	Message  string    `json:"message"`
+}
+
+// EventLog detects notable events in the readings and keeps the latest
+// MaxEvents of them. It is safe for concurrent use.
+type EventLog struct {
+	mu     sync.Mutex
+	events []Event
+
+	// thresholds are the charge percentages whose crossings are logged
+	thresholds []ChargeThreshold
+
+	// The previous readings; onAC is nil until the first observation
+	states   map[int]battery.State
+	percents map[int]float64
+	onAC     *bool
+	last     time.Time
+	interval time.Duration
+
+	// store persists the notes when set
+	store *store.Store
+}
+
+// NewEventLog creates an event log that also logs the charge crossing the
+// given thresholds
+func NewEventLog(thresholds ...ChargeThreshold) *EventLog {
+	return &EventLog{
+		thresholds: thresholds,
+		states:     make(map[int]battery.State),
+		percents:   make(map[int]float64),
+	}
+}
+
+// SetThresholds replaces the charge thresholds whose crossings are logged,
+// e.g. after the configuration was reloaded
+func (l *EventLog) SetThresholds(thresholds ...ChargeThreshold) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.thresholds = thresholds
+}
+
+// Observe compares the latest readings against the previous ones and logs
+// the events that occurred in between. The first readings log nothing.
+func (l *EventLog) Observe(batteries []*battery.Info, source battery.PowerSource, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.last.IsZero() {
+		gap := now.Sub(l.last)
+		if gap > MaxSampleGap && gap > EventGapFactor*l.interval {
+			l.add(Event{Time: l.last, Battery: -1, Kind: EventSuspend, Severity: SeverityInfo,
+				Message: fmt.Sprintf("No readings for %s (suspended)", gap.Round(time.Second))})
+		} else {
+			l.interval = gap
+		}
+	}
+	l.last = now
+
+	if source.Detected {
+		if l.onAC != nil && *l.onAC != source.OnAC {
+			message := "AC adapter unplugged"
+			if source.OnAC {
+				message = "AC adapter plugged in"
+			}
+			l.add(Event{Time: now, Battery: -1, Kind: EventPower, Severity: SeverityInfo, Message: message})
+		}
+		onAC := source.OnAC
+		l.onAC = &onAC
+	}
+
+	for _, info := range batteries {
+		l.observeBattery(info, now)
+	}
+}
+
+// observeBattery logs the state changes and threshold crossings of a battery
+func (l *EventLog) observeBattery(info *battery.Info, now time.Time) {
+	state, percent := info.State.Base(), info.ChargePercent()
+	previous, seen := l.states[info.Index]
+	before := l.percents[info.Index]
+	l.states[info.Index], l.percents[info.Index] = state, percent
+	if !seen {
+		return
+	}
+
+	if state != previous {
+		l.add(Event{Time: now, Battery: info.Index, Kind: EventState, Severity: SeverityInfo,
+			Message: fmt.Sprintf("Battery %d: %s → %s at %.0f%%", info.Index, previous, state, percent)})
+	}
+	for _, threshold := range l.thresholds {
+		switch {
+		case before > threshold.Percent && percent <= threshold.Percent:
+			l.add(Event{Time: now, Battery: info.Index, Kind: EventThreshold, Severity: threshold.Severity,
+				Message: fmt.Sprintf("Battery %d dropped to %.0f%%", info.Index, threshold.Percent)})
+		case before < threshold.Percent && percent >= threshold.Percent:
+			l.add(Event{Time: now, Battery: info.Index, Kind: EventThreshold, Severity: SeverityInfo,
+				Message: fmt.Sprintf("Battery %d reached %.0f%%", info.Index, threshold.Percent)})
+		}
+	}
+}
+
+// SetStore saves the notes added from now on into the store and loads the
+// notes saved earlier today
+func (l *EventLog) SetStore(st *store.Store, now time.Time) error {
+	var notes []Event
+	if err := st.Load(NotesCollection, now.Format(healthDateFormat), &notes); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.store = st
+	l.events = append(notes, l.events...)
+	return nil
+}
+
+// Annotate adds a note for the whole system at now, saved with the day's
+// notes when a store is set
+func (l *EventLog) Annotate(text string, now time.Time) (Event, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return Event{}, fmt.Errorf("empty note")
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	event := Event{Time: now, Battery: -1, Kind: EventNote, Severity: SeverityInfo, Message: text}
+	l.add(event)
+	if l.store == nil {
+		return event, nil
+	}
+
+	day := now.Format(healthDateFormat)
+	var notes []Event
+	if err := l.store.Load(NotesCollection, day, &notes); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return event, err
+	}
+	return event, l.store.Save(NotesCollection, day, append(notes, event))
+}
+
+// add appends an event, dropping the oldest beyond MaxEvents
+func (l *EventLog) add(event Event) {
+	l.events = append(l.events, event)
+	if len(l.events) > MaxEvents {
+		l.events = l.events[len(l.events)-MaxEvents:]
+	}
+}
+
+// Matches reports whether the event is at least of the given severity and
+// its message contains the query, ignoring case (an empty query matches all)
+func (e Event) Matches(query string, severity Severity) bool {
+	return e.Severity.AtLeast(severity) && strings.Contains(strings.ToLower(e.Message), strings.ToLower(query))
+}
+
+// Events returns a copy of the logged events, oldest first
+func (l *EventLog) Events() []Event {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Event(nil), l.events...)
+}
@@ -0,0 +1,123 @@
+package stats
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/battery"
+	"github.com/xsikor/go-battop/internal/store"
+)
+
+func TestEventLog(t *testing.T) {
+	log := NewEventLog(ChargeThreshold{Percent: 20, Severity: SeverityWarn}, ChargeThreshold{Percent: 80, Severity: SeverityInfo})
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	reading := func(state battery.State, percent float64) []*battery.Info {
+		return []*battery.Info{{Index: 0, State: state, Current: percent * 500, Full: 50000}}
+	}
+	onAC := battery.PowerSource{Detected: true, OnAC: true}
+	onBattery := battery.PowerSource{Detected: true}
+
+	log.Observe(reading(battery.StateCharging, 78), onAC, start)
+	log.Observe(reading(battery.StateCharging, 80), onAC, start.Add(10*time.Second))
+	log.Observe(reading(battery.StateDischarging, 80), onBattery, start.Add(20*time.Second))
+	log.Observe(reading(battery.StateDischarging, 21), onBattery, start.Add(30*time.Second))
+	// A suspend stops the readings for an hour
+	log.Observe(reading(battery.StateDischarging, 19), onBattery, start.Add(time.Hour))
+
+	want := []struct {
+		kind     EventKind
+		severity Severity
+		message  string
+	}{
+		{EventThreshold, SeverityInfo, "reached 80%"},
+		{EventPower, SeverityInfo, "AC adapter unplugged"},
+		{EventState, SeverityInfo, "Charging → Discharging"},
+		{EventSuspend, SeverityInfo, "No readings for 59m30s"},
+		{EventThreshold, SeverityWarn, "dropped to 20%"},
+	}
+	events := log.Events()
+	if len(events) != len(want) {
+		t.Fatalf("events = %v, want %d", events, len(want))
+	}
+	for i, w := range want {
+		if events[i].Kind != w.kind || events[i].Severity != w.severity || !strings.Contains(events[i].Message, w.message) {
+			t.Errorf("event %d = %s %s %q, want %s %s containing %q", i, events[i].Kind, events[i].Severity, events[i].Message,
+				w.kind, w.severity, w.message)
+		}
+	}
+	if suspend := events[3]; !suspend.Time.Equal(start.Add(30*time.Second)) || suspend.Battery != -1 {
+		t.Errorf("suspend event at %v for battery %d, want the start of the gap for the system", suspend.Time, suspend.Battery)
+	}
+}
+
+func TestEventLogSetThresholds(t *testing.T) {
+	log := NewEventLog(ChargeThreshold{Percent: 20, Severity: SeverityWarn})
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	reading := func(percent float64) []*battery.Info {
+		return []*battery.Info{{Index: 0, State: battery.StateDischarging, Current: percent * 500, Full: 50000}}
+	}
+
+	// A reload raises the low threshold to 30%
+	log.Observe(reading(35), battery.PowerSource{}, start)
+	log.SetThresholds(ChargeThreshold{Percent: 30, Severity: SeverityWarn})
+	log.Observe(reading(25), battery.PowerSource{}, start.Add(10*time.Second))
+	log.Observe(reading(15), battery.PowerSource{}, start.Add(20*time.Second))
+
+	events := log.Events()
+	if len(events) != 1 || !strings.Contains(events[0].Message, "dropped to 30%") {
+		t.Errorf("events = %v, want only the crossing of the new threshold", events)
+	}
+}
+
+func TestEventLogNotes(t *testing.T) {
+	st := store.New(t.TempDir())
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	log := NewEventLog()
+	if err := log.SetStore(st, now); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := log.Annotate("  ", now); err == nil {
+		t.Error("Annotate accepted an empty note")
+	}
+	if _, err := log.Annotate(" started compile ", now); err != nil {
+		t.Fatal(err)
+	}
+
+	// A restart the same day loads the note
+	restarted := NewEventLog()
+	if err := restarted.SetStore(st, now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	events := restarted.Events()
+	if len(events) != 1 || events[0].Kind != EventNote || events[0].Message != "started compile" || !events[0].Time.Equal(now) {
+		t.Errorf("events after restart = %v, want the note", events)
+	}
+
+	// The next day starts without notes
+	if err := NewEventLog().SetStore(st, now.AddDate(0, 0, 1)); err != nil {
+		t.Errorf("SetStore on a day without notes: %v", err)
+	}
+}
+
+func TestEventMatches(t *testing.T) {
+	event := Event{Kind: EventThreshold, Severity: SeverityWarn, Message: "Battery 0 dropped to 20%"}
+
+	tests := []struct {
+		query    string
+		severity Severity
+		want     bool
+	}{
+		{"", SeverityInfo, true},
+		{"DROPPED", SeverityInfo, true},
+		{"dropped", SeverityWarn, true},
+		{"dropped", SeverityCritical, false},
+		{"unplugged", SeverityInfo, false},
+	}
+	for _, tt := range tests {
+		if got := event.Matches(tt.query, tt.severity); got != tt.want {
+			t.Errorf("Matches(%q, %s) = %v, want %v", tt.query, tt.severity, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package stats
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Goal is a time the batteries should last until, e.g. the end of the workday
+type Goal struct {
+	// Until is when the batteries should run out at the earliest
+	Until time.Time
+}
+
+// ParseGoal parses a goal relative to now: a duration ("3h") or a time of
+// day ("18:00", "until 18:00"); a time of day that already passed refers to
+// tomorrow.
+func ParseGoal(text string, now time.Time) (Goal, error) {
+	if strings.TrimSpace(text) == "" {
+		return Goal{}, fmt.Errorf("empty goal")
+	}
+	reserve, err := ParseReserve(text, now)
+	if err != nil {
+		return Goal{}, err
+	}
+	if reserve.Label != "" {
+		return Goal{}, fmt.Errorf("unexpected %q: expected a duration (3h) or a time of day (18:00)", reserve.Label)
+	}
+	return Goal{Until: reserve.Until}, nil
+}
+
+// String returns the deadline
+func (g Goal) String() string {
+	return "until " + g.Until.Format(reserveClockLayout)
+}
+
+// Budget returns the average draw in mW at which the remaining energy in mWh
+// lasts until the goal, and the time left. Budget returns false once the
+// goal has passed.
+func (g Goal) Budget(energy float64, now time.Time) (float64, time.Duration, bool) {
+	left := g.Until.Sub(now)
+	if left <= 0 {
+		return 0, 0, false
+	}
+	return max(energy, 0) / left.Hours(), left, true
+}
@@ -0,0 +1,56 @@
+package stats
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseGoal(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		text  string
+		until time.Time
+		err   bool
+	}{
+		{text: "3h", until: now.Add(3 * time.Hour)},
+		{text: "18:00", until: time.Date(2024, 3, 1, 18, 0, 0, 0, time.UTC)},
+		{text: "until 18:00", until: time.Date(2024, 3, 1, 18, 0, 0, 0, time.UTC)},
+		{text: "09:30", until: time.Date(2024, 3, 2, 9, 30, 0, 0, time.UTC)},
+		{text: "flight 4h", err: true},
+		{text: "soon", err: true},
+		{text: " ", err: true},
+	}
+
+	for _, tt := range tests {
+		goal, err := ParseGoal(tt.text, now)
+		if tt.err {
+			if err == nil {
+				t.Errorf("ParseGoal(%q) = %v, want error", tt.text, goal)
+			}
+			continue
+		}
+		if err != nil || !goal.Until.Equal(tt.until) {
+			t.Errorf("ParseGoal(%q) = %v, %v, want %v", tt.text, goal.Until, err, tt.until)
+		}
+	}
+}
+
+func TestGoalBudget(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	goal := Goal{Until: now.Add(4 * time.Hour)}
+
+	budget, left, ok := goal.Budget(40000, now)
+	if !ok || budget != 10000 || left != 4*time.Hour {
+		t.Errorf("Budget = %v, %v, %v, want 10000, 4h, true", budget, left, ok)
+	}
+
+	// The budget grows as time passes without using energy
+	if later, _, _ := goal.Budget(40000, now.Add(2*time.Hour)); later != 20000 {
+		t.Errorf("Budget two hours later = %v, want 20000", later)
+	}
+
+	if _, _, ok := goal.Budget(40000, goal.Until); ok {
+		t.Error("Budget after the goal reported ok")
+	}
+}
@@ -0,0 +1,192 @@
+package stats
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/battery"
+	"github.com/xsikor/go-battop/internal/store"
+)
+
+// HealthCollection is the store collection holding health histories
+const HealthCollection = "health"
+
+// HealthThreshold is the health percentage commonly considered worn out
+const HealthThreshold = 80.0
+
+// healthDateFormat is the date format of health history entries
+const healthDateFormat = "2006-01-02"
+
+// HealthRecord is a daily capacity reading
+type HealthRecord struct {
+	Date   string  `json:"date"`
+	Full   float64 `json:"full_mwh"`
+	Design float64 `json:"design_mwh"`
+	Cycles int     `json:"cycles,omitempty"`
+}
+
+// Time returns the date of the record
+func (r HealthRecord) Time() time.Time {
+	t, _ := time.ParseInLocation(healthDateFormat, r.Date, time.Local)
+	return t
+}
+
+// Health returns the health percentage of the record
+func (r HealthRecord) Health() float64 {
+	if r.Design <= 0 {
+		return 0
+	}
+	return r.Full / r.Design * 100
+}
+
+// HealthHistory is the persisted capacity fade history of one battery
+type HealthHistory struct {
+	mu      sync.RWMutex
+	store   *store.Store
+	key     string
+	Records []HealthRecord `json:"records"`
+}
+
+// HealthKey returns the store key of a battery's health history, preferring
+// the serial number so histories survive battery index changes
+func HealthKey(info *battery.Info) string {
+	if info.Serial != "" {
+		return "serial-" + info.Serial
+	}
+	return fmt.Sprintf("battery%d", info.Index)
+}
+
+// LoadHealthHistory loads a battery's health history, returning an empty
+// history if none was recorded yet
+func LoadHealthHistory(st *store.Store, info *battery.Info) *HealthHistory {
+	history := &HealthHistory{
+		store:   st,
+		key:     HealthKey(info),
+		Records: make([]HealthRecord, 0),
+	}
+	// A missing history is expected on first run
+	_ = st.Load(HealthCollection, history.key, history)
+	return history
+}
+
+// Record stores today's reading if none exists yet and saves the history.
+// It returns whether a new record was added.
+func (h *HealthHistory) Record(info *battery.Info, now time.Time) (bool, error) {
+	if info.Full <= 0 || info.Design <= 0 {
+		return false, nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	date := now.Format(healthDateFormat)
+	if n := len(h.Records); n > 0 && h.Records[n-1].Date == date {
+		return false, nil
+	}
+
+	h.Records = append(h.Records, HealthRecord{
+		Date:   date,
+		Full:   info.Full,
+		Design: info.Design,
+		Cycles: info.CycleCount,
+	})
+	return true, h.store.Save(HealthCollection, h.key, h)
+}
+
+// Entries returns a copy of the recorded entries
+func (h *HealthHistory) Entries() []HealthRecord {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return append([]HealthRecord(nil), h.Records...)
+}
+
+// Projection confidence requirements: the number of daily records and the
+// fraction of the health variation explained by the linear fit
+const (
+	projectionMediumRecords = 14
+	projectionHighRecords   = 60
+	projectionMediumFit     = 0.5
+	projectionHighFit       = 0.8
+)
+
+// healthFit is a least-squares fit of health over days since origin
+type healthFit struct {
+	origin    time.Time
+	records   int
+	slope     float64
+	intercept float64
+	r2        float64
+}
+
+// fit fits a line through the health records. It returns false when there
+// are fewer than two records or they are all on the same day.
+func (h *HealthHistory) fit() (healthFit, bool) {
+	records := h.Entries()
+	if len(records) < 2 {
+		return healthFit{}, false
+	}
+
+	origin := records[0].Time()
+	var sumX, sumY, sumXY, sumXX, sumYY float64
+	for _, r := range records {
+		x := r.Time().Sub(origin).Hours() / 24
+		y := r.Health()
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+		sumYY += y * y
+	}
+
+	n := float64(len(records))
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return healthFit{}, false
+	}
+	slope := (n*sumXY - sumX*sumY) / denominator
+
+	// Coefficient of determination; a flat history is explained perfectly
+	r2 := 1.0
+	if variance := n*sumYY - sumY*sumY; variance > 0 {
+		covariance := n*sumXY - sumX*sumY
+		r2 = covariance * covariance / (denominator * variance)
+	}
+
+	return healthFit{
+		origin:    origin,
+		records:   len(records),
+		slope:     slope,
+		intercept: (sumY - slope*sumX) / n,
+		r2:        r2,
+	}, true
+}
+
+// Projection estimates when health reaches the threshold percentage using a
+// least-squares fit of health over time. It returns false when there is not
+// enough data or health isn't declining.
+func (h *HealthHistory) Projection(threshold float64) (time.Time, bool) {
+	fit, ok := h.fit()
+	if !ok || fit.slope >= 0 {
+		return time.Time{}, false
+	}
+
+	days := (threshold - fit.intercept) / fit.slope
+	return fit.origin.Add(time.Duration(days * 24 * float64(time.Hour))), true
+}
+
+// ProjectionConfidence rates the Projection from the number of records and
+// how well the linear fit explains them
+func (h *HealthHistory) ProjectionConfidence() battery.Confidence {
+	fit, ok := h.fit()
+	switch {
+	case !ok || fit.slope >= 0:
+		return battery.ConfidenceUnknown
+	case fit.records < projectionMediumRecords || fit.r2 < projectionMediumFit:
+		return battery.ConfidenceLow
+	case fit.records < projectionHighRecords || fit.r2 < projectionHighFit:
+		return battery.ConfidenceMedium
+	default:
+		return battery.ConfidenceHigh
+	}
+}
@@ -0,0 +1,117 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/battery"
+	"github.com/xsikor/go-battop/internal/store"
+)
+
+func TestHealthProjection(t *testing.T) {
+	origin := time.Date(2026, 1, 1, 0, 0, 0, 0, time.Local)
+	// records returns one record per day offset with the given health
+	records := func(points ...float64) []HealthRecord {
+		var rs []HealthRecord
+		for i := 0; i+1 < len(points); i += 2 {
+			rs = append(rs, HealthRecord{
+				Date:   origin.AddDate(0, 0, int(points[i])).Format(healthDateFormat),
+				Full:   points[i+1] * 1000,
+				Design: 100000,
+			})
+		}
+		return rs
+	}
+	// decline returns n daily records losing rate points a day, alternately
+	// noise points above and below the line
+	decline := func(n int, rate, noise float64) []float64 {
+		var points []float64
+		for day := 0; day < n; day++ {
+			health := 95 - rate*float64(day)
+			if day%2 == 1 {
+				health += noise
+			} else {
+				health -= noise
+			}
+			points = append(points, float64(day), health)
+		}
+		return points
+	}
+
+	tests := []struct {
+		name       string
+		records    []HealthRecord
+		ok         bool
+		days       float64
+		confidence battery.Confidence
+		slack      time.Duration
+	}{
+		{"no records", nil, false, 0, battery.ConfidenceUnknown, 0},
+		{"one record", records(0, 95), false, 0, battery.ConfidenceUnknown, 0},
+		{"same day", append(records(0, 95), records(0, 94)...), false, 0, battery.ConfidenceUnknown, 0},
+		{"flat", records(0, 95, 10, 95, 20, 95), false, 0, battery.ConfidenceUnknown, 0},
+		{"improving", records(0, 90, 10, 91), false, 0, battery.ConfidenceUnknown, 0},
+		{"two records", records(0, 90, 10, 89), true, 100, battery.ConfidenceLow, 0},
+		{"irregular dates", records(0, 90, 3, 89.7, 30, 87), true, 100, battery.ConfidenceLow, 0},
+		{"two weeks", records(decline(14, 0.1, 0)...), true, 150, battery.ConfidenceMedium, 0},
+		{"two months", records(decline(60, 0.1, 0)...), true, 150, battery.ConfidenceHigh, 0},
+		{"two noisy months", records(decline(60, 0.1, 2)...), true, 150, battery.ConfidenceLow, 7 * 24 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history := &HealthHistory{Records: tt.records}
+			at, ok := history.Projection(HealthThreshold)
+			if ok != tt.ok {
+				t.Fatalf("projection found %t, want %t", ok, tt.ok)
+			}
+			if ok {
+				want := origin.Add(time.Duration(tt.days * 24 * float64(time.Hour)))
+				slack := max(tt.slack, time.Hour)
+				if diff := at.Sub(want); diff > slack || diff < -slack {
+					t.Errorf("projected %s, want %s", at.Format(time.RFC3339), want.Format(time.RFC3339))
+				}
+			}
+			if got := history.ProjectionConfidence(); got != tt.confidence {
+				t.Errorf("confidence %s, want %s", got, tt.confidence)
+			}
+		})
+	}
+}
+
+func TestHealthRecord(t *testing.T) {
+	st := store.New(t.TempDir())
+	info := &battery.Info{Serial: "X1", Full: 45000, Design: 50000, CycleCount: 120}
+	history := LoadHealthHistory(st, info)
+
+	day := time.Date(2026, 3, 1, 9, 0, 0, 0, time.Local)
+	steps := []struct {
+		at    time.Time
+		full  float64
+		added bool
+	}{
+		{day, 45000, true},
+		// One record per day
+		{day.Add(8 * time.Hour), 44000, false},
+		{day.AddDate(0, 0, 1), 44900, true},
+		// Readings without a capacity are not recorded
+		{day.AddDate(0, 0, 2), 0, false},
+	}
+	for i, step := range steps {
+		info.Full = step.full
+		added, err := history.Record(info, step.at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if added != step.added {
+			t.Errorf("step %d: added %t, want %t", i, added, step.added)
+		}
+	}
+
+	loaded := LoadHealthHistory(st, info).Entries()
+	if len(loaded) != 2 || math.Abs(loaded[0].Health()-90) > 1e-9 || loaded[1].Cycles != 120 {
+		t.Errorf("reloaded history %s", fmt.Sprint(loaded))
+	}
+}
@@ -0,0 +1,105 @@
+package stats
+
+import "time"
+
+// HistogramStep is the discharge power range of a histogram bucket in mW
+const HistogramStep = 1000.0
+
+// Histogram is the time spent at each discharge power, in HistogramStep wide
+// buckets starting at 0 mW
+type Histogram struct {
+	// Durations holds the time spent in every bucket, lowest power first
+	Durations []time.Duration
+}
+
+// HistogramBin is a power range of a histogram and the time spent in it
+type HistogramBin struct {
+	// Min and Max bound the discharge power in mW
+	Min, Max float64
+
+	// Duration is the time spent discharging within the range
+	Duration time.Duration
+}
+
+// add adds time spent at a discharge power in mW
+func (h *Histogram) add(power float64, d time.Duration) {
+	bucket := int(power / HistogramStep)
+	if bucket < 0 {
+		return
+	}
+	for len(h.Durations) <= bucket {
+		h.Durations = append(h.Durations, 0)
+	}
+	h.Durations[bucket] += d
+}
+
+// clone returns a copy that doesn't share the buckets
+func (h Histogram) clone() Histogram {
+	return Histogram{Durations: append([]time.Duration(nil), h.Durations...)}
+}
+
+// Total returns the time covered by the histogram
+func (h Histogram) Total() time.Duration {
+	var total time.Duration
+	for _, d := range h.Durations {
+		total += d
+	}
+	return total
+}
+
+// Bins merges the buckets between the lowest and the highest power seen into
+// at most count bins of equal width
+func (h Histogram) Bins(count int) []HistogramBin {
+	first, last := -1, -1
+	for i, d := range h.Durations {
+		if d > 0 {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first < 0 || count <= 0 {
+		return nil
+	}
+
+	width := (last - first + count) / count
+	var bins []HistogramBin
+	for start := first; start <= last; start += width {
+		bin := HistogramBin{
+			Min: float64(start) * HistogramStep,
+			Max: float64(start+width) * HistogramStep,
+		}
+		for i := start; i < start+width && i <= last; i++ {
+			bin.Duration += h.Durations[i]
+		}
+		bins = append(bins, bin)
+	}
+	return bins
+}
+
+// Median returns the discharge power in mW at the middle of the bucket that
+// splits the time in half, or 0 for an empty histogram
+func (h Histogram) Median() float64 {
+	if h.Total() <= 0 {
+		return 0
+	}
+	return h.Quantile(0.5) - HistogramStep/2
+}
+
+// Quantile returns the discharge power in mW below which the given fraction
+// of the time was spent, at the bucket resolution
+func (h Histogram) Quantile(q float64) float64 {
+	total := h.Total()
+	if total <= 0 {
+		return 0
+	}
+	var sum time.Duration
+	for i, d := range h.Durations {
+		sum += d
+		if float64(sum) >= q*float64(total) {
+			return float64(i+1) * HistogramStep
+		}
+	}
+	return float64(len(h.Durations)) * HistogramStep
+}
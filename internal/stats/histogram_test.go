@@ -0,0 +1,48 @@
+package stats
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/battery"
+)
+
+func TestTrackerDischargeHistogram(t *testing.T) {
+	tracker := NewTracker()
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// A minute at 8.5 W, two at 12 W and a charging minute that isn't counted
+	rates := []float64{0, -8500, -12000, -12000, 20000}
+	for i, rate := range rates {
+		tracker.Add([]*battery.Info{{ChargeRate: rate, UpdatedAt: start.Add(time.Duration(i) * time.Minute)}})
+	}
+
+	histogram := tracker.Summary().Discharge
+	if total := histogram.Total(); total != 3*time.Minute {
+		t.Fatalf("total = %v, want 3m", total)
+	}
+
+	bins := histogram.Bins(2)
+	want := []HistogramBin{
+		{Min: 8000, Max: 11000, Duration: time.Minute},
+		{Min: 11000, Max: 14000, Duration: 2 * time.Minute},
+	}
+	if len(bins) != len(want) {
+		t.Fatalf("bins = %v, want %v", bins, want)
+	}
+	for i := range want {
+		if bins[i] != want[i] {
+			t.Errorf("bin %d = %v, want %v", i, bins[i], want[i])
+		}
+	}
+	if q := histogram.Quantile(0.5); q != 13000 {
+		t.Errorf("half of the time below %v mW, want 13000", q)
+	}
+	if median := histogram.Median(); median != 12500 {
+		t.Errorf("median = %v mW, want the middle of the 12 W bucket", median)
+	}
+	if rate := tracker.Summary().DischargeRate(); math.Abs(rate-32500.0/3) > 1e-6 {
+		t.Errorf("average discharge = %v mW, want %v", rate, 32500.0/3)
+	}
+}
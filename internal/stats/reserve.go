@@ -0,0 +1,101 @@
+package stats
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// reserveClockLayout is the time-of-day format accepted for reserve deadlines
+const reserveClockLayout = "15:04"
+
+// Reserve is an upcoming period without a charger, e.g. a meeting or a flight
+type Reserve struct {
+	// Label describes the period (e.g., "flight"), may be empty
+	Label string
+
+	// Until is when the period ends
+	Until time.Time
+}
+
+// ParseReserve parses a reserve description relative to now. It accepts a
+// duration ("4h", "flight 4h") or a time of day ("15:30", "meeting until
+// 15:30"); a time of day that already passed refers to tomorrow.
+func ParseReserve(text string, now time.Time) (Reserve, error) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return Reserve{}, fmt.Errorf("empty reserve")
+	}
+
+	last := fields[len(fields)-1]
+	label := fields[:len(fields)-1]
+	if n := len(label); n > 0 && strings.EqualFold(label[n-1], "until") {
+		label = label[:n-1]
+	}
+	reserve := Reserve{Label: strings.Join(label, " ")}
+
+	if d, err := time.ParseDuration(last); err == nil {
+		if d <= 0 {
+			return Reserve{}, fmt.Errorf("reserve duration must be positive")
+		}
+		reserve.Until = now.Add(d)
+		return reserve, nil
+	}
+
+	clock, err := time.ParseInLocation(reserveClockLayout, last, now.Location())
+	if err != nil {
+		return Reserve{}, fmt.Errorf("expected a duration (4h) or a time of day (15:30), got %q", last)
+	}
+	until := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+	if !until.After(now) {
+		until = until.AddDate(0, 0, 1)
+	}
+	reserve.Until = until
+	return reserve, nil
+}
+
+// String returns the label and deadline
+func (r Reserve) String() string {
+	if r.Label == "" {
+		return "until " + r.Until.Format(reserveClockLayout)
+	}
+	return r.Label + " until " + r.Until.Format(reserveClockLayout)
+}
+
+// ReservePlan is the projection of a reserve against the remaining energy
+type ReservePlan struct {
+	// Left is the time until the reserve period ends
+	Left time.Duration
+
+	// Runtime is how long the remaining energy lasts at the expected draw
+	Runtime time.Duration
+
+	// Known is false when no draw is known yet
+	Known bool
+}
+
+// Short reports whether the batteries are projected to run out before the period ends
+func (p ReservePlan) Short() bool {
+	return p.Known && p.Runtime < p.Left
+}
+
+// Margin returns how much runtime is left over (negative when short)
+func (p ReservePlan) Margin() time.Duration {
+	return p.Runtime - p.Left
+}
+
+// Plan projects the remaining energy in mWh at the draw in mW against the
+// reserve. Plan returns false once the period is over.
+func (r Reserve) Plan(energy, draw float64, now time.Time) (ReservePlan, bool) {
+	left := r.Until.Sub(now)
+	if left <= 0 {
+		return ReservePlan{}, false
+	}
+
+	plan := ReservePlan{Left: left}
+	if draw > 0 {
+		plan.Known = true
+		plan.Runtime = time.Duration(energy / draw * float64(time.Hour))
+	}
+	return plan, true
+}
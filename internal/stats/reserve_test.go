@@ -0,0 +1,24 @@
+package stats
+
+import (
+	"testing"
+	"time"
+)
+
+func FuzzParseReserve(f *testing.F) {
+	for _, seed := range []string{"4h", "flight 4h", "15:30", "meeting until 15:30", "until", "-1h", "25:99", ""} {
+		f.Add(seed)
+	}
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	f.Fuzz(func(t *testing.T, text string) {
+		reserve, err := ParseReserve(text, now)
+		if err != nil {
+			return
+		}
+		if !reserve.Until.After(now) {
+			t.Errorf("ParseReserve(%q) ends at %v, not after %v", text, reserve.Until, now)
+		}
+		_ = reserve.String()
+	})
+}
@@ -0,0 +1,175 @@
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/battery"
+)
+
+// Snapshot is the capacity and cycle count of the batteries at a point in
+// time, recorded by battop diff to compare against later
+type Snapshot struct {
+	Time      time.Time         `json:"time"`
+	Batteries []BatterySnapshot `json:"batteries"`
+}
+
+// BatterySnapshot is the identity, capacity and cycle count of one battery
+type BatterySnapshot struct {
+	Index        int     `json:"index"`
+	Manufacturer string  `json:"manufacturer,omitempty"`
+	Model        string  `json:"model,omitempty"`
+	Serial       string  `json:"serial,omitempty"`
+	Full         float64 `json:"full_mwh"`
+	Design       float64 `json:"design_mwh"`
+	Voltage      float64 `json:"nominal_voltage_v,omitempty"`
+	Cycles       int     `json:"cycles,omitempty"`
+	HasCycles    bool    `json:"has_cycles,omitempty"`
+}
+
+// Health returns the health percentage of the battery
+func (b BatterySnapshot) Health() float64 {
+	if b.Design <= 0 {
+		return 0
+	}
+	return b.Full / b.Design * 100
+}
+
+// NewSnapshot takes a snapshot of the batteries; empty bays are skipped
+func NewSnapshot(batteries []*battery.Info, now time.Time) Snapshot {
+	snapshot := Snapshot{Time: now}
+	for _, info := range batteries {
+		if info.State == battery.StateNotPresent {
+			continue
+		}
+		snapshot.Batteries = append(snapshot.Batteries, BatterySnapshot{
+			Index:        info.Index,
+			Manufacturer: info.Manufacturer,
+			Model:        info.Model,
+			Serial:       info.Serial,
+			Full:         info.Full,
+			Design:       info.Design,
+			Voltage:      info.NominalVoltage(),
+			Cycles:       info.CycleCount,
+			HasCycles:    info.Capabilities.HasCycles,
+		})
+	}
+	return snapshot
+}
+
+// LoadSnapshot reads a snapshot file
+func LoadSnapshot(path string) (Snapshot, error) {
+	var snapshot Snapshot
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return snapshot, err
+	}
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return snapshot, fmt.Errorf("%s is not a battop snapshot: %w", path, err)
+	}
+	return snapshot, nil
+}
+
+// Save writes the snapshot to a file
+func (s Snapshot) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// SnapshotDiff compares one battery between two snapshots
+type SnapshotDiff struct {
+	Old, New BatterySnapshot
+
+	// Elapsed is the time between the snapshots
+	Elapsed time.Duration
+
+	// Fade is the health lost in percentage points, negative when it rose
+	// (e.g., after a calibration)
+	Fade float64
+
+	// Cycles is the number of cycles between the snapshots, valid when
+	// both report a cycle count
+	Cycles    int
+	HasCycles bool
+
+	// EndOfLife is when health is projected to reach HealthThreshold at the
+	// fade rate between the snapshots, zero when health didn't decline
+	EndOfLife time.Time
+
+	// CyclesLeft is the projected number of cycles until HealthThreshold at
+	// the fade per cycle, -1 when unknown
+	CyclesLeft int
+}
+
+// FadePerYear returns the health lost per year in percentage points
+func (d SnapshotDiff) FadePerYear() float64 {
+	if d.Elapsed <= 0 {
+		return 0
+	}
+	return d.Fade / d.Elapsed.Hours() * 24 * 365
+}
+
+// FadePerCycle returns the health lost per cycle in percentage points, 0
+// when no cycles are known
+func (d SnapshotDiff) FadePerCycle() float64 {
+	if !d.HasCycles || d.Cycles <= 0 {
+		return 0
+	}
+	return d.Fade / float64(d.Cycles)
+}
+
+// DiffSnapshots compares the batteries found in both snapshots, matched by
+// serial number when both have one and by index otherwise
+func DiffSnapshots(old, new Snapshot) []SnapshotDiff {
+	var diffs []SnapshotDiff
+	for _, now := range new.Batteries {
+		then, ok := matchBattery(old.Batteries, now)
+		if !ok {
+			continue
+		}
+
+		diff := SnapshotDiff{
+			Old:        then,
+			New:        now,
+			Elapsed:    new.Time.Sub(old.Time),
+			Fade:       then.Health() - now.Health(),
+			HasCycles:  then.HasCycles && now.HasCycles,
+			CyclesLeft: -1,
+		}
+		if diff.HasCycles {
+			diff.Cycles = now.Cycles - then.Cycles
+		}
+		remaining := now.Health() - HealthThreshold
+		if diff.Fade > 0 && diff.Elapsed > 0 && remaining > 0 {
+			years := remaining / diff.FadePerYear()
+			diff.EndOfLife = new.Time.Add(time.Duration(years * 365 * 24 * float64(time.Hour)))
+		}
+		if perCycle := diff.FadePerCycle(); perCycle > 0 && remaining > 0 {
+			diff.CyclesLeft = int(math.Round(remaining / perCycle))
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs
+}
+
+// matchBattery finds the battery of an earlier snapshot
+func matchBattery(batteries []BatterySnapshot, battery BatterySnapshot) (BatterySnapshot, bool) {
+	for _, candidate := range batteries {
+		if battery.Serial != "" && candidate.Serial != "" {
+			if candidate.Serial == battery.Serial {
+				return candidate, true
+			}
+			continue
+		}
+		if candidate.Index == battery.Index {
+			return candidate, true
+		}
+	}
+	return BatterySnapshot{}, false
+}
@@ -0,0 +1,88 @@
+package stats
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/battery"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	then := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	old := Snapshot{Time: then, Batteries: []BatterySnapshot{
+		{Index: 0, Serial: "A", Full: 50000, Design: 50000, Cycles: 100, HasCycles: true},
+		{Index: 1, Serial: "B", Full: 40000, Design: 50000},
+	}}
+
+	tests := []struct {
+		name      string
+		battery   BatterySnapshot
+		fade      float64
+		cycles    int
+		endOfLife time.Time
+		left      int
+	}{
+		{
+			name:    "fading",
+			battery: BatterySnapshot{Index: 1, Serial: "A", Full: 45000, Design: 50000, Cycles: 300, HasCycles: true},
+			fade:    10, cycles: 200,
+			// 10 points in a year leave 10 points: another year, or 200 cycles
+			endOfLife: then.AddDate(2, 0, 0), left: 200,
+		},
+		{
+			name:    "without cycles",
+			battery: BatterySnapshot{Index: 0, Serial: "B", Full: 39000, Design: 50000},
+			fade:    2, left: -1,
+		},
+		{
+			name:    "recalibrated",
+			battery: BatterySnapshot{Index: 0, Serial: "A", Full: 51000, Design: 50000, Cycles: 110, HasCycles: true},
+			fade:    -2, cycles: 10, left: -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diffs := DiffSnapshots(old, Snapshot{Time: then.AddDate(1, 0, 0), Batteries: []BatterySnapshot{tt.battery}})
+			if len(diffs) != 1 {
+				t.Fatalf("got %d diffs, want 1", len(diffs))
+			}
+			diff := diffs[0]
+			if diff.Old.Serial != tt.battery.Serial {
+				t.Errorf("matched battery %q, want %q", diff.Old.Serial, tt.battery.Serial)
+			}
+			if diff.Fade != tt.fade || diff.Cycles != tt.cycles || diff.CyclesLeft != tt.left {
+				t.Errorf("fade %v, cycles %d, cycles left %d; want %v, %d, %d", diff.Fade, diff.Cycles, diff.CyclesLeft, tt.fade, tt.cycles, tt.left)
+			}
+			if got := diff.EndOfLife.Truncate(24 * time.Hour); !got.Equal(tt.endOfLife) {
+				t.Errorf("end of life %s, want %s", got, tt.endOfLife)
+			}
+		})
+	}
+
+	// A battery that wasn't in the old snapshot is skipped
+	if diffs := DiffSnapshots(old, Snapshot{Batteries: []BatterySnapshot{{Serial: "C"}}}); len(diffs) != 0 {
+		t.Errorf("compared a new battery: %+v", diffs)
+	}
+}
+
+func TestSnapshotSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	batteries := []*battery.Info{
+		{Index: 0, State: battery.StateFull, Serial: "A", Full: 50000, Design: 57000, CycleCount: 42, Capabilities: battery.Capabilities{HasCycles: true}},
+		{Index: 1, State: battery.StateNotPresent},
+	}
+	snapshot := NewSnapshot(batteries, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err := snapshot.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadSnapshot(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.Time.Equal(snapshot.Time) || len(loaded.Batteries) != 1 || loaded.Batteries[0] != snapshot.Batteries[0] {
+		t.Errorf("loaded %+v, want %+v", loaded, snapshot)
+	}
+}
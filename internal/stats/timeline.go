@@ -0,0 +1,245 @@
+package stats
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/battery"
+	"github.com/xsikor/go-battop/internal/store"
+)
+
+// TimelineCollection is the store collection holding daily power timelines
+const TimelineCollection = "timeline"
+
+// MinutesPerDay is the number of timeline slots per day
+const MinutesPerDay = 24 * 60
+
+// MaxHistoryGap is the longest time a recorded state is assumed to last when
+// backfilling the timeline from a source's history
+const MaxHistoryGap = 15 * time.Minute
+
+// PowerState is the system power state during one timeline minute
+type PowerState byte
+
+// Timeline power states, stored as one character per minute
+const (
+	// PowerNone marks minutes without data (battop wasn't running)
+	PowerNone PowerState = '.'
+	// PowerCharging marks minutes with a charging battery
+	PowerCharging PowerState = 'C'
+	// PowerDischarging marks minutes on battery power
+	PowerDischarging PowerState = 'D'
+	// PowerACIdle marks minutes on AC power without charging
+	PowerACIdle PowerState = 'A'
+	// PowerSleep marks minutes the system was suspended
+	PowerSleep PowerState = 'S'
+)
+
+// String returns a human-readable name of the power state
+func (s PowerState) String() string {
+	switch s {
+	case PowerCharging:
+		return "Charging"
+	case PowerDischarging:
+		return "Discharging"
+	case PowerACIdle:
+		return "AC idle"
+	case PowerSleep:
+		return "Sleep"
+	default:
+		return "No data"
+	}
+}
+
+// TimelineDay holds the power state of every minute of one day
+type TimelineDay struct {
+	Date    string `json:"date"`
+	Minutes string `json:"minutes"`
+}
+
+// newTimelineDay creates a day without data
+func newTimelineDay(date string) *TimelineDay {
+	return &TimelineDay{
+		Date:    date,
+		Minutes: strings.Repeat(string(PowerNone), MinutesPerDay),
+	}
+}
+
+// At returns the power state of a minute of the day
+func (d *TimelineDay) At(minute int) PowerState {
+	if minute < 0 || minute >= len(d.Minutes) {
+		return PowerNone
+	}
+	return PowerState(d.Minutes[minute])
+}
+
+// Timeline records the power state per minute and persists one record per day
+type Timeline struct {
+	mu         sync.Mutex
+	store      *store.Store
+	day        *TimelineDay
+	minutes    []byte
+	lastSample time.Time
+	lastSaved  int
+}
+
+// NewTimeline creates a timeline recording into the given store
+func NewTimeline(st *store.Store) *Timeline {
+	return &Timeline{store: st, lastSaved: -1}
+}
+
+// classifyPowerState derives the timeline state from the battery readings
+func classifyPowerState(batteries []*battery.Info, source battery.PowerSource) PowerState {
+	if !source.OnAC {
+		return PowerDischarging
+	}
+	for _, info := range batteries {
+		if info.State.Base() == battery.StateCharging {
+			return PowerCharging
+		}
+	}
+	return PowerACIdle
+}
+
+// Add records the current power state. Gaps longer than MaxSampleGap while
+// battop was running mean the system was suspended and are marked as sleep.
+func (t *Timeline) Add(batteries []*battery.Info, source battery.PowerSource, now time.Time) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	date := now.Format(healthDateFormat)
+	var previous error
+	if t.day == nil || t.day.Date != date {
+		previous = t.switchDay(date)
+	}
+
+	minute := minuteOfDay(now)
+	if !t.lastSample.IsZero() && now.Sub(t.lastSample) > MaxSampleGap {
+		start := 0
+		if t.lastSample.Format(healthDateFormat) == date {
+			start = minuteOfDay(t.lastSample) + 1
+		}
+		for m := start; m < minute; m++ {
+			t.minutes[m] = byte(PowerSleep)
+		}
+	}
+	t.minutes[minute] = byte(classifyPowerState(batteries, source))
+	t.lastSample = now
+
+	if minute == t.lastSaved {
+		return previous
+	}
+	t.lastSaved = minute
+	if err := t.save(); err != nil {
+		return err
+	}
+	return previous
+}
+
+// Backfill fills the minutes of today without data from the states a source
+// recorded before battop started. Each state lasts until the next record,
+// at most MaxHistoryGap; minutes recorded by battop are kept.
+func (t *Timeline) Backfill(records []battery.StateRecord, now time.Time) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	date := now.Format(healthDateFormat)
+	if t.day == nil || t.day.Date != date {
+		if err := t.switchDay(date); err != nil {
+			return err
+		}
+	}
+
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	filled := false
+	for i, record := range records {
+		state := historyPowerState(record.State)
+		if state == PowerNone {
+			continue
+		}
+		end := record.Time.Add(MaxHistoryGap)
+		if i+1 < len(records) && records[i+1].Time.Before(end) {
+			end = records[i+1].Time
+		}
+		if end.After(now) {
+			end = now
+		}
+		for at := record.Time; at.Before(end); at = at.Add(time.Minute) {
+			if at.Before(midnight) {
+				continue
+			}
+			minute := minuteOfDay(at)
+			if PowerState(t.minutes[minute]) == PowerNone {
+				t.minutes[minute] = byte(state)
+				filled = true
+			}
+		}
+	}
+
+	if !filled {
+		return nil
+	}
+	return t.save()
+}
+
+// historyPowerState maps a recorded battery state to a timeline state
+func historyPowerState(state battery.State) PowerState {
+	switch state.Base() {
+	case battery.StateCharging:
+		return PowerCharging
+	case battery.StateDischarging:
+		return PowerDischarging
+	case battery.StateFull, battery.StateNotCharging:
+		return PowerACIdle
+	default:
+		return PowerNone
+	}
+}
+
+// switchDay saves the current day and loads or creates the given one
+func (t *Timeline) switchDay(date string) error {
+	var err error
+	if t.day != nil {
+		err = t.save()
+	}
+
+	day := newTimelineDay(date)
+	if loadErr := t.store.Load(TimelineCollection, date, day); loadErr != nil || len(day.Minutes) != MinutesPerDay {
+		day = newTimelineDay(date)
+	}
+	t.day = day
+	t.minutes = []byte(day.Minutes)
+	t.lastSaved = -1
+	return err
+}
+
+// save persists the current day
+func (t *Timeline) save() error {
+	t.day.Minutes = string(t.minutes)
+	return t.store.Save(TimelineCollection, t.day.Date, t.day)
+}
+
+// Day returns the timeline of the given date, including unsaved minutes of the current day
+func (t *Timeline) Day(date time.Time) TimelineDay {
+	name := date.Format(healthDateFormat)
+
+	t.mu.Lock()
+	if t.day != nil && t.day.Date == name {
+		day := TimelineDay{Date: name, Minutes: string(t.minutes)}
+		t.mu.Unlock()
+		return day
+	}
+	t.mu.Unlock()
+
+	day := newTimelineDay(name)
+	if err := t.store.Load(TimelineCollection, name, day); err != nil || len(day.Minutes) != MinutesPerDay {
+		return *newTimelineDay(name)
+	}
+	return *day
+}
+
+// minuteOfDay returns the minute index of a time within its day
+func minuteOfDay(t time.Time) int {
+	return t.Hour()*60 + t.Minute()
+}
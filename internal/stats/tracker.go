@@ -0,0 +1,223 @@
+package stats
+
+import (
+	"sync"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/battery"
+)
+
+// MaxSampleGap is the longest interval between two samples that is still
+// integrated; longer gaps (e.g., suspend) are skipped
+const MaxSampleGap = time.Minute
+
+// MinActiveDuration is how much active drain the session needs before its
+// average replaces the baseline in active use estimates
+const MinActiveDuration = 5 * time.Minute
+
+// ConfidentActiveDuration is how much active drain makes active use estimates high confidence
+const ConfidentActiveDuration = 30 * time.Minute
+
+// Drain accumulates energy drawn from the batteries over time
+type Drain struct {
+	// Energy drawn in mWh
+	Energy float64
+
+	// Duration covered by the samples
+	Duration time.Duration
+}
+
+// AverageRate returns the average discharge power in mW
+func (d Drain) AverageRate() float64 {
+	if d.Duration <= 0 {
+		return 0
+	}
+	return d.Energy / d.Duration.Hours()
+}
+
+// Session accumulates the energy flow since battop started
+type Session struct {
+	// Drawn is the energy taken from the batteries in mWh
+	Drawn float64
+
+	// Charged is the energy stored into the batteries in mWh
+	Charged float64
+
+	// Duration covered by the samples
+	Duration time.Duration
+}
+
+// Net returns the net energy change in mWh (negative when the batteries drained)
+func (s Session) Net() float64 {
+	return s.Charged - s.Drawn
+}
+
+// AverageRate returns the average net power in mW (negative when draining)
+func (s Session) AverageRate() float64 {
+	if s.Duration <= 0 {
+		return 0
+	}
+	return s.Net() / s.Duration.Hours()
+}
+
+// Summary is a snapshot of the accumulated statistics
+type Summary struct {
+	// Session is the energy flow since battop started
+	Session Session
+
+	// Active is the drain while the user session was active
+	Active Drain
+
+	// Idle is the drain while the user session was idle or locked
+	Idle Drain
+
+	// Baseline is the active drain of a recorded discharge run, used until
+	// the session has collected enough active samples
+	Baseline Drain
+
+	// Discharge is the time spent at each discharge power
+	Discharge Histogram
+}
+
+// ActiveRate returns the average discharge power in mW while the session is
+// active, preferring the current session over the baseline
+func (s Summary) ActiveRate() float64 {
+	if s.Active.Duration >= MinActiveDuration || s.Baseline.Duration <= 0 {
+		return s.Active.AverageRate()
+	}
+	return s.Baseline.AverageRate()
+}
+
+// ActiveConfidence rates the active use estimates from the depth of the drain
+// history behind ActiveRate
+func (s Summary) ActiveConfidence() battery.Confidence {
+	switch {
+	case s.Active.Duration >= ConfidentActiveDuration:
+		return battery.ConfidenceHigh
+	case s.Active.Duration >= MinActiveDuration || s.Baseline.Duration > 0:
+		return battery.ConfidenceMedium
+	default:
+		return battery.ConfidenceLow
+	}
+}
+
+// ActiveUseRemaining returns how long the remaining energy in mWh lasts at
+// the active discharge rate, or 0 when no active drain is known
+func (s Summary) ActiveUseRemaining(energy float64) time.Duration {
+	return RemainingAt(energy, s.ActiveRate())
+}
+
+// DischargeRate returns the average discharge power in mW of the session,
+// active and idle
+func (s Summary) DischargeRate() float64 {
+	return Drain{
+		Energy:   s.Active.Energy + s.Idle.Energy,
+		Duration: s.Active.Duration + s.Idle.Duration,
+	}.AverageRate()
+}
+
+// RemainingAt returns how long energy in mWh lasts at a discharge power in
+// mW, or 0 when either is unknown
+func RemainingAt(energy, rate float64) time.Duration {
+	if rate <= 0 || energy <= 0 {
+		return 0
+	}
+	return time.Duration(energy / rate * float64(time.Hour))
+}
+
+// Tracker accumulates statistics from battery samples
+type Tracker struct {
+	mu         sync.Mutex
+	lastSample time.Time
+	session    Session
+	active     Drain
+	idle       Drain
+	baseline   Drain
+	discharge  Histogram
+}
+
+// NewTracker creates a new statistics tracker
+func NewTracker() *Tracker {
+	return &Tracker{}
+}
+
+// SetBaseline sets the active drain used before the session has enough active samples
+func (t *Tracker) SetBaseline(baseline Drain) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.baseline = baseline
+}
+
+// Add integrates a set of battery samples taken at the same time
+func (t *Tracker) Add(batteries []*battery.Info) {
+	if len(batteries) == 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	sampledAt := batteries[0].UpdatedAt
+	elapsed := sampledAt.Sub(t.lastSample)
+	first := t.lastSample.IsZero()
+	t.lastSample = sampledAt
+
+	if first || elapsed <= 0 || elapsed > MaxSampleGap {
+		return
+	}
+
+	hours := elapsed.Hours()
+	charged, drawn := chargePower(batteries), dischargePower(batteries)
+	t.session.Charged += charged * hours
+	t.session.Drawn += drawn * hours
+	t.session.Duration += elapsed
+
+	if drawn <= 0 {
+		return
+	}
+	t.discharge.add(drawn, elapsed)
+
+	bucket := &t.active
+	if batteries[0].Idle {
+		bucket = &t.idle
+	}
+	bucket.Energy += drawn * hours
+	bucket.Duration += elapsed
+}
+
+// Summary returns a snapshot of the accumulated statistics
+func (t *Tracker) Summary() Summary {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return Summary{
+		Session:   t.session,
+		Active:    t.active,
+		Idle:      t.idle,
+		Baseline:  t.baseline,
+		Discharge: t.discharge.clone(),
+	}
+}
+
+// dischargePower returns the combined discharge power of all batteries in mW
+func dischargePower(batteries []*battery.Info) float64 {
+	total := 0.0
+	for _, info := range batteries {
+		if info.ChargeRate < 0 {
+			total += -info.ChargeRate
+		}
+	}
+	return total
+}
+
+// chargePower returns the combined charge power of all batteries in mW
+func chargePower(batteries []*battery.Info) float64 {
+	total := 0.0
+	for _, info := range batteries {
+		if info.ChargeRate > 0 {
+			total += info.ChargeRate
+		}
+	}
+	return total
+}
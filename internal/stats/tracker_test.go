@@ -0,0 +1,195 @@
+package stats
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/battery"
+)
+
+func TestTrackerAdd(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	type sample struct {
+		after time.Duration
+		rates []float64
+		idle  bool
+	}
+	tests := []struct {
+		name    string
+		samples []sample
+		want    Summary
+	}{
+		{
+			name:    "first sample only sets the start",
+			samples: []sample{{0, []float64{-10000}, false}},
+			want:    Summary{},
+		},
+		{
+			name: "active drain",
+			samples: []sample{
+				{0, []float64{-10000}, false},
+				{30 * time.Second, []float64{-12000}, false},
+				{time.Minute, []float64{-12000}, false},
+			},
+			want: Summary{
+				Session: Session{Drawn: 200, Duration: time.Minute},
+				Active:  Drain{Energy: 200, Duration: time.Minute},
+			},
+		},
+		{
+			name: "idle drain",
+			samples: []sample{
+				{0, []float64{-10000}, false},
+				{36 * time.Second, []float64{-4000}, true},
+				{72 * time.Second, []float64{-10000}, false},
+			},
+			want: Summary{
+				Session: Session{Drawn: 140, Duration: 72 * time.Second},
+				Active:  Drain{Energy: 100, Duration: 36 * time.Second},
+				Idle:    Drain{Energy: 40, Duration: 36 * time.Second},
+			},
+		},
+		{
+			name: "charging is not drain",
+			samples: []sample{
+				{0, []float64{20000}, false},
+				{36 * time.Second, []float64{20000}, false},
+			},
+			want: Summary{Session: Session{Charged: 200, Duration: 36 * time.Second}},
+		},
+		{
+			name: "two batteries",
+			samples: []sample{
+				{0, []float64{-6000, 3000}, false},
+				{36 * time.Second, []float64{-6000, 3000}, false},
+			},
+			want: Summary{
+				Session: Session{Drawn: 60, Charged: 30, Duration: 36 * time.Second},
+				Active:  Drain{Energy: 60, Duration: 36 * time.Second},
+			},
+		},
+		{
+			name: "gap longer than MaxSampleGap",
+			samples: []sample{
+				{0, []float64{-10000}, false},
+				{MaxSampleGap + time.Second, []float64{-10000}, false},
+				{MaxSampleGap + 37*time.Second, []float64{-10000}, false},
+			},
+			want: Summary{
+				Session: Session{Drawn: 100, Duration: 36 * time.Second},
+				Active:  Drain{Energy: 100, Duration: 36 * time.Second},
+			},
+		},
+		{
+			name: "gap of exactly MaxSampleGap",
+			samples: []sample{
+				{0, []float64{-6000}, false},
+				{MaxSampleGap, []float64{-6000}, false},
+			},
+			want: Summary{
+				Session: Session{Drawn: 100, Duration: MaxSampleGap},
+				Active:  Drain{Energy: 100, Duration: MaxSampleGap},
+			},
+		},
+		{
+			name: "repeated and backwards timestamps",
+			samples: []sample{
+				{time.Minute, []float64{-10000}, false},
+				{time.Minute, []float64{-10000}, false},
+				{0, []float64{-10000}, false},
+				{36 * time.Second, []float64{-10000}, false},
+			},
+			want: Summary{
+				Session: Session{Drawn: 100, Duration: 36 * time.Second},
+				Active:  Drain{Energy: 100, Duration: 36 * time.Second},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := NewTracker()
+			for _, s := range tt.samples {
+				batteries := make([]*battery.Info, len(s.rates))
+				for i, rate := range s.rates {
+					batteries[i] = &battery.Info{Index: i, ChargeRate: rate, Idle: s.idle, UpdatedAt: start.Add(s.after)}
+				}
+				tracker.Add(batteries)
+			}
+
+			got := tracker.Summary()
+			if !sessionEqual(got.Session, tt.want.Session) {
+				t.Errorf("session %+v, want %+v", got.Session, tt.want.Session)
+			}
+			if !drainEqual(got.Active, tt.want.Active) {
+				t.Errorf("active %+v, want %+v", got.Active, tt.want.Active)
+			}
+			if !drainEqual(got.Idle, tt.want.Idle) {
+				t.Errorf("idle %+v, want %+v", got.Idle, tt.want.Idle)
+			}
+		})
+	}
+}
+
+func TestTrackerAddEmpty(t *testing.T) {
+	tracker := NewTracker()
+	tracker.Add(nil)
+	if got := tracker.Summary(); got.Session != (Session{}) {
+		t.Errorf("session %+v after no batteries", got.Session)
+	}
+}
+
+func TestSessionEnergy(t *testing.T) {
+	tests := []struct {
+		name    string
+		session Session
+		net     float64
+		rate    float64
+	}{
+		{"empty", Session{}, 0, 0},
+		{"draining", Session{Drawn: 3000, Charged: 500, Duration: 30 * time.Minute}, -2500, -5000},
+		{"charging", Session{Drawn: 1000, Charged: 16000, Duration: time.Hour}, 15000, 15000},
+		{"balanced", Session{Drawn: 2000, Charged: 2000, Duration: time.Hour}, 0, 0},
+		{"no duration", Session{Drawn: 100}, -100, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.session.Net(); math.Abs(got-tt.net) > 1e-9 {
+				t.Errorf("net %.1f mWh, want %.1f mWh", got, tt.net)
+			}
+			if got := tt.session.AverageRate(); math.Abs(got-tt.rate) > 1e-9 {
+				t.Errorf("average %.1f mW, want %.1f mW", got, tt.rate)
+			}
+		})
+	}
+}
+
+func TestRemainingAt(t *testing.T) {
+	tests := []struct {
+		energy, rate float64
+		want         time.Duration
+	}{
+		{40000, 10000, 4 * time.Hour},
+		{5000, 20000, 15 * time.Minute},
+		{0, 10000, 0},
+		{40000, 0, 0},
+		{40000, -10000, 0},
+	}
+	for _, tt := range tests {
+		if got := RemainingAt(tt.energy, tt.rate); got != tt.want {
+			t.Errorf("RemainingAt(%.0f, %.0f) = %v, want %v", tt.energy, tt.rate, got, tt.want)
+		}
+	}
+}
+
+// sessionEqual compares sessions with a tolerance for the energy integration
+func sessionEqual(a, b Session) bool {
+	return math.Abs(a.Drawn-b.Drawn) < 1e-6 && math.Abs(a.Charged-b.Charged) < 1e-6 && a.Duration == b.Duration
+}
+
+// drainEqual compares drains with a tolerance for the energy integration
+func drainEqual(a, b Drain) bool {
+	return math.Abs(a.Energy-b.Energy) < 1e-6 && a.Duration == b.Duration
+}
@@ -0,0 +1,109 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Store persists battop data as JSON files in a data directory
+type Store struct {
+	dir string
+}
+
+// New creates a store rooted at the given directory
+func New(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// DefaultDir returns the default data directory ($XDG_DATA_HOME/battop or ~/.local/share/battop)
+func DefaultDir() string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "battop")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "battop")
+	}
+	return filepath.Join(home, ".local", "share", "battop")
+}
+
+// Dir returns the data directory
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Save writes v as JSON to the named entry in the collection, replacing it atomically
+func (s *Store) Save(collection, name string, v interface{}) error {
+	path, err := s.path(collection, name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s/%s: %w", collection, name, err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s/%s: %w", collection, name, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write %s/%s: %w", collection, name, err)
+	}
+	return nil
+}
+
+// Load reads the named entry of the collection into v
+func (s *Store) Load(collection, name string, v interface{}) error {
+	path, err := s.path(collection, name)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s/%s: %w", collection, name, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to decode %s/%s: %w", collection, name, err)
+	}
+	return nil
+}
+
+// List returns the entry names of the collection in sorted order
+func (s *Store) List(collection string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(s.dir, collection))
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", collection, err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), ".json"); ok && !entry.IsDir() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// path returns the file path of a collection entry, rejecting names that
+// would escape the collection directory
+func (s *Store) path(collection, name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return "", fmt.Errorf("invalid name %q", name)
+	}
+	return filepath.Join(s.dir, collection, name+".json"), nil
+}
@@ -0,0 +1,240 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/xsikor/go-battop/internal/battery"
+	"github.com/xsikor/go-battop/internal/format"
+)
+
+// BreakdownChartHeight is the number of rows of the stacked power chart
+const BreakdownChartHeight = 12
+
+// breakdownStyle is the color and fallback symbol of a power segment
+type breakdownStyle struct {
+	color  string
+	symbol rune
+}
+
+// breakdownStyles maps the power segments to their chart style
+var breakdownStyles = map[string]breakdownStyle{
+	battery.SegmentCores:   {"aqua", 'c'},
+	battery.SegmentGPU:     {"fuchsia", 'g'},
+	battery.SegmentPackage: {"yellow", 'p'},
+	battery.SegmentDRAM:    {"green", 'd'},
+	battery.SegmentRest:    {"gray", 'r'},
+}
+
+// breakdownSample is the power breakdown at one tick
+type breakdownSample struct {
+	total    float64
+	segments []battery.PowerSegment
+}
+
+// BreakdownView shows what the battery discharge is spent on, split into the
+// RAPL domains (CPU cores, GPU, package, DRAM) and the rest of the system
+type BreakdownView struct {
+	root    *tview.TextView
+	read    func(time.Time) ([]battery.PowerDomain, error)
+	theme   *Theme
+	format  format.Formatter
+	samples []breakdownSample
+	domains []battery.PowerDomain
+	err     error
+}
+
+// NewBreakdownView creates a new power breakdown view reading domains with the given function
+func NewBreakdownView(read func(time.Time) ([]battery.PowerDomain, error), theme *Theme, formatter format.Formatter) *BreakdownView {
+	v := &BreakdownView{
+		root:   tview.NewTextView(),
+		read:   read,
+		theme:  theme,
+		format: formatter,
+	}
+	v.root.SetDynamicColors(true).SetBackgroundColor(tcell.ColorDefault)
+	return v
+}
+
+// SetTheme sets the theme used for the next update
+func (v *BreakdownView) SetTheme(theme *Theme) {
+	v.theme = theme
+}
+
+// SetFormatter sets the formatter used for the next update
+func (v *BreakdownView) SetFormatter(formatter format.Formatter) {
+	v.format = formatter
+}
+
+// GetRoot returns the root UI element
+func (v *BreakdownView) GetRoot() tview.Primitive {
+	return v.root
+}
+
+// Sample reads the RAPL domains and records the breakdown against the
+// battery discharge. It runs on every tick, also while the page is hidden.
+func (v *BreakdownView) Sample(batteries []*battery.Info, now time.Time) {
+	domains, err := v.read(now)
+	v.err = err
+	if err != nil {
+		slog.Debug("Failed to read RAPL domains", "error", err)
+		return
+	}
+	if len(domains) == 0 {
+		return
+	}
+
+	total := 0.0
+	for _, info := range batteries {
+		if info.ChargeRate < 0 {
+			total += -info.ChargeRate
+		}
+	}
+
+	v.domains = domains
+	v.samples = append(v.samples, breakdownSample{total: total, segments: battery.BreakdownPower(domains, total)})
+	if len(v.samples) > MaxChartDataPoints {
+		v.samples = v.samples[len(v.samples)-MaxChartDataPoints:]
+	}
+}
+
+// CPUPower returns the power of the CPU packages (cores, GPU and the rest of
+// the package) at the latest sample, or false before the first sample
+func (v *BreakdownView) CPUPower() (float64, bool) {
+	if v.err != nil || len(v.samples) == 0 {
+		return 0, false
+	}
+	power := 0.0
+	for _, segment := range v.samples[len(v.samples)-1].segments {
+		switch segment.Name {
+		case battery.SegmentCores, battery.SegmentGPU, battery.SegmentPackage:
+			power += segment.Power
+		}
+	}
+	return power, true
+}
+
+// Update redraws the latest breakdown and the stacked chart
+func (v *BreakdownView) Update() {
+	var text strings.Builder
+	text.WriteString("[white::b]Power breakdown[-::-]\n\n")
+
+	switch {
+	case v.err != nil:
+		fmt.Fprintf(&text, "[gray]Failed to read RAPL counters: %v[-]\n", v.err)
+	case len(v.samples) == 0:
+		text.WriteString("[gray]Measuring...[-]\n")
+	default:
+		v.writeLatest(&text)
+		text.WriteString("\n")
+		v.writeChart(&text)
+	}
+
+	v.root.SetText(text.String())
+	slog.Debug("Updated power breakdown view", "samples", len(v.samples))
+}
+
+// writeLatest writes the latest battery discharge and the power of every segment
+func (v *BreakdownView) writeLatest(text *strings.Builder) {
+	latest := v.samples[len(v.samples)-1]
+	if latest.total > 0 {
+		fmt.Fprintf(text, "[cyan]Battery discharge:[-] %s\n\n", v.format.Power(latest.total))
+	} else {
+		fmt.Fprintf(text, "[cyan]Battery discharge:[-] [gray]%s (not discharging, rest of system unknown)[-]\n\n", Unavailable)
+	}
+
+	stacked := 0.0
+	for _, segment := range latest.segments {
+		stacked += segment.Power
+	}
+	for _, segment := range latest.segments {
+		if segment.Name == battery.SegmentRest && latest.total <= 0 {
+			continue
+		}
+		share := 0.0
+		if stacked > 0 {
+			share = segment.Power / stacked * 100
+		}
+		bar := CreateProgressBar(share, ProgressBarWidth, ProgressBarStyleASCII)
+		fmt.Fprintf(text, "%s %-16s [%s]%s[-] %9s  [gray]%s[-]\n", v.cell(segment.Name), segment.Name,
+			breakdownStyles[segment.Name].color, bar, v.format.Power(segment.Power), v.format.Percent(share))
+	}
+
+	for _, domain := range v.domains {
+		if domain.Name == "psys" {
+			fmt.Fprintf(text, "\n[gray]Platform (psys): %s[-]\n", v.format.Power(domain.Power))
+		}
+	}
+}
+
+// writeChart writes the stacked chart of the recorded samples, newest on the right
+func (v *BreakdownView) writeChart(text *strings.Builder) {
+	width := DefaultChartWidth
+	if _, _, w, _ := v.root.GetInnerRect(); w > 0 {
+		width = w
+	}
+	columns := min(len(v.samples), max(width-2, 1))
+	samples := v.samples[len(v.samples)-columns:]
+
+	peak := 0.0
+	for _, sample := range samples {
+		sum := 0.0
+		for _, segment := range sample.segments {
+			sum += segment.Power
+		}
+		peak = max(peak, sum)
+	}
+	if peak <= 0 {
+		text.WriteString("[gray]No power drawn[-]\n")
+		return
+	}
+
+	fmt.Fprintf(text, "[gray]%s[-]\n", v.format.Power(peak))
+	for row := BreakdownChartHeight; row > 0; row-- {
+		level := (float64(row) - 0.5) / BreakdownChartHeight * peak
+		text.WriteString("[gray]│[-]")
+		for _, sample := range samples {
+			text.WriteString(v.stackCell(sample, level))
+		}
+		text.WriteString("\n")
+	}
+	fmt.Fprintf(text, "[gray]└%s[-]\n", strings.Repeat("─", columns))
+	v.writeLegend(text)
+}
+
+// stackCell returns the cell of a sample at the given power level
+func (v *BreakdownView) stackCell(sample breakdownSample, level float64) string {
+	cumulative := 0.0
+	for _, segment := range sample.segments {
+		cumulative += segment.Power
+		if level < cumulative {
+			return v.cell(segment.Name)
+		}
+	}
+	return " "
+}
+
+// writeLegend writes the segment legend
+func (v *BreakdownView) writeLegend(text *strings.Builder) {
+	items := make([]string, 0, len(breakdownStyles))
+	for _, segment := range v.samples[len(v.samples)-1].segments {
+		items = append(items, v.cell(segment.Name)+" "+segment.Name)
+	}
+	text.WriteString(strings.Join(items, "  "))
+	text.WriteString("\n")
+}
+
+// cell renders a single chart cell of a segment, with a symbol instead of a
+// block when the theme uses symbols so segments don't rely on color alone
+func (v *BreakdownView) cell(segment string) string {
+	style := breakdownStyles[segment]
+	char := "█"
+	if v.theme.Symbols {
+		char = string(style.symbol)
+	}
+	return fmt.Sprintf("[%s]%s[-]", style.color, char)
+}
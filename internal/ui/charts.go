@@ -6,30 +6,78 @@ import (
 	"math"
 	"strings"
 	"time"
+
+	"github.com/rivo/tview"
+	"github.com/xsikor/go-battop/pkg/plot"
 )
 
-// Chart represents a time-series chart
+// Chart is a titled time-series chart with time labels, plotted by plot.Chart
 type Chart struct {
-	title     string
-	data      *ChartData
-	width     int
-	height    int
-	minValue  float64
-	maxValue  float64
-	autoScale bool
-	unit      string
-	color     string
+	title      string
+	data       *plot.Data
+	capacity   int
+	plot       *plot.Chart
+	viewport   plot.Viewport
+	width      int
+	height     int
+	unit       string
+	color      string
+	timeFormat string
+	hidden     bool
+	collapsed  bool
+	zoom       int
+	tiers      []plot.Tier
+
+	// gapThreshold is the interruption of the readings shown as a gap, 0 for none
+	gapThreshold time.Duration
+
+	// cursor is the time of the inspected point while inspecting; the zero
+	// time inspects the newest point
+	inspecting bool
+	cursor     time.Time
+
+	// reference returns the value of a dimmed reference line at a time,
+	// drawn as the referenceLine series
+	reference     func(time.Time) (float64, bool)
+	referenceLine *plot.Series
 }
 
 // NewChart creates a new chart
 func NewChart(title string, maxDataPoints int, unit string, color string) *Chart {
-	return &Chart{
-		title:     title,
-		data:      NewChartData(maxDataPoints),
-		autoScale: true,
-		unit:      unit,
-		color:     color,
+	c := &Chart{
+		title:      title,
+		capacity:   maxDataPoints,
+		plot:       plot.NewChart(),
+		unit:       unit,
+		color:      color,
+		timeFormat: TimeFormat,
+	}
+	c.data = c.newData()
+	c.plot.AddSeries(title, c.data, plot.Style{Color: color})
+	c.plot.SetLabelFormat(c.formatValue)
+	return c
+}
+
+// newData creates the data of a series with the chart's tiers
+func (c *Chart) newData() *plot.Data {
+	data := plot.NewTieredData(c.capacity, c.tiers...)
+	data.SetGapThreshold(c.gapThreshold)
+	return data
+}
+
+// ShowSuspends breaks the series where the readings stopped for longer than
+// SuspendGapThreshold, e.g. during a suspend, and labels the gaps with their
+// length
+func (c *Chart) ShowSuspends() {
+	c.gapThreshold = SuspendGapThreshold
+	for _, series := range c.plot.Series() {
+		if series != c.referenceLine {
+			series.Data.SetGapThreshold(c.gapThreshold)
+		}
 	}
+	c.plot.SetGapLabel(func(gap time.Duration) string {
+		return "suspended " + formatChartDuration(gap)
+	})
 }
 
 // SetSize sets the chart dimensions
@@ -40,306 +88,379 @@ func (c *Chart) SetSize(width, height int) {
 
 // SetScale sets manual scale for the chart
 func (c *Chart) SetScale(min, max float64) {
-	c.minValue = min
-	c.maxValue = max
-	c.autoScale = false
-}
-
-// AddValue adds a new value to the chart
-func (c *Chart) AddValue(value float64) {
-	c.data.Add(value)
+	c.viewport.Min = min
+	c.viewport.Max = max
 }
 
-// Render renders the chart as a string
-func (c *Chart) Render() string {
-	slog.Debug("Chart.Render", "title", c.title, "width", c.width, "height", c.height, "dataPoints", len(c.data.values))
-
-	if c.width <= 0 || c.height <= 0 {
-		return " [gray]Initializing...[-]"
+// SetReference sets a function providing a named reference line drawn
+// dimmed behind the data, or nil to remove it
+func (c *Chart) SetReference(name string, reference func(time.Time) (float64, bool)) {
+	if c.referenceLine != nil {
+		c.plot.RemoveSeries(c.referenceLine.Name)
+		c.referenceLine = nil
 	}
-
-	if len(c.data.values) == 0 {
-		return c.renderEmptyChart()
+	c.reference = reference
+	if reference != nil {
+		c.referenceLine = c.plot.AddSeries(name, plot.NewData(0), plot.Style{Color: "gray", Dim: true})
+		c.referenceLine.Background = true
 	}
+}
 
-	var result strings.Builder
-	min, max := c.calculateBounds()
-
-	c.renderTitle(&result)
-	c.renderChartBody(&result, min, max)
-	c.renderXAxis(&result)
-	result.WriteString(c.createTimeLabels())
-
-	return result.String()
+// AddSeries adds a named series drawn over the chart's own series. The marker
+// tells it apart without colors; a legend row lists the series.
+func (c *Chart) AddSeries(name, color string, marker rune) {
+	series := c.plot.AddSeries(name, c.newData(), plot.Style{Color: color})
+	series.Marker = marker
 }
 
-// renderTitle renders the chart title with decorative borders
-func (c *Chart) renderTitle(result *strings.Builder) {
-	titleStr := c.prepareTitleString()
-	leftPad, rightPad := c.calculateTitlePadding(titleStr)
+// AddSeriesValue adds a value recorded now to the named series
+func (c *Chart) AddSeriesValue(name string, value float64) {
+	c.AddSeriesValueAt(name, time.Now(), value)
+}
 
-	if leftPad > 0 {
-		result.WriteString(strings.Repeat("─", leftPad))
-	}
-	result.WriteString(fmt.Sprintf("[%s:b]%s[-]", c.color, titleStr))
-	if rightPad > 0 {
-		result.WriteString(strings.Repeat("─", rightPad))
+// AddSeriesValueAt adds a value recorded at the given time to the named series
+func (c *Chart) AddSeriesValueAt(name string, at time.Time, value float64) {
+	for _, series := range c.plot.Series() {
+		if series.Name == name {
+			series.Data.AddAt(at, value)
+		}
 	}
-	result.WriteString("\n")
 }
 
-// prepareTitleString prepares the title string, truncating if necessary
-func (c *Chart) prepareTitleString() string {
-	titleStr := fmt.Sprintf(" %s ", c.title)
-	titleLen := len(titleStr)
-
-	if c.width < titleLen {
-		// Truncate title if too long
-		return c.title[:c.width-2] + " "
+// SetGradient colors the points of the chart's own series by value, or draws
+// them in the chart color when nil
+func (c *Chart) SetGradient(gradient func(value float64) string) {
+	for _, series := range c.plot.Series() {
+		if series.Data == c.data {
+			series.Gradient = gradient
+		}
 	}
-	return titleStr
 }
 
-// calculateTitlePadding calculates left and right padding for centered title
-func (c *Chart) calculateTitlePadding(titleStr string) (int, int) {
-	titleLen := len(titleStr)
-	totalPadding := c.width - titleLen
+// SetTiers keeps downsampled histories of every series for the zoom levels.
+// It clears the recorded values.
+func (c *Chart) SetTiers(tiers ...plot.Tier) {
+	c.tiers = tiers
+	for _, series := range c.plot.Series() {
+		if series == c.referenceLine {
+			continue
+		}
+		data := c.newData()
+		if series.Data == c.data {
+			c.data = data
+		}
+		series.Data = data
+	}
+}
 
-	if totalPadding <= 0 {
-		return 0, 0
+// adopt takes over the recorded values, zoom, inspection and reference line
+// of the chart it replaces, converting the values v to v*factor+offset for a
+// changed unit. Series missing from the old chart stay empty.
+func (c *Chart) adopt(old *Chart, factor, offset float64) {
+	for _, series := range c.plot.Series() {
+		for _, previous := range old.plot.Series() {
+			if previous == old.referenceLine || previous.Name != series.Name {
+				continue
+			}
+			previous.Data.Convert(factor, offset)
+			if series.Data == c.data {
+				c.data = previous.Data
+			}
+			series.Data = previous.Data
+		}
 	}
 
-	leftPad := totalPadding / 2
-	rightPad := totalPadding - leftPad
-	return leftPad, rightPad
+	c.width, c.height = old.width, old.height
+	c.collapsed = old.collapsed
+	c.inspecting, c.cursor = old.inspecting, old.cursor
+	c.viewport = old.viewport
+	c.SetZoom(old.zoom)
+	if old.referenceLine != nil {
+		c.SetReference(old.referenceLine.Name, old.reference)
+	}
 }
 
-// renderChartBody renders the Y-axis labels and chart content
-func (c *Chart) renderChartBody(result *strings.Builder, min, max float64) {
-	chartHeight := c.calculateChartHeight()
-	grid := c.createGrid(min, max, chartHeight)
+// SetZoom selects the plotted history: 0 for the raw values, n for the n-th tier
+func (c *Chart) SetZoom(level int) {
+	c.zoom = level
+	c.plot.SetTier(level)
+}
 
-	for i := 0; i < chartHeight; i++ {
-		yValue := c.calculateYValue(i, chartHeight, min, max)
-		label := c.formatValue(yValue)
+// AddThreshold adds a dashed, labeled horizontal line at a value
+func (c *Chart) AddThreshold(value float64, label string) {
+	c.plot.AddThreshold(plot.Threshold{Value: value, Label: label, Style: plot.Style{Color: c.color, Dim: true}})
+}
 
-		result.WriteString(fmt.Sprintf("[gray]%8s ┤[-] ", label))
-		result.WriteString(grid[i])
-		result.WriteString("\n")
-	}
+// SetThreshold moves the labeled threshold line to a value, adding it when
+// missing
+func (c *Chart) SetThreshold(value float64, label string) {
+	c.plot.RemoveThreshold(label)
+	c.AddThreshold(value, label)
 }
 
-// calculateChartHeight calculates the effective chart height
-func (c *Chart) calculateChartHeight() int {
-	chartHeight := c.height - ChartHeightReserve
-	if chartHeight < MinChartHeight {
-		return MinChartHeight
-	}
-	return chartHeight
+// RemoveThreshold removes the labeled threshold line
+func (c *Chart) RemoveThreshold(label string) {
+	c.plot.RemoveThreshold(label)
 }
 
-// calculateYValue calculates the Y-axis value for a given row
-func (c *Chart) calculateYValue(row, totalRows int, min, max float64) float64 {
-	if totalRows <= 1 {
-		return max
-	}
-	return max - (float64(row)/float64(totalRows-1))*(max-min)
+// SetAnnotations marks moments such as events on the chart
+func (c *Chart) SetAnnotations(annotations []plot.Annotation) {
+	c.plot.SetAnnotations(annotations)
 }
 
-// renderXAxis renders the X-axis decoration
-func (c *Chart) renderXAxis(result *strings.Builder) {
-	result.WriteString(fmt.Sprintf("[gray]%8s └", ""))
-	result.WriteString(strings.Repeat("─", c.width-YAxisLabelWidth))
-	result.WriteString("[-]\n")
+// SetInspecting shows or hides the inspection cursor, starting on the newest point
+func (c *Chart) SetInspecting(inspecting bool) {
+	c.inspecting = inspecting
+	c.cursor = time.Time{}
 }
 
-// calculateBounds calculates the min and max values for the chart
-func (c *Chart) calculateBounds() (float64, float64) {
-	if !c.autoScale {
-		return c.minValue, c.maxValue
+// MoveCursor moves the inspection cursor by steps points, within the visible points
+func (c *Chart) MoveCursor(steps int) {
+	points := c.data.TierPoints(c.zoom)
+	if len(points) == 0 {
+		return
 	}
+	oldest := max(len(points)-(c.width-plot.AxisWidth), 0)
+	index := min(max(c.cursorIndex(points)+steps, oldest), len(points)-1)
+	c.cursor = points[index].Time
+}
 
-	if len(c.data.values) == 0 {
-		return 0, 1
+// cursorIndex returns the index of the inspected point: the newest point not
+// after the cursor time
+func (c *Chart) cursorIndex(points []plot.Point) int {
+	if c.cursor.IsZero() {
+		return len(points) - 1
 	}
-
-	min, max := c.data.values[0], c.data.values[0]
-	for _, v := range c.data.values {
-		if v < min {
-			min = v
-		}
-		if v > max {
-			max = v
+	for i := len(points) - 1; i > 0; i-- {
+		if !points[i].Time.After(c.cursor) {
+			return i
 		}
 	}
+	return 0
+}
 
-	// Add some padding
-	range_ := max - min
-	if range_ < 0.001 {
-		// If values are too close, add artificial range
-		min = min - 0.5
-		max = max + 0.5
-		return min, max
-	}
+// SetAxisScale sets how values map to the value axis
+func (c *Chart) SetAxisScale(scale plot.Scale) {
+	c.plot.SetScale(scale)
+}
 
-	padding := range_ * 0.1
-	min = min - padding
-	max = max + padding
+// SetHidden hides or shows the chart within its chart set
+func (c *Chart) SetHidden(hidden bool) {
+	c.hidden = hidden
+}
 
-	return min, max
+// Hidden reports whether the chart is hidden
+func (c *Chart) Hidden() bool {
+	return c.hidden
 }
 
-// createGrid creates the chart grid with data points
-func (c *Chart) createGrid(min, max float64, height int) []string {
-	chartWidth := c.calculateEffectiveChartWidth()
-	grid := c.initializeEmptyGrid(height, chartWidth)
+// SetCollapsed collapses the chart to its title row or expands it
+func (c *Chart) SetCollapsed(collapsed bool) {
+	c.collapsed = collapsed
+}
 
-	if len(c.data.values) == 0 {
-		return grid
-	}
+// Collapsed reports whether the chart is collapsed to its title row
+func (c *Chart) Collapsed() bool {
+	return c.collapsed
+}
 
-	c.plotDataPoints(grid, min, max, height, chartWidth)
-	c.applyColorToGrid(grid)
+// SetTimeFormat sets the format of the x-axis time labels
+func (c *Chart) SetTimeFormat(format string) {
+	c.timeFormat = format
+}
 
-	return grid
+// SetWarmupUntil marks the samples up to the given time as warm-up samples,
+// whose bogus values are excluded from the scale and marked on the x-axis
+func (c *Chart) SetWarmupUntil(until time.Time) {
+	c.viewport.Settle = until
+}
+
+// AddValue adds a new value to the chart
+func (c *Chart) AddValue(value float64) {
+	c.data.Add(value)
 }
 
-// calculateEffectiveChartWidth calculates the chart width minus Y-axis labels
-func (c *Chart) calculateEffectiveChartWidth() int {
-	return c.width - YAxisLabelWidth
+// AddValueAt adds a value recorded at the given time to the chart
+func (c *Chart) AddValueAt(at time.Time, value float64) {
+	c.data.AddAt(at, value)
 }
 
-// initializeEmptyGrid creates an empty grid filled with spaces
-func (c *Chart) initializeEmptyGrid(height, width int) []string {
-	grid := make([]string, height)
-	for i := 0; i < height; i++ {
-		grid[i] = strings.Repeat(" ", width)
+// Reset removes all values from the chart
+func (c *Chart) Reset() {
+	for _, series := range c.plot.Series() {
+		series.Data.Reset()
 	}
-	return grid
 }
 
-// plotDataPoints plots all data points on the grid
-func (c *Chart) plotDataPoints(grid []string, min, max float64, height, chartWidth int) {
-	startIdx, endIdx := c.calculateVisibleDataRange(chartWidth)
-
-	for i := startIdx; i < endIdx; i++ {
-		x := i - startIdx
-		if x >= chartWidth {
-			break
-		}
+// Render renders the chart as a string
+func (c *Chart) Render() string {
+	points := c.data.TierPoints(c.zoom)
+	slog.Debug("Chart.Render", "title", c.title, "width", c.width, "height", c.height, "dataPoints", len(points))
 
-		c.plotSinglePoint(grid, i, x, min, max, height, chartWidth, startIdx)
+	if c.width <= 0 || c.height <= 0 {
+		return " [gray]Initializing...[-]"
 	}
-}
 
-// calculateVisibleDataRange determines which data points are visible
-func (c *Chart) calculateVisibleDataRange(chartWidth int) (int, int) {
-	dataPoints := len(c.data.values)
-	startIdx := 0
-	if dataPoints > chartWidth {
-		startIdx = dataPoints - chartWidth
+	if c.collapsed {
+		var result strings.Builder
+		c.renderTitle(&result)
+		return strings.TrimSuffix(result.String(), "\n")
 	}
-	return startIdx, dataPoints
-}
 
-// plotSinglePoint plots a single data point and connects it to the previous point
-func (c *Chart) plotSinglePoint(grid []string, dataIdx, x int, min, max float64, height, chartWidth, startIdx int) {
-	value := c.data.values[dataIdx]
-	y := c.valueToY(value, min, max, height)
+	if len(points) == 0 {
+		return c.renderEmptyChart()
+	}
 
-	// Plot the point
-	if y >= 0 && y < height {
-		c.setGridPoint(grid, x, y, dataIdx, height, min, max)
+	c.updateReference(points)
+	c.plot.SetViewport(c.viewport)
+	c.plot.SetLegend(len(c.plot.Series()) > 1)
+	c.plot.SetCursor(-1)
+	if c.inspecting {
+		c.plot.SetCursor(len(points) - 1 - c.cursorIndex(points))
 	}
 
-	// Connect to previous point
-	if dataIdx > startIdx {
-		prevValue := c.data.values[dataIdx-1]
-		prevY := c.valueToY(prevValue, min, max, height)
-		c.drawVerticalLine(grid, x, prevY, y, chartWidth, height)
+	var result strings.Builder
+	c.renderTitle(&result)
+	result.WriteString(c.plot.Text(c.width, c.calculateChartHeight()+1))
+	result.WriteString("\n")
+	result.WriteString(c.createTimeLabels(points))
+	result.WriteString("\n")
+	if c.inspecting {
+		result.WriteString(c.createCursorLine(len(points) - 1 - c.cursorIndex(points)))
+	} else {
+		result.WriteString(c.createStatsLine())
 	}
+
+	return result.String()
 }
 
-// setGridPoint sets a single point on the grid with the appropriate character
-func (c *Chart) setGridPoint(grid []string, x, y, dataIdx, height int, min, max float64) {
-	line := []rune(grid[y])
-	if x < len(line) {
-		char := c.getPlotChar(dataIdx, y, height, min, max)
-		line[x] = char
-		grid[y] = string(line)
+// createCursorLine creates the time and values of the point under the
+// inspection cursor, shown instead of the statistics
+func (c *Chart) createCursorLine(back int) string {
+	columns := c.width - plot.AxisWidth
+	x, ok := c.plot.Column(columns, back)
+	if !ok {
+		return ""
 	}
-}
 
-// applyColorToGrid applies the chart color to all grid lines
-func (c *Chart) applyColorToGrid(grid []string) {
-	for i := range grid {
-		grid[i] = fmt.Sprintf("[%s]%s[-]", c.color, grid[i])
+	var line strings.Builder
+	for i, series := range c.plot.Series() {
+		p, ok := c.plot.PointAt(series.Name, columns, x)
+		if !ok {
+			continue
+		}
+		if line.Len() == 0 {
+			fmt.Fprintf(&line, "%*s[gray]▸ %s[-]", plot.AxisWidth, "", p.Time.Format(c.timeFormat))
+		}
+		value := Unavailable
+		if !math.IsNaN(p.Value) {
+			value = c.formatValue(p.Value)
+		}
+		if i == 0 {
+			fmt.Fprintf(&line, "  [%s]%s[-]", c.color, value)
+		} else {
+			fmt.Fprintf(&line, "  [gray]%s[-] %s", series.Name, value)
+		}
 	}
+	return line.String()
 }
 
-// valueToY converts a value to Y coordinate
-func (c *Chart) valueToY(value, min, max float64, height int) int {
-	if max <= min {
-		return height / 2
+// createStatsLine creates the statistics of the visible values below the
+// time labels, shortened to the range and current value in narrow charts
+func (c *Chart) createStatsLine() string {
+	s, ok := c.plot.Summarize(c.title, c.width-plot.AxisWidth)
+	if !ok {
+		return ""
 	}
-	normalized := (value - min) / (max - min)
-	y := int(float64(height-1) * (1 - normalized))
-	if y < 0 {
-		y = 0
+
+	low, high, mean, now := c.formatValue(s.Min), c.formatValue(s.Max), c.formatValue(s.Mean), c.formatValue(s.Current)
+	lines := []string{
+		fmt.Sprintf("[gray]min [-]%s[gray]  max [-]%s[gray]  avg [-]%s[gray]  now [-]%s", low, high, mean, now),
+		fmt.Sprintf("[gray]%s..%s  now [-]%s", low, high, now),
 	}
-	if y >= height {
-		y = height - 1
+	for _, line := range lines {
+		if tview.TaggedStringWidth(line) <= c.width-plot.AxisWidth {
+			return fmt.Sprintf("%*s", plot.AxisWidth, "") + line
+		}
 	}
-	return y
+	return ""
 }
 
-// getPlotChar determines which character to use for plotting
-func (c *Chart) getPlotChar(dataIdx, y, height int, min, max float64) rune {
-	// For the most recent data point, use a different character
-	if dataIdx == len(c.data.values)-1 {
-		return '*' // Current value
+// updateReference evaluates the reference line at the data timestamps
+func (c *Chart) updateReference(points []plot.Point) {
+	if c.reference == nil {
+		return
 	}
 
-	// Check if this is a peak or valley
-	if dataIdx > 0 && dataIdx < len(c.data.values)-1 {
-		prev := c.data.values[dataIdx-1]
-		next := c.data.values[dataIdx+1]
-
-		prevY := c.valueToY(prev, min, max, height)
-		nextY := c.valueToY(next, min, max, height)
-
-		if y < prevY && y < nextY {
-			return '/' // Peak
-		}
-		if y > prevY && y > nextY {
-			return '\\' // Valley
+	data := plot.NewData(len(points))
+	for _, p := range points {
+		value, ok := c.reference(p.Time)
+		if !ok {
+			value = math.NaN()
 		}
+		data.AddAt(p.Time, value)
 	}
+	c.referenceLine.Data = data
+}
+
+// renderTitle renders the chart title with decorative borders
+func (c *Chart) renderTitle(result *strings.Builder) {
+	titleStr := c.prepareTitleString()
+	leftPad, rightPad := c.calculateTitlePadding(titleStr)
 
-	return 'o' // Regular point
+	if leftPad > 0 {
+		result.WriteString(strings.Repeat("─", leftPad))
+	}
+	result.WriteString(fmt.Sprintf("[%s:b]%s[-]", c.color, titleStr))
+	if rightPad > 0 {
+		result.WriteString(strings.Repeat("─", rightPad))
+	}
+	result.WriteString("\n")
 }
 
-// drawVerticalLine draws a vertical line between two points
-func (c *Chart) drawVerticalLine(grid []string, x, y1, y2 int, width, height int) {
-	if x >= width || x < 0 {
-		return
+// prepareTitleString prepares the title string with the zoom level, truncating if necessary
+func (c *Chart) prepareTitleString() string {
+	parts := []string{c.title}
+	if c.collapsed {
+		parts[0] = "▸ " + c.title
+	}
+	if c.zoom > 0 {
+		parts = append(parts, zoomLabel(c.zoom))
 	}
+	if scale := c.plot.Scale(); scale != plot.ScaleLinear {
+		parts = append(parts, scale.String())
+	}
+	titleStr := " " + strings.Join(parts, " · ") + " "
+	titleLen := len(titleStr)
 
-	start := y1
-	end := y2
-	if start > end {
-		start, end = end, start
+	if c.width < titleLen {
+		// Truncate title if too long
+		return c.title[:c.width-2] + " "
 	}
+	return titleStr
+}
 
-	for y := start; y <= end; y++ {
-		if y >= 0 && y < height && y != y1 && y != y2 {
-			line := []rune(grid[y])
-			if x < len(line) && line[x] == ' ' {
-				line[x] = '│'
-			}
-			grid[y] = string(line)
-		}
+// calculateTitlePadding calculates left and right padding for centered title
+func (c *Chart) calculateTitlePadding(titleStr string) (int, int) {
+	titleLen := len(titleStr)
+	totalPadding := c.width - titleLen
+
+	if totalPadding <= 0 {
+		return 0, 0
 	}
+
+	leftPad := totalPadding / 2
+	rightPad := totalPadding - leftPad
+	return leftPad, rightPad
+}
+
+// calculateChartHeight calculates the effective chart height
+func (c *Chart) calculateChartHeight() int {
+	chartHeight := c.height - ChartHeightReserve
+	if chartHeight < MinChartHeight {
+		return MinChartHeight
+	}
+	return chartHeight
 }
 
 // formatValue formats a value for display
@@ -419,12 +540,12 @@ func (c *Chart) renderEmptyChart() string {
 		result.WriteString(fmt.Sprintf("[gray]%8s ┤[-] ", label))
 
 		// Empty chart line
-		result.WriteString(fmt.Sprintf("[gray]%s[-]\n", strings.Repeat("·", c.width-11)))
+		result.WriteString(fmt.Sprintf("[gray]%s[-]\n", strings.Repeat("·", c.width-plot.AxisWidth)))
 	}
 
 	// X-axis
 	result.WriteString(fmt.Sprintf("[gray]%8s └", ""))
-	result.WriteString(strings.Repeat("─", c.width-11))
+	result.WriteString(strings.Repeat("─", c.width-plot.AxisWidth))
 	result.WriteString("[-]\n")
 
 	// Time labels placeholder
@@ -434,30 +555,30 @@ func (c *Chart) renderEmptyChart() string {
 }
 
 // createTimeLabels creates time labels for x-axis
-func (c *Chart) createTimeLabels() string {
-	if len(c.data.timestamps) == 0 {
+func (c *Chart) createTimeLabels(points []plot.Point) string {
+	if len(points) == 0 {
 		return ""
 	}
 
 	var result strings.Builder
 	result.WriteString(fmt.Sprintf("[gray]%8s   ", ""))
 
-	chartWidth := c.width - 11
+	chartWidth := c.width - plot.AxisWidth
 
 	// Show time labels at start, middle, and end
-	if len(c.data.timestamps) > 0 {
+	if len(points) > 0 {
 		// Calculate time range
-		startTime := c.data.timestamps[0]
-		endTime := c.data.timestamps[len(c.data.timestamps)-1]
+		startTime := points[0].Time
+		endTime := points[len(points)-1].Time
 		duration := endTime.Sub(startTime)
 
 		// Start time
-		result.WriteString(fmt.Sprintf("[gray]%s", startTime.Format("15:04:05")))
+		result.WriteString(fmt.Sprintf("[gray]%s", startTime.Format(c.timeFormat)))
 
 		// Calculate spacing
-		labelWidth := 8
+		labelWidth := len(c.timeFormat)
 		spacing := chartWidth - (3 * labelWidth)
-		if spacing > 0 && len(c.data.timestamps) > 1 {
+		if spacing > 0 && len(points) > 1 {
 			// Middle section with duration info
 			midSpacing := spacing / 2
 			if midSpacing > 4 {
@@ -472,11 +593,11 @@ func (c *Chart) createTimeLabels() string {
 				if remainingSpace > 0 {
 					result.WriteString(strings.Repeat(" ", remainingSpace))
 				}
-				result.WriteString(fmt.Sprintf("[gray]%s", endTime.Format("15:04:05")))
+				result.WriteString(fmt.Sprintf("[gray]%s", endTime.Format(c.timeFormat)))
 			} else {
 				// Not enough space for duration, just add spacing
 				result.WriteString(strings.Repeat(" ", spacing))
-				result.WriteString(fmt.Sprintf("[gray]%s", endTime.Format("15:04:05")))
+				result.WriteString(fmt.Sprintf("[gray]%s", endTime.Format(c.timeFormat)))
 			}
 		}
 	}
@@ -493,53 +614,202 @@ func formatChartDuration(d time.Duration) string {
 	if d < time.Hour {
 		return fmt.Sprintf("%dm", int(d.Minutes()))
 	}
+	if d >= 48*time.Hour {
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
 	return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
 }
 
+// chartTitle is where a chart's title row was rendered within the chart set
+type chartTitle struct {
+	chart *Chart
+	x, y  int
+	width int
+}
+
 // ChartSet manages multiple charts
 type ChartSet struct {
-	charts []*Chart
-	width  int
-	height int
+	charts  []*Chart
+	width   int
+	height  int
+	layout  ChartLayout
+	columns int
+	titles  []chartTitle
 }
 
 // NewChartSet creates a new chart set
 func NewChartSet() *ChartSet {
 	return &ChartSet{
-		charts: make([]*Chart, 0),
+		charts:  make([]*Chart, 0),
+		layout:  ChartLayoutStacked,
+		columns: 1,
 	}
 }
 
+// SetLayout sets how the charts are arranged
+func (cs *ChartSet) SetLayout(layout ChartLayout) {
+	cs.layout = layout
+}
+
+// Layout returns how the charts are arranged
+func (cs *ChartSet) Layout() ChartLayout {
+	return cs.layout
+}
+
 // AddChart adds a chart to the set
 func (cs *ChartSet) AddChart(chart *Chart) {
 	cs.charts = append(cs.charts, chart)
 }
 
+// visibleCharts returns the charts that aren't hidden
+func (cs *ChartSet) visibleCharts() []*Chart {
+	visible := make([]*Chart, 0, len(cs.charts))
+	for _, chart := range cs.charts {
+		if !chart.Hidden() {
+			visible = append(visible, chart)
+		}
+	}
+	return visible
+}
+
 // SetSize sets the size for all charts
 func (cs *ChartSet) SetSize(width, height int) {
 	cs.width = width
 	cs.height = height
 
-	// Distribute height among charts
-	if len(cs.charts) > 0 {
-		chartHeight := height / len(cs.charts)
-		slog.Debug("ChartSet SetSize", "width", width, "height", height, "chartCount", len(cs.charts), "chartHeight", chartHeight)
-		for _, chart := range cs.charts {
-			chart.SetSize(width, chartHeight)
+	// Distribute the space among visible charts only
+	visible := cs.visibleCharts()
+	if len(visible) == 0 {
+		return
+	}
+
+	cs.columns = cs.columnCount(len(visible))
+	rows := (len(visible) + cs.columns - 1) / cs.columns
+	chartWidth := (width - (cs.columns-1)*ChartColumnGap) / cs.columns
+
+	// Rows of collapsed charts only take their title row
+	collapsed := 0
+	for row := 0; row < rows; row++ {
+		if cs.rowCollapsed(visible, row) {
+			collapsed++
+		}
+	}
+	chartHeight := height
+	if expanded := rows - collapsed; expanded > 0 {
+		chartHeight = (height - collapsed) / expanded
+	}
+	slog.Debug("ChartSet SetSize", "width", width, "height", height, "chartCount", len(visible),
+		"columns", cs.columns, "chartWidth", chartWidth, "chartHeight", chartHeight)
+	for _, chart := range visible {
+		chart.SetSize(chartWidth, chartHeight)
+	}
+}
+
+// rowCollapsed reports whether all charts in a layout row are collapsed
+func (cs *ChartSet) rowCollapsed(visible []*Chart, row int) bool {
+	for _, chart := range visible[row*cs.columns : min((row+1)*cs.columns, len(visible))] {
+		if !chart.Collapsed() {
+			return false
+		}
+	}
+	return true
+}
+
+// ChartAt returns the chart whose title row is at the given position of the
+// last rendered chart set
+func (cs *ChartSet) ChartAt(x, y int) (*Chart, bool) {
+	for _, title := range cs.titles {
+		if y == title.y && x >= title.x && x < title.x+title.width {
+			return title.chart, true
 		}
 	}
+	return nil, false
 }
 
-// Render renders all charts
+// columnCount returns how many charts fit side by side in the current layout
+func (cs *ChartSet) columnCount(charts int) int {
+	if cs.layout != ChartLayoutColumns {
+		return 1
+	}
+
+	columns := (cs.width + ChartColumnGap) / (MinChartColumnWidth + ChartColumnGap)
+	if columns > charts {
+		columns = charts
+	}
+	if columns < 1 {
+		columns = 1
+	}
+	return columns
+}
+
+// Render renders all visible charts
 func (cs *ChartSet) Render() string {
+	visible := cs.visibleCharts()
+	if len(visible) == 0 && len(cs.charts) > 0 {
+		return fmt.Sprintf("[gray]All charts hidden, press [yellow]1[gray]-[yellow]%d[gray] to show them[-]", len(cs.charts))
+	}
+
 	var result strings.Builder
 
-	for i, chart := range cs.charts {
-		if i > 0 {
+	cs.titles = cs.titles[:0]
+	line := 0
+	for row := 0; row*cs.columns < len(visible); row++ {
+		if row > 0 {
 			result.WriteString("\n")
 		}
-		result.WriteString(chart.Render())
+		end := (row + 1) * cs.columns
+		if end > len(visible) {
+			end = len(visible)
+		}
+		x := 0
+		for _, chart := range visible[row*cs.columns : end] {
+			cs.titles = append(cs.titles, chartTitle{chart: chart, x: x, y: line, width: chart.width})
+			x += chart.width + ChartColumnGap
+		}
+		text := joinCharts(visible[row*cs.columns : end])
+		line += strings.Count(text, "\n") + 1
+		result.WriteString(text)
 	}
 
 	return result.String()
 }
+
+// joinCharts renders charts side by side, padding each line to the chart width
+func joinCharts(charts []*Chart) string {
+	if len(charts) == 1 {
+		return charts[0].Render()
+	}
+
+	rendered := make([][]string, len(charts))
+	lines := 0
+	for i, chart := range charts {
+		rendered[i] = strings.Split(chart.Render(), "\n")
+		if len(rendered[i]) > lines {
+			lines = len(rendered[i])
+		}
+	}
+
+	gap := strings.Repeat(" ", ChartColumnGap)
+	var result strings.Builder
+	for line := 0; line < lines; line++ {
+		if line > 0 {
+			result.WriteString("\n")
+		}
+		for i, chart := range charts {
+			if i > 0 {
+				result.WriteString(gap)
+			}
+			text := ""
+			if line < len(rendered[i]) {
+				text = rendered[i][line]
+			}
+			result.WriteString(text)
+			if i < len(charts)-1 {
+				if padding := chart.width - tview.TaggedStringWidth(text); padding > 0 {
+					result.WriteString(strings.Repeat(" ", padding))
+				}
+			}
+		}
+	}
+	return result.String()
+}
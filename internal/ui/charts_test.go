@@ -0,0 +1,235 @@
+package ui
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/battery"
+	"github.com/xsikor/go-battop/internal/stats"
+)
+
+func TestChartSetTitles(t *testing.T) {
+	set := NewChartSet()
+	voltage := NewChart("Voltage", MaxChartDataPoints, "V", "yellow")
+	power := NewChart("Power", MaxChartDataPoints, "W", "red")
+	for i := 0; i < 5; i++ {
+		voltage.AddValue(12)
+		power.AddValue(float64(i))
+	}
+	set.AddChart(voltage)
+	set.AddChart(power)
+
+	set.SetSize(80, 30)
+	lines := strings.Split(set.Render(), "\n")
+	if chart, ok := set.ChartAt(40, 0); !ok || chart != voltage {
+		t.Fatalf("chart at the first title = %v, %v", chart, ok)
+	}
+	second := -1
+	for y := 1; y < len(lines); y++ {
+		if chart, ok := set.ChartAt(40, y); ok && chart == power {
+			second = y
+		}
+	}
+	if second < 0 || !strings.Contains(lines[second], "Power") {
+		t.Fatalf("power title not found at its recorded row %d", second)
+	}
+
+	// A collapsed chart is only its title row and gives its height to the others
+	voltage.SetCollapsed(true)
+	set.SetSize(80, 30)
+	lines = strings.Split(set.Render(), "\n")
+	if !strings.Contains(lines[0], "▸ Voltage") {
+		t.Errorf("collapsed title = %q", lines[0])
+	}
+	if chart, ok := set.ChartAt(40, 1); !ok || chart != power {
+		t.Errorf("chart below the collapsed one = %v, %v", chart, ok)
+	}
+	if power.height != 29 {
+		t.Errorf("expanded chart height = %d, want 29", power.height)
+	}
+}
+
+func TestChartAdopt(t *testing.T) {
+	old := NewChart("Power", MaxChartDataPoints, "W", "red")
+	old.AddSeries("CPU", "blue", '+')
+	for i := 1; i <= 3; i++ {
+		old.AddValue(float64(i))
+		old.AddSeriesValue("CPU", 0.5)
+	}
+	old.SetCollapsed(true)
+	old.SetZoom(1)
+
+	// The replacement shows milliwatts and has no CPU series
+	chart := NewChart("Power", MaxChartDataPoints, "mW", "red")
+	chart.adopt(old, 1000, 0)
+
+	points := chart.data.Points()
+	if len(points) != 3 || points[2].Value != 3000 {
+		t.Errorf("adopted points = %v, want 3 values scaled to mW", points)
+	}
+	if len(chart.plot.Series()) != 1 {
+		t.Errorf("%d series, want only the chart's own", len(chart.plot.Series()))
+	}
+	if !chart.Collapsed() || chart.zoom != 1 {
+		t.Errorf("collapsed = %v, zoom = %d, want the old chart's state", chart.Collapsed(), chart.zoom)
+	}
+}
+
+func TestViewChartGradient(t *testing.T) {
+	spec, _ := findChartSpec("power")
+	stops := Themes[0].GaugeStops()
+	gradient := newViewChart(spec, ChartOptions{}).gradient(stops, spec.Severity)
+
+	// Values are in the display unit, W
+	if got, want := gradient(0), hexColor(stops[2]); got != want {
+		t.Errorf("idle color = %s, want %s", got, want)
+	}
+	if got, want := gradient(-GradientPowerLimit/1000.0), hexColor(stops[0]); got != want {
+		t.Errorf("color at the limit = %s, want %s", got, want)
+	}
+	if got, want := gradient(GradientPowerLimit/2000.0), hexColor(stops[1]); got != want {
+		t.Errorf("color at half the limit = %s, want %s", got, want)
+	}
+
+	if newViewChart(spec, ChartOptions{Color: "blue"}).severity != nil {
+		t.Error("gradient replaces a configured chart color")
+	}
+}
+
+func TestViewChartCRate(t *testing.T) {
+	spec, _ := findChartSpec("power")
+	chart := newViewChart(spec, ChartOptions{Unit: "C"})
+
+	// Without a design capacity the C-rate is unknown
+	chart.add(&battery.Info{ChargeRate: -10000})
+	chart.add(&battery.Info{ChargeRate: -25000, Design: 50000})
+	chart.add(&battery.Info{ChargeRate: 12500})
+
+	points := chart.chart.data.Points()
+	if len(points) != 3 || !math.IsNaN(points[0].Value) {
+		t.Fatalf("points = %v, want a gap first", points)
+	}
+	if points[1].Value != -0.5 || points[2].Value != 0.25 {
+		t.Errorf("C-rates = %v, %v, want -0.5, 0.25 (the last design capacity)", points[1].Value, points[2].Value)
+	}
+	if got := chart.factor(); got != 1.0/50000 {
+		t.Errorf("factor() = %v, want 1/50000", got)
+	}
+}
+
+func TestViewGoal(t *testing.T) {
+	config := testConfig{basis: ChargeBasisFull, mode: EstimateSmoothed}
+	view := NewView(0, config, config.Formatter())
+	view.SetPowerSource(battery.PowerSource{Detected: true})
+	// 36 Wh lasting about 3 hours leaves a budget of about 12 W, below the 9.5 W draw
+	view.SetGoal(&stats.Goal{Until: time.Now().Add(3 * time.Hour)})
+	view.Ingest(fullInfo(battery.StateDischarging))
+	view.Render()
+
+	if gauge := plainText(view.powerGauge); !strings.Contains(gauge, "of 12.00 W budget") {
+		t.Errorf("power gauge = %q, want the budget", gauge)
+	}
+	if panel := plainText(view.infoText); !strings.Contains(panel, "budget 12.00 W") || strings.Contains(panel, "over the") {
+		t.Errorf("info panel without the budget:\n%s", panel)
+	}
+
+	var power *viewChart
+	for _, chart := range view.charts {
+		if chart.spec.Name == "power" {
+			power = chart
+		}
+	}
+	thresholds := power.chart.plot.Thresholds()
+	if len(thresholds) != 1 || thresholds[0].Label != GoalBudgetLabel || math.Abs(thresholds[0].Value+12) > 0.01 {
+		t.Errorf("thresholds = %v, want a budget line at -12 W", thresholds)
+	}
+
+	view.SetGoal(nil)
+	view.Render()
+	if len(power.chart.plot.Thresholds()) != 0 {
+		t.Error("budget line kept after clearing the goal")
+	}
+}
+
+func TestViewChargingCost(t *testing.T) {
+	config := testConfig{basis: ChargeBasisFull, mode: EstimateSmoothed, tariff: stats.Tariff{Price: 0.30, Currency: "EUR", Carbon: 400}}
+	view := NewView(0, config, config.Formatter())
+
+	var text strings.Builder
+	view.addChargingCost(&text, stats.Session{Charged: 50000, Drawn: 10000, Duration: time.Hour}, 0)
+	if got := text.String(); !strings.Contains(got, "charged 50.00 Wh ≈ 0.015 EUR, 20.0 g CO₂") {
+		t.Errorf("charging cost = %q", got)
+	}
+
+	text.Reset()
+	view.addChargingCost(&text, stats.Session{Drawn: 10000, Duration: time.Hour}, 0)
+	if text.Len() != 0 {
+		t.Errorf("cost shown without charging: %q", text.String())
+	}
+}
+
+func TestViewNotes(t *testing.T) {
+	config := testConfig{basis: ChargeBasisFull, mode: EstimateSmoothed}
+	view := NewView(0, config, config.Formatter())
+	log := stats.NewEventLog()
+	view.SetEvents(log.Events)
+
+	view.Ingest(fullInfo(battery.StateDischarging))
+	if _, err := log.Annotate("started compile", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	view.Ingest(fullInfo(battery.StateDischarging))
+	view.Render()
+
+	if !strings.Contains(plainText(view.chartArea), "N") {
+		t.Errorf("charts without the note marker:\n%s", plainText(view.chartArea))
+	}
+	for _, history := range view.History() {
+		if len(history.Notes) != 1 || history.Notes[0].Message != "started compile" {
+			t.Errorf("%s history notes = %v, want the note", history.Chart, history.Notes)
+		}
+	}
+}
+
+func TestViewChargerWarning(t *testing.T) {
+	online := func(maxPower float64) battery.PowerSource {
+		return battery.PowerSource{OnAC: true, Detected: true, Adapters: []battery.ACAdapter{{Name: "AC", Online: true, MaxPower: maxPower}}}
+	}
+	tests := []struct {
+		name   string
+		source battery.PowerSource
+		state  battery.State
+		rate   float64
+		active stats.Drain
+		want   string
+	}{
+		{"on battery", battery.PowerSource{Detected: true}, battery.StateDischarging, -9500, stats.Drain{}, ""},
+		{"keeping up", online(65000), battery.StateCharging, 30000, stats.Drain{}, ""},
+		{"rated charger", online(45000), battery.StateDischarging, -9500, stats.Drain{}, "Charger underpowered (needs ≥ 60 W)"},
+		{"unrated charger", battery.PowerSource{OnAC: true}, battery.StateDischarging, -9500, stats.Drain{Energy: 25000, Duration: time.Hour}, "Charger underpowered (needs ≥ 30 W)"},
+		{"unknown draw", battery.PowerSource{OnAC: true}, battery.StateDischarging, -9500, stats.Drain{}, "Charger underpowered"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testConfig{basis: ChargeBasisFull, mode: EstimateSmoothed}
+			view := NewView(0, config, config.Formatter())
+			view.SetPowerSource(tt.source)
+			view.SetStats(stats.Summary{Active: tt.active})
+
+			info := fullInfo(tt.state)
+			info.ChargeRate, info.NetChargeRate = tt.rate, tt.rate
+			var text strings.Builder
+			view.addChargerWarning(&text, info)
+			got := strings.TrimSpace(stripped(text.String()))
+			if tt.want == "" && got != "" || !strings.Contains(got, tt.want) {
+				t.Errorf("warning %q, want %q", got, tt.want)
+			}
+			if tt.want == "Charger underpowered" && strings.Contains(got, "needs") {
+				t.Errorf("warning %q recommends a charger without a known draw", got)
+			}
+		})
+	}
+}
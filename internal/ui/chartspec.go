@@ -0,0 +1,365 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/xsikor/go-battop/internal/battery"
+	"github.com/xsikor/go-battop/internal/stats"
+	"github.com/xsikor/go-battop/pkg/plot"
+)
+
+// ChartOptions overrides the appearance of a chart
+type ChartOptions struct {
+	// Color replaces the default chart color (empty keeps the default)
+	Color string
+
+	// Unit replaces the default display unit (empty keeps the default)
+	Unit string
+
+	// Hidden hides the chart initially (nil keeps the chart's default)
+	Hidden *bool
+
+	// Overlays draws the chart's additional series with a legend
+	Overlays bool
+
+	// Scale is the value axis scale: linear, log or symlog (empty keeps linear)
+	Scale string
+
+	// Lines are the threshold lines drawn across the chart
+	Lines []ChartLine
+}
+
+// ChartLine is a labeled threshold line in the chart's display unit
+type ChartLine struct {
+	Value float64
+	Label string
+}
+
+// ParseChartLines parses comma-separated value[:label] threshold lines
+// (e.g., "20:low,80:target")
+func ParseChartLines(value string) ([]ChartLine, error) {
+	var lines []ChartLine
+	for _, item := range strings.Split(value, ",") {
+		number, label, _ := strings.Cut(strings.TrimSpace(item), ":")
+		v, err := strconv.ParseFloat(number, 64)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, fmt.Errorf("invalid line %q: must be value[:label], e.g. 20:low", item)
+		}
+		lines = append(lines, ChartLine{Value: v, Label: strings.TrimSpace(label)})
+	}
+	return lines, nil
+}
+
+// chartOverlay is an additional series drawn over a chart
+type chartOverlay struct {
+	// Name is the legend label
+	Name string
+
+	// Color is the series color
+	Color string
+
+	// Marker draws the points, telling the series apart without colors
+	Marker rune
+
+	// Value extracts the base value from a battery reading
+	Value func(info *battery.Info) float64
+}
+
+// chartSpec describes a chart the battery view can show
+type chartSpec struct {
+	// Name is the configuration key of the chart
+	Name string
+
+	// Title is the chart title
+	Title string
+
+	// Color is the default chart color
+	Color string
+
+	// Unit is the default display unit
+	Unit string
+
+	// Units maps each supported display unit to its scale from the base value
+	Units map[string]float64
+
+	// Offsets are added after scaling for units with another zero point
+	// (e.g., °F)
+	Offsets map[string]float64
+
+	// DesignRelative lists the units whose values are also divided by the
+	// battery's design capacity in mWh (e.g., the C-rate)
+	DesignRelative map[string]bool
+
+	// Hidden is true when the chart is hidden unless configured otherwise
+	Hidden bool
+
+	// Value extracts the base value from a battery reading
+	Value func(info *battery.Info) float64
+
+	// Severity rates a base value from 0 (fine) to 1 (critical) for the
+	// gradient points of 24-bit color terminals (nil keeps the chart color)
+	Severity func(value float64) float64
+
+	// Overlays are the series drawn over the chart when enabled
+	Overlays []chartOverlay
+}
+
+// chartSpecs lists the battery view charts in display order
+var chartSpecs = []chartSpec{
+	{
+		Name:  "voltage",
+		Title: "Voltage",
+		Color: "yellow",
+		Unit:  "V",
+		Units: map[string]float64{"V": 1, "mV": 1000},
+		Value: func(info *battery.Info) float64 { return info.Voltage },
+	},
+	{
+		Name:  "power",
+		Title: "Power",
+		Color: "green",
+		Unit:  "W",
+		Units: map[string]float64{"W": 0.001, "mW": 1, "C": 1},
+		// The C-rate is the power relative to the design capacity: 1C charges
+		// or drains the battery in an hour
+		DesignRelative: map[string]bool{"C": true},
+		Value:          func(info *battery.Info) float64 { return info.ChargeRate },
+		// Power draw and charge power alike, green when idle to red at the limit
+		Severity: func(mW float64) float64 { return math.Abs(mW) / GradientPowerLimit },
+		Overlays: []chartOverlay{{
+			Name:   "smoothed",
+			Color:  "white",
+			Marker: '+',
+			Value:  func(info *battery.Info) float64 { return info.SmoothedChargeRate },
+		}},
+	},
+	{
+		Name:     "charge",
+		Title:    "Charge",
+		Color:    "cyan",
+		Unit:     "%",
+		Units:    map[string]float64{"%": 1},
+		Value:    func(info *battery.Info) float64 { return info.ChargePercent() },
+		Severity: func(percent float64) float64 { return 1 - percent/100 },
+	},
+	{
+		Name:    "temperature",
+		Title:   "Temperature",
+		Color:   "red",
+		Unit:    "°C",
+		Units:   map[string]float64{"°C": 1, "°F": 1.8, "K": 1},
+		Offsets: map[string]float64{"°F": 32, "K": 273.15},
+		Hidden:  true,
+		Value:   func(info *battery.Info) float64 { return info.Temperature },
+		Severity: func(c float64) float64 {
+			return (c - GradientTemperatureLow) / (GradientTemperatureHigh - GradientTemperatureLow)
+		},
+	},
+}
+
+// colorAliases maps common ANSI color names to the names tview understands
+var colorAliases = map[string]string{
+	"magenta": "fuchsia",
+	"cyan":    "aqua",
+}
+
+// normalizeColor resolves ANSI color name aliases
+func normalizeColor(color string) string {
+	if alias, ok := colorAliases[strings.ToLower(color)]; ok {
+		return alias
+	}
+	return color
+}
+
+// ChartNames returns the configuration keys of the battery view charts
+func ChartNames() []string {
+	names := make([]string, 0, len(chartSpecs))
+	for _, spec := range chartSpecs {
+		names = append(names, spec.Name)
+	}
+	return names
+}
+
+// findChartSpec returns the chart spec with the given name
+func findChartSpec(name string) (chartSpec, bool) {
+	for _, spec := range chartSpecs {
+		if spec.Name == name {
+			return spec, true
+		}
+	}
+	return chartSpec{}, false
+}
+
+// ValidateChartOption checks a single chart option setting
+func ValidateChartOption(chart, option, value string) error {
+	spec, ok := findChartSpec(chart)
+	if !ok {
+		return fmt.Errorf("unknown chart %q: must be one of %s", chart, strings.Join(ChartNames(), ", "))
+	}
+
+	switch option {
+	case "color":
+		if tcell.GetColor(normalizeColor(value)) == tcell.ColorDefault {
+			return fmt.Errorf("unknown color %q", value)
+		}
+	case "unit":
+		if _, ok := spec.Units[value]; !ok {
+			units := make([]string, 0, len(spec.Units))
+			for unit := range spec.Units {
+				units = append(units, unit)
+			}
+			sort.Strings(units)
+			return fmt.Errorf("unsupported unit %q for %s chart: must be one of %s", value, chart, strings.Join(units, ", "))
+		}
+	case "hidden":
+		if value != "true" && value != "false" {
+			return fmt.Errorf("hidden must be 'true' or 'false'")
+		}
+	case "scale":
+		if _, err := plot.ParseScale(value); err != nil {
+			return err
+		}
+	case "lines":
+		if _, err := ParseChartLines(value); err != nil {
+			return err
+		}
+	case "overlays":
+		if len(spec.Overlays) == 0 {
+			return fmt.Errorf("the %s chart has no overlays", chart)
+		}
+		if value != "true" && value != "false" {
+			return fmt.Errorf("overlays must be 'true' or 'false'")
+		}
+	default:
+		return fmt.Errorf("unknown chart option %q: must be color, unit, hidden, overlays, scale or lines", option)
+	}
+	return nil
+}
+
+// viewChart is a chart built from a spec and its configured options
+type viewChart struct {
+	spec     chartSpec
+	chart    *Chart
+	scale    float64
+	offset   float64
+	overlays []chartOverlay
+
+	// severity rates the values for the gradient, nil when the chart color
+	// is configured or the chart has none
+	severity func(value float64) float64
+
+	// perDesign divides the values by design, the last known design
+	// capacity in mWh, for units relative to it
+	perDesign bool
+	design    float64
+}
+
+// newViewChart builds a chart from its spec with the options applied
+func newViewChart(spec chartSpec, options ChartOptions) *viewChart {
+	color := spec.Color
+	if options.Color != "" {
+		color = normalizeColor(options.Color)
+	}
+	unit := spec.Unit
+	if _, ok := spec.Units[options.Unit]; ok {
+		unit = options.Unit
+	}
+
+	chart := NewChart(spec.Title, MaxChartDataPoints, unit, color)
+	chart.ShowSuspends()
+	chart.SetTiers(ChartTiers...)
+	if scale, err := plot.ParseScale(options.Scale); err == nil {
+		chart.SetAxisScale(scale)
+	}
+	for _, line := range options.Lines {
+		chart.AddThreshold(line.Value, line.Label)
+	}
+	chart.SetHidden(spec.Hidden)
+	if options.Hidden != nil {
+		chart.SetHidden(*options.Hidden)
+	}
+
+	c := &viewChart{
+		spec:      spec,
+		chart:     chart,
+		scale:     spec.Units[unit],
+		offset:    spec.Offsets[unit],
+		perDesign: spec.DesignRelative[unit],
+	}
+	if options.Color == "" {
+		c.severity = spec.Severity
+	}
+	if options.Overlays {
+		c.overlays = spec.Overlays
+		for _, overlay := range c.overlays {
+			chart.AddSeries(overlay.Name, overlay.Color, overlay.Marker)
+		}
+	}
+	return c
+}
+
+// ChartHistory is the in-memory series of one chart of a battery
+type ChartHistory struct {
+	Battery int          `json:"battery"`
+	Chart   string       `json:"chart"`
+	Unit    string       `json:"unit"`
+	Points  []plot.Point `json:"points"`
+
+	// Notes are the moments the user tagged while the chart was recorded
+	Notes []stats.Event `json:"notes,omitempty"`
+}
+
+// history returns a copy of the chart series for the battery
+func (c *viewChart) history(battery int) ChartHistory {
+	return ChartHistory{
+		Battery: battery,
+		Chart:   c.spec.Name,
+		Unit:    c.chart.unit,
+		Points:  c.chart.data.Points(),
+	}
+}
+
+// factor returns the factor from base values to the display unit, NaN for a
+// unit relative to an unknown design capacity
+func (c *viewChart) factor() float64 {
+	if !c.perDesign {
+		return c.scale
+	}
+	if c.design <= 0 {
+		return math.NaN()
+	}
+	return c.scale / c.design
+}
+
+// gradient returns the colors of the chart points from gradient stops running
+// from critical to excellent, by the severity of their base value
+func (c *viewChart) gradient(stops []tcell.Color, severity func(value float64) float64) func(value float64) string {
+	return func(value float64) string {
+		return hexColor(gradientColor(stops, 1-severity((value-c.offset)/c.factor())))
+	}
+}
+
+// add records a battery reading in the chart. Units relative to the design
+// capacity keep the last one known and record gaps until there is one.
+func (c *viewChart) add(info *battery.Info) {
+	if info.Design > 0 {
+		c.design = info.Design
+	}
+	factor := c.factor()
+	// Readings are plotted at their own time, so a replay follows the
+	// recorded clock
+	at := info.UpdatedAt
+	if at.IsZero() {
+		at = time.Now()
+	}
+	c.chart.AddValueAt(at, c.spec.Value(info)*factor+c.offset)
+	for _, overlay := range c.overlays {
+		c.chart.AddSeriesValueAt(overlay.Name, at, overlay.Value(info)*factor+c.offset)
+	}
+}
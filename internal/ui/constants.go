@@ -1,5 +1,11 @@
 package ui
 
+import (
+	"time"
+
+	"github.com/xsikor/go-battop/pkg/plot"
+)
+
 // Chart dimensions
 const (
 	// DefaultChartWidth is the default width for charts
@@ -11,24 +17,227 @@ const (
 	// MaxChartDataPoints is the maximum number of data points to store
 	MaxChartDataPoints = 120
 
-	// ChartHeightReserve is space reserved for title, x-axis, and time labels
-	ChartHeightReserve = 4
+	// MaxHealthChartDataPoints is the maximum number of daily health readings to chart
+	MaxHealthChartDataPoints = 730
 
-	// YAxisLabelWidth is the width reserved for Y-axis labels
-	YAxisLabelWidth = 11
+	// ChartHeightReserve is space reserved for title, x-axis, time labels and statistics
+	ChartHeightReserve = 5
 
 	// MinChartHeight is the minimum height for a chart
 	MinChartHeight = 3
+
+	// MinChartColumnWidth is the minimum chart width in the columns layout
+	MinChartColumnWidth = 40
+
+	// ChartColumnGap is the space between charts in the columns layout
+	ChartColumnGap = 2
+
+	// GradientPowerLimit is the power in mW drawn red in gradient charts
+	GradientPowerLimit = 45000
+
+	// GradientTemperatureLow and GradientTemperatureHigh are the battery
+	// temperatures in °C drawn green and red in gradient charts
+	GradientTemperatureLow  = 25
+	GradientTemperatureHigh = 50
+
+	// GoalComfortRatio is the share of the goal's power budget a draw stays
+	// below to be shown as comfortably within it
+	GoalComfortRatio = 0.8
+
+	// GoalGradientSpan is the multiple of the goal's power budget drawn red
+	// in gradient power charts
+	GoalGradientSpan = 2
+
+	// GoalBudgetLabel labels the power budget line of the power chart
+	GoalBudgetLabel = "budget"
+
+	// TimelineLabelWidth is the width of the hour labels in the power timeline
+	TimelineLabelWidth = 6
+)
+
+// ChartTiers are the averaged histories kept next to the raw chart points,
+// plotted at the zoom levels after the live one: an hour of 10s averages and
+// 12 hours of 1m averages
+var ChartTiers = []plot.Tier{
+	{Step: 10 * time.Second, Capacity: 360},
+	{Step: time.Minute, Capacity: 720},
+}
+
+// zoomLabel returns the name of a zoom level
+func zoomLabel(level int) string {
+	if level <= 0 || level > len(ChartTiers) {
+		return "live"
+	}
+	return ChartTiers[level-1].Step.String() + " avg"
+}
+
+// SuspendGapThreshold is the shortest interruption of the readings, e.g. by
+// a suspend, that charts show as a gap instead of connecting across it
+const SuspendGapThreshold = 30 * time.Second
+
+// ReducedMotionInterval is the minimum time between visual updates in the
+// reduced-motion mode; readings are still sampled at every tick
+const ReducedMotionInterval = 5 * time.Second
+
+// ChartLayout selects how the chart set arranges its charts
+type ChartLayout string
+
+// Chart layouts
+const (
+	// ChartLayoutStacked stacks charts vertically at full width
+	ChartLayoutStacked ChartLayout = "stacked"
+
+	// ChartLayoutColumns arranges charts in a grid of columns
+	ChartLayoutColumns ChartLayout = "columns"
+)
+
+// ViewLayout selects how a battery view arranges the info panel, the gauges
+// and the charts
+type ViewLayout string
+
+// View layouts
+const (
+	// ViewLayoutHorizontal shows the info panel and gauges left of the charts
+	ViewLayoutHorizontal ViewLayout = "horizontal"
+
+	// ViewLayoutVertical stacks the info panel and gauges above the charts
+	ViewLayoutVertical ViewLayout = "vertical"
+
+	// ViewLayoutCompact shows only the gauges and one selected chart
+	ViewLayoutCompact ViewLayout = "compact"
 )
 
+// Terminal width breakpoints of the view layouts
+const (
+	// HorizontalLayoutWidth is the narrowest terminal the info panel is
+	// shown next to the charts in
+	HorizontalLayoutWidth = 100
+
+	// VerticalLayoutWidth is the narrowest terminal the info panel is shown
+	// above the charts in; narrower ones get the compact layout
+	VerticalLayoutWidth = 50
+)
+
+// layoutForWidth returns the view layout for a terminal width
+func layoutForWidth(width int) ViewLayout {
+	switch {
+	case width >= HorizontalLayoutWidth:
+		return ViewLayoutHorizontal
+	case width >= VerticalLayoutWidth:
+		return ViewLayoutVertical
+	default:
+		return ViewLayoutCompact
+	}
+}
+
+// ChargeBasis selects what the charge percentage is relative to
+type ChargeBasis string
+
+// Charge bases
+const (
+	// ChargeBasisFull shows charge as a percentage of the last full charge
+	ChargeBasisFull ChargeBasis = "full"
+
+	// ChargeBasisDesign shows charge as a percentage of the design capacity
+	ChargeBasisDesign ChargeBasis = "design"
+)
+
+// PanelMode selects how the left info panel is sized
+type PanelMode string
+
+// Panel modes
+const (
+	// PanelProportional sizes the panels by a left:right ratio
+	PanelProportional PanelMode = "proportional"
+
+	// PanelFixed gives the left panel a fixed number of columns
+	PanelFixed PanelMode = "fixed"
+)
+
+// PanelSize configures the width of the left info panel
+type PanelSize struct {
+	// Mode selects proportional or fixed sizing
+	Mode PanelMode
+
+	// Left and Right are the panel proportions in proportional mode
+	Left  int
+	Right int
+
+	// Width is the left panel width in columns in fixed mode
+	Width int
+}
+
+// DefaultPanelSize gives the left panel 20% of the width
+var DefaultPanelSize = PanelSize{Mode: PanelProportional, Left: 1, Right: 4, Width: 40}
+
+// Page names
+const (
+	// PageBattery is the name prefix of the per-battery pages
+	PageBattery = "battery"
+
+	// PageHealth is the health history page
+	PageHealth = "health"
+
+	// PageTimeline is the power timeline page
+	PageTimeline = "timeline"
+
+	// PagePeripherals is the peripheral batteries page
+	PagePeripherals = "peripherals"
+
+	// PageBreakdown is the RAPL power breakdown page
+	PageBreakdown = "breakdown"
+
+	// PageConsumers is the top consumers (per-process power) page
+	PageConsumers = "consumers"
+
+	// PageEventLog is the event log page
+	PageEventLog = "events"
+
+	// PageReservePrompt is the overlay asking for an unplugged period
+	PageReservePrompt = "reserve-prompt"
+
+	// PageGoalPrompt is the overlay asking for a battery life goal
+	PageGoalPrompt = "goal-prompt"
+
+	// PageNotePrompt is the overlay asking for a note on the current moment
+	PageNotePrompt = "note-prompt"
+
+	// PageSearchPrompt is the overlay searching the event log
+	PageSearchPrompt = "search-prompt"
+)
+
+// Unavailable is shown in place of values the battery doesn't report
+const Unavailable = "n/a"
+
 // Progress bar dimensions
 const (
 	// ProgressBarWidth is the default width for progress bars
 	ProgressBarWidth = 20
+
+	// SparklineWidth is the number of power samples in the power gauge sparkline
+	SparklineWidth = 16
 )
 
 // Time formatting
 const (
 	// TimeFormat is the format for displaying time
 	TimeFormat = "15:04:05"
+
+	// DateFormat is the format for displaying dates
+	DateFormat = "2006-01-02"
+)
+
+// EstimateMode selects which time-to-empty/full estimates are displayed
+type EstimateMode string
+
+// Time estimate modes
+const (
+	// EstimateSmoothed uses the moving-average charge rate
+	EstimateSmoothed EstimateMode = "smoothed"
+
+	// EstimateInstant uses the instantaneous charge rate
+	EstimateInstant EstimateMode = "instant"
+
+	// EstimateBoth shows the smoothed estimate with the instant one alongside
+	EstimateBoth EstimateMode = "both"
 )
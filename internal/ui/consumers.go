@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/xsikor/go-battop/internal/battery"
+	"github.com/xsikor/go-battop/internal/format"
+)
+
+// ConsumerCount is the number of processes listed on the top consumers page
+const ConsumerCount = 15
+
+// ConsumersView ranks processes by their estimated power, their share of the
+// busy CPU time applied to the CPU power
+type ConsumersView struct {
+	root      *tview.TextView
+	read      func(cpuPower float64) ([]battery.ProcessPower, error)
+	theme     *Theme
+	format    format.Formatter
+	processes []battery.ProcessPower
+	basis     string
+	sampled   bool
+	err       error
+}
+
+// NewConsumersView creates a new top consumers view reading processes with the given function
+func NewConsumersView(read func(cpuPower float64) ([]battery.ProcessPower, error), theme *Theme, formatter format.Formatter) *ConsumersView {
+	v := &ConsumersView{
+		root:   tview.NewTextView(),
+		read:   read,
+		theme:  theme,
+		format: formatter,
+	}
+	v.root.SetDynamicColors(true).SetBackgroundColor(tcell.ColorDefault)
+	return v
+}
+
+// SetTheme sets the theme used for the next update
+func (v *ConsumersView) SetTheme(theme *Theme) {
+	v.theme = theme
+}
+
+// SetFormatter sets the formatter used for the next update
+func (v *ConsumersView) SetFormatter(formatter format.Formatter) {
+	v.format = formatter
+}
+
+// GetRoot returns the root UI element
+func (v *ConsumersView) GetRoot() tview.Primitive {
+	return v.root
+}
+
+// Sample reads the CPU time of every process and splits the CPU power among
+// them. The CPU power is the RAPL package power when rapl is true and the
+// battery discharge otherwise. It runs on every tick, also while the page is hidden.
+func (v *ConsumersView) Sample(cpuPower float64, rapl bool) {
+	processes, err := v.read(cpuPower)
+	v.err = err
+	if err != nil {
+		slog.Debug("Failed to read process CPU times", "error", err)
+		return
+	}
+
+	v.processes = processes
+	v.sampled = true
+	v.basis = "battery discharge"
+	if rapl {
+		v.basis = "RAPL package power"
+	}
+}
+
+// Update redraws the ranked process list
+func (v *ConsumersView) Update() {
+	var text strings.Builder
+	text.WriteString("[white::b]Top consumers[-::-]\n\n")
+
+	switch {
+	case v.err != nil:
+		fmt.Fprintf(&text, "[gray]Failed to read process CPU times: %v[-]\n", v.err)
+	case !v.sampled:
+		text.WriteString("[gray]Measuring...[-]\n")
+	case len(v.processes) == 0:
+		text.WriteString("[gray]No CPU time used since the last update[-]\n")
+	default:
+		fmt.Fprintf(&text, "[gray]Estimated from each process's share of CPU time and the %s[-]\n\n", v.basis)
+		v.writeProcesses(&text)
+	}
+
+	v.root.SetText(text.String())
+	slog.Debug("Updated top consumers view", "processes", len(v.processes))
+}
+
+// writeProcesses writes the table of the top processes
+func (v *ConsumersView) writeProcesses(text *strings.Builder) {
+	processes := v.processes[:min(len(v.processes), ConsumerCount)]
+
+	nameWidth, unitWidth := len("Process"), len("Unit")
+	for _, process := range processes {
+		nameWidth = max(nameWidth, len(process.Name))
+		unitWidth = max(unitWidth, len(process.Unit))
+	}
+
+	fmt.Fprintf(text, "[cyan]%7s  %-*s  %-*s  %7s  %9s[-]\n", "PID", nameWidth, "Process", unitWidth, "Unit", "CPU", "Power")
+	for _, process := range processes {
+		power := Unavailable
+		if process.Power > 0 {
+			power = v.format.Power(process.Power)
+		}
+		fmt.Fprintf(text, "%7d  %-*s  [gray]%-*s[-]  %7s  %9s\n", process.PID, nameWidth, tview.Escape(process.Name),
+			unitWidth, process.Unit, v.format.Percent(process.CPU), power)
+	}
+}
@@ -0,0 +1,356 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rivo/tview"
+	"github.com/xsikor/go-battop/internal/battery"
+	"github.com/xsikor/go-battop/internal/format"
+	"github.com/xsikor/go-battop/internal/stats"
+	"github.com/xsikor/go-battop/internal/store"
+)
+
+// testConfig is a UI configuration with the defaults of the command line flags
+type testConfig struct {
+	compact bool
+	basis   ChargeBasis
+	mode    EstimateMode
+	tariff  stats.Tariff
+}
+
+func (c testConfig) Formatter() format.Formatter           { return format.New(format.UnitsHuman) }
+func (c testConfig) EstimateMode() EstimateMode            { return c.mode }
+func (c testConfig) Theme() string                         { return DefaultThemeName }
+func (c testConfig) ChartOptions(int, string) ChartOptions { return ChartOptions{} }
+func (c testConfig) ChartLayout() ChartLayout              { return ChartLayoutStacked }
+func (c testConfig) PanelSize() PanelSize                  { return DefaultPanelSize }
+func (c testConfig) CompactLayout() bool                   { return c.compact }
+func (c testConfig) ChargeTargetPercent() float64          { return 80 }
+func (c testConfig) ChargeBasis() ChargeBasis              { return c.basis }
+func (c testConfig) WarmupSamples() int                    { return 0 }
+func (c testConfig) ReducedMotion() bool                   { return false }
+func (c testConfig) BatteryIcon() bool                     { return true }
+func (c testConfig) Muted(time.Time) bool                  { return false }
+func (c testConfig) NoColor() bool                         { return false }
+func (c testConfig) EnergyTariff() stats.Tariff            { return c.tariff }
+
+// testSource serves fixed readings
+type testSource struct {
+	infos  []*battery.Info
+	source battery.PowerSource
+}
+
+func (s *testSource) Update() error                    { return nil }
+func (s *testSource) GetAll() ([]*battery.Info, error) { return s.infos, nil }
+func (s *testSource) PowerSource() battery.PowerSource { return s.source }
+func (s *testSource) Count() int                       { return len(s.infos) }
+func (s *testSource) Get(index int) (*battery.Info, error) {
+	if index < 0 || index >= len(s.infos) {
+		return nil, fmt.Errorf("battery %d not found", index)
+	}
+	return s.infos[index], nil
+}
+
+// degradation is one way a platform falls short of a full battery reading
+type degradation struct {
+	name  string
+	apply func(info *battery.Info)
+}
+
+// degradations lists the missing capabilities combined by the matrix
+var degradations = []degradation{
+	{"no temperature", func(info *battery.Info) {
+		info.Temperature = 0
+		info.Capabilities.HasTemperature = false
+	}},
+	{"no cycles", func(info *battery.Info) {
+		info.CycleCount = 0
+		info.Capabilities.HasCycles = false
+	}},
+	{"percent only", func(info *battery.Info) {
+		// Only a charge level in nominal units, as some peripherals and UPSes report
+		info.Current, info.Full = info.ChargePercent(), 100
+		info.Design, info.DesignVoltage, info.Voltage = 0, 0, 0
+		info.ChargeRate, info.SmoothedChargeRate, info.NetChargeRate = 0, 0, 0
+		info.Technology, info.Model, info.Manufacturer, info.Serial = "", "", "", ""
+		info.Capabilities.HasExtendedStats = false
+	}},
+	{"zero design", func(info *battery.Info) {
+		info.Design = 0
+	}},
+}
+
+// fullInfo returns a reading with every optional value present
+func fullInfo(state battery.State) *battery.Info {
+	rate := -9500.0
+	if state == battery.StateCharging {
+		rate = 30000
+	}
+	return &battery.Info{
+		State:              state,
+		Current:            36000,
+		Full:               48000,
+		Design:             57000,
+		ChargeRate:         rate,
+		SmoothedChargeRate: rate,
+		Voltage:            11.9,
+		DesignVoltage:      11.55,
+		CycleCount:         210,
+		Technology:         "Li-poly",
+		Model:              "5B10W13930",
+		Manufacturer:       "SMP",
+		Serial:             "1234",
+		Temperature:        31.5,
+		ChargeLimit:        &battery.ChargeLimit{Percent: 80, Mode: battery.ChargeLimitThreshold},
+		Capabilities: battery.Capabilities{
+			HasExtendedStats: true,
+			HasTemperature:   true,
+			HasCycles:        true,
+		},
+		UpdatedAt: time.Now(),
+	}
+}
+
+// degradedCase is one combination of the matrix
+type degradedCase struct {
+	name string
+	info *battery.Info
+}
+
+// degradationMatrix returns every combination of degradations for every state
+func degradationMatrix() []degradedCase {
+	states := []battery.State{
+		battery.StateDischarging, battery.StateCharging, battery.StateFull, battery.StateUnknown,
+		battery.StateOnBattery, battery.StatePendingCharge,
+	}
+
+	var cases []degradedCase
+	for _, state := range states {
+		for mask := 0; mask < 1<<len(degradations); mask++ {
+			info := fullInfo(state)
+			names := []string{state.String()}
+			for i, d := range degradations {
+				if mask&(1<<i) != 0 {
+					d.apply(info)
+					names = append(names, d.name)
+				}
+			}
+			cases = append(cases, degradedCase{name: strings.Join(names, "/"), info: info})
+		}
+	}
+	return cases
+}
+
+// plainText returns the text of a text view without color tags
+func plainText(view *tview.TextView) string {
+	return view.GetText(true)
+}
+
+// negativeDuration matches a negative hh:mm duration
+var negativeDuration = regexp.MustCompile(`-\d+:\d\d`)
+
+// assertSensible fails when rendered text shows broken values instead of placeholders
+func assertSensible(t *testing.T, panel, text string) {
+	t.Helper()
+	for _, broken := range []string{"NaN", "Inf", "%!", "Loading"} {
+		if strings.Contains(text, broken) {
+			t.Errorf("%s shows %q:\n%s", panel, broken, text)
+		}
+	}
+	if negativeDuration.MatchString(text) {
+		t.Errorf("%s shows a negative duration:\n%s", panel, text)
+	}
+}
+
+func TestViewDegradationMatrix(t *testing.T) {
+	for _, tc := range degradationMatrix() {
+		for _, config := range []testConfig{
+			{basis: ChargeBasisFull, mode: EstimateSmoothed},
+			{basis: ChargeBasisDesign, mode: EstimateBoth, compact: true, tariff: stats.Tariff{Price: 0.3, Currency: "EUR", Carbon: 400}},
+		} {
+			t.Run(fmt.Sprintf("%s/compact=%v", tc.name, config.compact), func(t *testing.T) {
+				info := tc.info
+				view := NewView(0, config, config.Formatter())
+				view.SetPowerSource(battery.PowerSource{Detected: true, OnAC: info.State.Base() != battery.StateDischarging})
+				// The compact configuration also covers the expanded details
+				view.SetDetails(config.compact)
+				// and a battery life goal
+				if config.compact {
+					view.SetGoal(&stats.Goal{Until: time.Now().Add(3 * time.Hour)})
+				}
+				view.Ingest(info)
+				view.Render()
+
+				panel := plainText(view.infoText)
+				assertSensible(t, "info panel", panel)
+				for _, gauge := range []*tview.TextView{view.chargeGauge, view.powerGauge, view.healthGauge} {
+					assertSensible(t, "gauge", plainText(gauge))
+				}
+				assertSensible(t, "charts", plainText(view.chartArea))
+				assertSensible(t, "battery icon", plainText(view.icon))
+
+				if strings.Contains(panel, "Temp:") != info.Capabilities.HasTemperature {
+					t.Errorf("temperature shown = %v, reported = %v", !info.Capabilities.HasTemperature, info.Capabilities.HasTemperature)
+				}
+				if strings.Contains(panel, "Cycles:") != info.Capabilities.HasCycles {
+					t.Errorf("cycles shown = %v, reported = %v", !info.Capabilities.HasCycles, info.Capabilities.HasCycles)
+				}
+				if info.Design <= 0 {
+					if strings.Contains(panel, "health") || strings.Contains(panel, "of design") {
+						t.Errorf("health derived without a design capacity:\n%s", panel)
+					}
+					if !strings.Contains(plainText(view.healthGauge), Unavailable) {
+						t.Errorf("health gauge without placeholder: %q", plainText(view.healthGauge))
+					}
+				}
+				if info.Voltage <= 0 && !strings.Contains(panel, "Voltage:   "+Unavailable) {
+					t.Errorf("missing voltage without placeholder:\n%s", panel)
+				}
+				for _, line := range strings.Split(plainText(view.chartArea), "\n") {
+					if strings.HasPrefix(strings.TrimSpace(line), "-") && strings.Contains(line, "V ┤") {
+						t.Errorf("voltage axis below zero: %q", line)
+					}
+				}
+				if info.Technology == "" && !strings.Contains(panel, "Type:      "+Unavailable) {
+					t.Errorf("missing technology without placeholder:\n%s", panel)
+				}
+			})
+		}
+	}
+}
+
+func TestViewEmptyBay(t *testing.T) {
+	config := testConfig{basis: ChargeBasisFull, mode: EstimateSmoothed}
+	view := NewView(1, config, config.Formatter())
+	view.Ingest(&battery.Info{Index: 1, State: battery.StateNotPresent, UpdatedAt: time.Now()})
+	view.Render()
+
+	panel := plainText(view.infoText)
+	assertSensible(t, "info panel", panel)
+	if !strings.Contains(panel, "Bay 2: empty") {
+		t.Errorf("empty bay not shown:\n%s", panel)
+	}
+	for _, gauge := range []*tview.TextView{view.chargeGauge, view.powerGauge, view.healthGauge} {
+		if text := plainText(gauge); !strings.Contains(text, Unavailable) {
+			t.Errorf("gauge without placeholder: %q", text)
+		}
+	}
+	assertSensible(t, "charts", plainText(view.chartArea))
+}
+
+func TestStatuslineDegradationMatrix(t *testing.T) {
+	template := strings.Join(StatuslinePlaceholders, "|")
+	for _, tc := range degradationMatrix() {
+		t.Run(tc.name, func(t *testing.T) {
+			source := &testSource{infos: []*battery.Info{tc.info}, source: battery.PowerSource{Detected: true}}
+			statusline, err := NewStatusline(source, testConfig{mode: EstimateSmoothed})
+			if err != nil {
+				t.Fatal(err)
+			}
+			line, err := statusline.Render(template)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertSensible(t, "statusline", line)
+			if strings.Contains(line, "{") {
+				t.Errorf("unreplaced placeholder in %q", line)
+			}
+			if tc.info.Design <= 0 && strings.Contains(line, "0%|") {
+				t.Errorf("health or design percentage without a design capacity: %q", line)
+			}
+		})
+	}
+}
+
+func TestPageDegradation(t *testing.T) {
+	theme, _ := ThemeByName(DefaultThemeName)
+	formatter := format.New(format.UnitsHuman)
+	st := store.New(t.TempDir())
+
+	for _, tc := range degradationMatrix() {
+		t.Run(tc.name, func(t *testing.T) {
+			health := NewHealthView(stats.LoadHealthHistory(st, tc.info), theme, formatter)
+			if _, err := stats.LoadHealthHistory(st, tc.info).Record(tc.info, time.Now()); err != nil {
+				t.Fatal(err)
+			}
+			health.Update()
+			assertSensible(t, "health page", plainText(health.summary))
+			assertSensible(t, "health chart", plainText(health.chartArea))
+		})
+	}
+
+	timeline := NewTimelineView(stats.NewTimeline(st), theme)
+	timeline.Update()
+	assertSensible(t, "timeline page", plainText(timeline.root))
+
+	log := stats.NewEventLog(stats.ChargeThreshold{Percent: 5, Severity: stats.SeverityCritical}, stats.ChargeThreshold{Percent: 20, Severity: stats.SeverityWarn})
+	events := NewEventLogView(log, theme, formatter)
+	events.Update()
+	assertSensible(t, "event log page", plainText(events.root))
+	for i, tc := range degradationMatrix() {
+		log.Observe([]*battery.Info{tc.info}, battery.PowerSource{Detected: true, OnAC: i%2 == 0}, time.Now())
+	}
+	events.Update()
+	assertSensible(t, "event log page", plainText(events.root))
+	if !strings.Contains(plainText(events.root), "AC adapter") {
+		t.Errorf("event log without the power source changes:\n%s", plainText(events.root))
+	}
+
+	for _, read := range []func() ([]battery.Peripheral, error){
+		func() ([]battery.Peripheral, error) { return nil, fmt.Errorf("upower not found") },
+		func() ([]battery.Peripheral, error) { return nil, nil },
+		func() ([]battery.Peripheral, error) {
+			return []battery.Peripheral{{Name: "Mouse", Kind: "mouse", Percent: 0}, {Name: "", Percent: 100}}, nil
+		},
+	} {
+		peripherals := NewPeripheralsView(read, theme, formatter)
+		peripherals.Update()
+		assertSensible(t, "peripherals page", plainText(peripherals.root))
+	}
+
+	for _, read := range []func(time.Time) ([]battery.PowerDomain, error){
+		func(time.Time) ([]battery.PowerDomain, error) { return nil, fmt.Errorf("permission denied") },
+		func(time.Time) ([]battery.PowerDomain, error) { return nil, nil },
+		func(time.Time) ([]battery.PowerDomain, error) {
+			return []battery.PowerDomain{{Zone: "intel-rapl:0", Name: "package-0"}}, nil
+		},
+		func(time.Time) ([]battery.PowerDomain, error) {
+			return []battery.PowerDomain{
+				{Zone: "intel-rapl:0", Name: "package-0", Power: 6000},
+				{Zone: "intel-rapl:0:0", Name: "core", Power: 3000},
+				{Zone: "intel-rapl:1", Name: "psys", Power: 15000},
+			}, nil
+		},
+	} {
+		breakdown := NewBreakdownView(read, theme, formatter)
+		breakdown.Update()
+		for _, tc := range degradationMatrix() {
+			breakdown.Sample([]*battery.Info{tc.info}, time.Now())
+		}
+		breakdown.Update()
+		assertSensible(t, "power breakdown page", plainText(breakdown.root))
+	}
+
+	for _, read := range []func(float64) ([]battery.ProcessPower, error){
+		func(float64) ([]battery.ProcessPower, error) { return nil, fmt.Errorf("permission denied") },
+		func(float64) ([]battery.ProcessPower, error) { return nil, nil },
+		func(cpuPower float64) ([]battery.ProcessPower, error) {
+			return []battery.ProcessPower{
+				{PID: 4242, Name: "[web]", Unit: "app-firefox.scope", CPU: 12.5, Power: cpuPower * 0.5},
+				{PID: 1, Name: "systemd", CPU: 0.1},
+			}, nil
+		},
+	} {
+		top := NewConsumersView(read, theme, formatter)
+		top.Update()
+		for _, tc := range degradationMatrix() {
+			top.Sample(-tc.info.ChargeRate, false)
+			top.Update()
+			assertSensible(t, "top consumers page", plainText(top.root))
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/xsikor/go-battop/internal/format"
+	"github.com/xsikor/go-battop/internal/stats"
+	"github.com/xsikor/go-battop/pkg/plot"
+)
+
+// eventMarks are the marks of the event kinds in the log and on the charts
+var eventMarks = map[stats.EventKind]rune{
+	stats.EventPower:     'A',
+	stats.EventState:     'S',
+	stats.EventThreshold: 'T',
+	stats.EventSuspend:   'Z',
+	stats.EventNote:      'N',
+}
+
+// eventMark returns the mark of an event kind
+func eventMark(kind stats.EventKind) rune {
+	if mark, ok := eventMarks[kind]; ok {
+		return mark
+	}
+	return '•'
+}
+
+// eventSeverities are the severity filters in the order f cycles through them
+var eventSeverities = []stats.Severity{stats.SeverityInfo, stats.SeverityWarn, stats.SeverityCritical}
+
+// EventLogView lists the logged events, newest first
+type EventLogView struct {
+	root   *tview.TextView
+	log    *stats.EventLog
+	theme  *Theme
+	format format.Formatter
+
+	// query and severity filter the listed events
+	query    string
+	severity stats.Severity
+}
+
+// NewEventLogView creates a new event log view
+func NewEventLogView(log *stats.EventLog, theme *Theme, formatter format.Formatter) *EventLogView {
+	v := &EventLogView{
+		root:     tview.NewTextView(),
+		log:      log,
+		theme:    theme,
+		format:   formatter,
+		severity: stats.SeverityInfo,
+	}
+	v.root.SetDynamicColors(true).SetBackgroundColor(tcell.ColorDefault)
+	return v
+}
+
+// SetTheme sets the theme used for the next update
+func (v *EventLogView) SetTheme(theme *Theme) {
+	v.theme = theme
+}
+
+// SetFormatter sets the formatter used for the next update
+func (v *EventLogView) SetFormatter(formatter format.Formatter) {
+	v.format = formatter
+}
+
+// Query returns the text the listed events are searched for
+func (v *EventLogView) Query() string {
+	return v.query
+}
+
+// SetQuery lists only the events whose message contains the text, ignoring
+// case; empty text lists all
+func (v *EventLogView) SetQuery(query string) {
+	v.query = query
+}
+
+// CycleSeverity switches to the next minimum severity of the listed events
+// and returns it
+func (v *EventLogView) CycleSeverity() stats.Severity {
+	for idx, severity := range eventSeverities {
+		if severity == v.severity {
+			v.severity = eventSeverities[(idx+1)%len(eventSeverities)]
+			return v.severity
+		}
+	}
+	v.severity = eventSeverities[0]
+	return v.severity
+}
+
+// GetRoot returns the root UI element
+func (v *EventLogView) GetRoot() tview.Primitive {
+	return v.root
+}
+
+// Update redraws the event list
+func (v *EventLogView) Update() {
+	events := v.log.Events()
+
+	var text strings.Builder
+	text.WriteString("[white::b]Event log[-::-]\n")
+	text.WriteString("[gray]A power source, S state, T threshold, Z suspend, N note (a to add); marked on the charts. ↑↓ scroll, / search, f severity[-]\n")
+	fmt.Fprintf(&text, "[gray]Showing %s and above", v.severity)
+	if v.query != "" {
+		fmt.Fprintf(&text, " matching \"[white]%s[gray]\"", tview.Escape(v.query))
+	}
+	text.WriteString("[-]\n\n")
+
+	shown := 0
+	for i := len(events) - 1; i >= 0; i-- {
+		event := events[i]
+		if !event.Matches(v.query, v.severity) {
+			continue
+		}
+		fmt.Fprintf(&text, "[gray]%s[-]  [%s]%c[-]  %s\n", v.format.Timestamp(event.Time), v.eventColor(event),
+			eventMark(event.Kind), tview.Escape(event.Message))
+		shown++
+	}
+	switch {
+	case len(events) == 0:
+		text.WriteString("[gray]No events yet[-]\n")
+	case shown == 0:
+		text.WriteString("[gray]No matching events[-]\n")
+	}
+
+	v.root.SetText(text.String())
+	slog.Debug("Updated event log view", "events", len(events), "shown", shown)
+}
+
+// eventColor returns the color of an event's mark
+func (v *EventLogView) eventColor(event stats.Event) string {
+	switch event.Severity {
+	case stats.SeverityCritical:
+		return v.theme.Critical
+	case stats.SeverityWarn:
+		return v.theme.Warning
+	}
+	switch event.Kind {
+	case stats.EventThreshold:
+		return v.theme.Warning
+	case stats.EventSuspend:
+		return "gray"
+	case stats.EventNote:
+		return "white"
+	default:
+		return v.theme.Excellent
+	}
+}
+
+// eventAnnotations returns the chart annotations of the events of a battery
+// and of the whole system
+func eventAnnotations(events []stats.Event, index int) []plot.Annotation {
+	var annotations []plot.Annotation
+	for _, event := range events {
+		if event.Battery != index && event.Battery >= 0 {
+			continue
+		}
+		style := plot.Style{Color: "gray", Dim: true}
+		if event.Kind == stats.EventNote {
+			// Notes stand out from the detected events
+			style = plot.Style{Color: "white", Bold: true}
+		}
+		annotations = append(annotations, plot.Annotation{Time: event.Time, Mark: eventMark(event.Kind), Style: style})
+	}
+	return annotations
+}
+
+// eventNotes returns the notes among the events
+func eventNotes(events []stats.Event) []stats.Event {
+	var notes []stats.Event
+	for _, event := range events {
+		if event.Kind == stats.EventNote {
+			notes = append(notes, event)
+		}
+	}
+	return notes
+}
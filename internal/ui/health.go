@@ -0,0 +1,117 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/xsikor/go-battop/internal/format"
+	"github.com/xsikor/go-battop/internal/stats"
+)
+
+// HealthView shows the capacity fade of a battery over its recorded history
+type HealthView struct {
+	root      *tview.Flex
+	summary   *tview.TextView
+	chartArea *tview.TextView
+
+	history *stats.HealthHistory
+	theme   *Theme
+	format  format.Formatter
+	chart   *Chart
+}
+
+// NewHealthView creates a new health history view
+func NewHealthView(history *stats.HealthHistory, theme *Theme, formatter format.Formatter) *HealthView {
+	v := &HealthView{
+		summary:   tview.NewTextView(),
+		chartArea: tview.NewTextView(),
+		history:   history,
+		theme:     theme,
+		format:    formatter,
+		chart:     NewChart("Health", MaxHealthChartDataPoints, "%", "green"),
+	}
+	v.chart.SetTimeFormat(DateFormat)
+
+	v.summary.SetDynamicColors(true).SetBackgroundColor(tcell.ColorDefault)
+	v.chartArea.SetDynamicColors(true).SetBackgroundColor(tcell.ColorDefault)
+
+	v.root = tview.NewFlex().SetDirection(tview.FlexRow)
+	v.root.AddItem(v.summary, 4, 0, false)
+	v.root.AddItem(v.chartArea, 0, 1, true)
+
+	return v
+}
+
+// SetTheme sets the theme used for the next update
+func (v *HealthView) SetTheme(theme *Theme) {
+	v.theme = theme
+}
+
+// GetRoot returns the root UI element
+func (v *HealthView) GetRoot() tview.Primitive {
+	return v.root
+}
+
+// Update rebuilds the chart and summary from the recorded history
+func (v *HealthView) Update() {
+	records := v.history.Entries()
+
+	v.chart.Reset()
+	for _, record := range records {
+		v.chart.AddValueAt(record.Time(), record.Health())
+	}
+
+	v.updateSummary(records)
+
+	width, height := DefaultChartWidth, DefaultChartHeight
+	if _, _, w, h := v.chartArea.GetInnerRect(); w > 0 && h > 0 {
+		width, height = w, h
+	}
+	v.chart.SetSize(width, height)
+	v.chartArea.SetText(v.chart.Render())
+
+	slog.Debug("Updated health history view", "records", len(records))
+}
+
+// updateSummary updates the text above the chart
+func (v *HealthView) updateSummary(records []stats.HealthRecord) {
+	var text strings.Builder
+	text.WriteString("[white::b]Health history[-::-]\n")
+
+	if len(records) == 0 {
+		text.WriteString("[gray]No capacity readings recorded yet[-]\n")
+		v.summary.SetText(text.String())
+		return
+	}
+
+	first, last := records[0], records[len(records)-1]
+	fmt.Fprintf(&text, "[yellow]Recorded:[-] %d days since %s\n", len(records), first.Date)
+	level := LevelByThreshold(last.Health(), ColorThresholdsHealth)
+	fmt.Fprintf(&text, "[yellow]Health:[-] %s → %s (%+.1f%%)\n",
+		v.format.Percent(first.Health()), v.theme.Label(level, v.format.Percent(last.Health())), last.Health()-first.Health())
+
+	fmt.Fprintf(&text, "[yellow]%.0f%% health:[-] %s\n", stats.HealthThreshold, v.projection(last))
+	v.summary.SetText(text.String())
+}
+
+// projection describes when health is expected to reach the threshold
+func (v *HealthView) projection(last stats.HealthRecord) string {
+	if last.Health() <= stats.HealthThreshold {
+		return v.theme.Label(LevelCritical, "reached")
+	}
+
+	date, ok := v.history.Projection(stats.HealthThreshold)
+	if !ok {
+		return "[gray]not enough data to project[-]"
+	}
+	if !date.After(time.Now()) {
+		return v.theme.Label(LevelWarning, "projected imminently")
+	}
+	confidence := v.history.ProjectionConfidence()
+	return fmt.Sprintf("projected %s (in %s, %s confidence)", date.Format(DateFormat),
+		confidence.Mark(formatChartDuration(time.Until(date))), confidence)
+}
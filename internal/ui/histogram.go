@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// histogramTitle is the chart area title of the discharge power histogram
+const histogramTitle = " Discharge Power Histogram "
+
+// HistogramQuantile is the fraction of the discharge time the peak power of
+// the histogram summary is above
+const HistogramQuantile = 0.95
+
+// renderHistogram renders the time spent at each discharge power during the
+// session as horizontal bars, with the most common range highlighted
+func (v *View) renderHistogram(text *strings.Builder) {
+	v.renderChartTitle(text, histogramTitle)
+
+	histogram := v.stats.Discharge
+	total := histogram.Total()
+	if total <= 0 {
+		text.WriteString(" [gray]No discharge recorded yet[-]")
+		return
+	}
+
+	// Title, blank line and summary
+	bins := histogram.Bins(max(v.chartHeight-3, 1))
+	typical := 0
+	for i, bin := range bins {
+		if bin.Duration > bins[typical].Duration {
+			typical = i
+		}
+	}
+
+	labelWidth := 0
+	for _, bin := range bins {
+		labelWidth = max(labelWidth, len(v.format.Power(bin.Min)))
+	}
+	const suffixWidth = 12
+	barWidth := max(v.chartWidth-labelWidth-3-suffixWidth, 1)
+
+	for i, bin := range bins {
+		style := v.theme.Warning
+		if i == typical {
+			style += "::b"
+		}
+		share := float64(bin.Duration) / float64(total)
+		bar := CreateBar(float64(bin.Duration)/float64(bins[typical].Duration), barWidth)
+		fmt.Fprintf(text, " %*s [gray]│[-][%s]%-*s[-:-:-] %6s %3.0f%%\n",
+			labelWidth, v.format.Power(bin.Min), style, barWidth, bar,
+			formatChartDuration(bin.Duration), share*100)
+	}
+
+	fmt.Fprintf(text, "\n [gray]typical[-] %s–%s  [gray]%.0f%% below[-] %s  [gray]over[-] %s",
+		v.format.Power(bins[typical].Min), v.format.Power(bins[typical].Max),
+		HistogramQuantile*100, v.format.Power(histogram.Quantile(HistogramQuantile)),
+		formatChartDuration(total.Round(time.Second)))
+}
@@ -3,41 +3,112 @@ package ui
 import (
 	"fmt"
 	"log/slog"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 	"github.com/xsikor/go-battop/internal/battery"
 	"github.com/xsikor/go-battop/internal/errors"
+	"github.com/xsikor/go-battop/internal/format"
+	"github.com/xsikor/go-battop/internal/stats"
+	"github.com/xsikor/go-battop/pkg/plot"
 )
 
-// Config provides access to UI-related configuration settings
+// UnitsConfig provides the settings for how values are formatted and estimated
+type UnitsConfig interface {
+	Formatter() format.Formatter
+	EstimateMode() EstimateMode
+	ChargeBasis() ChargeBasis
+	WarmupSamples() int
+	EnergyTariff() stats.Tariff
+}
+
+// ThemeConfig provides the settings for how the UI looks
+type ThemeConfig interface {
+	Theme() string
+	NoColor() bool
+	ReducedMotion() bool
+	BatteryIcon() bool
+}
+
+// AlertConfig provides the settings for what the UI calls attention to
+type AlertConfig interface {
+	ChargeTargetPercent() float64
+	Muted(now time.Time) bool
+}
+
+// LayoutConfig provides the settings for how the panels and charts are arranged
+type LayoutConfig interface {
+	ChartOptions(index int, chart string) ChartOptions
+	ChartLayout() ChartLayout
+	PanelSize() PanelSize
+	CompactLayout() bool
+}
+
+// Config provides access to all UI-related configuration settings
 type Config interface {
-	FormatPower(mW float64) string
-	FormatEnergy(mWh float64) string
-	FormatVoltage(v float64) string
+	UnitsConfig
+	ThemeConfig
+	AlertConfig
+	LayoutConfig
 }
 
 // Interface manages the terminal-based battery monitoring UI
 type Interface struct {
-	root    *tview.Flex
-	view    *View
-	manager *battery.Manager
-	config  Config
+	root     *tview.Flex
+	pages    *tview.Pages
+	footer   *tview.Flex
+	status   *tview.TextView
+	hints    *tview.TextView
+	toast    *tview.TextView
+	views    []*View
+	active   int
+	theme    *Theme
+	format   format.Formatter
+	health   *HealthView
+	timeline *TimelineView
+	devices  *PeripheralsView
+	power    *BreakdownView
+	top      *ConsumersView
+	events   *EventLogView
+	manager  battery.Source
+	stats    *stats.Tracker
+	config   Config
+	rendered time.Time
+	zoom     int
+	scale    plot.Scale
+
+	// inspecting shows the chart cursor, moved with the arrow keys
+	inspecting bool
+
+	// muted shows the quiet hours indicator in the status bar
+	muted bool
+
+	// delay is the update delay shown in the status bar
+	delay time.Duration
 }
 
-// NewInterface creates a new UI interface with the given battery manager and configuration
-func NewInterface(manager *battery.Manager, config Config) (*Interface, error) {
+// NewInterface creates a new UI interface with the given battery source, statistics tracker and configuration
+func NewInterface(manager battery.Source, tracker *stats.Tracker, config Config) (*Interface, error) {
 	if manager == nil {
-		return nil, fmt.Errorf("battery manager is nil")
+		return nil, fmt.Errorf("battery source is nil")
+	}
+	if tracker == nil {
+		return nil, fmt.Errorf("statistics tracker is nil")
 	}
 
 	i := &Interface{
 		manager: manager,
+		stats:   tracker,
 		config:  config,
+		theme:   resolveTheme(config),
+		format:  config.Formatter(),
 	}
 
-	// Initialize first battery only
-	if err := i.initializeBattery(); err != nil {
+	// Initialize one view per battery
+	if err := i.initializeBatteries(); err != nil {
 		return nil, err
 	}
 
@@ -52,8 +123,8 @@ func (i *Interface) GetRoot() tview.Primitive {
 	return i.root
 }
 
-// initializeBattery initializes the first battery view
-func (i *Interface) initializeBattery() error {
+// initializeBatteries creates a view for every battery
+func (i *Interface) initializeBatteries() error {
 	batteries, err := i.manager.GetAll()
 	if err != nil {
 		return fmt.Errorf("failed to get batteries: %w", err)
@@ -63,12 +134,15 @@ func (i *Interface) initializeBattery() error {
 		return errors.ErrNoBatteries
 	}
 
-	// Create a view for the first battery only
-	bat := batteries[0]
-	i.view = NewView(bat.Index, i.config)
-	i.view.Update(bat)
+	i.views = make([]*View, 0, len(batteries))
+	for _, bat := range batteries {
+		view := NewView(bat.Index, i.config, i.format)
+		view.Ingest(bat)
+		i.views = append(i.views, view)
+		slog.Info("Initialized battery view", "index", bat.Index)
+	}
 
-	slog.Info("Initialized battery view", "index", bat.Index)
+	i.renderActive()
 	return nil
 }
 
@@ -77,41 +151,576 @@ func (i *Interface) buildLayout() {
 	// Create main container
 	container := tview.NewFlex().SetDirection(tview.FlexRow)
 
-	// Add the battery view - takes all space except footer
-	container.AddItem(i.view.GetRoot(), 0, 1, true)
+	// Add one page per battery - takes all space except footer
+	i.pages = tview.NewPages()
+	for idx, view := range i.views {
+		i.pages.AddPage(batteryPage(idx), view.GetRoot(), true, idx == i.active)
+	}
+	container.AddItem(i.pages, 0, 1, true)
+
+	// Add the toast box, hidden until a message is shown
+	i.toast = newToast()
+	container.AddItem(i.toast, 0, 0, false)
 
-	// Add help footer
-	helpText := tview.NewTextView()
-	helpText.SetDynamicColors(true)
-	helpText.SetTextAlign(tview.AlignCenter)
-	helpText.SetBackgroundColor(tcell.ColorDefault)
-	helpText.SetText("[gray]Press [yellow]q[gray]/[yellow]ESC[gray] to quit[-]")
-	container.AddItem(helpText, 1, 0, false)
+	// Add the footer: the status bar on the left, the key hints for the
+	// panel in front on the right
+	i.status = tview.NewTextView()
+	i.status.SetDynamicColors(true)
+	i.status.SetBackgroundColor(tcell.ColorDefault)
+	i.hints = tview.NewTextView()
+	i.hints.SetDynamicColors(true)
+	i.hints.SetTextAlign(tview.AlignRight)
+	i.hints.SetBackgroundColor(tcell.ColorDefault)
+	i.footer = tview.NewFlex()
+	i.footer.AddItem(i.status, 0, 0, false)
+	i.footer.AddItem(i.hints, 0, 1, false)
+	i.updateFooter()
+	container.AddItem(i.footer, 1, 0, false)
 
 	i.root = container
 }
 
-// Update updates the UI with latest battery information
+// updateFooter updates the status bar and the key hints, giving the status
+// bar the width it needs so it is never cut off by the hints
+func (i *Interface) updateFooter() {
+	status := i.statusBar(time.Now())
+	i.status.SetText(status)
+	i.footer.ResizeItem(i.status, tview.TaggedStringWidth(status)+1, 0)
+	i.hints.SetText(strings.Join(i.keyHints(), "  ") + "[-]")
+}
+
+// SetDelay shows the update delay in the status bar
+func (i *Interface) SetDelay(delay time.Duration) {
+	i.delay = delay
+	i.updateFooter()
+}
+
+// formatDelay formats an update delay for the status bar (e.g., 250ms, 1.5s, 1m)
+func formatDelay(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	case d < time.Minute:
+		return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+	default:
+		return formatChartDuration(d)
+	}
+}
+
+// tabStripLabel starts the battery tabs in the footer
+const tabStripLabel = "Battery "
+
+// tabStrip returns the clickable battery tabs of the footer, the active one highlighted
+func (i *Interface) tabStrip() string {
+	var strip strings.Builder
+	strip.WriteString("[white]" + tabStripLabel)
+	for tab := range i.views {
+		if tab == i.active && i.config.NoColor() {
+			fmt.Fprintf(&strip, "[::r] %d [::-]", tab+1)
+		} else if tab == i.active {
+			fmt.Fprintf(&strip, "[black:white] %d [-:-]", tab+1)
+		} else {
+			fmt.Fprintf(&strip, "[white] %d ", tab+1)
+		}
+	}
+	return strip.String()
+}
+
+// tabAt returns the battery tab at a column of the status bar
+func (i *Interface) tabAt(x int) (int, bool) {
+	if len(i.views) < 2 {
+		return 0, false
+	}
+	x -= len(tabStripLabel)
+	for tab := range i.views {
+		label := len(fmt.Sprintf(" %d ", tab+1))
+		if x >= 0 && x < label {
+			return tab, true
+		}
+		x -= label
+	}
+	return 0, false
+}
+
+// Click handles a left click at a screen position: a battery tab in the
+// footer switches to its battery, a chart title collapses or expands the chart
+func (i *Interface) Click(x, y int) {
+	left, top, _, _ := i.status.GetInnerRect()
+	if y == top {
+		if tab, ok := i.tabAt(x - left); ok {
+			i.switchTo(tab)
+		}
+		return
+	}
+	if front, _ := i.pages.GetFrontPage(); front != batteryPage(i.active) {
+		return
+	}
+	if i.views[i.active].Click(x, y) {
+		i.renderActive()
+	}
+}
+
+// batteryPage returns the page name of the battery view at a tab position
+func batteryPage(tab int) string {
+	return fmt.Sprintf("%s%d", PageBattery, tab)
+}
+
+// SetChargeReference overlays a named reference curve on the first battery's charge chart
+func (i *Interface) SetChargeReference(name string, reference func(time.Time) (float64, bool)) {
+	i.views[0].SetChargeReference(name, reference)
+}
+
+// SetHealthHistory enables the health history page for the given history
+func (i *Interface) SetHealthHistory(history *stats.HealthHistory) {
+	i.health = NewHealthView(history, i.theme, i.format)
+	i.pages.AddPage(PageHealth, i.health.GetRoot(), true, false)
+}
+
+// ToggleHealthHistory switches between the battery and health history pages
+func (i *Interface) ToggleHealthHistory() {
+	if i.health == nil {
+		return
+	}
+	i.togglePage(PageHealth)
+}
+
+// SetTimeline enables the power timeline page for the given timeline
+func (i *Interface) SetTimeline(timeline *stats.Timeline) {
+	i.timeline = NewTimelineView(timeline, i.theme)
+	i.pages.AddPage(PageTimeline, i.timeline.GetRoot(), true, false)
+}
+
+// ToggleTimeline switches between the battery and power timeline pages
+func (i *Interface) ToggleTimeline() {
+	if i.timeline == nil {
+		return
+	}
+	i.togglePage(PageTimeline)
+}
+
+// SetPeripherals enables the peripherals page reading devices with the given function
+func (i *Interface) SetPeripherals(read func() ([]battery.Peripheral, error)) {
+	i.devices = NewPeripheralsView(read, i.theme, i.format)
+	i.pages.AddPage(PagePeripherals, i.devices.GetRoot(), true, false)
+}
+
+// TogglePeripherals switches between the battery and peripherals pages
+func (i *Interface) TogglePeripherals() {
+	if i.devices == nil {
+		return
+	}
+	i.togglePage(PagePeripherals)
+}
+
+// SetPowerBreakdown enables the power breakdown page reading RAPL domains with the given function
+func (i *Interface) SetPowerBreakdown(read func(time.Time) ([]battery.PowerDomain, error)) {
+	i.power = NewBreakdownView(read, i.theme, i.format)
+	i.pages.AddPage(PageBreakdown, i.power.GetRoot(), true, false)
+}
+
+// TogglePowerBreakdown switches between the battery and power breakdown pages
+func (i *Interface) TogglePowerBreakdown() {
+	if i.power == nil {
+		return
+	}
+	i.togglePage(PageBreakdown)
+}
+
+// SetConsumers enables the top consumers page reading processes with the given function
+func (i *Interface) SetConsumers(read func(cpuPower float64) ([]battery.ProcessPower, error)) {
+	i.top = NewConsumersView(read, i.theme, i.format)
+	i.pages.AddPage(PageConsumers, i.top.GetRoot(), true, false)
+}
+
+// ToggleConsumers switches between the battery and top consumers pages
+func (i *Interface) ToggleConsumers() {
+	if i.top == nil {
+		return
+	}
+	i.togglePage(PageConsumers)
+}
+
+// SetEventLog enables the event log page and marks the events on the charts
+func (i *Interface) SetEventLog(log *stats.EventLog) {
+	i.events = NewEventLogView(log, i.theme, i.format)
+	i.pages.AddPage(PageEventLog, i.events.GetRoot(), true, false)
+	for _, view := range i.views {
+		view.SetEvents(log.Events)
+	}
+}
+
+// ToggleEventLog switches between the battery and event log pages
+func (i *Interface) ToggleEventLog() {
+	if i.events == nil {
+		return
+	}
+	i.togglePage(PageEventLog)
+}
+
+// CycleEventSeverity shows the event log filtered to the next minimum
+// severity and returns it
+func (i *Interface) CycleEventSeverity() stats.Severity {
+	if i.events == nil {
+		return stats.SeverityInfo
+	}
+	severity := i.events.CycleSeverity()
+	if i.frontPage() != PageEventLog {
+		i.togglePage(PageEventLog)
+		return severity
+	}
+	i.events.Update()
+	return severity
+}
+
+// togglePage shows the given page, or returns to the active battery when it is already shown
+func (i *Interface) togglePage(name string) {
+	if i.frontPage() == name {
+		i.switchTo(i.active)
+		return
+	}
+	i.pages.SwitchToPage(name)
+	i.renderFront()
+	i.updateFooter()
+}
+
+// ToggleChart shows or hides the chart at the given position in every battery view
+func (i *Interface) ToggleChart(position int) {
+	for _, view := range i.views {
+		view.ToggleChart(position)
+	}
+	i.renderFront()
+}
+
+// ToggleChartLayout switches the chart layout of every battery view
+func (i *Interface) ToggleChartLayout() {
+	for _, view := range i.views {
+		view.ToggleChartLayout()
+	}
+	i.renderFront()
+}
+
+// CycleZoom switches the charts of every view to the next zoom level: the
+// live values, then the averaged histories of ChartTiers
+func (i *Interface) CycleZoom() {
+	i.setZoom((i.zoom + 1) % (len(ChartTiers) + 1))
+}
+
+// Zoom zooms the charts of every view out by steps levels, or in for negative steps
+func (i *Interface) Zoom(steps int) {
+	i.setZoom(min(max(i.zoom+steps, 0), len(ChartTiers)))
+}
+
+// setZoom switches the charts of every view to a zoom level
+func (i *Interface) setZoom(level int) {
+	i.zoom = level
+	for _, view := range i.views {
+		view.SetZoom(i.zoom)
+	}
+	i.updateFooter()
+	i.renderFront()
+}
+
+// ToggleInspect shows or hides the inspection cursor on the charts of every view
+func (i *Interface) ToggleInspect() {
+	i.inspecting = !i.inspecting
+	for _, view := range i.views {
+		view.SetInspecting(i.inspecting)
+	}
+	i.updateFooter()
+	i.renderFront()
+}
+
+// Inspecting reports whether the inspection cursor is shown
+func (i *Interface) Inspecting() bool {
+	return i.inspecting
+}
+
+// MoveCursor moves the inspection cursor of the active view by steps points
+func (i *Interface) MoveCursor(steps int) {
+	i.views[i.active].MoveCursor(steps)
+	i.renderFront()
+}
+
+// CycleScale switches the charts of every view to the next value axis
+// scale, replacing the configured scales, and returns its name
+func (i *Interface) CycleScale() string {
+	i.scale = i.scale.Next()
+	for _, view := range i.views {
+		view.SetAxisScale(i.scale)
+	}
+	i.renderFront()
+	return i.scale.String()
+}
+
+// CycleTheme switches every view to the next installed theme and returns its name
+func (i *Interface) CycleTheme() string {
+	i.setTheme(NextTheme(i.theme))
+	i.renderFront()
+	return i.theme.Name
+}
+
+// setTheme switches every view to a theme
+func (i *Interface) setTheme(theme *Theme) {
+	i.theme = theme
+	for _, view := range i.views {
+		view.SetTheme(i.theme)
+	}
+	if i.health != nil {
+		i.health.SetTheme(i.theme)
+	}
+	if i.timeline != nil {
+		i.timeline.SetTheme(i.theme)
+	}
+	if i.devices != nil {
+		i.devices.SetTheme(i.theme)
+	}
+	if i.power != nil {
+		i.power.SetTheme(i.theme)
+	}
+	if i.top != nil {
+		i.top.SetTheme(i.theme)
+	}
+	if i.events != nil {
+		i.events.SetTheme(i.theme)
+	}
+	i.updateFooter()
+}
+
+// Reload applies a changed configuration: the theme, units, chart settings
+// and panel size. The recorded readings are kept.
+func (i *Interface) Reload() {
+	i.format = i.config.Formatter()
+	for _, view := range i.views {
+		view.Reconfigure(i.format)
+	}
+	if i.power != nil {
+		i.power.SetFormatter(i.format)
+	}
+	if i.top != nil {
+		i.top.SetFormatter(i.format)
+	}
+	if i.events != nil {
+		i.events.SetFormatter(i.format)
+	}
+	i.setTheme(resolveTheme(i.config))
+
+	i.renderFront()
+}
+
+// ToggleCompact switches every battery view between the full and compact layouts
+func (i *Interface) ToggleCompact() {
+	compact := !i.views[i.active].Compact()
+	for _, view := range i.views {
+		view.SetCompact(compact)
+	}
+	i.renderFront()
+}
+
+// ScrollInfo scrolls the info panel of the active battery by rows
+func (i *Interface) ScrollInfo(rows int) {
+	i.views[i.active].ScrollInfo(rows)
+}
+
+// PageInfo scrolls the info panel of the active battery by pages
+func (i *Interface) PageInfo(pages int) {
+	i.views[i.active].PageInfo(pages)
+}
+
+// InfoAt reports whether a screen position is on the info panel of the
+// active battery while it's shown
+func (i *Interface) InfoAt(x, y int) bool {
+	return i.frontPage() == batteryPage(i.active) && i.views[i.active].InfoAt(x, y)
+}
+
+// Resize switches every battery view to the layout for the terminal width.
+// The charts take their new sizes in the next render after the layout was
+// drawn.
+func (i *Interface) Resize(width int) {
+	layout := layoutForWidth(width)
+	for _, view := range i.views {
+		view.SetLayout(layout)
+	}
+}
+
+// ToggleDetails expands or collapses the details section of every battery view
+func (i *Interface) ToggleDetails() {
+	details := !i.views[i.active].Details()
+	for _, view := range i.views {
+		view.SetDetails(details)
+	}
+	i.renderFront()
+}
+
+// ToggleHistogram switches every battery view between the charts and the
+// discharge power histogram
+func (i *Interface) ToggleHistogram() {
+	histogram := !i.views[i.active].Histogram()
+	for _, view := range i.views {
+		view.SetHistogram(histogram)
+	}
+	i.renderFront()
+}
+
+// ToggleChargeBasis switches every battery view between percent of full and
+// percent of design capacity and returns the new basis
+func (i *Interface) ToggleChargeBasis() ChargeBasis {
+	basis := ChargeBasisDesign
+	if i.views[i.active].ChargeBasis() == ChargeBasisDesign {
+		basis = ChargeBasisFull
+	}
+	for _, view := range i.views {
+		view.SetChargeBasis(basis)
+	}
+	i.renderFront()
+	return basis
+}
+
+// SetTrueColor enables 24-bit color rendering when the terminal supports it
+func (i *Interface) SetTrueColor(enabled bool) {
+	for _, view := range i.views {
+		view.SetTrueColor(enabled)
+	}
+}
+
+// History returns the in-memory chart series of every battery
+func (i *Interface) History() []ChartHistory {
+	history := make([]ChartHistory, 0)
+	for _, view := range i.views {
+		history = append(history, view.History()...)
+	}
+	return history
+}
+
+// frontPage returns the name of the visible page
+func (i *Interface) frontPage() string {
+	name, _ := i.pages.GetFrontPage()
+	return name
+}
+
+// renderFront renders only the visible page
+func (i *Interface) renderFront() {
+	switch i.frontPage() {
+	case PageHealth:
+		i.health.Update()
+	case PageTimeline:
+		i.timeline.Update()
+	case PagePeripherals:
+		i.devices.Update()
+	case PageBreakdown:
+		i.power.Update()
+	case PageConsumers:
+		i.top.Update()
+	case PageEventLog:
+		i.events.Update()
+	default:
+		i.renderActive()
+	}
+}
+
+// SetStatusProvider sets a function returning a status line shown in the first battery's info panel
+func (i *Interface) SetStatusProvider(status func() string) {
+	i.views[0].SetStatusProvider(status)
+}
+
+// Update ingests the latest battery information into every view and renders
+// only the visible one, so background tabs don't multiply the render cost
 func (i *Interface) Update() error {
 	batteries, err := i.manager.GetAll()
 	if err != nil {
 		return fmt.Errorf("failed to get batteries: %w", err)
 	}
 
-	// Update the first battery view
-	if len(batteries) > 0 {
-		i.view.Update(batteries[0])
+	for idx, view := range i.views {
+		if idx < len(batteries) {
+			view.Ingest(batteries[idx])
+		}
+	}
+	if i.power != nil {
+		i.power.Sample(batteries, time.Now())
+	}
+	if i.top != nil {
+		i.top.Sample(i.cpuPower(batteries))
+	}
+
+	now := time.Now()
+	i.muted = i.config.Muted(now)
+	i.updateFooter()
+
+	// Reduced motion holds the visuals still between updates
+	if i.config.ReducedMotion() && now.Sub(i.rendered) < ReducedMotionInterval {
+		return nil
+	}
+	i.rendered = now
+	i.renderFront()
+
+	return nil
+}
+
+// cpuPower returns the power the top consumers are ranked by: the RAPL
+// package power when available and the battery discharge otherwise
+func (i *Interface) cpuPower(batteries []*battery.Info) (float64, bool) {
+	if i.power != nil {
+		if power, ok := i.power.CPUPower(); ok {
+			return power, true
+		}
+	}
+	discharge := 0.0
+	for _, info := range batteries {
+		if info.ChargeRate < 0 {
+			discharge += -info.ChargeRate
+		}
+	}
+	return discharge, false
+}
+
+// Refresh shows the latest battery information without adding chart
+// samples, for sources that report changes between ticks
+func (i *Interface) Refresh() error {
+	batteries, err := i.manager.GetAll()
+	if err != nil {
+		return fmt.Errorf("failed to get batteries: %w", err)
+	}
+
+	for idx, view := range i.views {
+		if idx < len(batteries) {
+			view.Refresh(batteries[idx])
+		}
 	}
 
+	i.updateFooter()
+	i.renderFront()
+
 	return nil
 }
 
-// NextTab is no longer needed but kept for interface compatibility
+// renderActive renders the view of the active tab
+func (i *Interface) renderActive() {
+	view := i.views[i.active]
+	view.SetPowerSource(i.manager.PowerSource())
+	view.SetStats(i.stats.Summary())
+	view.Render()
+}
+
+// switchTo shows the battery view at the given tab position
+func (i *Interface) switchTo(tab int) {
+	i.active = tab
+	i.renderActive()
+	i.pages.SwitchToPage(batteryPage(tab))
+	i.updateFooter()
+}
+
+// NextTab switches to the next battery
 func (i *Interface) NextTab() {
-	// No-op
+	if len(i.views) < 2 {
+		return
+	}
+	i.switchTo((i.active + 1) % len(i.views))
 }
 
-// PreviousTab is no longer needed but kept for interface compatibility
+// PreviousTab switches to the previous battery
 func (i *Interface) PreviousTab() {
-	// No-op
+	if len(i.views) < 2 {
+		return
+	}
+	i.switchTo((i.active - 1 + len(i.views)) % len(i.views))
 }
@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/xsikor/go-battop/internal/battery"
+)
+
+func TestViewLayout(t *testing.T) {
+	tests := []struct {
+		width   int
+		compact bool
+		want    ViewLayout
+	}{
+		{width: 160, want: ViewLayoutHorizontal},
+		{width: HorizontalLayoutWidth, want: ViewLayoutHorizontal},
+		{width: HorizontalLayoutWidth - 1, want: ViewLayoutVertical},
+		{width: 80, want: ViewLayoutVertical},
+		{width: VerticalLayoutWidth, want: ViewLayoutVertical},
+		{width: VerticalLayoutWidth - 1, want: ViewLayoutCompact},
+		{width: 160, compact: true, want: ViewLayoutCompact},
+	}
+	for _, tt := range tests {
+		view := NewView(0, testConfig{compact: tt.compact}, testConfig{}.Formatter())
+		view.SetLayout(layoutForWidth(tt.width))
+		if got := view.Layout(); got != tt.want {
+			t.Errorf("width %d, compact %v: Layout() = %s, want %s", tt.width, tt.compact, got, tt.want)
+		}
+	}
+}
+
+func TestInfoPanelScroll(t *testing.T) {
+	config := testConfig{basis: ChargeBasisFull, mode: EstimateSmoothed}
+	view := NewView(0, config, config.Formatter())
+	view.Ingest(fullInfo(battery.StateDischarging))
+
+	// An 80x24 terminal leaves the info panel far fewer rows than it has
+	view.infoText.SetRect(0, 0, 40, 8)
+	view.infoMore.SetRect(0, 8, 40, 0)
+	view.Render()
+	rows := view.infoRows(40)
+	if rows <= 8 {
+		t.Fatalf("info text has %d rows, the test needs more than 8", rows)
+	}
+	if more := plainText(view.infoMore); !strings.Contains(more, fmt.Sprintf("↓ %d more", rows-7)) || strings.Contains(more, "↑") {
+		t.Errorf("indicator at the top = %q", more)
+	}
+
+	// The first rows are the most important ones
+	if first := strings.SplitN(plainText(view.infoText), "\n", 2)[0]; !strings.Contains(first, "Discharging") {
+		t.Errorf("first row = %q, want the state", first)
+	}
+
+	// Scrolling stops at the last row
+	view.infoMore.SetRect(0, 7, 40, 1)
+	view.infoText.SetRect(0, 0, 40, 7)
+	view.ScrollInfo(1000)
+	if view.infoScroll != rows-7 {
+		t.Errorf("scrolled to row %d, want %d", view.infoScroll, rows-7)
+	}
+	if more := plainText(view.infoMore); !strings.Contains(more, fmt.Sprintf("↑ %d", rows-7)) || strings.Contains(more, "↓") {
+		t.Errorf("indicator at the bottom = %q", more)
+	}
+
+	// Without overflow the indicator is hidden and the text unscrolled
+	view.infoText.SetRect(0, 0, 40, 100)
+	view.ScrollInfo(0)
+	if view.infoScroll != 0 || plainText(view.infoMore) != "" {
+		t.Errorf("fitting text: scroll %d, indicator %q", view.infoScroll, plainText(view.infoMore))
+	}
+}
@@ -0,0 +1,46 @@
+package ui
+
+import "github.com/gdamore/tcell/v2"
+
+// monochromeScreen draws everything in the terminal's default colors, keeping
+// only attributes such as bold and reverse. Since every color tag ends up as
+// a cell style, this strips the colors of all widgets, tags and charts alike.
+type monochromeScreen struct {
+	tcell.Screen
+}
+
+// NewMonochromeScreen wraps a screen so it ignores all colors
+func NewMonochromeScreen(screen tcell.Screen) tcell.Screen {
+	return &monochromeScreen{Screen: screen}
+}
+
+// Colors reports no colors, so callers don't enable color features
+func (s *monochromeScreen) Colors() int {
+	return 0
+}
+
+// SetContent sets a cell without its colors
+func (s *monochromeScreen) SetContent(x, y int, primary rune, combining []rune, style tcell.Style) {
+	s.Screen.SetContent(x, y, primary, combining, monochrome(style))
+}
+
+// SetCell sets a cell without its colors
+func (s *monochromeScreen) SetCell(x, y int, style tcell.Style, ch ...rune) {
+	s.Screen.SetCell(x, y, monochrome(style), ch...)
+}
+
+// Fill fills the screen without colors
+func (s *monochromeScreen) Fill(r rune, style tcell.Style) {
+	s.Screen.Fill(r, monochrome(style))
+}
+
+// SetStyle sets the default style without colors
+func (s *monochromeScreen) SetStyle(style tcell.Style) {
+	s.Screen.SetStyle(monochrome(style))
+}
+
+// monochrome returns a style with its attributes in the default colors
+func monochrome(style tcell.Style) tcell.Style {
+	_, _, attrs := style.Decompose()
+	return tcell.StyleDefault.Attributes(attrs)
+}
@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+func TestMonochromeScreen(t *testing.T) {
+	simulation := tcell.NewSimulationScreen("UTF-8")
+	if err := simulation.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer simulation.Fini()
+	simulation.SetSize(20, 1)
+
+	screen := NewMonochromeScreen(simulation)
+	if screen.Colors() != 0 {
+		t.Errorf("Colors() = %d, want 0", screen.Colors())
+	}
+
+	text := tview.NewTextView().SetDynamicColors(true)
+	text.SetText(MonochromeTheme.Label(LevelCritical, "low") + " [red:blue:b]x")
+	text.SetRect(0, 0, 20, 1)
+	text.Draw(screen)
+
+	for x, want := range []rune("✗ low x") {
+		primary, _, style, _ := simulation.GetContent(x, 0)
+		if primary != want {
+			t.Errorf("cell %d = %q, want %q", x, primary, want)
+		}
+		fg, bg, _ := style.Decompose()
+		if fg != tcell.ColorDefault || bg != tcell.ColorDefault {
+			t.Errorf("cell %d colored %v on %v", x, fg, bg)
+		}
+	}
+	_, _, style, _ := simulation.GetContent(6, 0)
+	if _, _, attrs := style.Decompose(); attrs&tcell.AttrBold == 0 {
+		t.Error("bold attribute dropped")
+	}
+}
@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/xsikor/go-battop/internal/battery"
+	"github.com/xsikor/go-battop/internal/format"
+)
+
+// PeripheralsView lists the batteries of peripherals (mice, keyboards,
+// headsets, phones) with their charge and state
+type PeripheralsView struct {
+	root   *tview.TextView
+	read   func() ([]battery.Peripheral, error)
+	theme  *Theme
+	format format.Formatter
+}
+
+// NewPeripheralsView creates a new peripherals view reading devices with the given function
+func NewPeripheralsView(read func() ([]battery.Peripheral, error), theme *Theme, formatter format.Formatter) *PeripheralsView {
+	v := &PeripheralsView{
+		root:   tview.NewTextView(),
+		read:   read,
+		theme:  theme,
+		format: formatter,
+	}
+	v.root.SetDynamicColors(true).SetBackgroundColor(tcell.ColorDefault)
+	return v
+}
+
+// SetTheme sets the theme used for the next update
+func (v *PeripheralsView) SetTheme(theme *Theme) {
+	v.theme = theme
+}
+
+// GetRoot returns the root UI element
+func (v *PeripheralsView) GetRoot() tview.Primitive {
+	return v.root
+}
+
+// Update reads the peripherals and redraws the list
+func (v *PeripheralsView) Update() {
+	var text strings.Builder
+	text.WriteString("[white::b]Peripherals[-::-]\n\n")
+
+	peripherals, err := v.read()
+	switch {
+	case err != nil:
+		slog.Debug("Failed to read peripherals", "error", err)
+		fmt.Fprintf(&text, "[gray]Failed to read peripherals: %v[-]\n", err)
+	case len(peripherals) == 0:
+		text.WriteString("[gray]No peripherals with a battery connected[-]\n")
+	}
+
+	nameWidth := 0
+	for _, peripheral := range peripherals {
+		nameWidth = max(nameWidth, len(peripheral.Name))
+	}
+	for _, peripheral := range peripherals {
+		level := LevelByThreshold(peripheral.Percent, ColorThresholdsDefault)
+		bar := CreateProgressBar(peripheral.Percent, ProgressBarWidth, ProgressBarStyleASCII)
+		fmt.Fprintf(&text, "%-*s  [gray]%-12s[-] [%s]%s[-] %s",
+			nameWidth, peripheral.Name, peripheral.Kind,
+			v.theme.Color(level), bar, v.theme.Label(level, v.format.Percent(peripheral.Percent)))
+		if peripheral.State != battery.StateUnknown {
+			fmt.Fprintf(&text, "  [gray]%s[-]", peripheral.State)
+		}
+		text.WriteString("\n")
+	}
+
+	v.root.SetText(text.String())
+	slog.Debug("Updated peripherals view", "count", len(peripherals))
+}
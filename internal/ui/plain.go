@@ -0,0 +1,262 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/battery"
+	"github.com/xsikor/go-battop/internal/errors"
+	"github.com/xsikor/go-battop/internal/format"
+)
+
+const (
+	// PlainTrendWindow is the span of readings the plain mode trends cover
+	PlainTrendWindow = 5 * time.Minute
+
+	// PlainTrendMinSpan is the span of readings needed before a trend is described
+	PlainTrendMinSpan = time.Minute
+
+	// PlainPercentStep is the charge step in percent the plain mode announces
+	PlainPercentStep = 5
+)
+
+// Thresholds below which a trend is described as steady
+const (
+	// plainPowerTrend is the relative power change
+	plainPowerTrend = 0.1
+	// plainPowerFloor is the power in mW changes are measured against at least,
+	// so an idle battery doesn't flip between rising and falling
+	plainPowerFloor = 1000
+	// plainChargeTrend is the charge change in percentage points
+	plainChargeTrend = 0.5
+	// plainTemperatureTrend is the temperature change in °C
+	plainTemperatureTrend = 1
+)
+
+// Plain renders the batteries as labeled "Label: value" lines without
+// colors, box-drawing or graphics, for screen readers and braille displays.
+// Charts are replaced by trend summaries of the recent readings.
+type Plain struct {
+	manager battery.Source
+	config  UnitsConfig
+	format  format.Formatter
+	history map[int][]plainSample
+	states  map[int]battery.State
+	steps   map[int]int
+}
+
+// plainSample is one reading of a battery kept for the trends
+type plainSample struct {
+	at          time.Time
+	percent     float64
+	power       float64
+	temperature float64
+}
+
+// NewPlain creates a new plain renderer with the given battery source and configuration
+func NewPlain(manager battery.Source, config UnitsConfig) (*Plain, error) {
+	if manager == nil {
+		return nil, fmt.Errorf("battery source is nil")
+	}
+
+	return &Plain{
+		manager: manager,
+		config:  config,
+		format:  config.Formatter(),
+		history: make(map[int][]plainSample),
+		states:  make(map[int]battery.State),
+		steps:   make(map[int]int),
+	}, nil
+}
+
+// Record adds the current readings to the trends and returns one line for
+// each change worth announcing: a state change or charge crossing a multiple
+// of PlainPercentStep. The first readings of a battery announce nothing.
+func (p *Plain) Record(now time.Time) ([]string, error) {
+	batteries, err := p.manager.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get batteries: %w", err)
+	}
+
+	var lines []string
+	for _, info := range batteries {
+		percent := info.ChargePercent()
+		samples := append(p.history[info.Index], plainSample{
+			at:          now,
+			percent:     percent,
+			power:       math.Abs(info.ChargeRate),
+			temperature: info.Temperature,
+		})
+		for len(samples) > 0 && now.Sub(samples[0].at) > PlainTrendWindow {
+			samples = samples[1:]
+		}
+		p.history[info.Index] = samples
+
+		state, step := info.State.Base(), int(percent)/PlainPercentStep
+		previous, seen := p.states[info.Index]
+		p.states[info.Index] = state
+		if !seen {
+			p.steps[info.Index] = step
+			continue
+		}
+		if state != previous {
+			lines = append(lines, fmt.Sprintf("Battery %d: now %s at %s", info.Index, strings.ToLower(state.String()), p.format.Percent(percent)))
+			p.steps[info.Index] = step
+			continue
+		}
+		if step != p.steps[info.Index] {
+			lines = append(lines, fmt.Sprintf("Battery %d: %s at %s", info.Index, strings.ToLower(state.String()), p.format.Percent(percent)))
+			p.steps[info.Index] = step
+		}
+	}
+	return lines, nil
+}
+
+// Report returns the full readings of every battery, one labeled value per
+// line, with a blank line between batteries
+func (p *Plain) Report(now time.Time) (string, error) {
+	batteries, err := p.manager.GetAll()
+	if err != nil {
+		return "", fmt.Errorf("failed to get batteries: %w", err)
+	}
+	if len(batteries) == 0 {
+		return "", errors.ErrNoBatteries
+	}
+
+	source := "battery"
+	if p.manager.PowerSource().OnAC {
+		source = "AC adapter"
+	}
+	sections := []string{"Power source: " + source}
+	for _, info := range batteries {
+		sections = append(sections, strings.Join(p.batteryLines(info, now), "\n"))
+	}
+	return strings.Join(sections, "\n\n"), nil
+}
+
+// batteryLines returns the labeled lines of one battery
+func (p *Plain) batteryLines(info *battery.Info, now time.Time) []string {
+	name := strings.TrimSpace(info.Manufacturer + " " + info.Model)
+	if name == "" {
+		name = "unknown model"
+	}
+	if info.Technology != "" {
+		name += ", " + info.Technology
+	}
+
+	lines := []string{
+		fmt.Sprintf("Battery %d: %s", info.Index, name),
+		"State: " + strings.ToLower(info.State.String()),
+		"Charge: " + p.format.Percent(info.ChargePercent()),
+	}
+
+	mode := p.config.EstimateMode()
+	tte, ttf := estimatedTimes(info, mode)
+	estimate := ""
+	if estimateConfidence(info, mode) == battery.ConfidenceLow {
+		estimate = " (rough estimate)"
+	}
+	if info.State.Base() == battery.StateDischarging && tte > 0 {
+		lines = append(lines, "Time left: "+spokenDuration(tte)+estimate)
+	}
+	if info.State.Base() == battery.StateCharging && ttf > 0 {
+		lines = append(lines, "Time to full: "+spokenDuration(ttf)+estimate)
+	}
+
+	voltage := p.format.Voltage(info.Voltage)
+	if cells, ok := battery.EstimateCells(info); ok {
+		voltage += fmt.Sprintf(", about %d cells at %s each", cells.Cells, p.format.Voltage(cells.Voltage))
+	}
+	volts := info.NominalVoltage()
+	lines = append(lines,
+		"Power: "+p.format.Power(math.Abs(info.ChargeRate)),
+		"Voltage: "+voltage,
+		fmt.Sprintf("Capacity: %s of %s", p.format.Capacity(info.Current, volts), p.format.Capacity(info.Full, volts)),
+	)
+	if info.Design > 0 {
+		lines = append(lines, "Health: "+p.format.Percent(info.Health()))
+	}
+	if info.Capabilities.HasCycles && info.CycleCount > 0 {
+		lines = append(lines, fmt.Sprintf("Cycles: %d", info.CycleCount))
+	}
+	if info.Capabilities.HasTemperature {
+		lines = append(lines, "Temperature: "+p.format.Temperature(info.Temperature))
+	}
+
+	return append(lines, p.trendLines(info, now)...)
+}
+
+// trendLines summarizes the recent readings of a battery in words
+func (p *Plain) trendLines(info *battery.Info, now time.Time) []string {
+	samples := p.history[info.Index]
+	if len(samples) < 2 || samples[len(samples)-1].at.Sub(samples[0].at) < PlainTrendMinSpan {
+		return []string{"Trend: collecting readings"}
+	}
+	span := "over the last " + spokenDuration(now.Sub(samples[0].at))
+
+	lines := []string{
+		"Charge trend: " + describeTrend(samples, func(s plainSample) float64 { return s.percent },
+			plainChargeTrend, p.format.Percent, span),
+	}
+
+	old, _ := trendEnds(samples, func(s plainSample) float64 { return s.power })
+	lines = append(lines, "Power trend: "+describeTrend(samples, func(s plainSample) float64 { return s.power },
+		plainPowerTrend*max(old, plainPowerFloor), p.format.Power, span))
+
+	if info.Capabilities.HasTemperature {
+		lines = append(lines, "Temperature trend: "+describeTrend(samples, func(s plainSample) float64 { return s.temperature },
+			plainTemperatureTrend, p.format.Temperature, span))
+	}
+	return lines
+}
+
+// describeTrend describes how a value changed across the samples, steady
+// when it changed less than threshold
+func describeTrend(samples []plainSample, value func(plainSample) float64, threshold float64, text func(float64) string, span string) string {
+	old, recent := trendEnds(samples, value)
+	switch {
+	case recent-old >= threshold:
+		return fmt.Sprintf("rising from %s to %s %s", text(old), text(recent), span)
+	case old-recent >= threshold:
+		return fmt.Sprintf("falling from %s to %s %s", text(old), text(recent), span)
+	default:
+		return fmt.Sprintf("steady at about %s %s", text(recent), span)
+	}
+}
+
+// trendEnds returns the averages of the oldest and newest quarter of the
+// samples, so a single noisy reading doesn't make a trend
+func trendEnds(samples []plainSample, value func(plainSample) float64) (old, recent float64) {
+	n := max(len(samples)/4, 1)
+	for i := 0; i < n; i++ {
+		old += value(samples[i])
+		recent += value(samples[len(samples)-1-i])
+	}
+	return old / float64(n), recent / float64(n)
+}
+
+// spokenDuration formats a duration in words, which screen readers read
+// better than hh:mm
+func spokenDuration(d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	hours, minutes := minutes/60, minutes%60
+
+	var parts []string
+	if hours > 0 {
+		parts = append(parts, plural(hours, "hour"))
+	}
+	if minutes > 0 || hours == 0 {
+		parts = append(parts, plural(minutes, "minute"))
+	}
+	return strings.Join(parts, " ")
+}
+
+// plural formats a count with its noun in singular or plural
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
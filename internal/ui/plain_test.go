@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/battery"
+)
+
+func TestPlainReport(t *testing.T) {
+	info := fullInfo(battery.StateDischarging)
+	info.Current = 36300
+	source := &testSource{infos: []*battery.Info{info}, source: battery.PowerSource{Detected: true}}
+	plain, err := NewPlain(source, testConfig{mode: EstimateSmoothed})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	if lines, err := plain.Record(start); err != nil || len(lines) != 0 {
+		t.Fatalf("first Record() = %v, %v, want no announcements", lines, err)
+	}
+
+	// Discharge harder for five minutes, crossing 75% on the fourth reading
+	for i := 1; i <= 10; i++ {
+		info.Current -= 100
+		info.ChargeRate -= 500
+		lines, err := plain.Record(start.Add(time.Duration(i) * 30 * time.Second))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := i == 4; (len(lines) == 1) != want {
+			t.Errorf("reading %d announced %q", i, lines)
+		}
+	}
+
+	info.State = battery.StateCharging
+	lines, err := plain.Record(start.Add(5 * time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 1 || lines[0] != "Battery 0: now charging at 73.5%" {
+		t.Errorf("state change announced %q", lines)
+	}
+
+	report, err := plain.Report(start.Add(5 * time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSensible(t, "plain report", report)
+	for _, want := range []string{
+		"Battery 0: SMP 5B10W13930, Li-poly\n",
+		"Charge trend: falling from 75.4% to 73.6% over the last 5 minutes",
+		"Power trend: rising from 10.00 W to 14.33 W over the last 5 minutes",
+		"Temperature trend: steady at about 31.5 °C over the last 5 minutes",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report lacks %q:\n%s", want, report)
+		}
+	}
+	for _, markup := range []string{"[", "│", "█"} {
+		if strings.Contains(report, markup) {
+			t.Errorf("report contains %q:\n%s", markup, report)
+		}
+	}
+}
+
+func TestSpokenDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0 minutes"},
+		{time.Minute, "1 minute"},
+		{2*time.Hour + 5*time.Minute, "2 hours 5 minutes"},
+		{time.Hour + 20*time.Second, "1 hour"},
+	}
+	for _, tt := range tests {
+		if got := spokenDuration(tt.d); got != tt.want {
+			t.Errorf("spokenDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
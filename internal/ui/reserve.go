@@ -0,0 +1,122 @@
+package ui
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/xsikor/go-battop/internal/stats"
+)
+
+// Labels of the planning input fields
+const (
+	reservePromptLabel = "Unplugged until: "
+	goalPromptLabel    = "Last until: "
+	notePromptLabel    = "Note: "
+	searchPromptLabel  = "Search: "
+)
+
+// SetReserve plans the unplugged period against the charge of every battery (nil clears it)
+func (i *Interface) SetReserve(reserve *stats.Reserve) {
+	for _, view := range i.views {
+		view.SetReserve(reserve)
+	}
+	i.renderFront()
+}
+
+// SetGoal rates the power of every battery against the budget that makes it
+// last until the goal (nil clears it)
+func (i *Interface) SetGoal(goal *stats.Goal) {
+	for _, view := range i.views {
+		view.SetGoal(goal)
+	}
+	i.renderFront()
+}
+
+// PromptReserve shows an input field for an unplugged period over the current
+// page and returns it so the caller can focus it. Enter passes the text to
+// submit, which keeps the prompt open with the error when it fails; Escape
+// closes the prompt.
+func (i *Interface) PromptReserve(submit func(text string) error, closed func()) tview.Primitive {
+	return i.prompt(PageReservePrompt, " Reserve planning ", reservePromptLabel, "15:30, flight 4h, empty to clear", submit, closed)
+}
+
+// PromptGoal shows an input field for a battery life goal over the current
+// page, like PromptReserve
+func (i *Interface) PromptGoal(submit func(text string) error, closed func()) tview.Primitive {
+	return i.prompt(PageGoalPrompt, " Battery life goal ", goalPromptLabel, "18:00, 3h, empty to clear", submit, closed)
+}
+
+// PromptNote shows an input field for a note tagging the current moment,
+// like PromptReserve, and renders the note's chart marker right away
+func (i *Interface) PromptNote(submit func(text string) error, closed func()) tview.Primitive {
+	return i.prompt(PageNotePrompt, " Tag this moment ", notePromptLabel, "started compile", func(text string) error {
+		if err := submit(text); err != nil {
+			return err
+		}
+		i.renderFront()
+		return nil
+	}, closed)
+}
+
+// SearchEventLog shows the event log with an input field over it that
+// filters the events as the text is typed, like PromptReserve. Enter keeps
+// the search, Escape restores the previous one. Returns nil without an
+// event log.
+func (i *Interface) SearchEventLog(closed func()) tview.Primitive {
+	if i.events == nil {
+		return nil
+	}
+	if i.frontPage() != PageEventLog {
+		i.togglePage(PageEventLog)
+	}
+
+	previous := i.events.Query()
+	submitted := false
+	input := i.prompt(PageSearchPrompt, " Search events ", searchPromptLabel, "unplugged, dropped", func(string) error {
+		submitted = true
+		return nil
+	}, func() {
+		if !submitted {
+			i.events.SetQuery(previous)
+			i.events.Update()
+		}
+		closed()
+	})
+	input.SetText(previous)
+	input.SetChangedFunc(func(text string) {
+		i.events.SetQuery(text)
+		i.events.Update()
+	})
+	return input
+}
+
+// prompt shows a titled input field centered over the current page
+func (i *Interface) prompt(page, title, label, placeholder string, submit func(text string) error, closed func()) *tview.InputField {
+	input := tview.NewInputField().
+		SetLabel(label).
+		SetPlaceholder(placeholder).
+		SetFieldWidth(0)
+	input.SetBorder(true).SetTitle(title)
+
+	input.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			if err := submit(input.GetText()); err != nil {
+				input.SetTitle(" " + err.Error() + " ").SetTitleColor(tcell.GetColor(i.theme.Critical))
+				return
+			}
+		}
+		i.pages.RemovePage(page)
+		closed()
+	})
+
+	// Center the prompt over the current page
+	modal := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(input, 3, 0, true).
+			AddItem(nil, 0, 1, false), 0, 3, true).
+		AddItem(nil, 0, 1, false)
+
+	i.pages.AddPage(page, modal, true, true)
+	return input
+}
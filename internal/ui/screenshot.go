@@ -0,0 +1,137 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/xsikor/go-battop/pkg/plot"
+)
+
+// Chart image size of one chart, stacked with a gap in between
+const (
+	ChartImageWidth  = 960
+	ChartImageHeight = 240
+	ChartImageGap    = 8
+)
+
+// ScreenText returns the contents of a screen as lines of text with ANSI
+// color and attribute escapes
+func ScreenText(screen tcell.Screen) string {
+	width, height := screen.Size()
+	var text strings.Builder
+	for y := 0; y < height; y++ {
+		last := ""
+		for x := 0; x < width; x++ {
+			mainc, combc, style, cellWidth := screen.GetContent(x, y)
+			if sgr := ansiStyle(style); sgr != last {
+				text.WriteString(sgr)
+				last = sgr
+			}
+			if mainc == 0 {
+				mainc = ' '
+			}
+			text.WriteRune(mainc)
+			for _, r := range combc {
+				text.WriteRune(r)
+			}
+			if cellWidth > 1 {
+				x += cellWidth - 1
+			}
+		}
+		text.WriteString("\x1b[0m\n")
+	}
+	return text.String()
+}
+
+// ansiStyle returns the SGR escape sequence of a cell style
+func ansiStyle(style tcell.Style) string {
+	fg, bg, attrs := style.Decompose()
+	codes := []string{"0"}
+	if attrs&tcell.AttrBold != 0 {
+		codes = append(codes, "1")
+	}
+	if attrs&tcell.AttrDim != 0 {
+		codes = append(codes, "2")
+	}
+	if attrs&tcell.AttrReverse != 0 {
+		codes = append(codes, "7")
+	}
+	if r, g, b := fg.RGB(); fg != tcell.ColorDefault && r >= 0 {
+		codes = append(codes, fmt.Sprintf("38;2;%d;%d;%d", r, g, b))
+	}
+	if r, g, b := bg.RGB(); bg != tcell.ColorDefault && r >= 0 {
+		codes = append(codes, fmt.Sprintf("48;2;%d;%d;%d", r, g, b))
+	}
+	return "\x1b[" + strings.Join(codes, ";") + "m"
+}
+
+// ImagePalette maps tview color names to image colors, for rendering charts
+// with RenderImage and RenderSVG
+func ImagePalette(name string) (color.Color, bool) {
+	c := tcell.GetColor(normalizeColor(name))
+	r, g, b := c.RGB()
+	if c == tcell.ColorDefault || r < 0 {
+		return nil, false
+	}
+	return color.RGBA{uint8(r), uint8(g), uint8(b), 0xff}, true
+}
+
+// visibleCharts returns the charts of the active battery that aren't hidden
+func (i *Interface) visibleCharts() []*Chart {
+	var charts []*Chart
+	for _, chart := range i.views[i.active].charts {
+		if !chart.chart.Hidden() {
+			charts = append(charts, chart.chart)
+		}
+	}
+	return charts
+}
+
+// ChartImage renders the visible charts of the active battery stacked into
+// an image, or returns nil when no chart is visible
+func (i *Interface) ChartImage() image.Image {
+	charts := i.visibleCharts()
+	if len(charts) == 0 {
+		return nil
+	}
+
+	height := len(charts)*(ChartImageHeight+ChartImageGap) - ChartImageGap
+	img := image.NewRGBA(image.Rect(0, 0, ChartImageWidth, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(plot.ImageBackground), image.Point{}, draw.Src)
+	for n, chart := range charts {
+		top := n * (ChartImageHeight + ChartImageGap)
+		panel := img.SubImage(image.Rect(0, top, ChartImageWidth, top+ChartImageHeight)).(*image.RGBA)
+		chart.plot.RenderImage(panel, ImagePalette)
+	}
+	return img
+}
+
+// WriteChartSVG writes the visible charts of the active battery stacked into
+// an SVG image laid out like the ChartImage, with the value and time labels
+// the image leaves out
+func (i *Interface) WriteChartSVG(w io.Writer) error {
+	charts := i.visibleCharts()
+	if len(charts) == 0 {
+		return fmt.Errorf("no visible charts")
+	}
+
+	height := len(charts)*(ChartImageHeight+ChartImageGap) - ChartImageGap
+	var svg strings.Builder
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`,
+		ChartImageWidth, height, ChartImageWidth, height)
+	for n, chart := range charts {
+		fmt.Fprintf(&svg, `<g transform="translate(0 %d)">`, n*(ChartImageHeight+ChartImageGap))
+		if err := chart.plot.RenderSVG(&svg, ChartImageWidth, ChartImageHeight, ImagePalette); err != nil {
+			return err
+		}
+		svg.WriteString("</g>")
+	}
+	svg.WriteString("</svg>\n")
+	_, err := io.WriteString(w, svg.String())
+	return err
+}
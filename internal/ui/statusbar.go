@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/xsikor/go-battop/internal/battery"
+)
+
+// StatusBarSeparator separates the fields of the footer status bar
+const StatusBarSeparator = " [gray]│[-] "
+
+// hint formats a key hint of the footer
+func hint(key, action string) string {
+	return "[yellow]" + key + "[gray] " + action
+}
+
+// pageHints are the key hints shown while a page other than a battery is in
+// front, led by the key that returns to the batteries
+var pageHints = map[string][]string{
+	PageHealth:      {hint("w", "back"), hint("s", "screenshot")},
+	PageTimeline:    {hint("p", "back"), hint("s", "screenshot")},
+	PagePeripherals: {hint("b", "back")},
+	PageBreakdown:   {hint("n", "back"), hint("c", "top consumers")},
+	PageConsumers:   {hint("c", "back"), hint("n", "power breakdown")},
+	PageEventLog:    {hint("e", "back"), hint("/", "search"), hint("f", "severity"), hint("a", "note")},
+}
+
+// keyHints returns the key hints for the panel in front
+func (i *Interface) keyHints() []string {
+	if i.inspecting {
+		return []string{hint("←→", "move cursor"), hint("i", "done"), hint("q", "quit")}
+	}
+	if hints, ok := pageHints[i.frontPage()]; ok {
+		return append(hints, hint("q", "quit"))
+	}
+
+	var hints []string
+	if len(i.views) > 1 {
+		hints = append(hints, hint("Tab", "battery"))
+	}
+	hints = append(hints,
+		hint("1-4", "charts"), hint("j/k", "scroll"), hint("i", "inspect"),
+		hint("z", "zoom"), hint("y", "scale"), hint("[]", "delay"),
+		hint("m", "compact"), hint("g", "hist"), hint("|", "layout"),
+		hint("w", "health"), hint("p", "timeline"), hint("e", "events"), hint("b", "devices"),
+		hint("n", "power"), hint("c", "top"), hint("a", "note"), hint("u", "reserve"),
+		hint("o", "goal"), hint("d", "details"), hint("D", "design"), hint("t", "theme"),
+		hint("r", "reload"), hint("s", "shot"), hint("x", "export"), hint("q", "quit"))
+	return hints
+}
+
+// statusBar returns the live summary of the footer: the battery tabs, the
+// quiet hours mark, the update delay, the active battery's charge, state,
+// power and time remaining, and the clock. The short fields come first so a
+// narrow terminal cuts off the summary rather than them.
+func (i *Interface) statusBar(now time.Time) string {
+	var fields []string
+	if len(i.views) > 1 {
+		fields = append(fields, i.tabStrip()+"[-:-]")
+	}
+	if i.muted {
+		fields = append(fields, "🔕 quiet")
+	}
+	fields = append(fields, "[gray]every[-] "+formatDelay(i.delay))
+	if summary := i.views[i.active].statusSummary(); summary != "" {
+		fields = append(fields, summary)
+	}
+	fields = append(fields, "[white]"+now.Format("15:04")+"[-]")
+	return strings.Join(fields, StatusBarSeparator)
+}
+
+// statusSummary returns the charge, state, power and time remaining of the
+// battery for the footer status bar
+func (v *View) statusSummary() string {
+	info := v.info
+	if info == nil {
+		return ""
+	}
+	if info.State == battery.StateNotPresent {
+		return "[gray]" + StateIcon(info.State) + " empty bay[-]"
+	}
+
+	percent := info.ChargePercent()
+	level := LevelByThreshold(percent, ColorThresholdsDefault)
+	summary := fmt.Sprintf("%s %s", v.theme.Label(level, fmt.Sprintf("%.0f%%", percent)),
+		v.theme.Label(StateLevel(info.State), StateIcon(info.State)))
+	if info.ChargeRate != 0 {
+		summary += " [white]" + v.format.Power(math.Abs(info.ChargeRate)) + "[-]"
+	}
+
+	if v.warmingUp() {
+		return summary
+	}
+	mode := v.config.EstimateMode()
+	tte, ttf := estimatedTimes(info, mode)
+	confidence := estimateConfidence(info, mode)
+	switch state := info.State.Base(); {
+	case state == battery.StateDischarging && tte > 0:
+		summary += " " + confidence.Mark(v.format.Duration(tte)) + " [gray]left[-]"
+	case state == battery.StateCharging && ttf > 0:
+		summary += " " + confidence.Mark(v.format.Duration(ttf)) + " [gray]to full[-]"
+	}
+	return summary
+}
@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/xsikor/go-battop/internal/battery"
+	"github.com/xsikor/go-battop/internal/stats"
+	"github.com/xsikor/go-battop/internal/store"
+)
+
+// stripped removes the color tags from a text
+func stripped(text string) string {
+	return tview.NewTextView().SetDynamicColors(true).SetText(text).GetText(true)
+}
+
+func TestStatusBar(t *testing.T) {
+	config := testConfig{basis: ChargeBasisFull, mode: EstimateSmoothed}
+	discharging, charging := fullInfo(battery.StateDischarging), fullInfo(battery.StateCharging)
+	source := &testSource{infos: []*battery.Info{discharging, charging}}
+	ui, err := NewInterface(source, stats.NewTracker(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ui.SetDelay(2 * time.Second)
+
+	now := time.Date(2026, 3, 1, 14, 32, 0, 0, time.Local)
+	status := stripped(ui.statusBar(now))
+	for _, want := range []string{"Battery  1  2", "↓", "left", "every 2s", "14:32"} {
+		if !strings.Contains(status, want) {
+			t.Errorf("status bar %q is missing %q", status, want)
+		}
+	}
+
+	ui.NextTab()
+	if status := stripped(ui.statusBar(now)); !strings.Contains(status, "to full") {
+		t.Errorf("status bar of the charging battery = %q", status)
+	}
+
+	tests := []struct {
+		name  string
+		setup func()
+		want  string
+		not   string
+	}{
+		{"battery", func() {}, "j/k scroll", "back"},
+		{"inspecting", ui.ToggleInspect, "move cursor", "j/k scroll"},
+		{"health", func() {
+			ui.ToggleInspect()
+			ui.SetHealthHistory(stats.LoadHealthHistory(store.New(t.TempDir()), discharging))
+			ui.ToggleHealthHistory()
+		}, "w back", "j/k scroll"},
+		{"back to the battery", ui.ToggleHealthHistory, "Tab battery", "w back"},
+	}
+	for _, tc := range tests {
+		tc.setup()
+		hints := plainText(ui.hints)
+		if !strings.Contains(hints, tc.want) || strings.Contains(hints, tc.not) {
+			t.Errorf("%s: hints %q should contain %q and not %q", tc.name, hints, tc.want, tc.not)
+		}
+	}
+}
+
+func TestStatusBarNarrow(t *testing.T) {
+	config := testConfig{basis: ChargeBasisFull, mode: EstimateSmoothed}
+	source := &testSource{infos: []*battery.Info{fullInfo(battery.StateDischarging), fullInfo(battery.StateCharging)}}
+	ui, err := NewInterface(source, stats.NewTracker(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ui.muted = true
+	ui.SetDelay(2 * time.Second)
+
+	simulation := tcell.NewSimulationScreen("UTF-8")
+	if err := simulation.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer simulation.Fini()
+
+	// The quiet hours mark and the delay stay on screen when the summary and
+	// the hints don't fit
+	const width = 40
+	simulation.SetSize(width, 1)
+	ui.footer.SetRect(0, 0, width, 1)
+	ui.footer.Draw(simulation)
+	var line strings.Builder
+	for x := range width {
+		primary, _, _, _ := simulation.GetContent(x, 0)
+		line.WriteRune(primary)
+	}
+	for _, want := range []string{"quiet", "every 2s"} {
+		if !strings.Contains(line.String(), want) {
+			t.Errorf("footer %q is missing %q", line.String(), want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/xsikor/go-battop/internal/battery"
+	"github.com/xsikor/go-battop/internal/errors"
+)
+
+// DefaultStatuslineFormat is the statusline template used when none is given
+const DefaultStatuslineFormat = "{percent} {state} {time_left}"
+
+// StatuslinePlaceholders lists the placeholders a statusline template may use
+var StatuslinePlaceholders = []string{
+	"{percent}", "{design_percent}", "{state}", "{state_icon}", "{time_left}", "{time_full}",
+	"{confidence}", "{power}", "{health}", "{voltage}", "{energy}", "{source}",
+}
+
+// Statusline renders a single line from a template for status bars such as
+// tmux status-right, polybar or waybar custom modules
+type Statusline struct {
+	manager battery.Source
+	config  UnitsConfig
+}
+
+// NewStatusline creates a new statusline with the given battery source and configuration
+func NewStatusline(manager battery.Source, config UnitsConfig) (*Statusline, error) {
+	if manager == nil {
+		return nil, fmt.Errorf("battery source is nil")
+	}
+
+	return &Statusline{
+		manager: manager,
+		config:  config,
+	}, nil
+}
+
+// Render fills the template with the current values of the first battery.
+// Placeholders without a value (e.g. {time_left} while charging) become empty.
+func (s *Statusline) Render(template string) (string, error) {
+	batteries, err := s.manager.GetAll()
+	if err != nil {
+		return "", fmt.Errorf("failed to get batteries: %w", err)
+	}
+	if len(batteries) == 0 {
+		return "", errors.ErrNoBatteries
+	}
+
+	info := batteries[0]
+	f := s.config.Formatter()
+
+	tte, ttf := estimatedTimes(info, s.config.EstimateMode())
+	confidence := estimateConfidence(info, s.config.EstimateMode())
+	timeLeft, timeFull := "", ""
+	if info.State.Base() == battery.StateDischarging && tte > 0 {
+		timeLeft = confidence.Mark(f.Duration(tte))
+	}
+	if info.State.Base() == battery.StateCharging && ttf > 0 {
+		timeFull = confidence.Mark(f.Duration(ttf))
+	}
+
+	designPercent, health, voltage := "", "", ""
+	if info.Design > 0 {
+		designPercent = fmt.Sprintf("%.0f%%", info.DesignPercent())
+		health = fmt.Sprintf("%.0f%%", info.Health())
+	}
+	if info.Voltage > 0 {
+		voltage = f.Voltage(info.Voltage)
+	}
+
+	replacer := strings.NewReplacer(
+		"{percent}", fmt.Sprintf("%.0f%%", info.ChargePercent()),
+		"{design_percent}", designPercent,
+		"{state}", info.State.String(),
+		"{state_icon}", StateIcon(info.State),
+		"{time_left}", timeLeft,
+		"{time_full}", timeFull,
+		"{confidence}", confidence.String(),
+		"{power}", f.Power(math.Abs(info.ChargeRate)),
+		"{health}", health,
+		"{voltage}", voltage,
+		"{energy}", f.Capacity(info.Current, info.NominalVoltage()),
+		"{source}", s.manager.PowerSource().String(),
+	)
+
+	return strings.TrimSpace(replacer.Replace(template)), nil
+}
@@ -0,0 +1,204 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/xsikor/go-battop/internal/battery"
+)
+
+// Level is the semantic severity of a value
+type Level int
+
+const (
+	// LevelExcellent indicates a value in the best range
+	LevelExcellent Level = iota
+	// LevelGood indicates an acceptable value
+	LevelGood
+	// LevelWarning indicates a value that needs attention
+	LevelWarning
+	// LevelCritical indicates a value that needs action
+	LevelCritical
+)
+
+// Theme maps semantic levels to colors. Color-blind palettes avoid
+// green/red semantics and add redundant symbols so levels don't rely on hue.
+type Theme struct {
+	// Name identifies the theme in configuration
+	Name string
+
+	// Colors indexed by Level
+	Excellent string
+	Good      string
+	Warning   string
+	Critical  string
+
+	// Symbols adds ✓/!/✗ markers to gauges and state labels
+	Symbols bool
+}
+
+// DefaultThemeName is the name of the default theme
+const DefaultThemeName = "default"
+
+// Themes lists the installed themes
+var Themes = []*Theme{
+	{Name: DefaultThemeName, Excellent: "green", Good: "yellow", Warning: "orange", Critical: "red"},
+	// Red-green deficiencies: blue/yellow axis, which stays distinguishable
+	{Name: "deuteranopia", Excellent: "#56B4E9", Good: "#F0E442", Warning: "#E69F00", Critical: "#CC79A7", Symbols: true},
+	{Name: "protanopia", Excellent: "#0096FF", Good: "#F0E442", Warning: "#E69F00", Critical: "#A0A0A0", Symbols: true},
+	// Blue-yellow deficiency: red/cyan axis
+	{Name: "tritanopia", Excellent: "#00C8C8", Good: "#FFFFFF", Warning: "#FF8CB4", Critical: "#E62828", Symbols: true},
+}
+
+// MonochromeTheme is the null palette used without colors. "-" resets tags
+// to the default color, and the symbols carry the levels instead of hues.
+var MonochromeTheme = &Theme{Name: "none", Excellent: "-", Good: "-", Warning: "-", Critical: "-", Symbols: true}
+
+// GaugeStops returns the gradient color stops for gauges, from the
+// critical color at empty through the good to the excellent color at full
+func (t *Theme) GaugeStops() []tcell.Color {
+	return []tcell.Color{
+		tcell.GetColor(normalizeColor(t.Critical)),
+		tcell.GetColor(normalizeColor(t.Good)),
+		tcell.GetColor(normalizeColor(t.Excellent)),
+	}
+}
+
+// ThemeNames returns the names of the installed themes
+func ThemeNames() []string {
+	names := make([]string, 0, len(Themes))
+	for _, theme := range Themes {
+		names = append(names, theme.Name)
+	}
+	return names
+}
+
+// ThemeByName returns the installed theme with the given name
+func ThemeByName(name string) (*Theme, bool) {
+	for _, theme := range Themes {
+		if theme.Name == name {
+			return theme, true
+		}
+	}
+	return nil, false
+}
+
+// NextTheme returns the installed theme following the given one, wrapping around
+func NextTheme(current *Theme) *Theme {
+	for i, theme := range Themes {
+		if theme == current {
+			return Themes[(i+1)%len(Themes)]
+		}
+	}
+	return Themes[0]
+}
+
+// Color returns the color of a level
+func (t *Theme) Color(level Level) string {
+	switch level {
+	case LevelExcellent:
+		return t.Excellent
+	case LevelGood:
+		return t.Good
+	case LevelWarning:
+		return t.Warning
+	default:
+		return t.Critical
+	}
+}
+
+// Symbol returns the redundant marker of a level, or "" when the theme has none
+func (t *Theme) Symbol(level Level) string {
+	if !t.Symbols {
+		return ""
+	}
+
+	switch level {
+	case LevelExcellent:
+		return "✓"
+	case LevelCritical:
+		return "✗"
+	default:
+		return "!"
+	}
+}
+
+// Label formats text in the level color, prefixed with the level symbol
+func (t *Theme) Label(level Level, text string) string {
+	if symbol := t.Symbol(level); symbol != "" {
+		text = symbol + " " + text
+	}
+	return fmt.Sprintf("[%s]%s[-]", t.Color(level), text)
+}
+
+// LevelByThreshold returns the level of a percentage for the given thresholds
+func LevelByThreshold(percent float64, thresholds ColorThresholds) Level {
+	switch {
+	case percent >= thresholds.Excellent:
+		return LevelExcellent
+	case percent >= thresholds.Good:
+		return LevelGood
+	case percent >= thresholds.Warning:
+		return LevelWarning
+	default:
+		return LevelCritical
+	}
+}
+
+// StateLevel returns the level used to display a battery state
+func StateLevel(state battery.State) Level {
+	switch state {
+	case battery.StateCharging, battery.StateFull:
+		return LevelExcellent
+	case battery.StateNotCharging, battery.StatePendingCharge, battery.StatePendingDischarge,
+		battery.StateBoost, battery.StateTrim:
+		return LevelGood
+	case battery.StateDischarging, battery.StateOnBattery, battery.StateCalibrating, battery.StateNotPresent:
+		return LevelWarning
+	case battery.StateEmpty, battery.StateBypass:
+		return LevelCritical
+	default:
+		return LevelGood
+	}
+}
+
+// StateIcon returns a one-character icon of a battery state
+func StateIcon(state battery.State) string {
+	switch state {
+	case battery.StateCharging:
+		return "↑"
+	case battery.StateDischarging, battery.StateOnBattery:
+		return "↓"
+	case battery.StateFull:
+		return "■"
+	case battery.StateEmpty:
+		return "□"
+	case battery.StateNotCharging, battery.StatePendingCharge, battery.StatePendingDischarge:
+		return "="
+	case battery.StateNotPresent:
+		return "∅"
+	case battery.StateBoost:
+		return "+"
+	case battery.StateTrim:
+		return "-"
+	case battery.StateBypass:
+		return "⇄"
+	case battery.StateCalibrating:
+		return "~"
+	default:
+		return "?"
+	}
+}
+
+// resolveTheme returns the configured theme, falling back to the default
+// theme, or the null palette without colors
+func resolveTheme(config ThemeConfig) *Theme {
+	if config.NoColor() {
+		return MonochromeTheme
+	}
+	if theme, ok := ThemeByName(config.Theme()); ok {
+		return theme
+	}
+	return Themes[0]
+}
@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/xsikor/go-battop/internal/battery"
+	"github.com/xsikor/go-battop/internal/errors"
+	"github.com/xsikor/go-battop/internal/format"
+)
+
+// TickerSeparator separates metrics on the ticker line
+const TickerSeparator = " • "
+
+// Ticker renders a one-line battery summary for narrow status panes.
+// The primary metrics (charge, power, time) are always shown, while the
+// secondary metrics rotate one at a time.
+type Ticker struct {
+	manager battery.Source
+	config  UnitsConfig
+	format  format.Formatter
+	offset  int
+}
+
+// NewTicker creates a new ticker with the given battery source and configuration
+func NewTicker(manager battery.Source, config UnitsConfig) (*Ticker, error) {
+	if manager == nil {
+		return nil, fmt.Errorf("battery source is nil")
+	}
+
+	return &Ticker{
+		manager: manager,
+		config:  config,
+		format:  config.Formatter(),
+	}, nil
+}
+
+// Rotate advances the ticker to the next secondary metric
+func (t *Ticker) Rotate() {
+	t.offset++
+}
+
+// Line returns the current ticker line for the first battery
+func (t *Ticker) Line() (string, error) {
+	batteries, err := t.manager.GetAll()
+	if err != nil {
+		return "", fmt.Errorf("failed to get batteries: %w", err)
+	}
+	if len(batteries) == 0 {
+		return "", errors.ErrNoBatteries
+	}
+
+	info := batteries[0]
+	segments := []string{t.primarySegment(info)}
+
+	secondary := t.secondarySegments(info)
+	if len(secondary) > 0 {
+		segments = append(segments, secondary[t.offset%len(secondary)])
+	}
+
+	return strings.Join(segments, TickerSeparator), nil
+}
+
+// primarySegment builds the always-visible charge, power and time summary
+func (t *Ticker) primarySegment(info *battery.Info) string {
+	parts := []string{fmt.Sprintf("%.0f%%", info.ChargePercent())}
+
+	switch {
+	case info.ChargeRate > 0:
+		parts = append(parts, "↑"+t.format.Power(info.ChargeRate))
+	case info.ChargeRate < 0:
+		parts = append(parts, "↓"+t.format.Power(math.Abs(info.ChargeRate)))
+	default:
+		parts = append(parts, "="+t.format.Power(0))
+	}
+
+	tte, ttf := estimatedTimes(info, t.config.EstimateMode())
+	confidence := estimateConfidence(info, t.config.EstimateMode())
+	if info.State.Base() == battery.StateDischarging && tte > 0 {
+		parts = append(parts, confidence.Mark(t.format.Duration(tte))+" left")
+	}
+	if info.State.Base() == battery.StateCharging && ttf > 0 {
+		parts = append(parts, confidence.Mark(t.format.Duration(ttf))+" to full")
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// secondarySegments builds the list of metrics the ticker rotates through
+func (t *Ticker) secondarySegments(info *battery.Info) []string {
+	segments := []string{
+		fmt.Sprintf("health %.0f%%", info.Health()),
+	}
+
+	if info.CycleCount > 0 {
+		segments = append(segments, fmt.Sprintf("%d cycles", info.CycleCount))
+	}
+
+	volts := info.NominalVoltage()
+	segments = append(segments,
+		t.format.Voltage(info.Voltage),
+		fmt.Sprintf("%s / %s", t.format.Capacity(info.Current, volts), t.format.Capacity(info.Full, volts)),
+	)
+
+	return segments
+}
@@ -0,0 +1,140 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/xsikor/go-battop/internal/stats"
+)
+
+// timelineStates lists the power states in legend order
+var timelineStates = []stats.PowerState{
+	stats.PowerCharging,
+	stats.PowerDischarging,
+	stats.PowerACIdle,
+	stats.PowerSleep,
+}
+
+// TimelineView shows the day's power states as one horizontal bar per hour
+type TimelineView struct {
+	root     *tview.TextView
+	timeline *stats.Timeline
+	theme    *Theme
+}
+
+// NewTimelineView creates a new power timeline view
+func NewTimelineView(timeline *stats.Timeline, theme *Theme) *TimelineView {
+	v := &TimelineView{
+		root:     tview.NewTextView(),
+		timeline: timeline,
+		theme:    theme,
+	}
+	v.root.SetDynamicColors(true).SetBackgroundColor(tcell.ColorDefault)
+	return v
+}
+
+// SetTheme sets the theme used for the next update
+func (v *TimelineView) SetTheme(theme *Theme) {
+	v.theme = theme
+}
+
+// GetRoot returns the root UI element
+func (v *TimelineView) GetRoot() tview.Primitive {
+	return v.root
+}
+
+// Update redraws today's timeline
+func (v *TimelineView) Update() {
+	now := time.Now()
+	day := v.timeline.Day(now)
+
+	width := DefaultChartWidth
+	if _, _, w, _ := v.root.GetInnerRect(); w > 0 {
+		width = w
+	}
+	cells := width - TimelineLabelWidth
+	if cells > 60 {
+		cells = 60
+	}
+	if cells < 1 {
+		cells = 1
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "[white::b]Power timeline %s[-::-]\n", day.Date)
+	v.writeLegend(&text)
+	text.WriteString("\n")
+
+	for hour := 0; hour < 24; hour++ {
+		fmt.Fprintf(&text, "[gray]%02d │[-]", hour)
+		for cell := 0; cell < cells; cell++ {
+			start := hour*60 + cell*60/cells
+			end := hour*60 + (cell+1)*60/cells
+			text.WriteString(v.cell(dominantState(day, start, end)))
+		}
+		if hour == now.Hour() {
+			text.WriteString(" [white]◂[-]")
+		}
+		text.WriteString("\n")
+	}
+
+	v.root.SetText(text.String())
+	slog.Debug("Updated power timeline view", "date", day.Date, "cells", cells)
+}
+
+// writeLegend writes the state legend
+func (v *TimelineView) writeLegend(text *strings.Builder) {
+	items := make([]string, 0, len(timelineStates)+1)
+	for _, state := range timelineStates {
+		items = append(items, v.cell(state)+" "+state.String())
+	}
+	items = append(items, v.cell(stats.PowerNone)+" "+stats.PowerNone.String())
+	text.WriteString(strings.Join(items, "  "))
+	text.WriteString("\n")
+}
+
+// cell renders a single timeline cell for a power state
+func (v *TimelineView) cell(state stats.PowerState) string {
+	switch state {
+	case stats.PowerCharging:
+		return fmt.Sprintf("[%s]%s[-]", v.theme.Excellent, v.cellChar('+'))
+	case stats.PowerDischarging:
+		return fmt.Sprintf("[%s]%s[-]", v.theme.Warning, v.cellChar('-'))
+	case stats.PowerACIdle:
+		return fmt.Sprintf("[%s]%s[-]", v.theme.Good, v.cellChar('='))
+	case stats.PowerSleep:
+		return "[gray]░[-]"
+	default:
+		return "[gray::d]·[-::-]"
+	}
+}
+
+// cellChar returns the block character, or the given symbol when the theme
+// uses symbols so states don't rely on color alone
+func (v *TimelineView) cellChar(symbol rune) string {
+	if v.theme.Symbols {
+		return string(symbol)
+	}
+	return "█"
+}
+
+// dominantState returns the most frequent recorded state in a minute range
+func dominantState(day stats.TimelineDay, start, end int) stats.PowerState {
+	counts := make(map[stats.PowerState]int)
+	best, bestCount := stats.PowerNone, 0
+	for minute := start; minute < end; minute++ {
+		state := day.At(minute)
+		if state == stats.PowerNone {
+			continue
+		}
+		counts[state]++
+		if counts[state] > bestCount {
+			best, bestCount = state, counts[state]
+		}
+	}
+	return best
+}
@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// newToast creates the toast box shown above the footer
+func newToast() *tview.TextView {
+	toast := tview.NewTextView()
+	toast.SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetBackgroundColor(tcell.ColorDefault)
+	toast.SetBorder(true)
+	return toast
+}
+
+// ShowToast shows a message in a box above the footer, one row per line of
+// the message. An empty message hides the box. Toasts are not shown in the
+// reduced-motion mode.
+func (i *Interface) ShowToast(message string) {
+	if i.config.ReducedMotion() {
+		return
+	}
+	i.toast.SetText(message)
+	i.toast.SetBorderColor(tcell.GetColor(i.theme.Warning))
+
+	height := 0
+	if message != "" {
+		height = strings.Count(message, "\n") + 3
+	}
+	i.root.ResizeItem(i.toast, height, 0)
+}
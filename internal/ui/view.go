@@ -4,96 +4,127 @@ import (
 	"fmt"
 	"log/slog"
 	"math"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 	"github.com/xsikor/go-battop/internal/battery"
+	"github.com/xsikor/go-battop/internal/format"
+	"github.com/xsikor/go-battop/internal/stats"
+	"github.com/xsikor/go-battop/pkg/plot"
 )
 
-// ChartData holds time-series data for charts
-type ChartData struct {
-	timestamps []time.Time
-	values     []float64
-	maxSize    int
-}
-
-// NewChartData creates new chart data storage
-func NewChartData(maxSize int) *ChartData {
-	return &ChartData{
-		timestamps: make([]time.Time, 0, maxSize),
-		values:     make([]float64, 0, maxSize),
-		maxSize:    maxSize,
-	}
-}
-
-// Add adds a new data point
-func (cd *ChartData) Add(value float64) {
-	cd.timestamps = append(cd.timestamps, time.Now())
-	cd.values = append(cd.values, value)
-
-	// Remove old data if we exceed max size
-	if len(cd.values) > cd.maxSize {
-		cd.timestamps = cd.timestamps[1:]
-		cd.values = cd.values[1:]
-	}
-}
-
 // View represents a single battery view
 type View struct {
 	root        *tview.Flex
 	infoText    *tview.TextView
+	infoMore    *tview.TextView
 	chargeGauge *tview.TextView
 	powerGauge  *tview.TextView
 	healthGauge *tview.TextView
 	chartArea   *tview.TextView
 
-	index      int
-	config     Config
-	lastUpdate time.Time
+	// icon is the optional large battery graphic above the gauges
+	icon *tview.TextView
+
+	index       int
+	config      Config
+	format      format.Formatter
+	theme       *Theme
+	trueColor   bool
+	info        *battery.Info
+	lastUpdate  time.Time
+	powerSource battery.PowerSource
+	stats       stats.Summary
+	status      func() string
+	reference   string
+	reserve     *stats.Reserve
+	goal        *stats.Goal
+	events      func() []stats.Event
+
+	// Charts; chartsMu guards charts, which Reconfigure replaces on the UI
+	// goroutine while History reads them from the API
+	charts   []*viewChart
+	chartsMu sync.RWMutex
+	chartSet *ChartSet
+
+	// Compact mode shows only the gauges and one selected chart, whatever
+	// the layout for the terminal width is
+	compact  bool
+	selected int
 
-	// Charts
-	voltageChart *Chart
-	powerChart   *Chart
-	chargeChart  *Chart
-	chartSet     *ChartSet
+	// layout is the layout for the terminal width
+	layout ViewLayout
+
+	// infoPanel is the flex holding the info text and its overflow
+	// indicator, nil in the compact layout
+	infoPanel *tview.Flex
+
+	// infoScroll is the first row of the info text shown
+	infoScroll int
+
+	// histogram shows the discharge power histogram instead of the charts
+	histogram bool
+
+	// details expands the serial, firmware, manufacture date and chemistry
+	details bool
+
+	// basis selects what the charge gauge percentage is relative to
+	basis ChargeBasis
+
+	// samples counts ingested readings to withhold estimates during warm-up
+	samples int
+
+	// power holds the last power readings for the gauge sparkline
+	power []float64
 
 	// Track chart dimensions
 	chartWidth  int
 	chartHeight int
 }
 
-// NewView creates a new battery view
-func NewView(index int, config Config) *View {
+// NewView creates a new battery view using the given formatter for values
+func NewView(index int, config Config, formatter format.Formatter) *View {
 	v := &View{
 		index:       index,
 		config:      config,
+		format:      formatter,
+		theme:       resolveTheme(config),
 		infoText:    tview.NewTextView(),
+		infoMore:    tview.NewTextView(),
 		chargeGauge: tview.NewTextView(),
 		powerGauge:  tview.NewTextView(),
 		healthGauge: tview.NewTextView(),
 		chartArea:   tview.NewTextView(),
+		icon:        tview.NewTextView(),
 		chartWidth:  DefaultChartWidth,
 		chartHeight: DefaultChartHeight,
+		root:        tview.NewFlex(),
+		compact:     config.CompactLayout(),
+		layout:      ViewLayoutHorizontal,
+		basis:       config.ChargeBasis(),
+		selected:    -1,
 	}
 
-	// Create charts
-	v.voltageChart = NewChart("Voltage", MaxChartDataPoints, "V", "yellow")
-	v.powerChart = NewChart("Power", MaxChartDataPoints, "W", "green")
-	v.chargeChart = NewChart("Charge", MaxChartDataPoints, "%", "cyan")
-
-	// Create chart set
+	// Create the configured charts
 	v.chartSet = NewChartSet()
-	v.chartSet.AddChart(v.voltageChart)
-	v.chartSet.AddChart(v.powerChart)
-	v.chartSet.AddChart(v.chargeChart)
+	v.chartSet.SetLayout(config.ChartLayout())
+	for _, spec := range chartSpecs {
+		chart := newViewChart(spec, config.ChartOptions(index, spec.Name))
+		v.charts = append(v.charts, chart)
+		v.chartSet.AddChart(chart.chart)
+	}
 
 	// Configure text views
 	v.infoText.SetDynamicColors(true).SetBackgroundColor(tcell.ColorDefault)
+	v.infoMore.SetDynamicColors(true).SetTextAlign(tview.AlignCenter).SetBackgroundColor(tcell.ColorDefault)
 	v.chargeGauge.SetDynamicColors(true).SetBackgroundColor(tcell.ColorDefault)
 	v.powerGauge.SetDynamicColors(true).SetBackgroundColor(tcell.ColorDefault)
 	v.healthGauge.SetDynamicColors(true).SetBackgroundColor(tcell.ColorDefault)
+	v.icon.SetDynamicColors(true).SetTextAlign(tview.AlignCenter).SetBackgroundColor(tcell.ColorDefault)
 
 	// Initialize text views with placeholder content
 	v.infoText.SetText("[gray]Loading battery information...[-]")
@@ -113,16 +144,33 @@ func NewView(index int, config Config) *View {
 
 // buildLayout builds the view layout
 func (v *View) buildLayout() {
-	slog.Debug("Building view layout")
+	layout := v.Layout()
+	slog.Debug("Building view layout", "layout", layout)
+	v.root.Clear()
+	v.infoPanel = nil
+
+	switch layout {
+	case ViewLayoutCompact:
+		v.buildCompactLayout()
+		return
+	case ViewLayoutVertical:
+		v.buildVerticalLayout()
+		return
+	}
 
 	// Main container (horizontal split)
-	v.root = tview.NewFlex().SetDirection(tview.FlexColumn)
+	v.root.SetDirection(tview.FlexColumn)
 
 	// Left panel (info and gauges)
 	leftPanel := tview.NewFlex().SetDirection(tview.FlexRow)
 
 	// Add battery info directly (no frame for now to test)
 	leftPanel.AddItem(v.infoText, 0, 2, false)
+	leftPanel.AddItem(v.infoMore, 0, 0, false)
+	v.infoPanel = leftPanel
+	if v.config.BatteryIcon() {
+		leftPanel.AddItem(v.icon, BatteryIconHeight, 0, false)
+	}
 
 	// Add gauges directly (no frames for now to test)
 	leftPanel.AddItem(v.chargeGauge, 1, 0, false)
@@ -130,17 +178,119 @@ func (v *View) buildLayout() {
 	leftPanel.AddItem(v.healthGauge, 1, 0, false)
 
 	// Right panel (charts) - no frame to maximize space
-	// Option 1: Use percentage-based layout (current implementation)
-	// Left panel gets 20% of space, right gets 80%
-	v.root.AddItem(leftPanel, 0, 1, false)  // 20% of space (1/5)
-	v.root.AddItem(v.chartArea, 0, 4, true) // 80% of space (4/5)
+	size := v.config.PanelSize()
+	if size.Mode == PanelFixed {
+		// Fixed width gives a consistent left panel regardless of terminal width
+		v.root.AddItem(leftPanel, size.Width, 0, false)
+		v.root.AddItem(v.chartArea, 0, 1, true)
+	} else {
+		v.root.AddItem(leftPanel, 0, size.Left, false)
+		v.root.AddItem(v.chartArea, 0, size.Right, true)
+	}
+
+	slog.Debug("Layout build complete", "mode", size.Mode, "left", size.Left, "right", size.Right, "width", size.Width)
+}
+
+// buildVerticalLayout stacks the info panel and gauges above the charts at
+// the full width of terminals too narrow to split
+func (v *View) buildVerticalLayout() {
+	v.root.SetDirection(tview.FlexRow)
+	v.root.AddItem(v.infoText, 0, 2, false)
+	v.root.AddItem(v.infoMore, 0, 0, false)
+	v.infoPanel = v.root
+	v.root.AddItem(v.chargeGauge, 1, 0, false)
+	v.root.AddItem(v.powerGauge, 1, 0, false)
+	v.root.AddItem(v.healthGauge, 1, 0, false)
+	v.root.AddItem(v.chartArea, 0, 3, true)
+}
 
-	// Option 2: Fixed width for left panel (uncomment to use)
-	// This gives consistent left panel size regardless of terminal width
-	// v.root.AddItem(leftPanel, 40, 0, false)  // Fixed 40 chars width
-	// v.root.AddItem(v.chartArea, 0, 1, true)  // Remaining space
+// buildCompactLayout stacks the gauges above a single chart for small panes
+func (v *View) buildCompactLayout() {
+	v.root.SetDirection(tview.FlexRow)
+	v.root.AddItem(v.chargeGauge, 1, 0, false)
+	v.root.AddItem(v.powerGauge, 1, 0, false)
+	v.root.AddItem(v.healthGauge, 1, 0, false)
+	v.root.AddItem(v.chartArea, 0, 1, true)
+}
 
-	slog.Debug("Layout build complete", "leftProportion", 1, "rightProportion", 4)
+// SetCompact switches between the compact layout and the layout for the
+// terminal width
+func (v *View) SetCompact(compact bool) {
+	if compact == v.compact {
+		return
+	}
+	v.compact = compact
+	v.buildLayout()
+}
+
+// SetLayout sets the layout for the terminal width, used unless the compact
+// layout was chosen
+func (v *View) SetLayout(layout ViewLayout) {
+	if layout == v.layout {
+		return
+	}
+	v.layout = layout
+	v.buildLayout()
+}
+
+// Layout returns the layout shown
+func (v *View) Layout() ViewLayout {
+	if v.compact {
+		return ViewLayoutCompact
+	}
+	return v.layout
+}
+
+// SetHistogram shows the discharge power histogram instead of the charts
+func (v *View) SetHistogram(histogram bool) {
+	v.histogram = histogram
+}
+
+// Histogram reports whether the discharge power histogram is shown
+func (v *View) Histogram() bool {
+	return v.histogram
+}
+
+// SetDetails expands or collapses the battery details section
+func (v *View) SetDetails(details bool) {
+	v.details = details
+}
+
+// Details reports whether the battery details section is expanded
+func (v *View) Details() bool {
+	return v.details
+}
+
+// Compact reports whether the compact layout was chosen
+func (v *View) Compact() bool {
+	return v.compact
+}
+
+// SetChargeBasis sets what the charge gauge percentage is relative to
+func (v *View) SetChargeBasis(basis ChargeBasis) {
+	v.basis = basis
+}
+
+// ChargeBasis returns what the charge gauge percentage is relative to
+func (v *View) ChargeBasis() ChargeBasis {
+	return v.basis
+}
+
+// compactChart returns the chart shown in compact mode: the selected one,
+// or the first visible chart when none was selected
+func (v *View) compactChart() *Chart {
+	if v.selected >= 0 && v.selected < len(v.charts) {
+		return v.charts[v.selected].chart
+	}
+	for _, chart := range v.charts {
+		if !chart.chart.Hidden() {
+			return chart.chart
+		}
+	}
+	if len(v.charts) > 0 {
+		return v.charts[0].chart
+	}
+	return nil
 }
 
 // GetRoot returns the root UI element
@@ -148,29 +298,229 @@ func (v *View) GetRoot() tview.Primitive {
 	return v.root
 }
 
-// Update updates the view with new battery information
+// SetPowerSource sets the system power source shown alongside the battery state
+func (v *View) SetPowerSource(source battery.PowerSource) {
+	v.powerSource = source
+}
+
+// SetChargeReference overlays a reference curve on the charge chart
+func (v *View) SetChargeReference(name string, reference func(time.Time) (float64, bool)) {
+	for _, chart := range v.charts {
+		if chart.spec.Name == "charge" {
+			v.reference = name
+			chart.chart.SetReference(name, reference)
+		}
+	}
+}
+
+// ToggleChart shows or hides the chart at the given position (0-based).
+// In compact mode it selects the chart to show instead.
+func (v *View) ToggleChart(position int) {
+	if position < 0 || position >= len(v.charts) {
+		return
+	}
+	if v.Layout() == ViewLayoutCompact {
+		v.selected = position
+		return
+	}
+	chart := v.charts[position].chart
+	chart.SetHidden(!chart.Hidden())
+}
+
+// Click collapses or expands the chart whose title is at the given screen
+// position and reports whether there was one
+func (v *View) Click(x, y int) bool {
+	if v.Layout() == ViewLayoutCompact || v.histogram {
+		return false
+	}
+	left, top, _, _ := v.chartArea.GetInnerRect()
+	if v.chartAreaTitleShown() {
+		top++
+	}
+	chart, ok := v.chartSet.ChartAt(x-left, y-top)
+	if !ok {
+		return false
+	}
+	chart.SetCollapsed(!chart.Collapsed())
+	return true
+}
+
+// ToggleChartLayout switches between the stacked and columns chart layouts
+func (v *View) ToggleChartLayout() {
+	if v.chartSet.Layout() == ChartLayoutColumns {
+		v.chartSet.SetLayout(ChartLayoutStacked)
+		return
+	}
+	v.chartSet.SetLayout(ChartLayoutColumns)
+}
+
+// SetInspecting shows or hides the inspection cursor of every chart
+func (v *View) SetInspecting(inspecting bool) {
+	for _, chart := range v.charts {
+		chart.chart.SetInspecting(inspecting)
+	}
+}
+
+// MoveCursor moves the inspection cursor of every chart by steps points
+func (v *View) MoveCursor(steps int) {
+	for _, chart := range v.charts {
+		chart.chart.MoveCursor(steps)
+	}
+}
+
+// SetAxisScale sets the value axis scale of every chart
+func (v *View) SetAxisScale(scale plot.Scale) {
+	for _, chart := range v.charts {
+		chart.chart.SetAxisScale(scale)
+	}
+}
+
+// SetZoom selects the plotted history of every chart
+func (v *View) SetZoom(level int) {
+	for _, chart := range v.charts {
+		chart.chart.SetZoom(level)
+	}
+}
+
+// Reconfigure applies changed settings: the formatter, chart options and
+// layout, and the panel size. The charts keep their recorded values.
+func (v *View) Reconfigure(formatter format.Formatter) {
+	v.format = formatter
+
+	v.chartsMu.Lock()
+	defer v.chartsMu.Unlock()
+	v.chartSet = NewChartSet()
+	v.chartSet.SetLayout(v.config.ChartLayout())
+	for i, spec := range chartSpecs {
+		options := v.config.ChartOptions(v.index, spec.Name)
+		chart := newViewChart(spec, options)
+		old := v.charts[i]
+		chart.design = old.design
+		factor := chart.factor() / old.factor()
+		chart.chart.adopt(old.chart, factor, chart.offset-old.offset*factor)
+		if options.Scale == "" {
+			// Keep the scale chosen with the y key
+			chart.chart.SetAxisScale(old.chart.plot.Scale())
+		}
+		v.charts[i] = chart
+		v.chartSet.AddChart(chart.chart)
+	}
+
+	v.buildLayout()
+}
+
+// SetTheme sets the theme used for the next render
+func (v *View) SetTheme(theme *Theme) {
+	v.theme = theme
+}
+
+// SetTrueColor enables 24-bit gradient gauges and chart points
+func (v *View) SetTrueColor(enabled bool) {
+	v.trueColor = enabled
+}
+
+// SetStatusProvider sets a function returning a status line (e.g., recording state)
+func (v *View) SetStatusProvider(status func() string) {
+	v.status = status
+}
+
+// SetReserve sets the unplugged period planned against the remaining charge (nil clears it)
+func (v *View) SetReserve(reserve *stats.Reserve) {
+	v.reserve = reserve
+}
+
+// SetGoal sets the time the charge should last until, which the power is
+// rated against (nil clears it)
+func (v *View) SetGoal(goal *stats.Goal) {
+	v.goal = goal
+}
+
+// SetEvents sets a function returning the logged events, marked on the
+// charts (nil for none)
+func (v *View) SetEvents(events func() []stats.Event) {
+	v.events = events
+}
+
+// History returns the in-memory series of every chart, safe to call from other goroutines
+func (v *View) History() []ChartHistory {
+	var notes []stats.Event
+	if v.events != nil {
+		notes = eventNotes(v.events())
+	}
+	v.chartsMu.RLock()
+	defer v.chartsMu.RUnlock()
+	history := make([]ChartHistory, 0, len(v.charts))
+	for _, chart := range v.charts {
+		entry := chart.history(v.index)
+		entry.Notes = notes
+		history = append(history, entry)
+	}
+	return history
+}
+
+// SetStats sets the statistics summary shown in the info panel
+func (v *View) SetStats(summary stats.Summary) {
+	v.stats = summary
+}
+
+// Update ingests new battery information and renders the view
 func (v *View) Update(info *battery.Info) {
+	v.Ingest(info)
+	v.Render()
+}
+
+// Ingest records new battery information in the chart history without
+// rendering, so views in background tabs keep accumulating data cheaply
+func (v *View) Ingest(info *battery.Info) {
+	v.info = info
 	v.lastUpdate = time.Now()
-	slog.Debug("Updating view", "batteryIndex", v.index)
+	if info.State == battery.StateNotPresent {
+		return
+	}
+	v.samples++
+	v.power = append(v.power, math.Abs(info.ChargeRate))
+	if len(v.power) > SparklineWidth {
+		v.power = v.power[len(v.power)-SparklineWidth:]
+	}
 
 	// Update chart data
-	v.voltageChart.AddValue(info.Voltage)
-
-	// Convert power to human-readable units if needed
-	power := info.ChargeRate
-	if v.config != nil {
-		// For chart display, use raw watts
-		power = info.ChargeRate / 1000.0
+	for _, chart := range v.charts {
+		chart.add(info)
+		if v.warmingUp() {
+			chart.chart.SetWarmupUntil(info.UpdatedAt)
+		}
 	}
-	v.powerChart.AddValue(power)
+}
 
-	v.chargeChart.AddValue(info.ChargePercent())
+// Refresh replaces the shown battery information without recording it in
+// the charts
+func (v *View) Refresh(info *battery.Info) {
+	v.info = info
+	v.lastUpdate = time.Now()
+}
+
+// warmingUp reports whether the first readings, whose rates are often bogus
+// (0 W followed by a spike), are still coming in
+func (v *View) warmingUp() bool {
+	return v.samples <= v.config.WarmupSamples()
+}
+
+// Render redraws the info panel, gauges and charts from the latest ingested data
+func (v *View) Render() {
+	info := v.info
+	if info == nil {
+		return
+	}
+	slog.Debug("Rendering view", "batteryIndex", v.index)
 
 	// Update info text
 	v.updateInfoText(info)
 
 	// Update gauges
 	v.updateGauges(info)
+	if v.config.BatteryIcon() {
+		v.updateIcon(info)
+	}
 
 	// Update charts with current dimensions
 	_, _, w, h := v.chartArea.GetInnerRect()
@@ -189,26 +539,203 @@ func (v *View) Update(info *battery.Info) {
 // updateInfoText updates the battery information display
 func (v *View) updateInfoText(info *battery.Info) {
 	var text strings.Builder
+	if info.State == battery.StateNotPresent {
+		v.addEmptySlot(&text)
+		v.infoText.SetText(text.String())
+		v.updateInfoScroll()
+		return
+	}
 
-	// Build each section
+	// Build each section, most important first so small terminals show the
+	// state and estimates before having to scroll
 	v.addBatteryState(&text, info)
+	v.addPowerSource(&text, info)
+	v.addBatteryTimeRemaining(&text, info)
+	v.addReserve(&text, info)
+	v.addGoal(&text, info)
+	v.addStatus(&text)
 	v.addSeparator(&text)
-	v.addBatteryIdentity(&text, info)
-	v.addBatteryVoltage(&text, info)
 	v.addBatteryCapacity(&text, info)
-	v.addBatteryTimeRemaining(&text, info)
+	v.addChargeLimit(&text, info)
 	v.addBatteryCycles(&text, info)
+	v.addBatteryTemperature(&text, info)
+	v.addBatteryVoltage(&text, info)
+	v.addSessionStats(&text)
+	v.addDrainStats(&text)
+	text.WriteString("\n")
+	v.addBatteryIdentity(&text, info)
+	v.addBatteryDetails(&text, info)
 	v.addUpdateTimestamp(&text)
 
 	finalText := text.String()
 	slog.Debug("Updated info text", "length", len(finalText), "lines", strings.Count(finalText, "\n"))
 	v.infoText.SetText(finalText)
+	v.updateInfoScroll()
+}
+
+// infoRows returns the number of rows the info text takes when wrapped at
+// the given width
+func (v *View) infoRows(width int) int {
+	lines := strings.Split(v.infoText.GetText(false), "\n")
+	if width <= 0 {
+		return len(lines)
+	}
+	rows := 0
+	for _, line := range lines {
+		rows += max(1, (tview.TaggedStringWidth(line)+width-1)/width)
+	}
+	return rows
+}
+
+// updateInfoScroll keeps the scroll position of the info text within the
+// text and shows the overflow indicator below it while rows are hidden
+func (v *View) updateInfoScroll() {
+	if v.infoPanel == nil {
+		return
+	}
+	_, _, width, height := v.infoText.GetInnerRect()
+	_, _, _, shown := v.infoMore.GetRect()
+	if height <= 0 {
+		// Not drawn yet
+		return
+	}
+
+	// The indicator takes a row of the info text while it's shown
+	available := height + shown
+	rows := v.infoRows(width)
+	visible := available
+	if rows > available {
+		visible = available - 1
+	}
+	v.infoScroll = max(min(v.infoScroll, rows-visible), 0)
+	v.infoText.ScrollTo(v.infoScroll, 0)
+
+	if visible == available {
+		v.infoMore.SetText("")
+		v.infoPanel.ResizeItem(v.infoMore, 0, 0)
+		return
+	}
+	var hints []string
+	if v.infoScroll > 0 {
+		hints = append(hints, fmt.Sprintf("↑ %d", v.infoScroll))
+	}
+	if below := rows - v.infoScroll - visible; below > 0 {
+		hints = append(hints, fmt.Sprintf("↓ %d more", below))
+	}
+	v.infoMore.SetText("[gray]" + strings.Join(hints, " ") + " [yellow]j[gray]/[yellow]k[-]")
+	v.infoPanel.ResizeItem(v.infoMore, 1, 0)
+}
+
+// ScrollInfo scrolls the info text by the given number of rows, down for
+// positive ones
+func (v *View) ScrollInfo(rows int) {
+	v.infoScroll = max(v.infoScroll+rows, 0)
+	v.updateInfoScroll()
+}
+
+// PageInfo scrolls the info text by the given number of pages
+func (v *View) PageInfo(pages int) {
+	_, _, _, height := v.infoText.GetInnerRect()
+	v.ScrollInfo(pages * max(height-1, 1))
+}
+
+// InfoAt reports whether a screen position is on the info text
+func (v *View) InfoAt(x, y int) bool {
+	if v.infoPanel == nil {
+		return false
+	}
+	left, top, width, height := v.infoText.GetRect()
+	return x >= left && x < left+width && y >= top && y < top+height
+}
+
+// addEmptySlot describes a detected battery bay without a battery
+func (v *View) addEmptySlot(text *strings.Builder) {
+	fmt.Fprintf(text, "[white:b]%s Bay %d: empty[-]\n", StateIcon(battery.StateNotPresent), v.index+1)
+	fmt.Fprintf(text, "\n[gray]The slot is detected but no battery is inserted.[-]\n")
+	v.addUpdateTimestamp(text)
 }
 
 // addBatteryState adds the battery state line
 func (v *View) addBatteryState(text *strings.Builder, info *battery.Info) {
-	stateColor := getStateColor(info.State)
-	fmt.Fprintf(text, "[%s:b]%s[-]\n", stateColor, info.State.String())
+	label := StateIcon(info.State) + " " + info.State.String()
+	if info.State == battery.StateUnknown {
+		fmt.Fprintf(text, "[white:b]%s[-]\n", label)
+		return
+	}
+	fmt.Fprintf(text, "[::b]%s[::-]\n", v.theme.Label(StateLevel(info.State), label))
+	v.addWarningLevel(text, info)
+}
+
+// addWarningLevel adds the low battery warning level reported by the source
+func (v *View) addWarningLevel(text *strings.Builder, info *battery.Info) {
+	switch info.Warning {
+	case "":
+		return
+	case "critical", "action":
+		fmt.Fprintf(text, "[%s::b]! Battery level %s[-::-]\n", v.theme.Critical, info.Warning)
+	default:
+		fmt.Fprintf(text, "[%s::b]! Battery level %s[-::-]\n", v.theme.Warning, info.Warning)
+	}
+}
+
+// addPowerSource adds the AC/battery power source line with charger details
+func (v *View) addPowerSource(text *strings.Builder, info *battery.Info) {
+	if !v.powerSource.OnAC {
+		fmt.Fprintf(text, "%s\n", v.theme.Label(LevelWarning, v.powerSource.String()))
+		return
+	}
+
+	text.WriteString(v.theme.Label(LevelExcellent, v.powerSource.String()))
+	if details := v.chargerDetails(); details != "" {
+		fmt.Fprintf(text, " [gray](%s)[-]", details)
+	}
+	text.WriteString("\n")
+
+	v.addChargerWarning(text, info)
+	v.addChargeTarget(text, info)
+}
+
+// addChargeTarget adds a reminder to unplug once the charge passed the target
+func (v *View) addChargeTarget(text *strings.Builder, info *battery.Info) {
+	target := v.config.ChargeTargetPercent()
+	if target <= 0 || !v.powerSource.OnAC || info.ChargePercent() < target {
+		return
+	}
+	fmt.Fprintf(text, "[%s::b]! Charge target %s reached, unplug[-::-]\n", v.theme.Warning, v.format.Percent(target))
+}
+
+// addChargerWarning adds a prominent warning when the charger can't keep up with the draw
+func (v *View) addChargerWarning(text *strings.Builder, info *battery.Info) {
+	balance := v.powerSource.Balance([]*battery.Info{info}, v.stats.Active.AverageRate())
+	if !balance.Underpowered {
+		return
+	}
+
+	if watts := balance.RecommendedWatts(); watts > 0 {
+		fmt.Fprintf(text, "[%s::b]! Charger underpowered (needs ≥ %.0f W)[-::-]\n", v.theme.Critical, watts)
+		return
+	}
+	fmt.Fprintf(text, "[%s::b]! Charger underpowered[-::-]\n", v.theme.Critical)
+}
+
+// chargerDetails describes the primary adapter's kind and negotiated profile
+func (v *View) chargerDetails() string {
+	adapter, ok := v.powerSource.Primary()
+	if !ok {
+		return ""
+	}
+
+	details := make([]string, 0, 3)
+	if adapter.Kind != battery.ChargerUnknown {
+		details = append(details, adapter.Kind.String())
+	}
+	if adapter.Voltage > 0 && adapter.Current > 0 {
+		details = append(details, v.format.Voltage(adapter.Voltage)+" / "+v.format.Current(adapter.Current))
+	}
+	if adapter.MaxPower > 0 {
+		details = append(details, v.format.Power(adapter.MaxPower))
+	}
+	return strings.Join(details, ", ")
 }
 
 // addSeparator adds a visual separator line
@@ -224,122 +751,547 @@ func (v *View) addBatteryIdentity(text *strings.Builder, info *battery.Info) {
 	if info.Model != "" {
 		fmt.Fprintf(text, "[cyan]Model:[-]     %s\n", info.Model)
 	}
-	fmt.Fprintf(text, "[cyan]Type:[-]      %s\n", info.Technology)
+	technology := info.Technology
+	if technology == "" {
+		technology = "[gray]" + Unavailable + "[-]"
+	}
+	fmt.Fprintf(text, "[cyan]Type:[-]      %s\n", technology)
+}
+
+// addBatteryDetails adds the expandable serial, firmware, manufacture date and
+// chemistry section
+func (v *View) addBatteryDetails(text *strings.Builder, info *battery.Info) {
+	if !v.details {
+		fmt.Fprintf(text, "[gray]▸ Details (d)[-]\n")
+		return
+	}
+	fmt.Fprintf(text, "[gray]▾ Details (d)[-]\n")
+
+	unavailable := "[gray]" + Unavailable + "[-]"
+	fmt.Fprintf(text, "[cyan]Serial:[-]    %s\n", battery.Coalesce(tview.Escape(info.Serial), unavailable))
+	fmt.Fprintf(text, "[cyan]Firmware:[-]  %s\n", battery.Coalesce(tview.Escape(info.Firmware), unavailable))
+
+	if info.ManufactureDate == "" {
+		fmt.Fprintf(text, "[cyan]Made:[-]      %s\n", unavailable)
+	} else {
+		fmt.Fprintf(text, "[cyan]Made:[-]      %s", info.ManufactureDate)
+		var notes []string
+		if made, err := time.Parse(battery.ManufactureDateLayout, info.ManufactureDate); err == nil && time.Since(made) > 0 {
+			notes = append(notes, fmt.Sprintf("%.1f years ago", time.Since(made).Hours()/24/365.25))
+		}
+		if info.ManufactureDateFromSerial {
+			notes = append(notes, "from serial")
+		}
+		if len(notes) > 0 {
+			fmt.Fprintf(text, " [gray](%s)[-]", strings.Join(notes, ", "))
+		}
+		text.WriteString("\n")
+	}
+
+	chemistry, ok := battery.ChemistryOf(battery.Coalesce(info.Chemistry, info.Technology))
+	if !ok {
+		fmt.Fprintf(text, "[cyan]Chemistry:[-] %s\n", battery.Coalesce(tview.Escape(info.Chemistry), unavailable))
+		return
+	}
+	fmt.Fprintf(text, "[cyan]Chemistry:[-] %s\n", chemistry.Name)
+	fmt.Fprintf(text, "           [gray]%s nominal, %s–%s per cell[-]\n",
+		v.format.Voltage(chemistry.NominalCellVoltage), v.format.Voltage(chemistry.MinCellVoltage), v.format.Voltage(chemistry.MaxCellVoltage))
 }
 
 // addBatteryVoltage adds voltage information
 func (v *View) addBatteryVoltage(text *strings.Builder, info *battery.Info) {
-	fmt.Fprintf(text, "[cyan]Voltage:[-]   %s ", v.config.FormatVoltage(info.Voltage))
-	fmt.Fprintf(text, "[gray](design: %s)[-]\n\n", v.config.FormatVoltage(info.DesignVoltage))
+	if info.Voltage <= 0 {
+		fmt.Fprintf(text, "[cyan]Voltage:[-]   [gray]%s[-]\n", Unavailable)
+		return
+	}
+	fmt.Fprintf(text, "[cyan]Voltage:[-]   %s", v.format.Voltage(info.Voltage))
+	if info.DesignVoltage > 0 {
+		fmt.Fprintf(text, " [gray](design: %s)[-]", v.format.Voltage(info.DesignVoltage))
+	}
+	text.WriteString("\n")
+	v.addCellVoltage(text, info)
+}
+
+// addCellVoltage adds the estimated voltage per cell, in the warning color
+// when low and the critical color when out of the chemistry's range
+func (v *View) addCellVoltage(text *strings.Builder, info *battery.Info) {
+	estimate, ok := battery.EstimateCells(info)
+	if !ok {
+		return
+	}
+	cells := fmt.Sprintf("≈ %d×%s/cell", estimate.Cells, v.format.Voltage(estimate.Voltage))
+	switch estimate.Status() {
+	case battery.CellLow:
+		cells = v.theme.Label(LevelWarning, cells+" low")
+	case battery.CellUndervoltage:
+		cells = v.theme.Label(LevelCritical, cells+" undervoltage")
+	case battery.CellOvervoltage:
+		cells = v.theme.Label(LevelCritical, cells+" overvoltage")
+	default:
+		cells = "[gray]" + cells + "[-]"
+	}
+	fmt.Fprintf(text, "           %s\n", cells)
 }
 
 // addBatteryCapacity adds capacity and health information
 func (v *View) addBatteryCapacity(text *strings.Builder, info *battery.Info) {
-	fmt.Fprintf(text, "[cyan]Current:[-]   %s\n", v.config.FormatEnergy(info.Current))
-	fmt.Fprintf(text, "[cyan]Full:[-]      %s ", v.config.FormatEnergy(info.Full))
+	volts := info.NominalVoltage()
+
+	// Without a design capacity neither the design percentage nor the health is known
+	if info.Design <= 0 {
+		fmt.Fprintf(text, "[cyan]Current:[-]   %s\n", v.format.Capacity(info.Current, volts))
+		fmt.Fprintf(text, "[cyan]Full:[-]      %s\n", v.format.Capacity(info.Full, volts))
+		fmt.Fprintf(text, "[cyan]Design:[-]    [gray]%s[-]\n", Unavailable)
+		return
+	}
+
+	fmt.Fprintf(text, "[cyan]Current:[-]   %s ", v.format.Capacity(info.Current, volts))
+	fmt.Fprintf(text, "[gray](%s of design)[-]\n", v.format.Percent(info.DesignPercent()))
+	fmt.Fprintf(text, "[cyan]Full:[-]      %s ", v.format.Capacity(info.Full, volts))
 
 	// Show battery health as percentage of design capacity
 	health := info.Health()
-	healthColor := getHealthColor(health)
-	fmt.Fprintf(text, "[gray]([%s]%.1f%%[-] health)[-]\n", healthColor, health)
+	healthColor := v.theme.Color(LevelByThreshold(health, ColorThresholdsHealth))
+	fmt.Fprintf(text, "[gray]([%s]%s[gray] health)[-]\n", healthColor, v.format.Percent(health))
+
+	fmt.Fprintf(text, "[cyan]Design:[-]    %s\n", v.format.Capacity(info.Design, volts))
+}
 
-	fmt.Fprintf(text, "[cyan]Design:[-]    %s\n", v.config.FormatEnergy(info.Design))
+// addChargeLimit explains why charging stops early when a vendor charge limit is active
+func (v *View) addChargeLimit(text *strings.Builder, info *battery.Info) {
+	if info.ChargeLimit == nil {
+		return
+	}
+	fmt.Fprintf(text, "[cyan]Charge limit:[-] %s [gray](%s ON)[-]\n",
+		v.format.Percent(info.ChargeLimit.Percent), info.ChargeLimit.Mode)
 }
 
 // addBatteryTimeRemaining adds time to empty/full information
 func (v *View) addBatteryTimeRemaining(text *strings.Builder, info *battery.Info) {
-	if info.State == battery.StateDischarging {
-		if tte := info.TimeToEmpty(); tte > 0 {
-			fmt.Fprintf(text, "\n[orange]Time remaining: %s[-]\n", formatDuration(tte))
+	state := info.State.Base()
+	if v.warmingUp() && (state == battery.StateDischarging || state == battery.StateCharging) {
+		fmt.Fprintf(text, "\n[gray]Estimating... (warming up)[-]\n")
+		return
+	}
+
+	mode := v.config.EstimateMode()
+	tte, ttf := estimatedTimes(info, mode)
+	confidence := estimateConfidence(info, mode)
+	instantConfidence := estimateConfidence(info, EstimateInstant)
+
+	if state == battery.StateDischarging && tte > 0 {
+		fmt.Fprintf(text, "\n[%s]Time remaining: %s[-]", v.theme.Warning, confidence.Mark(v.format.Duration(tte)))
+		if factor := info.TemperatureFactor(); factor < 1 {
+			fmt.Fprintf(text, " [aqua](cold, -%s)[-]", v.format.Percent((1-factor)*100))
+		}
+		if instant := info.TimeToEmpty(); mode == EstimateBoth && instant > 0 {
+			fmt.Fprintf(text, " [gray](instant %s)[-]", instantConfidence.Mark(v.format.Duration(instant)))
 		}
+		text.WriteString("\n")
+		v.addActiveUseRemaining(text, info)
+		v.addLoadEstimates(text, info)
+	}
+	if state == battery.StateCharging && ttf > 0 {
+		fmt.Fprintf(text, "\n[%s]Time to full: %s[-]", v.theme.Excellent, confidence.Mark(v.format.Duration(ttf)))
+		if instant := info.TimeToFull(); mode == EstimateBoth && instant > 0 {
+			fmt.Fprintf(text, " [gray](instant %s)[-]", instantConfidence.Mark(v.format.Duration(instant)))
+		}
+		text.WriteString("\n")
+	}
+}
+
+// addActiveUseRemaining adds the time left at the active drain rate, which is
+// more realistic than the raw estimate when it was measured while idle
+func (v *View) addActiveUseRemaining(text *strings.Builder, info *battery.Info) {
+	remaining := v.stats.ActiveUseRemaining(info.UsableEnergy())
+	if remaining <= 0 {
+		return
+	}
+	fmt.Fprintf(text, "[%s]Active use:     %s[-] [gray](at %s)[-]\n",
+		v.theme.Warning, v.stats.ActiveConfidence().Mark(v.format.Duration(remaining)), v.format.Power(v.stats.ActiveRate()))
+}
+
+// addLoadEstimates adds the time left at the current, the session average
+// and the session median draw, giving a range instead of one jumpy number
+func (v *View) addLoadEstimates(text *strings.Builder, info *battery.Info) {
+	if v.stats.Discharge.Total() <= 0 {
+		return
+	}
+
+	energy := info.UsableEnergy()
+	loads := []struct {
+		name string
+		rate float64
+	}{
+		{"now", -info.ChargeRate},
+		{"average", v.stats.DischargeRate()},
+		{"median", v.stats.Discharge.Median()},
 	}
-	if info.State == battery.StateCharging {
-		if ttf := info.TimeToFull(); ttf > 0 {
-			fmt.Fprintf(text, "\n[green]Time to full: %s[-]\n", formatDuration(ttf))
+	fmt.Fprintf(text, "[cyan]Life at load:[-]\n")
+	for _, load := range loads {
+		remaining := stats.RemainingAt(energy, load.rate)
+		if remaining <= 0 {
+			continue
 		}
+		fmt.Fprintf(text, "  %-8s %s [gray](%s)[-]\n", load.name, v.format.Duration(remaining), v.format.Power(load.rate))
+	}
+}
+
+// addReserve adds the countdown of the planned unplugged period and whether
+// the remaining charge covers it at the typical draw
+func (v *View) addReserve(text *strings.Builder, info *battery.Info) {
+	if v.reserve == nil || v.warmingUp() {
+		return
+	}
+
+	draw, confidence := v.stats.ActiveRate(), v.stats.ActiveConfidence()
+	if draw <= 0 && info.SmoothedChargeRate < 0 {
+		draw, confidence = -info.SmoothedChargeRate, info.EstimateConfidence
+	}
+	plan, ok := v.reserve.Plan(info.UsableEnergy(), draw, time.Now())
+	if !ok {
+		return
+	}
+
+	fmt.Fprintf(text, "\n[cyan]Reserve:[-]   %s [gray](%s left)[-]\n", v.reserve, v.format.Duration(plan.Left))
+	switch {
+	case !plan.Known:
+		fmt.Fprintf(text, "[gray]           waiting for discharge data[-]\n")
+	case plan.Short():
+		fmt.Fprintf(text, "[%s::b]! Falls short by %s[-::-]\n", v.theme.Critical, confidence.Mark(v.format.Duration(-plan.Margin())))
+	default:
+		fmt.Fprintf(text, "[%s]           covered, %s to spare[-]\n", v.theme.Excellent, confidence.Mark(v.format.Duration(plan.Margin())))
+	}
+}
+
+// addGoal adds the countdown to the battery life goal and the power budget
+// that makes the remaining charge last until then
+func (v *View) addGoal(text *strings.Builder, info *battery.Info) {
+	budget, left, ok := v.powerBudget(info)
+	if !ok {
+		return
+	}
+
+	fmt.Fprintf(text, "\n[cyan]Goal:[-]      %s [gray](%s left)[-]\n", v.goal, v.format.Duration(left))
+	draw := -info.ChargeRate
+	if info.State.Base() != battery.StateDischarging || draw <= budget {
+		fmt.Fprintf(text, "[gray]           budget %s[-]\n", v.format.Power(budget))
+		return
+	}
+	fmt.Fprintf(text, "[%s::b]! %s over the %s budget[-::-]\n", v.theme.Critical, v.format.Power(draw-budget), v.format.Power(budget))
+}
+
+// powerBudget returns the average draw in mW at which the remaining charge
+// lasts until the goal, and the time left; false without a goal or once it
+// passed
+func (v *View) powerBudget(info *battery.Info) (float64, time.Duration, bool) {
+	if v.goal == nil || info == nil {
+		return 0, 0, false
+	}
+	return v.goal.Budget(info.UsableEnergy(), time.Now())
+}
+
+// budgetLevel rates a draw in mW against the power budget
+func budgetLevel(draw, budget float64) Level {
+	switch {
+	case draw <= budget*GoalComfortRatio:
+		return LevelExcellent
+	case draw <= budget:
+		return LevelWarning
+	default:
+		return LevelCritical
 	}
 }
 
-// addBatteryCycles adds cycle count if available
+// addBatteryCycles adds cycle count if the platform reports it
 func (v *View) addBatteryCycles(text *strings.Builder, info *battery.Info) {
-	if info.CycleCount > 0 {
-		fmt.Fprintf(text, "\n[cyan]Cycles:[-]    %d\n", info.CycleCount)
+	if !info.Capabilities.HasCycles || info.CycleCount <= 0 {
+		return
+	}
+	fmt.Fprintf(text, "[cyan]Cycles:[-]    %d\n", info.CycleCount)
+}
+
+// addBatteryTemperature adds the battery temperature if the platform reports it
+func (v *View) addBatteryTemperature(text *strings.Builder, info *battery.Info) {
+	if !info.Capabilities.HasTemperature {
+		return
+	}
+	fmt.Fprintf(text, "[cyan]Temp:[-]      %s\n", v.format.Temperature(info.Temperature))
+}
+
+// addSessionStats adds the net energy used since battop started
+func (v *View) addSessionStats(text *strings.Builder) {
+	session := v.stats.Session
+	if session.Duration <= 0 {
+		return
+	}
+
+	volts := 0.0
+	if v.info != nil {
+		volts = v.info.NominalVoltage()
+	}
+	fmt.Fprintf(text, "\n[cyan]Session:[-]   %s over %s, avg %s\n",
+		v.format.Capacity(session.Net(), volts),
+		formatChartDuration(session.Duration),
+		v.format.Power(math.Abs(session.AverageRate())))
+	v.addChargingCost(text, session, volts)
+}
+
+// addChargingCost adds the estimated price and carbon emissions of the energy
+// charged this session when a tariff is configured
+func (v *View) addChargingCost(text *strings.Builder, session stats.Session, volts float64) {
+	tariff := v.config.EnergyTariff()
+	if !tariff.Enabled() || session.Charged <= 0 {
+		return
+	}
+
+	var parts []string
+	if tariff.Price > 0 {
+		parts = append(parts, "≈ "+formatCost(tariff.Cost(session.Charged), tariff.Currency))
+	}
+	if tariff.Carbon > 0 {
+		parts = append(parts, formatEmissions(tariff.Emissions(session.Charged)))
+	}
+	fmt.Fprintf(text, "[gray]           charged %s %s[-]\n", v.format.Capacity(session.Charged, volts), strings.Join(parts, ", "))
+}
+
+// formatCost formats a price with more decimals for the small amounts a
+// charging session costs
+func formatCost(amount float64, currency string) string {
+	decimals := 2
+	if amount < 1 {
+		decimals = 3
+	}
+	return strings.TrimSpace(strconv.FormatFloat(amount, 'f', decimals, 64) + " " + currency)
+}
+
+// formatEmissions formats carbon emissions given in g CO₂
+func formatEmissions(grams float64) string {
+	if grams >= 1000 {
+		return fmt.Sprintf("%.2f kg CO₂", grams/1000)
+	}
+	return fmt.Sprintf("%.1f g CO₂", grams)
+}
+
+// addDrainStats adds the average discharge power, split into active and idle
+// when idle samples were recorded
+func (v *View) addDrainStats(text *strings.Builder) {
+	active, idle := v.stats.Active, v.stats.Idle
+	if active.Duration <= 0 && idle.Duration <= 0 {
+		return
+	}
+
+	if idle.Duration <= 0 {
+		fmt.Fprintf(text, "\n[cyan]Drain:[-]     avg %s\n", v.format.Power(active.AverageRate()))
+		return
+	}
+
+	fmt.Fprintf(text, "\n[cyan]Drain:[-]     active %s [gray](%s)[-]\n",
+		v.format.Power(active.AverageRate()), v.format.Duration(active.Duration))
+	fmt.Fprintf(text, "           idle %s [gray](%s)[-]\n",
+		v.format.Power(idle.AverageRate()), v.format.Duration(idle.Duration))
+}
+
+// addStatus adds the recording status and reference curve name
+func (v *View) addStatus(text *strings.Builder) {
+	if v.status != nil {
+		if status := v.status(); status != "" {
+			fmt.Fprintf(text, "\n[%s]● %s[-]\n", v.theme.Critical, status)
+		}
+	}
+	if v.reference != "" {
+		fmt.Fprintf(text, "[gray]Reference: %s[-]\n", v.reference)
 	}
 }
 
 // addUpdateTimestamp adds the last update timestamp
 func (v *View) addUpdateTimestamp(text *strings.Builder) {
-	fmt.Fprintf(text, "\n[gray]Updated: %s[-]", v.lastUpdate.Format(TimeFormat))
+	fmt.Fprintf(text, "\n[gray]Updated: %s[-]", v.format.Timestamp(v.lastUpdate))
 }
 
 // updateGauges updates the gauge displays
 func (v *View) updateGauges(info *battery.Info) {
+	if info.State == battery.StateNotPresent {
+		for _, gauge := range []*tview.TextView{v.chargeGauge, v.powerGauge, v.healthGauge} {
+			gauge.SetText(fmt.Sprintf(" [gray]%s (empty bay)[-]", Unavailable))
+		}
+		return
+	}
 	v.updateChargeGauge(info)
 	v.updatePowerGauge(info)
 	v.updateHealthGauge(info)
 }
 
+// updateIcon redraws the battery graphic for the charge level and state
+func (v *View) updateIcon(info *battery.Info) {
+	if info.State == battery.StateNotPresent {
+		v.icon.SetText(BatteryIcon(0, "gray", false))
+		return
+	}
+	percent := info.ChargePercent()
+	level := LevelByThreshold(percent, ColorThresholdsDefault)
+	v.icon.SetText(BatteryIcon(percent, v.theme.Color(level), info.State.Base() == battery.StateCharging))
+}
+
 // updateChargeGauge updates the charge gauge display
 func (v *View) updateChargeGauge(info *battery.Info) {
-	chargePercent := info.ChargePercent()
-	chargeColor := getChargeColor(chargePercent)
-	chargeBar := CreateProgressBar(chargePercent, ProgressBarWidth, ProgressBarStyleASCII)
-	chargeText := fmt.Sprintf(" [%s]%s[-] [%s]%.1f%%[-]", chargeColor, chargeBar, chargeColor, chargePercent)
+	chargePercent := basisPercent(info, v.basis)
+	chargeLevel := LevelByThreshold(chargePercent, ColorThresholdsDefault)
+	chargeText := " " + v.gauge(chargePercent, chargeLevel, "E", "F")
+	if v.basis == ChargeBasisDesign && info.Design > 0 {
+		chargeText += " [gray]of original capacity[-]"
+	}
 	v.chargeGauge.SetText(chargeText)
 	slog.Debug("Updated charge gauge", "percent", chargePercent, "text", chargeText)
 }
 
-// updatePowerGauge updates the power gauge display
+// updatePowerGauge updates the power gauge display with a sparkline of the
+// recent power readings
 func (v *View) updatePowerGauge(info *battery.Info) {
 	var powerText string
 	absPower := math.Abs(info.ChargeRate)
 
-	// Idle state
-	if info.ChargeRate == 0 {
-		powerText = fmt.Sprintf(" [gray]=== IDLE[-] [gray]%s[-]", v.config.FormatPower(0))
-		v.powerGauge.SetText(powerText)
-		slog.Debug("Updated power gauge", "chargeRate", info.ChargeRate, "text", powerText)
-		return
+	switch {
+	case info.ChargeRate == 0:
+		powerText = fmt.Sprintf(" [gray]=== IDLE[-] [gray]%s[-]", v.format.Power(0))
+	case info.ChargeRate > 0:
+		powerText = fmt.Sprintf(" %s [white]%s[-]", v.theme.Label(LevelExcellent, ">>> CHARGING"), v.format.Power(absPower))
+	default:
+		powerText = fmt.Sprintf(" %s [white]%s[-]", v.theme.Label(LevelWarning, "<<< DISCHARGING"), v.format.Power(absPower))
+		if budget, _, ok := v.powerBudget(info); ok {
+			powerText = fmt.Sprintf(" %s %s [gray]of %s budget[-]", v.theme.Label(LevelWarning, "<<< DISCHARGING"),
+				v.theme.Label(budgetLevel(absPower, budget), v.format.Power(absPower)), v.format.Power(budget))
+		}
 	}
-
-	// Charging
-	if info.ChargeRate > 0 {
-		powerText = fmt.Sprintf(" [green]>>> CHARGING[-] [white]%s[-]", v.config.FormatPower(absPower))
-		v.powerGauge.SetText(powerText)
-		slog.Debug("Updated power gauge", "chargeRate", info.ChargeRate, "text", powerText)
-		return
+	if rate := v.cRate(info); rate != "" {
+		powerText += " [gray](" + rate + ")[-]"
+	}
+	if len(v.power) > 1 {
+		powerText += " [cyan]" + CreateSparkline(v.power, SparklineWidth) + "[-]"
 	}
-
-	// Discharging
-	powerText = fmt.Sprintf(" [orange]<<< DISCHARGING[-] [white]%s[-]", v.config.FormatPower(absPower))
 	v.powerGauge.SetText(powerText)
 	slog.Debug("Updated power gauge", "chargeRate", info.ChargeRate, "text", powerText)
 }
 
+// cRate formats the power relative to the design capacity when the power
+// chart shows the C-rate, or returns ""
+func (v *View) cRate(info *battery.Info) string {
+	for _, chart := range v.charts {
+		if chart.spec.Name == "power" && chart.perDesign && info.Design > 0 {
+			return fmt.Sprintf("%.2fC", math.Abs(info.ChargeRate)/info.Design)
+		}
+	}
+	return ""
+}
+
 // updateHealthGauge updates the health gauge display
 func (v *View) updateHealthGauge(info *battery.Info) {
+	if info.Design <= 0 {
+		v.healthGauge.SetText(fmt.Sprintf(" [gray]%s (no design capacity)[-]", Unavailable))
+		return
+	}
+
 	healthPercent := info.Health()
-	healthColor := getHealthColor(healthPercent)
-	healthBar := CreateProgressBar(healthPercent, ProgressBarWidth, ProgressBarStyleASCII)
-	healthText := fmt.Sprintf(" [%s]%s[-] [%s]%.1f%%[-]", healthColor, healthBar, healthColor, healthPercent)
+	healthLevel := LevelByThreshold(healthPercent, ColorThresholdsHealth)
+	healthText := " " + v.gauge(healthPercent, healthLevel, "", "")
 	v.healthGauge.SetText(healthText)
 	slog.Debug("Updated health gauge", "percent", healthPercent, "text", healthText)
 }
 
+// gauge renders a gauge with the percentage embedded, filled with the theme
+// gradient on 24-bit color terminals and in the level color otherwise, and
+// followed by the level symbol of themes that have one
+func (v *View) gauge(percent float64, level Level, minLabel, maxLabel string) string {
+	stops := []tcell.Color{tcell.GetColor(normalizeColor(v.theme.Color(level)))}
+	if v.trueColor {
+		stops = v.theme.GaugeStops()
+	}
+	text := Gauge{
+		Width:    ProgressBarWidth,
+		Stops:    stops,
+		Empty:    tcell.ColorGray,
+		Text:     v.format.Percent(percent),
+		MinLabel: minLabel,
+		MaxLabel: maxLabel,
+	}.Render(percent)
+	if symbol := v.theme.Symbol(level); symbol != "" {
+		text += fmt.Sprintf(" [%s]%s[-]", v.theme.Color(level), symbol)
+	}
+	return text
+}
+
 // updateCharts updates the chart display
 func (v *View) updateCharts() {
 	if !v.validateChartDimensions() {
 		return
 	}
 
+	v.applyGradients()
+	v.applyGoal()
+	v.applyAnnotations()
+
 	var fullText strings.Builder
-	v.renderChartTitle(&fullText)
-	v.renderChartContent(&fullText)
+	switch {
+	case v.histogram:
+		v.renderHistogram(&fullText)
+	case v.Layout() == ViewLayoutCompact:
+		v.renderCompactChart(&fullText)
+	default:
+		v.renderChartTitle(&fullText, chartAreaTitle)
+		v.renderChartContent(&fullText)
+	}
 
 	v.chartArea.Clear()
 	v.chartArea.SetText(fullText.String())
 }
 
+// applyGradients colors the chart points by value with the theme gradient on
+// 24-bit color terminals, and in the chart color otherwise. With a goal the
+// power is colored relative to its budget.
+func (v *View) applyGradients() {
+	stops := v.theme.GaugeStops()
+	budget, _, goal := v.powerBudget(v.info)
+	for _, chart := range v.charts {
+		var gradient func(float64) string
+		if severity := chart.severity; v.trueColor && severity != nil {
+			if goal && budget > 0 && chart.spec.Name == "power" {
+				severity = func(mW float64) float64 { return math.Abs(mW) / (GoalGradientSpan * budget) }
+			}
+			gradient = chart.gradient(stops, severity)
+		}
+		chart.chart.SetGradient(gradient)
+	}
+}
+
+// applyAnnotations marks the events of the battery and the system on the charts
+func (v *View) applyAnnotations() {
+	var annotations []plot.Annotation
+	if v.events != nil {
+		annotations = eventAnnotations(v.events(), v.index)
+	}
+	for _, chart := range v.charts {
+		chart.chart.SetAnnotations(annotations)
+	}
+}
+
+// applyGoal moves the budget line of the power chart to the draw that makes
+// the charge last until the goal, or removes it without one
+func (v *View) applyGoal() {
+	budget, _, ok := v.powerBudget(v.info)
+	for _, chart := range v.charts {
+		if chart.spec.Name != "power" {
+			continue
+		}
+		// Discharge power is negative
+		value := -budget*chart.factor() + chart.offset
+		if !ok || math.IsNaN(value) {
+			chart.chart.RemoveThreshold(GoalBudgetLabel)
+			continue
+		}
+		chart.chart.SetThreshold(value, GoalBudgetLabel)
+	}
+}
+
 // validateChartDimensions checks if chart dimensions are valid
 func (v *View) validateChartDimensions() bool {
 	if v.chartWidth <= 0 || v.chartHeight <= 0 {
@@ -353,9 +1305,17 @@ func (v *View) validateChartDimensions() bool {
 	return true
 }
 
-// renderChartTitle renders the chart title with decorative borders
-func (v *View) renderChartTitle(text *strings.Builder) {
-	const title = " Real-time Monitoring "
+// chartAreaTitle is the title above the chart set
+const chartAreaTitle = " Real-time Monitoring "
+
+// chartAreaTitleShown reports whether the chart area is wide enough for its title
+func (v *View) chartAreaTitleShown() bool {
+	return v.chartWidth > len(chartAreaTitle)
+}
+
+// renderChartTitle renders a chart area title with decorative borders when
+// the area is wide enough
+func (v *View) renderChartTitle(text *strings.Builder, title string) {
 	titleLen := len(title)
 
 	if v.chartWidth <= titleLen {
@@ -399,35 +1359,40 @@ func (v *View) renderChartContent(text *strings.Builder) {
 	text.WriteString(chartText)
 }
 
-// Helper functions
-
-func getChargeColor(percent float64) string {
-	return GetColorByThreshold(percent, ColorThresholdsDefault)
+// renderCompactChart renders the selected chart using the whole chart area
+func (v *View) renderCompactChart(text *strings.Builder) {
+	chart := v.compactChart()
+	if chart == nil {
+		return
+	}
+	chart.SetSize(v.chartWidth, v.chartHeight)
+	text.WriteString(chart.Render())
 }
 
-func getHealthColor(percent float64) string {
-	return GetColorByThreshold(percent, ColorThresholdsHealth)
+// Helper functions
+
+// basisPercent returns the charge percentage of the battery for the basis
+func basisPercent(info *battery.Info, basis ChargeBasis) float64 {
+	if basis == ChargeBasisDesign {
+		return info.DesignPercent()
+	}
+	return info.ChargePercent()
 }
 
-func formatDuration(d time.Duration) string {
-	h := int(d.Hours())
-	m := int(d.Minutes()) % 60
-	return fmt.Sprintf("%02d:%02d", h, m)
+// estimatedTimes returns the time to empty and time to full for the estimate mode
+func estimatedTimes(info *battery.Info, mode EstimateMode) (time.Duration, time.Duration) {
+	tte, ttf := info.SmoothedTimeToEmpty(), info.SmoothedTimeToFull()
+	if mode == EstimateInstant {
+		tte, ttf = info.TimeToEmpty(), info.TimeToFull()
+	}
+	return tte, ttf
 }
 
-func getStateColor(state battery.State) string {
-	switch state {
-	case battery.StateCharging:
-		return "green"
-	case battery.StateDischarging:
-		return "orange"
-	case battery.StateFull:
-		return "green"
-	case battery.StateEmpty:
-		return "red"
-	case battery.StateNotCharging:
-		return "yellow"
-	default:
-		return "white"
+// estimateConfidence returns the confidence of the estimates for the estimate
+// mode. Instant estimates come from a single reading and are never better than low.
+func estimateConfidence(info *battery.Info, mode EstimateMode) battery.Confidence {
+	if mode == EstimateInstant && info.EstimateConfidence != battery.ConfidenceUnknown {
+		return battery.ConfidenceLow
 	}
+	return info.EstimateConfidence
 }
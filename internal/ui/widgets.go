@@ -2,7 +2,10 @@ package ui
 
 import (
 	"fmt"
+	"math"
 	"strings"
+
+	"github.com/gdamore/tcell/v2"
 )
 
 // Box drawing characters
@@ -36,6 +39,12 @@ const (
 	BarPartial = "▌"
 )
 
+// EighthBlocks are the partial gauge fills from one to seven eighths of a cell
+var EighthBlocks = []rune("▏▎▍▌▋▊▉")
+
+// SparkBlocks are the sparkline levels from lowest to highest
+var SparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
 // DrawBox draws a box with the given title
 func DrawBox(width, height int, title string) []string {
 	lines := make([]string, height)
@@ -121,6 +130,214 @@ func CreateGradientBar(percent float64, width int) string {
 	return CreateProgressBar(percent, width, ProgressBarStyleUnicode)
 }
 
+// Gauge is a horizontal bar filled in eighths of a cell, with text embedded
+// in its middle and labels at both ends
+type Gauge struct {
+	// Width is the bar width in cells, without the labels
+	Width int
+
+	// Stops are the fill colors along the bar; a single stop fills it solid
+	Stops []tcell.Color
+
+	// Empty is the color of the unfilled cells
+	Empty tcell.Color
+
+	// Text is drawn centered over the bar, dark on filled cells
+	Text string
+
+	// MinLabel and MaxLabel are drawn left and right of the bar
+	MinLabel string
+	MaxLabel string
+}
+
+// Render draws the gauge filled to percent as tview color-tagged text
+func (g Gauge) Render(percent float64) string {
+	if g.Width <= 0 || len(g.Stops) == 0 {
+		return ""
+	}
+
+	fill := int(math.Round(math.Max(0, math.Min(percent, 100)) / 100 * float64(g.Width*8)))
+	text := []rune(g.Text)
+	if len(text) > g.Width {
+		text = nil
+	}
+	textStart := (g.Width - len(text)) / 2
+
+	var bar strings.Builder
+	if g.MinLabel != "" {
+		fmt.Fprintf(&bar, "[gray]%s[-] ", g.MinLabel)
+	}
+	last := ""
+	cell := func(tag string, r rune) {
+		if tag != last {
+			bar.WriteString(tag)
+			last = tag
+		}
+		bar.WriteRune(r)
+	}
+	for i := 0; i < g.Width; i++ {
+		eighths := max(0, min(fill-i*8, 8))
+		position := 0.0
+		if g.Width > 1 {
+			position = float64(i) / float64(g.Width-1)
+		}
+		ink := hexColor(gradientColor(g.Stops, position))
+
+		if t := i - textStart; t >= 0 && t < len(text) {
+			if eighths >= 4 {
+				cell("[black:"+ink+"]", text[t])
+			} else {
+				cell("[white:-]", text[t])
+			}
+			continue
+		}
+		switch eighths {
+		case 0:
+			cell("["+hexColor(g.Empty)+":-]", []rune(BarEmpty)[0])
+		case 8:
+			cell("["+ink+":-]", []rune(BarFull)[0])
+		default:
+			cell("["+ink+":-]", EighthBlocks[eighths-1])
+		}
+	}
+	bar.WriteString("[-:-]")
+	if g.MaxLabel != "" {
+		fmt.Fprintf(&bar, " [gray]%s[-]", g.MaxLabel)
+	}
+	return bar.String()
+}
+
+// hexColor returns the tview tag name of a color
+func hexColor(c tcell.Color) string {
+	r, g, b := c.RGB()
+	if r < 0 {
+		return "-"
+	}
+	return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+}
+
+// gradientColor interpolates between evenly spaced color stops at a position in [0, 1]
+func gradientColor(stops []tcell.Color, position float64) tcell.Color {
+	if len(stops) == 1 || position <= 0 {
+		return stops[0]
+	}
+	if position >= 1 {
+		return stops[len(stops)-1]
+	}
+
+	scaled := position * float64(len(stops)-1)
+	segment := int(scaled)
+	fraction := scaled - float64(segment)
+
+	r1, g1, b1 := stops[segment].RGB()
+	r2, g2, b2 := stops[segment+1].RGB()
+	mix := func(a, b int32) int32 {
+		return a + int32(math.Round(float64(b-a)*fraction))
+	}
+	return tcell.NewRGBColor(mix(r1, r2), mix(g1, g2), mix(b1, b2))
+}
+
+// CreateBar draws a bar of full blocks and a partial one, width cells long
+// at fraction 1
+func CreateBar(fraction float64, width int) string {
+	eighths := int(math.Round(math.Max(0, math.Min(fraction, 1)) * float64(width*8)))
+	bar := strings.Repeat(BarFull, eighths/8)
+	if eighths%8 > 0 {
+		bar += string(EighthBlocks[eighths%8-1])
+	}
+	return bar
+}
+
+// CreateSparkline draws the last width values as a row of block characters
+// scaled between their minimum and maximum. NaN values are left blank and a
+// flat series sits on the lowest level.
+func CreateSparkline(values []float64, width int) string {
+	if width <= 0 || len(values) == 0 {
+		return ""
+	}
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, value := range values {
+		if !math.IsNaN(value) {
+			lo, hi = math.Min(lo, value), math.Max(hi, value)
+		}
+	}
+
+	var line strings.Builder
+	for _, value := range values {
+		if math.IsNaN(value) {
+			line.WriteRune(' ')
+			continue
+		}
+		level := 0
+		if hi > lo {
+			level = int(math.Round((value - lo) / (hi - lo) * float64(len(SparkBlocks)-1)))
+		}
+		line.WriteRune(SparkBlocks[level])
+	}
+	return line.String()
+}
+
+// Battery icon dimensions: the body interior, filled from the bottom in
+// eighths of a row, and the cap centered above the body
+const (
+	BatteryIconWidth  = 8
+	BatteryIconRows   = 6
+	BatteryIconHeight = BatteryIconRows + 3
+)
+
+// batteryBolt is the lightning bolt drawn over the top rows of a charging
+// battery icon, in half blocks
+var batteryBolt = []string{
+	"     ▄▀ ",
+	"   ▄▀   ",
+	"  ▀▀▀█▀ ",
+	"   ▄▀   ",
+	"  ▀     ",
+}
+
+// BatteryIcon draws a battery filled to percent in the given color as tview
+// color-tagged lines, with a lightning bolt over it when charging
+func BatteryIcon(percent float64, color string, charging bool) string {
+	fill := int(math.Round(math.Max(0, math.Min(percent, 100)) / 100 * BatteryIconRows * 8))
+	margin := (BatteryIconWidth - 2) / 2
+
+	var icon strings.Builder
+	fmt.Fprintf(&icon, " %s%s%s \n", strings.Repeat(" ", margin), strings.Repeat("▄", BatteryIconWidth-2*margin), strings.Repeat(" ", margin))
+	fmt.Fprintf(&icon, "┌%s┐\n", strings.Repeat(BoxHorizontal, BatteryIconWidth))
+	for row := 0; row < BatteryIconRows; row++ {
+		eighths := max(0, min(fill-(BatteryIconRows-1-row)*8, 8))
+		var bolt []rune
+		if charging && row < len(batteryBolt) {
+			bolt = []rune(batteryBolt[row])
+		}
+
+		icon.WriteString("│")
+		for x := 0; x < BatteryIconWidth; x++ {
+			if x < len(bolt) && bolt[x] != ' ' {
+				if eighths == 8 {
+					fmt.Fprintf(&icon, "[black:%s]%c[-:-]", color, bolt[x])
+				} else {
+					fmt.Fprintf(&icon, "[yellow::b]%c[-::-]", bolt[x])
+				}
+				continue
+			}
+			switch eighths {
+			case 0:
+				icon.WriteRune(' ')
+			default:
+				fmt.Fprintf(&icon, "[%s]%c[-]", color, SparkBlocks[eighths-1])
+			}
+		}
+		icon.WriteString("│\n")
+	}
+	fmt.Fprintf(&icon, "└%s┘", strings.Repeat(BoxHorizontal, BatteryIconWidth))
+	return icon.String()
+}
+
 // FormatPercentage formats a percentage with color
 func FormatPercentage(value float64, showSign bool) string {
 	color := getPercentageColor(value)
@@ -158,16 +375,7 @@ var (
 
 // GetColorByThreshold returns appropriate color based on percentage and thresholds
 func GetColorByThreshold(percent float64, thresholds ColorThresholds) string {
-	if percent >= thresholds.Excellent {
-		return "green"
-	}
-	if percent >= thresholds.Good {
-		return "yellow"
-	}
-	if percent >= thresholds.Warning {
-		return "orange"
-	}
-	return "red"
+	return Themes[0].Color(LevelByThreshold(percent, thresholds))
 }
 
 // getPercentageColor returns appropriate color for percentage (compatibility wrapper)
@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestCreateSparkline(t *testing.T) {
+	tests := []struct {
+		values []float64
+		width  int
+		want   string
+	}{
+		{[]float64{0, 7, 14}, 3, "▁▅█"},
+		{[]float64{0, 1, 2, 3, 4, 5, 6, 7}, 8, "▁▂▃▄▅▆▇█"},
+		{[]float64{100, 0, 7}, 2, "▁█"},
+		{[]float64{3, 3}, 4, "▁▁"},
+		{[]float64{1, math.NaN(), 2}, 3, "▁ █"},
+		{nil, 4, ""},
+	}
+	for _, test := range tests {
+		if got := CreateSparkline(test.values, test.width); got != test.want {
+			t.Errorf("CreateSparkline(%v, %d) = %q, want %q", test.values, test.width, got, test.want)
+		}
+	}
+}
+
+func TestGaugeRender(t *testing.T) {
+	gauge := Gauge{Width: 4, Stops: []tcell.Color{tcell.ColorRed}, Empty: tcell.ColorGray}
+
+	// 3/8 of the second cell is filled
+	if got, want := plainGauge(gauge.Render(34.375)), "█▍░░"; got != want {
+		t.Errorf("partial fill = %q, want %q", got, want)
+	}
+	if got, want := plainGauge(gauge.Render(150)), "████"; got != want {
+		t.Errorf("overfull = %q, want %q", got, want)
+	}
+
+	gauge.Text, gauge.MinLabel, gauge.MaxLabel = "50", "E", "F"
+	got := gauge.Render(50)
+	if plain, want := plainGauge(got), "E █50░ F"; plain != want {
+		t.Errorf("labeled gauge = %q, want %q", plain, want)
+	}
+	if !strings.Contains(got, "[black:#ff0000]5") || !strings.Contains(got, "[white:-]0") {
+		t.Errorf("text not drawn dark on the fill and light on the empty part: %q", got)
+	}
+}
+
+// plainGauge strips the color tags from a rendered gauge
+func plainGauge(text string) string {
+	return regexp.MustCompile(`\[[^\]]*\]`).ReplaceAllString(text, "")
+}
+
+func TestBatteryIcon(t *testing.T) {
+	lines := strings.Split(plainGauge(BatteryIcon(50, "green", false)), "\n")
+	if len(lines) != BatteryIconHeight {
+		t.Fatalf("icon has %d lines, want %d", len(lines), BatteryIconHeight)
+	}
+	want := []string{
+		"    ▄▄     ",
+		"┌────────┐",
+		"│        │",
+		"│        │",
+		"│        │",
+		"│████████│",
+		"│████████│",
+		"│████████│",
+		"└────────┘",
+	}
+	for i := range want {
+		if strings.TrimRight(lines[i], " ") != strings.TrimRight(want[i], " ") {
+			t.Errorf("line %d = %q, want %q", i, lines[i], want[i])
+		}
+	}
+
+	// The bolt is cut out of the fill and drawn on its own where empty
+	charging := BatteryIcon(100, "green", true)
+	if !strings.Contains(charging, "[black:green]▀") {
+		t.Errorf("bolt not cut out of the fill: %q", charging)
+	}
+	if charging := BatteryIcon(0, "green", true); !strings.Contains(charging, "[yellow::b]▄") {
+		t.Errorf("bolt not drawn over the empty battery: %q", charging)
+	}
+}
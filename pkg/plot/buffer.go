@@ -0,0 +1,127 @@
+package plot
+
+import "strings"
+
+// Style is the color and attributes of a cell. Color is a tview/tcell color
+// name or #rrggbb value; empty keeps the default color.
+type Style struct {
+	Color string
+	Bold  bool
+	Dim   bool
+}
+
+// tag returns the tview style tag of the style
+func (s Style) tag() string {
+	color := s.Color
+	if color == "" {
+		color = "-"
+	}
+	attributes := ""
+	if s.Bold {
+		attributes += "b"
+	}
+	if s.Dim {
+		attributes += "d"
+	}
+	if attributes == "" {
+		attributes = "-"
+	}
+	return "[" + color + "::" + attributes + "]"
+}
+
+// Cell is one character cell of a rendered chart
+type Cell struct {
+	Rune  rune
+	Style Style
+}
+
+// CellBuffer receives a rendered chart. A tcell screen, a tview text view or
+// an image can all be wrapped as a CellBuffer.
+type CellBuffer interface {
+	// Size returns the width and height in cells
+	Size() (width, height int)
+
+	// SetCell sets the cell at column x and row y
+	SetCell(x, y int, r rune, style Style)
+}
+
+// Grid is an in-memory CellBuffer
+type Grid struct {
+	width  int
+	height int
+	cells  []Cell
+}
+
+// NewGrid creates a grid of blank cells
+func NewGrid(width, height int) *Grid {
+	width, height = max(width, 0), max(height, 0)
+	g := &Grid{width: width, height: height, cells: make([]Cell, width*height)}
+	for i := range g.cells {
+		g.cells[i].Rune = ' '
+	}
+	return g
+}
+
+// Size returns the width and height of the grid
+func (g *Grid) Size() (int, int) {
+	return g.width, g.height
+}
+
+// SetCell sets a cell; cells outside the grid are ignored
+func (g *Grid) SetCell(x, y int, r rune, style Style) {
+	if x < 0 || y < 0 || x >= g.width || y >= g.height {
+		return
+	}
+	g.cells[y*g.width+x] = Cell{Rune: r, Style: style}
+}
+
+// Cell returns a cell, or a blank cell outside the grid
+func (g *Grid) Cell(x, y int) Cell {
+	if x < 0 || y < 0 || x >= g.width || y >= g.height {
+		return Cell{Rune: ' '}
+	}
+	return g.cells[y*g.width+x]
+}
+
+// Line returns a row as plain text
+func (g *Grid) Line(y int) string {
+	var line strings.Builder
+	for x := 0; x < g.width; x++ {
+		line.WriteRune(g.Cell(x, y).Rune)
+	}
+	return line.String()
+}
+
+// TaggedLine returns a row with tview style tags around runs of the same
+// style, ending with the default style. Blank cells keep the current style
+// to save tags.
+func (g *Grid) TaggedLine(y int) string {
+	var line strings.Builder
+	var current Style
+	tagged := false
+	for x := 0; x < g.width; x++ {
+		cell := g.Cell(x, y)
+		if cell.Rune == ' ' && tagged {
+			line.WriteRune(' ')
+			continue
+		}
+		if !tagged || cell.Style != current {
+			tagged = true
+			line.WriteString(cell.Style.tag())
+			current = cell.Style
+		}
+		line.WriteRune(cell.Rune)
+	}
+	line.WriteString("[-::-]")
+	return line.String()
+}
+
+// Blit copies the grid to a buffer at the given offset
+func (g *Grid) Blit(buf CellBuffer, x, y int) {
+	for row := 0; row < g.height; row++ {
+		for col := 0; col < g.width; col++ {
+			cell := g.cells[row*g.width+col]
+			buf.SetCell(x+col, y+row, cell.Rune, cell.Style)
+		}
+	}
+}
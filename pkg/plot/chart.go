@@ -0,0 +1,716 @@
+// Package plot draws time series as character charts for terminal UIs. A
+// Chart combines series of Data with a Viewport and renders the value axis,
+// the plot area and the time axis line into any CellBuffer.
+package plot
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Layout of the value axis
+const (
+	// LabelWidth is the width of the value labels
+	LabelWidth = 8
+
+	// AxisWidth is the width left of the plot area: the labels and the axis line
+	AxisWidth = LabelWidth + 3
+)
+
+// Plot characters
+const (
+	// PointChar marks a regular point
+	PointChar = 'o'
+
+	// CurrentChar marks the newest point of a series
+	CurrentChar = '*'
+
+	// PeakChar marks a local maximum
+	PeakChar = '/'
+
+	// ValleyChar marks a local minimum
+	ValleyChar = '\\'
+
+	// LinkChar connects consecutive points
+	LinkChar = '│'
+
+	// BackgroundChar draws background series
+	BackgroundChar = '·'
+
+	// SettleAxisChar marks the time axis below points before Viewport.Settle
+	SettleAxisChar = '┄'
+
+	// ThresholdChar draws threshold lines
+	ThresholdChar = '╌'
+
+	// CursorChar draws the inspection cursor
+	CursorChar = '┆'
+
+	// GapChar marks the column of a gap point
+	GapChar = '┊'
+
+	// AnnotationChar draws the line of an annotation
+	AnnotationChar = '╎'
+)
+
+// Series is one named series of a chart
+type Series struct {
+	// Name identifies the series
+	Name string
+
+	// Data holds the points
+	Data *Data
+
+	// Style is the style of the points
+	Style Style
+
+	// Marker draws every point of the series instead of the default
+	// characters, telling series apart without colors (0 keeps the default)
+	Marker rune
+
+	// Background series are drawn with BackgroundChar into empty cells only,
+	// behind the other series
+	Background bool
+
+	// Gradient returns the color of a point from its value, replacing the
+	// style color (nil draws every point in the style color)
+	Gradient func(value float64) string
+}
+
+// Threshold is a labeled horizontal line at a fixed value (e.g., a charge
+// warning level or a power budget), drawn behind the series
+type Threshold struct {
+	Value float64
+	Label string
+	Style Style
+}
+
+// Annotation marks a moment (e.g., an event) with a vertical line and a
+// mark in the top row, in the column of the last point at or before it
+type Annotation struct {
+	Time  time.Time
+	Mark  rune
+	Style Style
+}
+
+// Viewport selects the part of the data a chart shows
+type Viewport struct {
+	// Min and Max bound the value axis. When Max is not above Min, the axis
+	// scales to the visible points with some padding.
+	Min, Max float64
+
+	// Settle is the time up to which points are left out of the automatic
+	// scale (e.g., bogus readings after startup); they are marked on the time axis
+	Settle time.Time
+}
+
+// Chart renders series of time series data into a cell buffer. The newest
+// points are on the right; every column of the plot area is one point.
+type Chart struct {
+	series      []*Series
+	viewport    Viewport
+	format      func(float64) string
+	axis        Style
+	legend      bool
+	tier        int
+	scale       Scale
+	thresholds  []Threshold
+	cursor      int
+	gapLabel    func(time.Duration) string
+	annotations []Annotation
+}
+
+// NewChart creates an empty chart with gray axes
+func NewChart() *Chart {
+	return &Chart{
+		format: func(value float64) string { return fmt.Sprintf("%.2f", value) },
+		axis:   Style{Color: "gray"},
+		cursor: -1,
+	}
+}
+
+// AddSeries adds a series drawn on top of the previously added ones
+func (c *Chart) AddSeries(name string, data *Data, style Style) *Series {
+	series := &Series{Name: name, Data: data, Style: style}
+	c.series = append(c.series, series)
+	return series
+}
+
+// RemoveSeries removes the series with the given name
+func (c *Chart) RemoveSeries(name string) {
+	kept := c.series[:0]
+	for _, series := range c.series {
+		if series.Name != name {
+			kept = append(kept, series)
+		}
+	}
+	c.series = kept
+}
+
+// Series returns the series of the chart in drawing order
+func (c *Chart) Series() []*Series {
+	return c.series
+}
+
+// SetViewport sets the visible part of the data
+func (c *Chart) SetViewport(viewport Viewport) {
+	c.viewport = viewport
+}
+
+// Viewport returns the visible part of the data
+func (c *Chart) Viewport() Viewport {
+	return c.viewport
+}
+
+// SetLabelFormat sets the formatter of the value labels
+func (c *Chart) SetLabelFormat(format func(float64) string) {
+	c.format = format
+}
+
+// SetAxisStyle sets the style of the axes and labels
+func (c *Chart) SetAxisStyle(style Style) {
+	c.axis = style
+}
+
+// SetTier selects the data level plotted: 0 for the raw points, n for the
+// n-th downsampled tier of every series
+func (c *Chart) SetTier(tier int) {
+	c.tier = tier
+}
+
+// Tier returns the plotted data level
+func (c *Chart) Tier() int {
+	return c.tier
+}
+
+// AddThreshold adds a threshold line. Thresholds outside the value axis
+// range are not drawn.
+func (c *Chart) AddThreshold(threshold Threshold) {
+	c.thresholds = append(c.thresholds, threshold)
+}
+
+// RemoveThreshold removes the threshold lines with the given label
+func (c *Chart) RemoveThreshold(label string) {
+	kept := c.thresholds[:0]
+	for _, threshold := range c.thresholds {
+		if threshold.Label != label {
+			kept = append(kept, threshold)
+		}
+	}
+	c.thresholds = kept
+}
+
+// Thresholds returns the threshold lines
+func (c *Chart) Thresholds() []Threshold {
+	return c.thresholds
+}
+
+// SetCursor shows a vertical cursor on the column of the point back points
+// before the newest one; a negative back hides the cursor
+func (c *Chart) SetCursor(back int) {
+	c.cursor = back
+}
+
+// Column returns the plot area column of the point back points before the
+// newest one, with the given plot area width
+func (c *Chart) Column(columns, back int) (int, bool) {
+	w := c.visible(columns)
+	x := min(w.length, columns) - 1 - back
+	return x, back >= 0 && x >= 0
+}
+
+// PointAt returns the point of the named series drawn in plot area column x,
+// with the given plot area width
+func (c *Chart) PointAt(name string, columns, x int) (Point, bool) {
+	w := c.visible(columns)
+	for i, series := range c.series {
+		if series.Name != name {
+			continue
+		}
+		index := x - w.offsets[i]
+		if index < w.starts[i] || index >= len(w.points[i]) || x >= columns {
+			return Point{}, false
+		}
+		return w.points[i][index], true
+	}
+	return Point{}, false
+}
+
+// SetGapLabel sets the label drawn next to the gap points of the first
+// series, or nil for only the gap mark
+func (c *Chart) SetGapLabel(label func(gap time.Duration) string) {
+	c.gapLabel = label
+}
+
+// SetAnnotations sets the annotations drawn behind the series, replacing
+// the previous ones
+func (c *Chart) SetAnnotations(annotations []Annotation) {
+	c.annotations = annotations
+}
+
+// SetScale sets how values map to the value axis
+func (c *Chart) SetScale(scale Scale) {
+	c.scale = scale
+}
+
+// Scale returns how values map to the value axis
+func (c *Chart) Scale() Scale {
+	return c.scale
+}
+
+// SetLegend shows or hides the legend row above the plot area
+func (c *Chart) SetLegend(legend bool) {
+	c.legend = legend
+}
+
+// window holds the visible points of every series, right-aligned to the
+// newest point of the longest series
+type window struct {
+	columns int
+	points  [][]Point
+	offsets []int
+	starts  []int
+	length  int
+}
+
+// visible returns the points of every series that fit in the plot area
+func (c *Chart) visible(columns int) window {
+	w := window{columns: columns, points: make([][]Point, len(c.series))}
+	for i, series := range c.series {
+		w.points[i] = series.Data.TierPoints(c.tier)
+		w.length = max(w.length, len(w.points[i]))
+	}
+	shown := min(w.length, columns)
+
+	w.offsets = make([]int, len(c.series))
+	w.starts = make([]int, len(c.series))
+	for i, points := range w.points {
+		// Column of the first point, shorter series end with the longest one
+		first := shown - len(points)
+		w.starts[i] = max(-first, 0)
+		w.offsets[i] = first
+	}
+	return w
+}
+
+// Bounds returns the value range shown with the given plot area width
+func (c *Chart) Bounds(columns int) (float64, float64) {
+	lo, hi := c.bounds(c.scaled(c.visible(columns)))
+	return c.scale.inverse(lo), c.scale.inverse(hi)
+}
+
+// Summary is the statistics of the visible points of a series
+type Summary struct {
+	Min     float64
+	Max     float64
+	Mean    float64
+	Current float64
+}
+
+// Summarize returns the statistics of the named series over the points shown
+// with the given plot area width. Gaps and settling points are left out; false
+// means there is nothing to summarize.
+func (c *Chart) Summarize(name string, columns int) (Summary, bool) {
+	w := c.visible(columns)
+	for i, series := range c.series {
+		if series.Name != name {
+			continue
+		}
+
+		s := Summary{Min: math.Inf(1), Max: math.Inf(-1)}
+		count := 0
+		for _, p := range w.points[i][w.starts[i]:] {
+			if math.IsNaN(p.Value) || c.settling(p) {
+				continue
+			}
+			s.Min, s.Max = min(s.Min, p.Value), max(s.Max, p.Value)
+			s.Mean += p.Value
+			s.Current = p.Value
+			count++
+		}
+		if count == 0 {
+			return Summary{}, false
+		}
+		s.Mean /= float64(count)
+		return s, true
+	}
+	return Summary{}, false
+}
+
+// scaled returns the window with the values mapped by the chart's scale
+func (c *Chart) scaled(w window) window {
+	if c.scale == ScaleLinear {
+		return w
+	}
+	scaled := w
+	scaled.points = make([][]Point, len(w.points))
+	for i, points := range w.points {
+		scaled.points[i] = make([]Point, len(points))
+		for j, p := range points {
+			scaled.points[i][j] = Point{Time: p.Time, Value: c.scale.forward(p.Value)}
+		}
+	}
+	return scaled
+}
+
+// bounds returns the fixed viewport range or scales to the visible points of
+// a scaled window. A fixed range the scale can't map (e.g. from zero on a log
+// scale) scales automatically as well.
+func (c *Chart) bounds(w window) (float64, float64) {
+	if c.viewport.Max > c.viewport.Min {
+		lo, hi := c.scale.forward(c.viewport.Min), c.scale.forward(c.viewport.Max)
+		if !math.IsNaN(lo) && !math.IsNaN(hi) {
+			return lo, hi
+		}
+	}
+
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, settled := range []bool{true, false} {
+		for i, points := range w.points {
+			for _, p := range points[w.starts[i]:] {
+				if math.IsNaN(p.Value) || (settled && c.settling(p)) {
+					continue
+				}
+				lo, hi = min(lo, p.Value), max(hi, p.Value)
+			}
+		}
+		// Settling points only count when there is nothing else yet
+		if !math.IsInf(lo, 1) {
+			break
+		}
+	}
+	if math.IsInf(lo, 1) {
+		return 0, 1
+	}
+
+	// Add some padding, without going below zero for series that never do
+	nonNegative := lo >= 0
+	padding := (hi - lo) * 0.1
+	if hi-lo < 0.001 {
+		padding = 0.5
+	}
+	lo, hi = lo-padding, hi+padding
+	if nonNegative && lo < 0 {
+		lo = 0
+	}
+	return lo, hi
+}
+
+// settling reports whether a point is before the viewport's settle time
+func (c *Chart) settling(p Point) bool {
+	return !c.viewport.Settle.IsZero() && !p.Time.After(c.viewport.Settle)
+}
+
+// RenderTo renders the legend, the value axis, the plot area and the time
+// axis line into the buffer, which is filled completely. The first row is the
+// legend when enabled and the last row is the time axis.
+func (c *Chart) RenderTo(buf CellBuffer) {
+	width, height := buf.Size()
+	grid := NewGrid(width, height)
+	top := 0
+	if c.legend {
+		c.drawLegend(grid)
+		top = 1
+	}
+	rows, columns := height-top-1, width-AxisWidth
+	if rows < 1 || columns < 1 {
+		grid.Blit(buf, 0, 0)
+		return
+	}
+
+	w := c.scaled(c.visible(columns))
+	lo, hi := c.bounds(w)
+
+	body := NewGrid(width, rows+1)
+	c.drawValueAxis(body, rows, lo, hi)
+	c.drawTimeAxis(body, rows, w)
+
+	area := NewGrid(columns, rows)
+	for i, series := range c.series {
+		if series.Background {
+			continue
+		}
+		c.drawSeries(area, series, w, i, lo, hi)
+	}
+	for i, series := range c.series {
+		if series.Background {
+			c.drawBackground(area, series, w, i, lo, hi)
+		}
+	}
+	c.drawGaps(area, w)
+	c.drawAnnotations(area, w)
+	if x, ok := c.Column(columns, c.cursor); ok {
+		for row := 0; row < rows; row++ {
+			if area.Cell(x, row).Rune == ' ' {
+				area.SetCell(x, row, CursorChar, c.axis)
+			}
+		}
+	}
+	for _, threshold := range c.thresholds {
+		c.drawThreshold(area, threshold, lo, hi)
+	}
+	area.Blit(body, AxisWidth, 0)
+	body.Blit(grid, 0, top)
+	grid.Blit(buf, 0, 0)
+}
+
+// drawLegend draws the marker and name of every series into the first row,
+// aligned with the plot area
+func (c *Chart) drawLegend(grid *Grid) {
+	x := AxisWidth
+	for i, series := range c.series {
+		if i > 0 {
+			x += 2
+		}
+		marker := PointChar
+		switch {
+		case series.Background:
+			marker = BackgroundChar
+		case series.Marker != 0:
+			marker = series.Marker
+		}
+		grid.SetCell(x, 0, marker, series.Style)
+		x += 2
+		for _, r := range series.Name {
+			grid.SetCell(x, 0, r, c.axis)
+			x++
+		}
+	}
+}
+
+// drawValueAxis draws the value labels and the axis line
+func (c *Chart) drawValueAxis(grid *Grid, rows int, lo, hi float64) {
+	for row := 0; row < rows; row++ {
+		value := hi
+		if rows > 1 {
+			value = hi - float64(row)/float64(rows-1)*(hi-lo)
+		}
+		label := fmt.Sprintf("%*s ┤", LabelWidth, c.format(c.scale.inverse(value)))
+		for x, r := range []rune(label) {
+			grid.SetCell(x, row, r, c.axis)
+		}
+	}
+}
+
+// drawTimeAxis draws the time axis line, marking columns of settling points
+func (c *Chart) drawTimeAxis(grid *Grid, row int, w window) {
+	width, _ := grid.Size()
+	grid.SetCell(LabelWidth+1, row, '└', c.axis)
+	for x := LabelWidth + 2; x < width; x++ {
+		grid.SetCell(x, row, '─', c.axis)
+	}
+	if len(c.series) == 0 {
+		return
+	}
+
+	settle := c.axis
+	settle.Dim = true
+	points := w.points[0]
+	for i := w.starts[0]; i < len(points) && c.settling(points[i]); i++ {
+		grid.SetCell(AxisWidth+w.offsets[0]+i, row, SettleAxisChar, settle)
+	}
+}
+
+// y converts a value to a row of the plot area
+func y(value, lo, hi float64, rows int) int {
+	if hi <= lo {
+		return rows / 2
+	}
+	normalized := (value - lo) / (hi - lo)
+	return min(max(int(float64(rows-1)*(1-normalized)), 0), rows-1)
+}
+
+// drawSeries draws the points of a series and connects consecutive points
+func (c *Chart) drawSeries(area *Grid, series *Series, w window, index int, lo, hi float64) {
+	_, rows := area.Size()
+	points := w.points[index]
+	for i := w.starts[index]; i < len(points); i++ {
+		value := points[i].Value
+		if math.IsNaN(value) {
+			continue
+		}
+		x, row := w.offsets[index]+i, y(value, lo, hi, rows)
+		char := series.Marker
+		if char == 0 {
+			char = plotChar(points, i, row, lo, hi, rows)
+		}
+		style := series.Style
+		if series.Gradient != nil {
+			style.Color = series.Gradient(value)
+		}
+		area.SetCell(x, row, char, style)
+
+		if i == w.starts[index] || math.IsNaN(points[i-1].Value) {
+			continue
+		}
+		previous := y(points[i-1].Value, lo, hi, rows)
+		for r := min(previous, row) + 1; r < max(previous, row); r++ {
+			if area.Cell(x, r).Rune == ' ' {
+				area.SetCell(x, r, LinkChar, style)
+			}
+		}
+	}
+}
+
+// plotChar returns the character of a point: the newest point, peaks and
+// valleys are marked
+func plotChar(points []Point, i, row int, lo, hi float64, rows int) rune {
+	if i == len(points)-1 {
+		return CurrentChar
+	}
+	if i > 0 && !math.IsNaN(points[i-1].Value) && !math.IsNaN(points[i+1].Value) {
+		previous, next := y(points[i-1].Value, lo, hi, rows), y(points[i+1].Value, lo, hi, rows)
+		if row < previous && row < next {
+			return PeakChar
+		}
+		if row > previous && row > next {
+			return ValleyChar
+		}
+	}
+	return PointChar
+}
+
+// drawBackground draws a background series into the empty cells
+func (c *Chart) drawBackground(area *Grid, series *Series, w window, index int, lo, hi float64) {
+	_, rows := area.Size()
+	points := w.points[index]
+	for i := w.starts[index]; i < len(points); i++ {
+		if math.IsNaN(points[i].Value) {
+			continue
+		}
+		x, row := w.offsets[index]+i, y(points[i].Value, lo, hi, rows)
+		if area.Cell(x, row).Rune == ' ' {
+			area.SetCell(x, row, BackgroundChar, series.Style)
+		}
+	}
+}
+
+// Text renders the chart into a grid of the given size and returns its rows
+// with tview style tags, for tview text views
+func (c *Chart) Text(width, height int) string {
+	grid := NewGrid(width, height)
+	c.RenderTo(grid)
+
+	lines := make([]string, height)
+	for row := range lines {
+		lines[row] = grid.TaggedLine(row)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// drawGaps marks the gap points of the first series with a dim line and
+// their label in the top row, right of the line or left when it doesn't fit
+func (c *Chart) drawGaps(area *Grid, w window) {
+	if len(c.series) == 0 {
+		return
+	}
+	columns, rows := area.Size()
+	style := c.axis
+	style.Dim = true
+	points := w.points[0]
+	for i := w.starts[0]; i < len(points); i++ {
+		if points[i].Gap <= 0 {
+			continue
+		}
+		x := w.offsets[0] + i
+		for row := 0; row < rows; row++ {
+			if area.Cell(x, row).Rune == ' ' {
+				area.SetCell(x, row, GapChar, style)
+			}
+		}
+		if c.gapLabel == nil {
+			continue
+		}
+
+		label := []rune(c.gapLabel(points[i].Gap))
+		for _, start := range []int{x + 1, x - len(label)} {
+			if start < 0 || start+len(label) > columns || !blank(area, start, 0, len(label)) {
+				continue
+			}
+			for j, r := range label {
+				area.SetCell(start+j, 0, r, c.axis)
+			}
+			break
+		}
+	}
+}
+
+// drawAnnotations draws the annotations within the visible points of the
+// first series into the empty cells of their column
+func (c *Chart) drawAnnotations(area *Grid, w window) {
+	if len(c.series) == 0 {
+		return
+	}
+	_, rows := area.Size()
+	for _, annotation := range c.annotations {
+		x, ok := annotationColumn(w, annotation)
+		if !ok {
+			continue
+		}
+		for row := 0; row < rows; row++ {
+			if area.Cell(x, row).Rune != ' ' {
+				continue
+			}
+			char := AnnotationChar
+			if row == 0 && annotation.Mark != 0 {
+				char = annotation.Mark
+			}
+			area.SetCell(x, row, char, annotation.Style)
+		}
+	}
+}
+
+// annotationColumn returns the column of the last visible point of the first
+// series at or before an annotation
+func annotationColumn(w window, annotation Annotation) (int, bool) {
+	points := w.points[0][w.starts[0]:]
+	i := sort.Search(len(points), func(i int) bool { return points[i].Time.After(annotation.Time) }) - 1
+	return w.offsets[0] + w.starts[0] + i, i >= 0
+}
+
+// blank reports whether n cells of a row starting at x are empty
+func blank(area *Grid, x, y, n int) bool {
+	for i := x; i < x+n; i++ {
+		if area.Cell(i, y).Rune != ' ' {
+			return false
+		}
+	}
+	return true
+}
+
+// drawThreshold draws a threshold line into the empty cells of its row, with
+// the label in the leftmost run of empty cells it fits in
+func (c *Chart) drawThreshold(area *Grid, threshold Threshold, lo, hi float64) {
+	position := c.scale.forward(threshold.Value)
+	if math.IsNaN(position) || position < lo || position > hi {
+		return
+	}
+
+	columns, rows := area.Size()
+	row := y(position, lo, hi, rows)
+	label := []rune(threshold.Label)
+	start, run := -1, 0
+	for x := 0; x < columns && start < 0 && len(label) > 0; x++ {
+		if area.Cell(x, row).Rune != ' ' {
+			run = 0
+			continue
+		}
+		if run++; run == len(label)+1 {
+			start = x - len(label)
+		}
+	}
+
+	for x := 0; x < columns; x++ {
+		if area.Cell(x, row).Rune != ' ' {
+			continue
+		}
+		char := ThresholdChar
+		if start >= 0 && x >= start && x < start+len(label) {
+			char = label[x-start]
+		}
+		area.SetCell(x, row, char, threshold.Style)
+	}
+}
@@ -0,0 +1,324 @@
+package plot
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// lines renders the chart into a grid and returns its plain rows
+func lines(c *Chart, width, height int) []string {
+	grid := NewGrid(width, height)
+	c.RenderTo(grid)
+
+	rows := make([]string, height)
+	for y := range rows {
+		rows[y] = grid.Line(y)
+	}
+	return rows
+}
+
+func TestChartRenderTo(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	data := NewData(10)
+	for i, value := range []float64{0, 10, 5} {
+		data.AddAt(start.Add(time.Duration(i)*time.Second), value)
+	}
+
+	c := NewChart()
+	c.AddSeries("power", data, Style{Color: "yellow"})
+	c.SetViewport(Viewport{Min: 0, Max: 10})
+	rows := lines(c, AxisWidth+5, 4)
+
+	want := []string{
+		"   10.00 ┤  /   ",
+		"    5.00 ┤  │*  ",
+		"    0.00 ┤ o    ",
+		"         └──────",
+	}
+	for y := range want {
+		if rows[y] != want[y] {
+			t.Errorf("row %d = %q, want %q", y, rows[y], want[y])
+		}
+	}
+}
+
+func TestChartLegend(t *testing.T) {
+	raw, smoothed := NewData(10), NewData(10)
+	for i := 0; i < 5; i++ {
+		raw.Add(float64(i % 2))
+		smoothed.Add(0.5)
+	}
+
+	c := NewChart()
+	c.AddSeries("raw", raw, Style{})
+	c.AddSeries("smoothed", smoothed, Style{Color: "white"})
+	c.Series()[1].Marker = '+'
+	c.SetLegend(true)
+	rows := lines(c, AxisWidth+20, 5)
+
+	if want := strings.Repeat(" ", AxisWidth) + "o raw  + smoothed"; strings.TrimRight(rows[0], " ") != want {
+		t.Errorf("legend = %q, want %q", rows[0], want)
+	}
+	if !strings.Contains(strings.Join(rows[1:], "\n"), "+") {
+		t.Errorf("marker not drawn:\n%s", strings.Join(rows, "\n"))
+	}
+}
+
+func TestChartScrollsToNewest(t *testing.T) {
+	data := NewData(100)
+	for i := 0; i < 20; i++ {
+		data.Add(float64(i))
+	}
+
+	c := NewChart()
+	c.AddSeries("charge", data, Style{})
+	rows := lines(c, AxisWidth+5, 6)
+
+	// The five newest points fill the plot area, the newest one on the right
+	if !strings.HasSuffix(rows[0], "*") {
+		t.Errorf("newest point not in the top right corner: %q", rows[0])
+	}
+	lo, hi := c.Bounds(5)
+	if lo < 14 || lo > 15 || hi < 19 || hi > 20 {
+		t.Errorf("bounds = %.2f..%.2f, want the last five points with padding", lo, hi)
+	}
+}
+
+func TestChartSettleExcludedFromScale(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	data := NewData(10)
+	for i, value := range []float64{500, 10, 11, 12} {
+		data.AddAt(start.Add(time.Duration(i)*time.Second), value)
+	}
+
+	c := NewChart()
+	c.AddSeries("power", data, Style{})
+	c.SetViewport(Viewport{Settle: start})
+	if _, hi := c.Bounds(10); hi > 20 {
+		t.Errorf("settling point counted in the scale: max %.1f", hi)
+	}
+
+	rows := lines(c, AxisWidth+10, 5)
+	if axis := rows[len(rows)-1]; !strings.ContainsRune(axis, SettleAxisChar) {
+		t.Errorf("settling point not marked on the time axis: %q", axis)
+	}
+}
+
+func TestChartGradient(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	data := NewData(10)
+	for i, value := range []float64{0, 10, 5} {
+		data.AddAt(start.Add(time.Duration(i)*time.Second), value)
+	}
+
+	c := NewChart()
+	c.AddSeries("power", data, Style{Color: "yellow", Bold: true}).Gradient = func(value float64) string {
+		if value > 5 {
+			return "red"
+		}
+		return "green"
+	}
+	c.SetViewport(Viewport{Min: 0, Max: 10})
+	grid := NewGrid(AxisWidth+5, 4)
+	c.RenderTo(grid)
+
+	tests := []struct {
+		x, y  int
+		color string
+	}{
+		{AxisWidth, 2, "green"},     // 0
+		{AxisWidth + 1, 0, "red"},   // 10
+		{AxisWidth + 1, 1, "red"},   // link below 10
+		{AxisWidth + 2, 1, "green"}, // 5
+	}
+	for _, tt := range tests {
+		cell := grid.Cell(tt.x, tt.y)
+		if cell.Style.Color != tt.color || !cell.Style.Bold {
+			t.Errorf("cell %d,%d %q style = %+v, want bold %s", tt.x, tt.y, cell.Rune, cell.Style, tt.color)
+		}
+	}
+}
+
+func TestChartBackgroundSeries(t *testing.T) {
+	data, reference := NewData(10), NewData(10)
+	for i := 0; i < 5; i++ {
+		data.Add(1)
+		value := 1.0
+		if i == 2 {
+			value = math.NaN()
+		}
+		reference.Add(value)
+	}
+
+	c := NewChart()
+	c.AddSeries("charge", data, Style{})
+	c.AddSeries("reference", reference, Style{Dim: true}).Background = true
+	c.SetViewport(Viewport{Min: 0, Max: 2})
+
+	for _, row := range lines(c, AxisWidth+5, 4) {
+		if strings.ContainsRune(row, BackgroundChar) {
+			t.Errorf("background series drawn over the data: %q", row)
+		}
+	}
+}
+
+func TestGridTaggedLine(t *testing.T) {
+	grid := NewGrid(4, 1)
+	grid.SetCell(0, 0, 'a', Style{Color: "red"})
+	grid.SetCell(1, 0, 'b', Style{Color: "red"})
+	grid.SetCell(3, 0, 'c', Style{Color: "gray", Dim: true})
+
+	if got, want := grid.TaggedLine(0), "[red::-]ab [gray::d]c[-::-]"; got != want {
+		t.Errorf("TaggedLine = %q, want %q", got, want)
+	}
+}
+
+func TestChartSummarize(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	data := NewData(10)
+	for i, value := range []float64{100, 4, math.NaN(), 8, 6, 12} {
+		data.AddAt(start.Add(time.Duration(i)*time.Second), value)
+	}
+
+	c := NewChart()
+	c.AddSeries("power", data, Style{})
+	c.SetViewport(Viewport{Settle: start})
+
+	// The settling first point and the gap are left out
+	s, ok := c.Summarize("power", 10)
+	if !ok {
+		t.Fatal("no summary")
+	}
+	if want := (Summary{Min: 4, Max: 12, Mean: 7.5, Current: 12}); s != want {
+		t.Errorf("summary = %+v, want %+v", s, want)
+	}
+
+	// Only the newest points fit in a narrow plot area
+	if s, _ := c.Summarize("power", 2); s.Min != 6 || s.Max != 12 {
+		t.Errorf("narrow summary = %+v, want 6 to 12", s)
+	}
+	if _, ok := c.Summarize("voltage", 10); ok {
+		t.Error("summary of an unknown series")
+	}
+}
+
+func TestChartThreshold(t *testing.T) {
+	data := NewData(10)
+	for _, value := range []float64{0, 10} {
+		data.Add(value)
+	}
+
+	c := NewChart()
+	c.AddSeries("charge", data, Style{})
+	c.SetViewport(Viewport{Min: 0, Max: 10})
+	c.AddThreshold(Threshold{Value: 5, Label: "low"})
+	c.AddThreshold(Threshold{Value: 20, Label: "off the axis"})
+	rows := lines(c, AxisWidth+8, 4)
+
+	// The line fills the empty cells of its row, the label the first gap
+	// right of the series' link that it fits in
+	if want := "    5.00 ┤ ╌│low╌╌╌"; rows[1] != want {
+		t.Errorf("threshold row = %q, want %q", rows[1], want)
+	}
+	if strings.Contains(strings.Join(rows, "\n"), "off") {
+		t.Errorf("threshold outside the axis drawn:\n%s", strings.Join(rows, "\n"))
+	}
+}
+
+func TestChartCursor(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	data := NewData(10)
+	for i, value := range []float64{0, 10, 0} {
+		data.AddAt(start.Add(time.Duration(i)*time.Second), value)
+	}
+
+	c := NewChart()
+	c.AddSeries("power", data, Style{})
+	c.SetViewport(Viewport{Min: 0, Max: 10})
+	c.SetCursor(2)
+	rows := lines(c, AxisWidth+5, 4)
+
+	// The cursor is on the oldest point, the columns map back to the points
+	if want := "    5.00 ┤ ┆││  "; rows[1] != want {
+		t.Errorf("cursor row = %q, want %q", rows[1], want)
+	}
+	x, ok := c.Column(5, 2)
+	if !ok || x != 0 {
+		t.Fatalf("column = %d, %v, want 0", x, ok)
+	}
+	if p, ok := c.PointAt("power", 5, 1); !ok || p.Value != 10 || !p.Time.Equal(start.Add(time.Second)) {
+		t.Errorf("point at column 1 = %v, %v", p, ok)
+	}
+	if _, ok := c.PointAt("power", 5, 3); ok {
+		t.Error("point right of the newest one")
+	}
+	if _, ok := c.Column(5, 3); ok {
+		t.Error("column left of the oldest point")
+	}
+}
+
+func TestChartGapLabel(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	data := NewData(10)
+	data.SetGapThreshold(30 * time.Second)
+	for _, at := range []time.Duration{0, time.Second, 2 * time.Second, 42 * time.Minute, 42*time.Minute + time.Second} {
+		data.AddAt(start.Add(at), 5)
+	}
+
+	c := NewChart()
+	c.AddSeries("power", data, Style{})
+	c.SetViewport(Viewport{Min: 0, Max: 10})
+	c.SetGapLabel(func(gap time.Duration) string { return "off " + gap.String() })
+	rows := lines(c, AxisWidth+16, 4)
+
+	// The series breaks at the gap, which is marked and labeled in the top row
+	want := []string{
+		"   10.00 ┤    ┊off 41m58s  ",
+		"    5.00 ┤ ooo┊o*          ",
+		"    0.00 ┤    ┊            ",
+	}
+	for y := range want {
+		if rows[y] != want[y] {
+			t.Errorf("row %d = %q, want %q", y, rows[y], want[y])
+		}
+	}
+}
+
+func TestChartAnnotations(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	data := NewData(10)
+	for i := 0; i < 4; i++ {
+		data.AddAt(start.Add(time.Duration(i)*time.Minute), 0)
+	}
+
+	c := NewChart()
+	c.AddSeries("charge", data, Style{})
+	c.SetViewport(Viewport{Min: 0, Max: 10})
+	c.SetAnnotations([]Annotation{
+		{Time: start.Add(90 * time.Second), Mark: 'A', Style: Style{Color: "gray"}},
+		// Before the first point
+		{Time: start.Add(-time.Minute), Mark: 'X'},
+	})
+	grid := NewGrid(AxisWidth+4, 4)
+	c.RenderTo(grid)
+
+	// The annotation falls between the second and third point
+	x := AxisWidth + 1
+	if cell := grid.Cell(x, 0); cell.Rune != 'A' || cell.Style.Color != "gray" {
+		t.Errorf("annotation mark = %q %+v, want a gray A", cell.Rune, cell.Style)
+	}
+	if cell := grid.Cell(x, 1); cell.Rune != AnnotationChar {
+		t.Errorf("annotation line = %q, want %q", cell.Rune, AnnotationChar)
+	}
+	// The point at the bottom row is drawn over the line
+	if cell := grid.Cell(x, 2); cell.Rune != PointChar {
+		t.Errorf("point under the annotation = %q, want %q", cell.Rune, PointChar)
+	}
+	for column := AxisWidth; column < AxisWidth+4; column++ {
+		if grid.Cell(column, 0).Rune == 'X' {
+			t.Error("annotation before the first point drawn")
+		}
+	}
+}
@@ -0,0 +1,272 @@
+package plot
+
+import (
+	"encoding/json"
+	"math"
+	"sync"
+	"time"
+)
+
+// GapFactor is how many times longer than the previous one an interval
+// between points must be to count as a gap
+const GapFactor = 5
+
+// Point is one value of a series
+type Point struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+
+	// Gap is the length of an interruption of the recording (e.g., a
+	// suspend) for the gap points inserted in its place, which have no value
+	Gap time.Duration `json:"gap,omitempty"`
+}
+
+// pointJSON is the JSON form of a Point, with a null value for gap points
+// since JSON has no NaN
+type pointJSON struct {
+	Time  time.Time     `json:"time"`
+	Value *float64      `json:"value"`
+	Gap   time.Duration `json:"gap,omitempty"`
+}
+
+// MarshalJSON encodes the point, writing the value of a gap as null
+func (p Point) MarshalJSON() ([]byte, error) {
+	out := pointJSON{Time: p.Time, Gap: p.Gap}
+	if !math.IsNaN(p.Value) && !math.IsInf(p.Value, 0) {
+		out.Value = &p.Value
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes a point, reading a null value as a gap
+func (p *Point) UnmarshalJSON(data []byte) error {
+	var in pointJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	*p = Point{Time: in.Time, Value: math.NaN(), Gap: in.Gap}
+	if in.Value != nil {
+		p.Value = *in.Value
+	}
+	return nil
+}
+
+// Tier is a downsampled copy of a series holding the averages of Step long
+// buckets, so long histories take bounded memory
+type Tier struct {
+	// Step is the bucket width
+	Step time.Duration
+
+	// Capacity is the number of averaged points kept
+	Capacity int
+}
+
+// tier holds the averaged points of a Tier and the bucket being filled
+type tier struct {
+	Tier
+	points []Point
+	bucket time.Time
+	sum    float64
+	count  int
+	open   bool
+}
+
+// add adds a raw point, closing the current bucket when the point is past it
+func (t *tier) add(at time.Time, value float64) {
+	start := at.Truncate(t.Step)
+	if t.open && !start.Equal(t.bucket) {
+		t.points = append(t.points, t.pending())
+		if len(t.points) > t.Capacity {
+			t.points = t.points[1:]
+		}
+		t.open = false
+	}
+	if !t.open {
+		t.bucket, t.sum, t.count, t.open = start, 0, 0, true
+	}
+	// Gaps only show when a whole bucket is missing
+	if !math.IsNaN(value) {
+		t.sum += value
+		t.count++
+	}
+}
+
+// addGap closes the current bucket and adds a gap point when the gap spans
+// more than a bucket
+func (t *tier) addGap(gap Point) {
+	if gap.Gap <= t.Step {
+		return
+	}
+	if t.open {
+		t.points = append(t.points, t.pending())
+		t.open = false
+	}
+	t.points = append(t.points, gap)
+	if len(t.points) > t.Capacity {
+		t.points = t.points[len(t.points)-t.Capacity:]
+	}
+}
+
+// pending returns the average of the current bucket, NaN when it only has gaps
+func (t *tier) pending() Point {
+	if t.count == 0 {
+		return Point{Time: t.bucket, Value: math.NaN()}
+	}
+	return Point{Time: t.bucket, Value: t.sum / float64(t.count)}
+}
+
+// Data is a bounded time series; beyond its capacity the oldest points are
+// dropped. It is safe for concurrent use.
+type Data struct {
+	mu       sync.RWMutex
+	points   []Point
+	capacity int
+	tiers    []*tier
+
+	// gapThreshold enables gap points for long intervals; interval is the
+	// previous regular one
+	gapThreshold time.Duration
+	interval     time.Duration
+}
+
+// NewData creates a series holding up to capacity points
+func NewData(capacity int) *Data {
+	return &Data{
+		points:   make([]Point, 0, capacity),
+		capacity: capacity,
+	}
+}
+
+// NewTieredData creates a series holding up to capacity raw points and the
+// given downsampled tiers, finest first
+func NewTieredData(capacity int, tiers ...Tier) *Data {
+	d := NewData(capacity)
+	for _, t := range tiers {
+		d.tiers = append(d.tiers, &tier{Tier: t, points: make([]Point, 0, t.Capacity)})
+	}
+	return d
+}
+
+// Add adds a point recorded now
+func (d *Data) Add(value float64) {
+	d.AddAt(time.Now(), value)
+}
+
+// SetGapThreshold enables gap points: when the wall-clock time between two
+// points is longer than threshold and GapFactor times the previous interval
+// (e.g., after a suspend), a gap point is added in between so the series
+// isn't connected across it. 0 disables them.
+func (d *Data) SetGapThreshold(threshold time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.gapThreshold = threshold
+}
+
+// AddAt adds a point recorded at the given time. NaN values are gaps.
+func (d *Data) AddAt(at time.Time, value float64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if n := len(d.points); n > 0 && d.gapThreshold > 0 {
+		// The monotonic clock stops during a suspend, the wall clock doesn't
+		last := d.points[n-1].Time
+		interval := at.Round(0).Sub(last.Round(0))
+		if interval > d.gapThreshold && interval > GapFactor*d.interval {
+			d.add(Point{Time: last.Add(interval / 2), Value: math.NaN(), Gap: interval})
+		} else if interval > 0 {
+			d.interval = interval
+		}
+	}
+	d.add(Point{Time: at, Value: value})
+}
+
+// add appends a point, dropping the oldest beyond the capacity
+func (d *Data) add(p Point) {
+	d.points = append(d.points, p)
+	if len(d.points) > d.capacity {
+		d.points = d.points[1:]
+	}
+
+	for _, t := range d.tiers {
+		if p.Gap > 0 {
+			t.addGap(p)
+		} else {
+			t.add(p.Time, p.Value)
+		}
+	}
+}
+
+// Reset removes all points
+func (d *Data) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.points = d.points[:0]
+	d.interval = 0
+	for _, t := range d.tiers {
+		t.points, t.open = t.points[:0], false
+	}
+}
+
+// Convert changes every recorded value v to v*factor+offset, e.g. for a
+// changed unit
+func (d *Data) Convert(factor, offset float64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for i := range d.points {
+		d.points[i].Value = d.points[i].Value*factor + offset
+	}
+	for _, t := range d.tiers {
+		for i := range t.points {
+			t.points[i].Value = t.points[i].Value*factor + offset
+		}
+		t.sum = t.sum*factor + offset*float64(t.count)
+	}
+}
+
+// Len returns the number of points
+func (d *Data) Len() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return len(d.points)
+}
+
+// Points returns a copy of the raw points, oldest first
+func (d *Data) Points() []Point {
+	return d.TierPoints(0)
+}
+
+// Tiers returns the downsampled tiers, finest first
+func (d *Data) Tiers() []Tier {
+	tiers := make([]Tier, len(d.tiers))
+	for i, t := range d.tiers {
+		tiers[i] = t.Tier
+	}
+	return tiers
+}
+
+// TierPoints returns a copy of the points of a level, oldest first. Level 0
+// is the raw points and level n the n-th tier; levels beyond the coarsest
+// tier return the coarsest tier. The newest point of a tier is the average of
+// its unfinished bucket.
+func (d *Data) TierPoints(level int) []Point {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if level <= 0 || len(d.tiers) == 0 {
+		points := make([]Point, len(d.points))
+		copy(points, d.points)
+		return points
+	}
+
+	t := d.tiers[min(level, len(d.tiers))-1]
+	points := make([]Point, len(t.points), len(t.points)+1)
+	copy(points, t.points)
+	if t.open {
+		points = append(points, t.pending())
+	}
+	return points
+}
@@ -0,0 +1,129 @@
+package plot
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTieredData(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	data := NewTieredData(5, Tier{Step: 10 * time.Second, Capacity: 2}, Tier{Step: time.Minute, Capacity: 2})
+
+	// 35 one-second points: three full 10s buckets and a started one
+	for i := 0; i < 35; i++ {
+		data.AddAt(start.Add(time.Duration(i)*time.Second), float64(i))
+	}
+
+	if raw := data.Points(); len(raw) != 5 || raw[4].Value != 34 {
+		t.Fatalf("raw points = %v, want the 5 newest", raw)
+	}
+
+	// The oldest bucket was dropped, the newest one is still filling
+	tens := data.TierPoints(1)
+	want := []Point{
+		{Time: start.Add(10 * time.Second), Value: 14.5},
+		{Time: start.Add(20 * time.Second), Value: 24.5},
+		{Time: start.Add(30 * time.Second), Value: 32},
+	}
+	if len(tens) != len(want) {
+		t.Fatalf("10s points = %v, want %v", tens, want)
+	}
+	for i := range want {
+		if !tens[i].Time.Equal(want[i].Time) || tens[i].Value != want[i].Value {
+			t.Errorf("10s point %d = %v, want %v", i, tens[i], want[i])
+		}
+	}
+
+	// Levels past the coarsest tier return the coarsest tier
+	if minutes := data.TierPoints(5); len(minutes) != 1 || minutes[0].Value != 17 {
+		t.Errorf("1m points = %v, want the average of 0..34", minutes)
+	}
+
+	// Converting covers the raw points, the tiers and the unfinished buckets
+	data.Convert(1000, 5)
+	if raw := data.Points(); raw[4].Value != 34005 {
+		t.Errorf("converted raw point = %v, want 34005", raw[4].Value)
+	}
+	if tens := data.TierPoints(1); tens[0].Value != 14505 || tens[2].Value != 32005 {
+		t.Errorf("converted 10s points = %v, want 14505 and 32005", tens)
+	}
+
+	data.Reset()
+	if n := len(data.TierPoints(1)); n != 0 {
+		t.Errorf("%d tier points after reset", n)
+	}
+}
+
+func TestTieredDataGaps(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	data := NewTieredData(10, Tier{Step: 10 * time.Second, Capacity: 10})
+
+	data.AddAt(start, 4)
+	data.AddAt(start.Add(5*time.Second), math.NaN())
+	data.AddAt(start.Add(10*time.Second), math.NaN())
+
+	// A gap within a bucket is averaged over, a bucket of gaps is a gap
+	points := data.TierPoints(1)
+	if len(points) != 2 || points[0].Value != 4 || !math.IsNaN(points[1].Value) {
+		t.Errorf("points = %v, want 4 and a gap", points)
+	}
+}
+
+func TestDataGapThreshold(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	data := NewTieredData(10, Tier{Step: 10 * time.Second, Capacity: 10})
+	data.SetGapThreshold(30 * time.Second)
+
+	// Slowing down a little is no gap, stopping for 42 minutes is
+	at := start
+	for _, step := range []time.Duration{0, time.Second, 2 * time.Second, 42 * time.Minute, time.Second} {
+		at = at.Add(step)
+		data.AddAt(at, 1)
+	}
+
+	points := data.Points()
+	if len(points) != 6 {
+		t.Fatalf("points = %v, want a gap point among the 5", points)
+	}
+	gap := points[3]
+	if !math.IsNaN(gap.Value) || gap.Gap != 42*time.Minute || !gap.Time.After(points[2].Time) || !gap.Time.Before(points[4].Time) {
+		t.Errorf("gap point = %v", gap)
+	}
+
+	// The tier closes the bucket before the gap and starts a new one after it
+	tens := data.TierPoints(1)
+	if len(tens) != 3 || tens[1].Gap != 42*time.Minute || tens[2].Value != 1 {
+		t.Errorf("10s points = %v", tens)
+	}
+}
+
+func TestPointJSON(t *testing.T) {
+	at := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	points := []Point{
+		{Time: at, Value: 9.5},
+		{Time: at.Add(time.Minute), Value: math.NaN(), Gap: 42 * time.Minute},
+		{Time: at.Add(2 * time.Minute), Value: 0},
+	}
+
+	data, err := json.Marshal(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"time":"2026-03-02T09:00:00Z","value":9.5},` +
+		`{"time":"2026-03-02T09:01:00Z","value":null,"gap":2520000000000},` +
+		`{"time":"2026-03-02T09:02:00Z","value":0}]`
+	if string(data) != want {
+		t.Errorf("JSON %s, want %s", data, want)
+	}
+
+	var decoded []Point
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 3 || decoded[0].Value != 9.5 || !math.IsNaN(decoded[1].Value) || decoded[1].Gap != 42*time.Minute ||
+		decoded[2].Value != 0 || !decoded[2].Time.Equal(points[2].Time) {
+		t.Errorf("decoded %v", decoded)
+	}
+}
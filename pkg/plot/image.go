@@ -0,0 +1,149 @@
+package plot
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// Image colors of the chart parts without a style color
+var (
+	ImageBackground = color.RGBA{0x10, 0x10, 0x10, 0xff}
+	ImageAxis       = color.RGBA{0x80, 0x80, 0x80, 0xff}
+	ImageSeries     = color.RGBA{0xe0, 0xe0, 0xe0, 0xff}
+)
+
+// RenderImage draws the chart into the bounds of an image, for exports
+// without a terminal: all points of every series as connected lines, the
+// threshold lines dashed, the annotations dotted, and the axes on the left
+// and bottom edge. There are
+// no labels. palette maps style colors to image colors; nil draws every
+// series in ImageSeries.
+func (c *Chart) RenderImage(img draw.Image, palette func(name string) (color.Color, bool)) {
+	r := img.Bounds()
+	draw.Draw(img, r, image.NewUniform(ImageBackground), image.Point{}, draw.Src)
+	if r.Dx() < 2 || r.Dy() < 2 {
+		return
+	}
+
+	length := 0
+	for _, series := range c.series {
+		length = max(length, series.Data.Len())
+	}
+	w := c.scaled(c.visible(max(length, 1)))
+	lo, hi := c.bounds(w)
+
+	// Pixel of a point within the plot area right of and above the axes
+	left, bottom := r.Min.X+1, r.Max.Y-2
+	px := func(column int) int {
+		if w.length < 2 {
+			return r.Max.X - 1
+		}
+		return left + column*(r.Max.X-1-left)/(w.length-1)
+	}
+	py := func(value float64) int {
+		return r.Min.Y + y(value, lo, hi, bottom-r.Min.Y+1)
+	}
+
+	for _, threshold := range c.thresholds {
+		position := c.scale.forward(threshold.Value)
+		if math.IsNaN(position) || position < lo || position > hi {
+			continue
+		}
+		ink := imageColor(threshold.Style, palette)
+		for x := left; x < r.Max.X; x++ {
+			if (x-left)%8 < 4 {
+				img.Set(x, py(position), ink)
+			}
+		}
+	}
+
+	if len(c.series) > 0 {
+		for _, annotation := range c.annotations {
+			column, ok := annotationColumn(w, annotation)
+			if !ok {
+				continue
+			}
+			ink := imageColor(annotation.Style, palette)
+			for y := r.Min.Y; y < bottom; y += 3 {
+				img.Set(px(column), y, ink)
+			}
+		}
+	}
+
+	for i, series := range c.series {
+		ink := imageColor(series.Style, palette)
+		points := w.points[i]
+		for j := w.starts[i]; j < len(points); j++ {
+			if math.IsNaN(points[j].Value) {
+				continue
+			}
+			x, y := px(w.offsets[i]+j), py(points[j].Value)
+			if series.Background || j == w.starts[i] || math.IsNaN(points[j-1].Value) {
+				img.Set(x, y, ink)
+				continue
+			}
+			drawLine(img, px(w.offsets[i]+j-1), py(points[j-1].Value), x, y, ink)
+		}
+	}
+
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		img.Set(r.Min.X, y, ImageAxis)
+	}
+	for x := r.Min.X; x < r.Max.X; x++ {
+		img.Set(x, r.Max.Y-1, ImageAxis)
+	}
+}
+
+// imageColor returns the image color of a style
+func imageColor(style Style, palette func(string) (color.Color, bool)) color.Color {
+	if palette != nil && style.Color != "" {
+		if ink, ok := palette(style.Color); ok {
+			return ink
+		}
+	}
+	return ImageSeries
+}
+
+// drawLine draws a line between two pixels
+func drawLine(img draw.Image, x0, y0, x1, y1 int, ink color.Color) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := sign(x1-x0), sign(y1-y0)
+	e := dx + dy
+	for {
+		img.Set(x0, y0, ink)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+// abs returns the absolute value of an int
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// sign returns -1, 0 or 1 for the sign of an int
+func sign(v int) int {
+	switch {
+	case v < 0:
+		return -1
+	case v > 0:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,63 @@
+package plot
+
+import (
+	"image"
+	"image/color"
+	"testing"
+	"time"
+)
+
+func TestChartRenderImage(t *testing.T) {
+	data := NewData(10)
+	for _, value := range []float64{0, 10} {
+		data.Add(value)
+	}
+
+	c := NewChart()
+	c.AddSeries("power", data, Style{Color: "red"})
+	c.SetViewport(Viewport{Min: 0, Max: 10})
+	c.AddThreshold(Threshold{Value: 5})
+
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	img := image.NewRGBA(image.Rect(0, 0, 21, 12))
+	c.RenderImage(img, func(name string) (color.Color, bool) { return red, name == "red" })
+
+	// The series runs from the bottom left to the top right of the plot area
+	if got := img.At(1, 10); got != red {
+		t.Errorf("first point = %v, want red", got)
+	}
+	if got := img.At(20, 0); got != red {
+		t.Errorf("last point = %v, want red", got)
+	}
+	if got := img.At(0, 5); got != ImageAxis {
+		t.Errorf("value axis = %v", got)
+	}
+	if got := img.At(3, 11); got != ImageAxis {
+		t.Errorf("time axis = %v", got)
+	}
+	// The dashed threshold has gaps
+	if img.At(2, 5) != ImageSeries || img.At(6, 5) != ImageBackground {
+		t.Errorf("threshold = %v, %v", img.At(2, 5), img.At(6, 5))
+	}
+}
+
+func TestChartRenderImageAnnotations(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	data := NewData(10)
+	for i := 0; i < 3; i++ {
+		data.AddAt(start.Add(time.Duration(i)*time.Minute), 0)
+	}
+
+	c := NewChart()
+	c.AddSeries("charge", data, Style{})
+	c.SetViewport(Viewport{Min: 0, Max: 10})
+	c.SetAnnotations([]Annotation{{Time: start.Add(time.Minute)}})
+
+	img := image.NewRGBA(image.Rect(0, 0, 21, 12))
+	c.RenderImage(img, nil)
+
+	// The middle point is at x 10; the dotted line starts at the top
+	if img.At(10, 0) != ImageSeries || img.At(10, 1) != ImageBackground || img.At(10, 3) != ImageSeries {
+		t.Errorf("annotation = %v, %v, %v", img.At(10, 0), img.At(10, 1), img.At(10, 3))
+	}
+}
@@ -0,0 +1,82 @@
+package plot
+
+import (
+	"fmt"
+	"math"
+)
+
+// SymlogThreshold is the distance from zero within which ScaleSymlog is linear
+const SymlogThreshold = 1.0
+
+// Scale maps values to the value axis
+type Scale int
+
+// Value axis scales
+const (
+	// ScaleLinear spaces values evenly
+	ScaleLinear Scale = iota
+
+	// ScaleLog spaces powers of ten evenly; values at or below zero are not drawn
+	ScaleLog
+
+	// ScaleSymlog is logarithmic in both directions from zero and linear
+	// within SymlogThreshold of it, for series that change sign
+	ScaleSymlog
+)
+
+// Scales lists the scales in cycling order
+var Scales = []Scale{ScaleLinear, ScaleLog, ScaleSymlog}
+
+// String returns the scale name
+func (s Scale) String() string {
+	switch s {
+	case ScaleLog:
+		return "log"
+	case ScaleSymlog:
+		return "symlog"
+	default:
+		return "linear"
+	}
+}
+
+// ParseScale returns the scale with the given name
+func ParseScale(name string) (Scale, error) {
+	for _, s := range Scales {
+		if s.String() == name {
+			return s, nil
+		}
+	}
+	return ScaleLinear, fmt.Errorf("unknown scale %q: must be linear, log or symlog", name)
+}
+
+// Next returns the scale after s in cycling order
+func (s Scale) Next() Scale {
+	return Scales[(int(s)+1)%len(Scales)]
+}
+
+// forward maps a value to the evenly spaced axis, NaN when it can't be drawn
+func (s Scale) forward(value float64) float64 {
+	switch s {
+	case ScaleLog:
+		if value <= 0 {
+			return math.NaN()
+		}
+		return math.Log10(value)
+	case ScaleSymlog:
+		return math.Copysign(math.Log10(1+math.Abs(value)/SymlogThreshold), value)
+	default:
+		return value
+	}
+}
+
+// inverse maps a position on the evenly spaced axis back to a value
+func (s Scale) inverse(position float64) float64 {
+	switch s {
+	case ScaleLog:
+		return math.Pow(10, position)
+	case ScaleSymlog:
+		return math.Copysign((math.Pow(10, math.Abs(position))-1)*SymlogThreshold, position)
+	default:
+		return position
+	}
+}
@@ -0,0 +1,75 @@
+package plot
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestScaleRoundTrip(t *testing.T) {
+	for _, scale := range Scales {
+		parsed, err := ParseScale(scale.String())
+		if err != nil || parsed != scale {
+			t.Errorf("ParseScale(%q) = %v, %v", scale, parsed, err)
+		}
+		for _, value := range []float64{-60, -0.5, 0, 0.5, 1, 60} {
+			position := scale.forward(value)
+			if math.IsNaN(position) {
+				continue
+			}
+			if back := scale.inverse(position); math.Abs(back-value) > 1e-9 {
+				t.Errorf("%s: %v maps back to %v", scale, value, back)
+			}
+		}
+	}
+	if _, err := ParseScale("cubic"); err == nil {
+		t.Error("expected an error for an unknown scale")
+	}
+}
+
+func TestChartLogScale(t *testing.T) {
+	data := NewData(10)
+	for _, value := range []float64{1, 10, 100, 0} {
+		data.Add(value)
+	}
+
+	c := NewChart()
+	c.AddSeries("power", data, Style{})
+	c.SetScale(ScaleLog)
+	c.SetViewport(Viewport{Min: 1, Max: 100})
+	rows := lines(c, AxisWidth+4, 4)
+
+	// Powers of ten are evenly spaced and zero is not drawn
+	want := []string{
+		"  100.00 ┤   o ",
+		"   10.00 ┤  o  ",
+		"    1.00 ┤ o   ",
+	}
+	for y := range want {
+		if rows[y] != want[y] {
+			t.Errorf("row %d = %q, want %q", y, rows[y], want[y])
+		}
+	}
+}
+
+func TestChartSymlogScale(t *testing.T) {
+	data := NewData(10)
+	for _, value := range []float64{-50, -1, 0, 1, 50} {
+		data.Add(value)
+	}
+
+	c := NewChart()
+	c.AddSeries("power", data, Style{})
+	c.SetScale(ScaleSymlog)
+	rows := lines(c, AxisWidth+5, 6)
+
+	// Small values around zero stay apart from the large swings
+	lo, hi := c.Bounds(5)
+	if lo > -50 || hi < 50 {
+		t.Errorf("bounds = %v..%v, want to include -50..50", lo, hi)
+	}
+	body := strings.Join(rows[:5], "\n")
+	if strings.Count(body, "o")+strings.Count(body, "\\")+strings.Count(body, "/")+strings.Count(body, "*") != 5 {
+		t.Errorf("not all points drawn:\n%s", body)
+	}
+}
@@ -0,0 +1,192 @@
+package plot
+
+import (
+	"fmt"
+	"html"
+	"image/color"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+// SVG layout in user units
+const (
+	// SVGLabelWidth is the width left of the plot area for the value labels
+	SVGLabelWidth = 64
+
+	// SVGFontSize is the size of the labels; the time labels and the legend
+	// take one line below and above the plot area
+	SVGFontSize = 11
+
+	// svgValueLabels is the number of value labels
+	svgValueLabels = 5
+)
+
+// RenderSVG writes the chart as an SVG image of the given size, for exports
+// without a terminal that stay sharp when scaled: all points of every series
+// as lines broken at gaps, background series as dots, the threshold lines
+// dashed with their labels, the annotations dotted, value labels in the
+// chart's label format on the left, the time of the first and last point
+// below, and the legend above when enabled. palette maps style colors to
+// image colors like in RenderImage; nil draws every series in ImageSeries.
+func (c *Chart) RenderSVG(out io.Writer, width, height int, palette func(name string) (color.Color, bool)) error {
+	var svg strings.Builder
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="%d">`,
+		width, height, width, height, SVGFontSize)
+	fmt.Fprintf(&svg, `<rect width="%d" height="%d" fill="%s"/>`, width, height, svgColor(ImageBackground))
+
+	top, bottom := float64(SVGFontSize)/2, float64(height-2*SVGFontSize)
+	if c.legend {
+		top += 2 * SVGFontSize
+		c.svgLegend(&svg, palette)
+	}
+	left, right := float64(SVGLabelWidth), float64(width-SVGFontSize/2)
+	if right-left < 2 || bottom-top < 2 {
+		svg.WriteString("</svg>")
+		_, err := io.WriteString(out, svg.String())
+		return err
+	}
+
+	length := 0
+	for _, series := range c.series {
+		length = max(length, series.Data.Len())
+	}
+	w := c.scaled(c.visible(max(length, 1)))
+	lo, hi := c.bounds(w)
+
+	// Position of a point within the plot area
+	px := func(column int) float64 {
+		if w.length < 2 {
+			return right
+		}
+		return left + float64(column)*(right-left)/float64(w.length-1)
+	}
+	py := func(value float64) float64 {
+		if hi <= lo {
+			return (top + bottom) / 2
+		}
+		return top + (hi-value)/(hi-lo)*(bottom-top)
+	}
+
+	axis := svgColor(ImageAxis)
+	for n := 0; n < svgValueLabels; n++ {
+		value := hi - float64(n)/(svgValueLabels-1)*(hi-lo)
+		fmt.Fprintf(&svg, `<text x="%.1f" y="%.1f" fill="%s" text-anchor="end" dominant-baseline="middle">%s</text>`,
+			left-6, py(value), axis, html.EscapeString(c.format(c.scale.inverse(value))))
+		fmt.Fprintf(&svg, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s"/>`, left-3, py(value), left, py(value), axis)
+	}
+	if first, last, ok := w.timeRange(); ok {
+		layout := "15:04"
+		if last.Sub(first) > 48*time.Hour {
+			layout = "2006-01-02"
+		}
+		fmt.Fprintf(&svg, `<text x="%.1f" y="%d" fill="%s">%s</text>`, left, height-SVGFontSize/2, axis, first.Format(layout))
+		fmt.Fprintf(&svg, `<text x="%.1f" y="%d" fill="%s" text-anchor="end">%s</text>`, right, height-SVGFontSize/2, axis, last.Format(layout))
+	}
+
+	for _, threshold := range c.thresholds {
+		position := c.scale.forward(threshold.Value)
+		if math.IsNaN(position) || position < lo || position > hi {
+			continue
+		}
+		ink := svgColor(imageColor(threshold.Style, palette))
+		fmt.Fprintf(&svg, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-dasharray="6 4"/>`,
+			left, py(position), right, py(position), ink)
+		if threshold.Label != "" {
+			fmt.Fprintf(&svg, `<text x="%.1f" y="%.1f" fill="%s">%s</text>`, left+4, py(position)-3, ink, html.EscapeString(threshold.Label))
+		}
+	}
+
+	if len(c.series) > 0 {
+		for _, annotation := range c.annotations {
+			column, ok := annotationColumn(w, annotation)
+			if !ok {
+				continue
+			}
+			fmt.Fprintf(&svg, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-dasharray="1 3"/>`,
+				px(column), top, px(column), bottom, svgColor(imageColor(annotation.Style, palette)))
+		}
+	}
+
+	for i, series := range c.series {
+		ink := svgColor(imageColor(series.Style, palette))
+		points := w.points[i]
+		if series.Background {
+			for j := w.starts[i]; j < len(points); j++ {
+				if !math.IsNaN(points[j].Value) {
+					fmt.Fprintf(&svg, `<circle cx="%.1f" cy="%.1f" r="1" fill="%s"/>`, px(w.offsets[i]+j), py(points[j].Value), ink)
+				}
+			}
+			continue
+		}
+
+		// One polyline per run of points between gaps
+		var run [][2]float64
+		flush := func() {
+			switch len(run) {
+			case 0:
+			case 1:
+				fmt.Fprintf(&svg, `<circle cx="%.1f" cy="%.1f" r="1.5" fill="%s"/>`, run[0][0], run[0][1], ink)
+			default:
+				svg.WriteString(`<polyline points="`)
+				for k, p := range run {
+					if k > 0 {
+						svg.WriteByte(' ')
+					}
+					fmt.Fprintf(&svg, "%.1f,%.1f", p[0], p[1])
+				}
+				fmt.Fprintf(&svg, `" fill="none" stroke="%s" stroke-width="1.5" stroke-linejoin="round"/>`, ink)
+			}
+			run = run[:0]
+		}
+		for j := w.starts[i]; j < len(points); j++ {
+			if math.IsNaN(points[j].Value) {
+				flush()
+				continue
+			}
+			run = append(run, [2]float64{px(w.offsets[i] + j), py(points[j].Value)})
+		}
+		flush()
+	}
+
+	fmt.Fprintf(&svg, `<path d="M%.1f %.1fV%.1fH%.1f" fill="none" stroke="%s"/>`, left, top, bottom, right, axis)
+	svg.WriteString("</svg>")
+	_, err := io.WriteString(out, svg.String())
+	return err
+}
+
+// svgLegend writes the color and name of every series into the top line
+func (c *Chart) svgLegend(svg *strings.Builder, palette func(string) (color.Color, bool)) {
+	x := float64(SVGLabelWidth)
+	for _, series := range c.series {
+		ink := svgColor(imageColor(series.Style, palette))
+		fmt.Fprintf(svg, `<rect x="%.1f" y="%d" width="%d" height="%d" fill="%s"/>`, x, SVGFontSize/2, SVGFontSize, SVGFontSize, ink)
+		x += 1.5 * SVGFontSize
+		fmt.Fprintf(svg, `<text x="%.1f" y="%d" fill="%s">%s</text>`, x, 3*SVGFontSize/2, svgColor(ImageAxis), html.EscapeString(series.Name))
+		x += float64(len([]rune(series.Name))+2) * SVGFontSize * 0.6
+	}
+}
+
+// timeRange returns the times of the first and last visible point
+func (w window) timeRange() (time.Time, time.Time, bool) {
+	var first, last time.Time
+	for i, points := range w.points {
+		if w.starts[i] >= len(points) {
+			continue
+		}
+		if t := points[w.starts[i]].Time; first.IsZero() || t.Before(first) {
+			first = t
+		}
+		if t := points[len(points)-1].Time; t.After(last) {
+			last = t
+		}
+	}
+	return first, last, !first.IsZero()
+}
+
+// svgColor returns the hex notation of a color
+func svgColor(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+}
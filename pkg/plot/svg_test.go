@@ -0,0 +1,70 @@
+package plot
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestChartRenderSVG(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	data := NewData(10)
+	for i, value := range []float64{0, 4, math.NaN(), 6, 10} {
+		data.AddAt(start.Add(time.Duration(i)*time.Minute), value)
+	}
+
+	c := NewChart()
+	c.AddSeries("power", data, Style{Color: "red"})
+	c.SetViewport(Viewport{Min: 0, Max: 10})
+	c.SetLabelFormat(func(value float64) string { return fmt.Sprintf("%.0f W", value) })
+	c.AddThreshold(Threshold{Value: 5, Label: "budget <5 W>"})
+	c.SetLegend(true)
+
+	var out strings.Builder
+	if err := c.RenderSVG(&out, 320, 120, nil); err != nil {
+		t.Fatal(err)
+	}
+	svg := out.String()
+
+	// The output is well-formed XML
+	decoder := xml.NewDecoder(strings.NewReader(svg))
+	for {
+		if _, err := decoder.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("invalid SVG: %v\n%s", err, svg)
+		}
+	}
+
+	for _, want := range []string{
+		`width="320" height="120"`,
+		">10 W</text>",
+		">0 W</text>",
+		">budget &lt;5 W&gt;</text>",
+		">power</text>",
+		">09:00</text>",
+		">09:04</text>",
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("SVG lacks %q:\n%s", want, svg)
+		}
+	}
+	// The gap splits the series in two lines
+	if got := strings.Count(svg, "<polyline"); got != 2 {
+		t.Errorf("%d polylines, want 2:\n%s", got, svg)
+	}
+}
+
+func TestChartRenderSVGEmpty(t *testing.T) {
+	var out strings.Builder
+	if err := NewChart().RenderSVG(&out, 100, 50, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out.String(), "<svg ") || !strings.HasSuffix(out.String(), "</svg>") {
+		t.Errorf("RenderSVG() = %q", out.String())
+	}
+}